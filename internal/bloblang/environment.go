@@ -79,6 +79,28 @@ func (e *Environment) NewMapping(blobl string) (*mapping.Executor, error) {
 	return exec, nil
 }
 
+// FormatMapping parses a Bloblang mapping using the Environment and returns it
+// re-emitted in a canonical layout, preserving comments.
+//
+// When a parsing error occurs the error will be the type *parser.Error.
+func (e *Environment) FormatMapping(blobl string) (string, error) {
+	return parser.FormatMapping(e.pCtx, blobl)
+}
+
+// AnalyseMapping parses a Bloblang mapping using the Environment and performs a
+// static analysis of it without executing it, returning any problems found.
+// The documents that the mapping is executed upon are optionally described by
+// a schema.
+//
+// When a parsing error occurs the error will be the type *parser.Error.
+func (e *Environment) AnalyseMapping(blobl string, schema *query.Schema) ([]mapping.Warning, error) {
+	exec, err := parser.ParseMapping(e.pCtx.Deactivated(), blobl)
+	if err != nil {
+		return nil, err
+	}
+	return exec.Analyse(schema), nil
+}
+
 // Deactivated returns a version of the environment where constructors are
 // disabled for all functions and methods, allowing mappings to be parsed and
 // validated but not executed.
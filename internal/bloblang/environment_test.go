@@ -0,0 +1,219 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package bloblang
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/mapping"
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/query"
+)
+
+func TestAnalyseMapping(t *testing.T) {
+	schema, err := query.NewSchemaFromJSONSchema([]byte(`{
+  "type": "object",
+  "properties": {
+    "id": { "type": "string" },
+    "age": { "type": "integer" },
+    "tags": { "type": "array", "items": { "type": "string" } },
+    "user": {
+      "type": "object",
+      "properties": {
+        "name": { "type": "string" }
+      },
+      "additionalProperties": false
+    },
+    "extra": { "type": "object" }
+  },
+  "additionalProperties": false
+}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		mapping  string
+		schema   *query.Schema
+		warnings []mapping.Warning
+	}{
+		{
+			name: "no problems",
+			mapping: `let name = this.name.uppercase()
+root.name = $name
+root.id = uuid_v4().lowercase()
+root.count = (this.count | 0) + 1
+root.tags = this.tags.map_each(t -> t.uppercase())`,
+		},
+		{
+			name: "method receiver types",
+			mapping: `root.a = 10.uppercase()
+root.b = "foo".floor()
+root.c = now().has_prefix("2025")
+root.d = uuid_v4().keys()`,
+			warnings: []mapping.Warning{
+				{Line: 1, Message: "method uppercase expects a string or bytes value, but number literal resolves to a number"},
+				{Line: 2, Message: "method floor expects a number value, but string literal resolves to a string"},
+				{Line: 4, Message: "method keys expects an object value, but function uuid_v4 resolves to a string"},
+			},
+		},
+		{
+			name: "method argument types",
+			mapping: `root.a = this.a.has_prefix(uuid_v4().length())
+root.b = this.b.replace_all(this.c, this.d)`,
+			warnings: []mapping.Warning{
+				{Line: 1, Message: "parameter value of method has_prefix expects a string value, but method length resolves to a number"},
+			},
+		},
+		{
+			name: "inferred types of variables",
+			mapping: `let num = 5
+let str = "foo"
+root.a = $num.uppercase()
+root.b = $str.uppercase()
+root.c = $num + $str`,
+			warnings: []mapping.Warning{
+				{Line: 3, Message: "method uppercase expects a string or bytes value, but variable num resolves to a number"},
+				{Line: 5, Message: "cannot add variable num (number) and variable str (string)"},
+			},
+		},
+		{
+			name: "operators",
+			mapping: `root.a = this.a - "foo"
+root.b = !"foo"
+root.c = this.c && "bar"
+root.d = this.d > {"foo":this.e}`,
+			warnings: []mapping.Warning{
+				{Line: 1, Message: "cannot subtract string literal as it resolves to a string rather than a number"},
+				{Line: 2, Message: "cannot negate string literal as it resolves to a string rather than a bool"},
+				{Line: 3, Message: "cannot boolean and string literal as it resolves to a string rather than a bool"},
+				{Line: 4, Message: "cannot compare object literal as it resolves to an object"},
+			},
+		},
+		{
+			name: "unreachable match cases",
+			mapping: `root.a = match this.a {
+  "foo" => 1
+  "bar" => 2
+  "foo" => 3
+  _ => 4
+  this.b => 5
+}
+root.b = match {
+  this.c == "x" => 1
+  "x".uppercase() => 2
+}`,
+			warnings: []mapping.Warning{
+				{Line: 1, Message: "match case 3 is unreachable as case 1 matches the same value"},
+				{Line: 1, Message: "match case 5 is unreachable as case 4 always matches"},
+				{Line: 8, Message: "match case 2 is unreachable as method uppercase resolves to a string rather than a bool"},
+			},
+		},
+		{
+			name: "conditions",
+			mapping: `root.a = if "foo".uppercase() { 1 }
+if 10.floor() {
+  root.b = 2
+}`,
+			warnings: []mapping.Warning{
+				{Line: 1, Message: "method uppercase resolves to a string rather than a bool and cannot be used as a condition"},
+				{Line: 2, Message: "method floor resolves to a number rather than a bool and cannot be used as a condition"},
+			},
+		},
+		{
+			name: "unused variables",
+			mapping: `map foo {
+  let unused_in_map = "a"
+  let used_in_map = "b"
+  root = $used_in_map
+}
+let unused = "c"
+let used = "d"
+let used_by_func = "e"
+if this.ok {
+  let unused_in_if = "f"
+}
+root.a = $used
+root.b = var("used_by_func")
+root.c = this.c.apply("foo")`,
+			warnings: []mapping.Warning{
+				{Line: 2, Message: "variable unused_in_map is declared but never used"},
+				{Line: 6, Message: "variable unused is declared but never used"},
+				{Line: 10, Message: "variable unused_in_if is declared but never used"},
+			},
+		},
+		{
+			name: "variable types after root level if",
+			mapping: `let a = 5
+let b = 5
+if this.ok {
+  let a = "foo"
+  let b = 10
+}
+root.a = $a.uppercase()
+root.b = $b.uppercase()`,
+			warnings: []mapping.Warning{
+				{Line: 8, Message: "method uppercase expects a string or bytes value, but variable b resolves to a number"},
+			},
+		},
+		{
+			name: "schema field references",
+			mapping: `root.id = this.id.uppercase()
+root.age = this.age.uppercase()
+root.missing = this.missing
+root.name = this.user.name
+root.nick = this.user.nick
+root.foo = this.id.foo
+root.extra = this.extra.anything
+root.tag = this.tags.0.uppercase()
+root.names = this.user.(u -> u.name + u.nope)
+root.nested = this.user.(this.name + this.nope)
+root.mapped = this.tags.map_each(t -> t.whatever)`,
+			schema: schema,
+			warnings: []mapping.Warning{
+				{Line: 2, Message: "method uppercase expects a string or bytes value, but field `this.age` resolves to a number"},
+				{Line: 3, Message: "field `this.missing` is not defined by the schema"},
+				{Line: 5, Message: "field `this.user.nick` is not defined by the schema"},
+				{Line: 6, Message: "field `this.id.foo` is not possible as the schema defines `id` as a string"},
+				{Line: 9, Message: "field `u.nope` is not defined by the schema"},
+				{Line: 10, Message: "field `this.nope` is not defined by the schema"},
+			},
+		},
+		{
+			name: "coercion defaults",
+			mapping: `root.a = this.age.number(5).uppercase()
+root.b = this.age.number(this.id).uppercase()
+root.c = this.id.bool(this.user).uppercase()
+root.d = this.id.bool().uppercase()`,
+			schema: schema,
+			warnings: []mapping.Warning{
+				{Line: 1, Message: "method uppercase expects a string or bytes value, but method number resolves to a number"},
+				{Line: 2, Message: "parameter default of method number expects a float value, but field `this.id` resolves to a string"},
+				{Line: 3, Message: "parameter default of method bool expects a bool value, but field `this.user` resolves to an object"},
+				{Line: 4, Message: "method uppercase expects a string or bytes value, but method bool resolves to a bool"},
+			},
+		},
+		{
+			name: "schema ignored within maps",
+			mapping: `map foo {
+  root = this.missing
+}
+root = this.user.apply("foo")`,
+			schema: schema,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			warnings, err := GlobalEnvironment().AnalyseMapping(test.mapping, test.schema)
+			require.NoError(t, err)
+			assert.Equal(t, test.warnings, warnings)
+		})
+	}
+}
+
+func TestAnalyseMappingParseError(t *testing.T) {
+	_, err := GlobalEnvironment().AnalyseMapping(`root = this.`, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package mapping
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/query"
+	"github.com/redpanda-data/benthos/v4/internal/value"
+)
+
+// Warning describes a problem found by the static analysis of a mapping.
+type Warning struct {
+	// The line of the mapping that the problem was found at.
+	Line int
+
+	Message string
+}
+
+// Analyse performs a static analysis of the mapping without executing it and
+// returns any problems found, ordered by line. The documents that the mapping
+// is executed upon are optionally described by a schema, which is used in
+// order to validate field references. Map definitions are analysed with an
+// unknown context, and map definitions imported from other files are ignored.
+//
+// The mapping must have been parsed with a deactivated environment, as
+// otherwise the arguments of functions and methods cannot be inspected.
+func (e *Executor) Analyse(schema *query.Schema) []Warning {
+	m := &mappingAnalyser{input: e.input}
+	m.scope(query.NewAnalysis(schema), e.statements)
+
+	mapNames := make([]string, 0, len(e.maps))
+	for k := range e.maps {
+		mapNames = append(mapNames, k)
+	}
+	sort.Strings(mapNames)
+	for _, k := range mapNames {
+		mapExec, ok := e.maps[k].(*Executor)
+		if !ok || !m.isLocal(mapExec.statements) {
+			continue
+		}
+		m.scope(query.NewAnalysis(nil), mapExec.statements)
+	}
+
+	sort.SliceStable(m.warnings, func(i, j int) bool {
+		return m.warnings[i].Line < m.warnings[j].Line
+	})
+	return m.warnings
+}
+
+type mappingAnalyser struct {
+	input    []rune
+	warnings []Warning
+}
+
+type declaredVar struct {
+	name string
+	line int
+}
+
+// isClip returns whether a slice is a tailing clip of the mapping input.
+func (m *mappingAnalyser) isClip(clip []rune) bool {
+	return len(clip) > 0 && len(clip) <= len(m.input) && &m.input[len(m.input)-len(clip)] == &clip[0]
+}
+
+func (m *mappingAnalyser) isLocal(stmts []Statement) bool {
+	for _, stmt := range stmts {
+		if !m.isClip(stmt.Input()) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *mappingAnalyser) lineOf(stmt Statement) int {
+	if !m.isClip(stmt.Input()) {
+		return 1
+	}
+	line, _ := LineAndColOf(m.input, stmt.Input())
+	return line
+}
+
+func (m *mappingAnalyser) warnf(line int, format string, args ...any) {
+	m.warnings = append(m.warnings, Warning{
+		Line:    line,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// drain runs an analysis function and reports any new issues as warnings of
+// a line.
+func (m *mappingAnalyser) drain(a *query.Analysis, line int, fn func()) {
+	prevIssues := len(a.Issues)
+	fn()
+	for _, issue := range a.Issues[prevIssues:] {
+		m.warnf(line, "%v", issue)
+	}
+}
+
+func (m *mappingAnalyser) infer(a *query.Analysis, line int, fn query.Function) (t value.Type) {
+	m.drain(a, line, func() {
+		t = a.Infer(fn)
+	})
+	return
+}
+
+// scope analyses a list of statements that share variables, and reports the
+// variables that they declare but never use.
+func (m *mappingAnalyser) scope(a *query.Analysis, stmts []Statement) {
+	var declared []declaredVar
+	m.statements(a, stmts, &declared)
+	for _, d := range declared {
+		if _, used := a.UsedVars[d.name]; !used {
+			m.warnf(d.line, "variable %v is declared but never used", d.name)
+		}
+	}
+}
+
+func isDeclared(declared []declaredVar, name string) bool {
+	for _, d := range declared {
+		if d.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *mappingAnalyser) statements(a *query.Analysis, stmts []Statement, declared *[]declaredVar) {
+	for _, stmt := range stmts {
+		line := m.lineOf(stmt)
+		switch t := stmt.(type) {
+		case *SingleStatement:
+			k := m.infer(a, line, t.query)
+			if v, ok := t.assignment.(*VarAssignment); ok {
+				if !isDeclared(*declared, v.name) {
+					*declared = append(*declared, declaredVar{name: v.name, line: line})
+				}
+				a.Vars[v.name] = k
+			}
+		case *RootLevelIfStatement:
+			m.rootLevelIf(a, line, t, declared)
+		}
+	}
+}
+
+// rootLevelIf analyses each branch of a root level if statement from the same
+// set of variables, and afterwards only keeps the types of variables that are
+// consistent across all possible outcomes.
+func (m *mappingAnalyser) rootLevelIf(a *query.Analysis, line int, r *RootLevelIfStatement, declared *[]declaredVar) {
+	before := make(map[string]value.Type, len(a.Vars))
+	for k, v := range a.Vars {
+		before[k] = v
+	}
+
+	var outcomes []map[string]value.Type
+	hasElse := false
+	for _, p := range r.pairs {
+		a.Vars = make(map[string]value.Type, len(before))
+		for k, v := range before {
+			a.Vars[k] = v
+		}
+		if p.query != nil {
+			m.drain(a, line, func() {
+				a.CheckCondition(p.query, false)
+			})
+		} else {
+			hasElse = true
+		}
+		m.statements(a, p.statements, declared)
+		outcomes = append(outcomes, a.Vars)
+	}
+	if !hasElse {
+		outcomes = append(outcomes, before)
+	}
+
+	merged := map[string]value.Type{}
+	for _, outcome := range outcomes {
+		for k, v := range outcome {
+			if prev, exists := merged[k]; exists && prev != v {
+				v = value.TUnknown
+			}
+			merged[k] = v
+		}
+	}
+	for k := range merged {
+		for _, outcome := range outcomes {
+			if _, exists := outcome[k]; !exists {
+				merged[k] = value.TUnknown
+				break
+			}
+		}
+	}
+	a.Vars = merged
+}
@@ -244,6 +244,9 @@ func (e *Executor) Exec(ctx query.FunctionContext) (any, error) {
 	var newObj any = value.Nothing(nil)
 	ctx.NewValue = &newObj
 
+	// Statements of maps applied by a traced mapping are not traced.
+	ctx.Tracer = nil
+
 	for _, stmt := range e.statements {
 		if err := stmt.Execute(ctx, AssignmentContext{
 			Vars: ctx.Vars,
@@ -267,6 +270,16 @@ func (e *Executor) ExecOnto(ctx query.FunctionContext, onto AssignmentContext) e
 	return nil
 }
 
+// ExecOntoTraced executes the mapping onto a provided assignment context and
+// returns a trace of the execution of each statement. The trace is returned
+// even when the mapping fails, in which case the last statement traced is the
+// one that failed.
+func (e *Executor) ExecOntoTraced(ctx query.FunctionContext, onto AssignmentContext) (*Trace, error) {
+	t := &Trace{input: e.input}
+	ctx.Tracer = t
+	return t, e.ExecOnto(ctx, onto)
+}
+
 // ToBytes executes this function for a message of a batch and returns the
 // result marshalled into a byte slice.
 func (e *Executor) ToBytes(ctx query.FunctionContext) ([]byte, error) {
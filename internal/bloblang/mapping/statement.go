@@ -4,6 +4,7 @@ package mapping
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/redpanda-data/benthos/v4/internal/bloblang/query"
 	"github.com/redpanda-data/benthos/v4/internal/value"
@@ -57,15 +58,27 @@ func (s *SingleStatement) Input() []rune {
 // Execute executes this statement and applies the result onto the assigned
 // destination.
 func (s *SingleStatement) Execute(fnContext query.FunctionContext, asContext AssignmentContext) error {
+	if t := tracerFrom(fnContext); t != nil {
+		target := s.assignment.Target()
+		i := t.begin(s.input, targetString(target), targetValue(target, asContext))
+		res, err := s.execute(fnContext, asContext)
+		t.end(i, res, asContext.Vars, err)
+		return err
+	}
+	_, err := s.execute(fnContext, asContext)
+	return err
+}
+
+func (s *SingleStatement) execute(fnContext query.FunctionContext, asContext AssignmentContext) (any, error) {
 	res, err := s.query.Exec(fnContext)
 	if err != nil {
-		return err
+		return value.Nothing(nil), err
 	}
 	if _, isNothing := res.(value.Nothing); isNothing {
 		// Skip assignment entirely
-		return nil
+		return res, nil
 	}
-	return s.assignment.Apply(res, asContext)
+	return res, s.assignment.Apply(res, asContext)
 }
 
 //------------------------------------------------------------------------------
@@ -134,20 +147,38 @@ func (r *RootLevelIfStatement) Input() []rune {
 // Execute executes this statement if the underlying condition evaluates to
 // true.
 func (r *RootLevelIfStatement) Execute(fnContext query.FunctionContext, asContext AssignmentContext) error {
+	t := tracerFrom(fnContext)
+	var traceIndex int
+	if t != nil {
+		traceIndex = t.begin(r.input, "", value.Nothing(nil))
+	}
+
 	for i, p := range r.pairs {
 		if p.query != nil {
 			queryVal, err := p.query.Exec(fnContext)
 			if err != nil {
-				return fmt.Errorf("failed to check if condition %v: %w", i+1, err)
+				err = fmt.Errorf("failed to check if condition %v: %w", i+1, err)
+				if t != nil {
+					t.end(traceIndex, value.Nothing(nil), asContext.Vars, err)
+				}
+				return err
 			}
 			queryRes, isBool := queryVal.(bool)
 			if !isBool {
-				return fmt.Errorf("%v resolved to a non-boolean value %v (%T)", p.query.Annotation(), queryVal, queryVal)
+				err = fmt.Errorf("%v resolved to a non-boolean value %v (%T)", p.query.Annotation(), queryVal, queryVal)
+				if t != nil {
+					t.end(traceIndex, value.Nothing(nil), asContext.Vars, err)
+				}
+				return err
 			}
 			if !queryRes {
 				continue
 			}
 		}
+		if t != nil {
+			t.TraceBranch("if statement", rootLevelIfBranch(i, p.query == nil))
+			t.end(traceIndex, value.Nothing(nil), asContext.Vars, nil)
+		}
 		for _, stmt := range p.statements {
 			if err := stmt.Execute(fnContext, asContext); err != nil {
 				return err
@@ -155,5 +186,19 @@ func (r *RootLevelIfStatement) Execute(fnContext query.FunctionContext, asContex
 		}
 		return nil
 	}
+	if t != nil {
+		t.TraceBranch("if statement", "none")
+		t.end(traceIndex, value.Nothing(nil), asContext.Vars, nil)
+	}
 	return nil
 }
+
+func rootLevelIfBranch(i int, isElse bool) string {
+	switch {
+	case isElse:
+		return "else"
+	case i == 0:
+		return "if"
+	}
+	return "else if " + strconv.Itoa(i)
+}
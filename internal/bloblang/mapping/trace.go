@@ -0,0 +1,168 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package mapping
+
+import (
+	"strings"
+
+	"github.com/Jeffail/gabs/v2"
+
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/query"
+	"github.com/redpanda-data/benthos/v4/internal/value"
+)
+
+// BranchTrace describes a branch taken by a conditional expression or
+// statement during the execution of a mapping statement.
+type BranchTrace struct {
+	// The kind of conditional, e.g. "if statement" or "match expression".
+	Expression string
+
+	// The branch that was taken, e.g. "else if 2" or "case 3", or "none" when
+	// no branch was taken.
+	Branch string
+}
+
+// StatementTrace describes the execution of a single mapping statement.
+type StatementTrace struct {
+	// The line of the mapping that the statement begins at.
+	Line int
+
+	// The first line of the statement expression.
+	Statement string
+
+	// A representation of the assignment target of the statement, e.g.
+	// "root.foo", "@bar" or "$baz". Empty for root level if statements.
+	Target string
+
+	// The value of the target before the statement was executed, which is
+	// value.Nothing when the target was not yet set.
+	Input any
+
+	// The result of the statement query, which is value.Nothing when the
+	// assignment was skipped.
+	Result any
+
+	// The branches of conditionals taken during the execution of the
+	// statement, in the order that they were taken.
+	Branches []BranchTrace
+
+	// The variables declared by the mapping after the statement was executed.
+	Vars map[string]any
+
+	// An error returned by the statement, if any.
+	Err error
+}
+
+// Trace records the execution of the statements of a mapping, including the
+// statements nested within root level if statements. Statements executed
+// within map definitions applied by the mapping are not recorded.
+type Trace struct {
+	input      []rune
+	Statements []StatementTrace
+}
+
+var _ query.BranchTracer = &Trace{}
+
+// TraceBranch records a branch taken by a conditional expression within the
+// statement currently being executed.
+func (t *Trace) TraceBranch(expression, branch string) {
+	if len(t.Statements) == 0 {
+		return
+	}
+	s := &t.Statements[len(t.Statements)-1]
+	s.Branches = append(s.Branches, BranchTrace{
+		Expression: expression,
+		Branch:     branch,
+	})
+}
+
+func (t *Trace) begin(stmtInput []rune, target string, input any) int {
+	var line int
+	if len(t.input) > 0 && len(stmtInput) > 0 {
+		line, _ = LineAndColOf(t.input, stmtInput)
+	}
+	stmt, _, _ := strings.Cut(string(stmtInput), "\n")
+	t.Statements = append(t.Statements, StatementTrace{
+		Line:      line,
+		Statement: strings.TrimSpace(stmt),
+		Target:    target,
+		Input:     input,
+		Result:    value.Nothing(nil),
+	})
+	return len(t.Statements) - 1
+}
+
+func (t *Trace) end(i int, result any, vars map[string]any, err error) {
+	t.Statements[i].Result = value.IClone(result)
+	t.Statements[i].Err = err
+	if len(vars) > 0 {
+		varsCopy := make(map[string]any, len(vars))
+		for k, v := range vars {
+			varsCopy[k] = value.IClone(v)
+		}
+		t.Statements[i].Vars = varsCopy
+	}
+}
+
+func tracerFrom(ctx query.FunctionContext) *Trace {
+	t, _ := ctx.Tracer.(*Trace)
+	return t
+}
+
+//------------------------------------------------------------------------------
+
+func targetString(p TargetPath) string {
+	switch p.Type {
+	case TargetMetadata:
+		if len(p.Path) == 0 {
+			return "meta"
+		}
+		return "@" + strings.Join(p.Path, ".")
+	case TargetVariable:
+		return "$" + strings.Join(p.Path, ".")
+	}
+	if len(p.Path) == 0 {
+		return "root"
+	}
+	return "root." + strings.Join(p.Path, ".")
+}
+
+func targetValue(p TargetPath, ctx AssignmentContext) any {
+	switch p.Type {
+	case TargetMetadata:
+		if ctx.Meta == nil {
+			return value.Nothing(nil)
+		}
+		var res any = value.Nothing(nil)
+		if len(p.Path) == 0 {
+			metaObj := map[string]any{}
+			_ = ctx.Meta.MetaIterMut(func(k string, v any) error {
+				metaObj[k] = value.IClone(v)
+				return nil
+			})
+			return metaObj
+		}
+		_ = ctx.Meta.MetaIterMut(func(k string, v any) error {
+			if k == p.Path[0] {
+				res = value.IClone(v)
+			}
+			return nil
+		})
+		return res
+	case TargetVariable:
+		if v, exists := ctx.Vars[strings.Join(p.Path, ".")]; exists {
+			return value.IClone(v)
+		}
+		return value.Nothing(nil)
+	}
+	if ctx.Value == nil {
+		return value.Nothing(nil)
+	}
+	if len(p.Path) == 0 {
+		return value.IClone(*ctx.Value)
+	}
+	if v := gabs.Wrap(*ctx.Value).S(p.Path...); v != nil {
+		return value.IClone(v.Data())
+	}
+	return value.Nothing(nil)
+}
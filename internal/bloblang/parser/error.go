@@ -171,6 +171,18 @@ func (e *Error) ErrorAtPosition(input []rune) string {
 	return fmt.Sprintf("line %v char %v: %v", line, char, errStr)
 }
 
+// ErrorMessage returns a human readable error string without any positional
+// information, which is useful when the position is conveyed separately.
+func (e *Error) ErrorMessage() string {
+	if importErr, isImport := e.Err.(*ImportError); isImport {
+		return fmt.Sprintf(
+			"failed to parse import '%v': %v", importErr.filepath,
+			importErr.perr.ErrorAtPosition(importErr.content),
+		)
+	}
+	return e.errorMsg(false)
+}
+
 // ErrorAtChar returns a human readable error string including the character
 // position of the error.
 func (e *Error) ErrorAtChar(input []rune) string {
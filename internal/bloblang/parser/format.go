@@ -0,0 +1,491 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package parser
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// FormatMapping parses a bloblang mapping and, if successful, returns the
+// mapping re-emitted in a canonical layout. Comments are preserved, and only
+// whitespace, the placement of brackets, and the quoting of path segments
+// that don't require quotes are modified.
+//
+// When the mapping fails to parse the error will be of type *Error.
+func FormatMapping(pCtx Context, expr string) (string, error) {
+	if _, err := ParseMapping(pCtx, expr); err != nil {
+		return "", err
+	}
+
+	tokens := fmtLex(expr)
+	formatted := fmtEmit(fmtLayout(tokens))
+
+	// The formatter only operates on whitespace and therefore these checks
+	// should never fail, but a formatter silently changing the meaning of a
+	// mapping would be far worse than refusing to format it.
+	if !fmtSameTokens(tokens, fmtLex(formatted)) {
+		return "", errors.New("formatting altered the contents of the mapping")
+	}
+	if _, err := ParseMapping(pCtx, formatted); err != nil {
+		return "", errors.New("formatting produced an invalid mapping: " + err.ErrorMessage())
+	}
+	return formatted, nil
+}
+
+//------------------------------------------------------------------------------
+
+type fmtTokenKind int
+
+const (
+	fmtTokenWord fmtTokenKind = iota
+	fmtTokenNumber
+	fmtTokenString
+	fmtTokenComment
+	fmtTokenNewline
+	fmtTokenOp
+)
+
+type fmtToken struct {
+	kind fmtTokenKind
+	text string
+
+	// Set for opening curly brackets that begin a block (if, match, map, etc)
+	// rather than an object literal.
+	block bool
+}
+
+func (t fmtToken) is(text string) bool {
+	return t.kind == fmtTokenOp && t.text == text
+}
+
+func (t fmtToken) opens() bool {
+	return t.is("(") || t.is("[") || t.is("{")
+}
+
+func (t fmtToken) closes() bool {
+	return t.is(")") || t.is("]") || t.is("}")
+}
+
+var fmtKeywords = map[string]struct{}{
+	"if": {}, "else": {}, "match": {}, "let": {}, "map": {}, "import": {},
+}
+
+var fmtPathSegmentRegexp = regexp.MustCompile(`^"[a-zA-Z_][a-zA-Z0-9_]*"$`)
+
+var fmtOperators = []string{"==", "!=", "<=", ">=", "&&", "||", "->", "=>"}
+
+func fmtIsWordChar(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// fmtLex breaks a mapping down into tokens, where the only information lost is
+// whitespace other than newlines.
+func fmtLex(expr string) []fmtToken {
+	in := []rune(expr)
+
+	var tokens []fmtToken
+	push := func(kind fmtTokenKind, text string) {
+		tokens = append(tokens, fmtToken{kind: kind, text: text})
+	}
+
+	scanString := func(i int) int {
+		if strings.HasPrefix(string(in[i:min(i+3, len(in))]), `"""`) {
+			if end := strings.Index(string(in[i+3:]), `"""`); end >= 0 {
+				return i + 3 + len([]rune(string(in[i+3:])[:end])) + 3
+			}
+			return len(in)
+		}
+		escaped := false
+		for j := i + 1; j < len(in); j++ {
+			switch {
+			case in[j] == '"' && !escaped:
+				return j + 1
+			case in[j] == '\\':
+				escaped = !escaped
+			default:
+				escaped = false
+			}
+		}
+		return len(in)
+	}
+
+	for i := 0; i < len(in); {
+		r := in[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\r':
+			i++
+		case r == '\n':
+			push(fmtTokenNewline, "\n")
+			i++
+		case r == '#':
+			j := i
+			for j < len(in) && in[j] != '\n' {
+				j++
+			}
+			push(fmtTokenComment, strings.TrimRight(string(in[i:j]), " \t\r"))
+			i = j
+		case r == '"':
+			j := scanString(i)
+			push(fmtTokenString, string(in[i:j]))
+			i = j
+		case r >= '0' && r <= '9':
+			j := i
+			for j < len(in) && in[j] >= '0' && in[j] <= '9' {
+				j++
+			}
+			if j < len(in) && fmtIsWordChar(in[j]) {
+				// Path segments are allowed to begin with digits.
+				for j < len(in) && fmtIsWordChar(in[j]) {
+					j++
+				}
+				push(fmtTokenWord, string(in[i:j]))
+				i = j
+				break
+			}
+			if j+1 < len(in) && in[j] == '.' && in[j+1] >= '0' && in[j+1] <= '9' {
+				for j++; j < len(in) && in[j] >= '0' && in[j] <= '9'; j++ {
+				}
+			}
+			push(fmtTokenNumber, string(in[i:j]))
+			i = j
+		case fmtIsWordChar(r), r == '$' || r == '@':
+			j := i + 1
+			if r == '@' && j < len(in) && in[j] == '"' {
+				j = scanString(j)
+			} else {
+				for j < len(in) && fmtIsWordChar(in[j]) {
+					j++
+				}
+			}
+			if r == '@' && j == i+1 {
+				push(fmtTokenOp, "@")
+			} else {
+				push(fmtTokenWord, string(in[i:j]))
+			}
+			i = j
+		default:
+			op := string(r)
+			for _, o := range fmtOperators {
+				if strings.HasPrefix(string(in[i:min(i+2, len(in))]), o) {
+					op = o
+					break
+				}
+			}
+			push(fmtTokenOp, op)
+			i += len([]rune(op))
+		}
+	}
+
+	// Path segments are quoted only when necessary.
+	for i := 1; i < len(tokens); i++ {
+		if tokens[i].kind != fmtTokenString || !tokens[i-1].is(".") || !fmtPathSegmentRegexp.MatchString(tokens[i].text) {
+			continue
+		}
+		segment := tokens[i].text[1 : len(tokens[i].text)-1]
+		if _, isKeyword := fmtKeywords[segment]; !isKeyword {
+			tokens[i] = fmtToken{kind: fmtTokenWord, text: segment}
+		}
+	}
+	return tokens
+}
+
+// fmtSameTokens returns whether two token streams are equal when ignoring
+// newlines.
+func fmtSameTokens(a, b []fmtToken) bool {
+	filter := func(tokens []fmtToken) (res []fmtToken) {
+		for _, t := range tokens {
+			if t.kind != fmtTokenNewline {
+				t.block = false
+				res = append(res, t)
+			}
+		}
+		return
+	}
+	a, b = filter(a), filter(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func fmtIsKeyword(tokens []fmtToken, i int) bool {
+	if tokens[i].kind != fmtTokenWord {
+		return false
+	}
+	if i > 0 && tokens[i-1].is(".") {
+		return false
+	}
+	_, isKeyword := fmtKeywords[tokens[i].text]
+	return isKeyword
+}
+
+// fmtLineHasKeyword returns whether the line containing the token at index i
+// contains a keyword that begins a block.
+func fmtLineHasKeyword(tokens []fmtToken, i int) bool {
+	for ; i >= 0 && tokens[i].kind != fmtTokenNewline; i-- {
+		if fmtIsKeyword(tokens, i) && tokens[i].text != "let" && tokens[i].text != "import" {
+			return true
+		}
+	}
+	return false
+}
+
+// fmtPrevSignificant returns the index of the closest token before i that
+// isn't a newline or comment, or -1.
+func fmtPrevSignificant(tokens []fmtToken, i int) int {
+	for i--; i >= 0; i-- {
+		if k := tokens[i].kind; k != fmtTokenNewline && k != fmtTokenComment {
+			return i
+		}
+	}
+	return -1
+}
+
+// fmtLayout normalises the placement of newlines within a token stream:
+//
+// - Brackets that span multiple lines are opened at the end of a line and
+// closed at the beginning of a line.
+// - The opening bracket of a block and the else keyword following a block
+// are placed on the same line as the token preceding them.
+// - Blank lines are collapsed and removed at the beginning and end of the
+// mapping and brackets.
+func fmtLayout(tokens []fmtToken) []fmtToken {
+	// Classify curly brackets, where those following a value are blocks and
+	// those following operators (or nothing) are object literals.
+	for i, t := range tokens {
+		if !t.is("{") {
+			continue
+		}
+		p := fmtPrevSignificant(tokens, i)
+		if p < 0 {
+			continue
+		}
+		prev := tokens[p]
+		if !(prev.kind == fmtTokenWord || prev.kind == fmtTokenString ||
+			prev.kind == fmtTokenNumber || prev.is(")") || prev.is("]")) {
+			continue
+		}
+
+		// A block opened on a new line must follow a line that begins one,
+		// otherwise it's an object literal beginning a new statement or case.
+		sameLine := true
+		for _, between := range tokens[p+1 : i] {
+			if between.kind == fmtTokenNewline {
+				sameLine = false
+			}
+		}
+		tokens[i].block = sameLine || fmtLineHasKeyword(tokens, p)
+	}
+
+	// Find the matching pairs of brackets that span multiple lines.
+	openMultiline := map[int]bool{}
+	closeMultiline := map[int]bool{}
+	var stack []int
+	for i, t := range tokens {
+		switch {
+		case t.is("{") || t.is("["):
+			stack = append(stack, i)
+		case t.is("(") || t.is(")"):
+		case t.is("}") || t.is("]"):
+			if len(stack) == 0 {
+				continue
+			}
+			open := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, inner := range tokens[open+1 : i] {
+				if inner.kind == fmtTokenNewline {
+					openMultiline[open], closeMultiline[i] = true, true
+					break
+				}
+			}
+		}
+	}
+
+	newline := fmtToken{kind: fmtTokenNewline, text: "\n"}
+
+	var res []fmtToken
+	trimNewlines := func() {
+		for len(res) > 0 && res[len(res)-1].kind == fmtTokenNewline {
+			res = res[:len(res)-1]
+		}
+	}
+	for i, t := range tokens {
+		switch {
+		case t.kind == fmtTokenNewline:
+			if len(res) == 0 {
+				continue
+			}
+			last := res[len(res)-1]
+			if last.opens() {
+				break
+			}
+			if last.kind == fmtTokenNewline && len(res) > 1 && res[len(res)-2].kind == fmtTokenNewline {
+				continue
+			}
+		case t.block || (t.kind == fmtTokenWord && t.text == "else" && fmtIsKeyword(tokens, i)):
+			// Join with the previous line only when there are no comments in
+			// between.
+			if p := fmtPrevSignificant(tokens, i); p >= 0 && (t.block || tokens[p].is("}")) {
+				joinable := true
+				for _, between := range tokens[p+1 : i] {
+					if between.kind == fmtTokenComment {
+						joinable = false
+					}
+				}
+				if joinable {
+					trimNewlines()
+				}
+			}
+		case t.closes():
+			if closeMultiline[i] {
+				trimNewlines()
+				res = append(res, newline)
+			}
+		}
+		res = append(res, t)
+
+		if openMultiline[i] {
+			next := i + 1
+			if next < len(tokens) && tokens[next].kind == fmtTokenComment {
+				continue
+			}
+			if next < len(tokens) && tokens[next].kind != fmtTokenNewline {
+				res = append(res, newline)
+			}
+		}
+	}
+
+	// Remove blank lines following opening brackets.
+	var cleaned []fmtToken
+	for _, t := range res {
+		if t.kind == fmtTokenNewline {
+			n := len(cleaned)
+			if n > 1 && cleaned[n-1].kind == fmtTokenNewline && cleaned[n-2].opens() {
+				continue
+			}
+		}
+		cleaned = append(cleaned, t)
+	}
+	res = cleaned
+
+	trimNewlines()
+	return res
+}
+
+// fmtEmit writes a token stream as text, with each line indented according to
+// the brackets that are open at the beginning of it, and with canonical
+// spacing between the tokens of each line.
+func fmtEmit(tokens []fmtToken) string {
+	type openBracket struct {
+		indent int
+		block  bool
+	}
+
+	var buf strings.Builder
+	var stack []openBracket
+
+	lineStart, lineIndent := true, 0
+	prev := -1 // Index of the previous token on the current line
+	unaryPrev := false
+
+	// Index of the last token that wasn't a comment, used for detecting lines
+	// that continue an expression from the previous line.
+	lastSignificant := -1
+
+	// Continuation lines are indented once relative to the line beginning the
+	// expression, unless that line opened a bracket, in which case they're
+	// already indented by it.
+	lineDepth, lineIsCont, contIndent := 0, false, 0
+
+	for i, t := range tokens {
+		if t.kind == fmtTokenNewline {
+			if prev >= 0 && !lineIsCont {
+				contIndent = 1
+				if len(stack) > lineDepth {
+					contIndent = 0
+				}
+			}
+			buf.WriteByte('\n')
+			lineStart, prev, unaryPrev = true, -1, false
+			continue
+		}
+
+		if lineStart {
+			lineIndent = 0
+			if len(stack) > 0 {
+				lineIndent = stack[len(stack)-1].indent
+				if !t.closes() {
+					lineIndent++
+				}
+			}
+			lineIsCont = false
+			if lastSignificant >= 0 {
+				last := tokens[lastSignificant]
+				lineIsCont = last.kind == fmtTokenOp && !last.opens() && !last.closes() && !last.is(",")
+			}
+			if lineIsCont && !t.closes() {
+				lineIndent += contIndent
+			}
+			buf.WriteString(strings.Repeat("  ", lineIndent))
+			lineDepth = len(stack)
+		} else if fmtNeedsSpace(tokens, prev, i, unaryPrev, len(stack) > 0 && stack[len(stack)-1].block) {
+			buf.WriteByte(' ')
+		}
+
+		unary := false
+		if t.is("-") || t.is("!") {
+			p := prev
+			unary = p < 0 || t.is("!") ||
+				(tokens[p].kind == fmtTokenOp && !tokens[p].closes()) ||
+				fmtIsKeyword(tokens, p)
+		}
+
+		switch {
+		case t.opens():
+			stack = append(stack, openBracket{indent: lineIndent, block: t.block})
+		case t.closes():
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+
+		buf.WriteString(t.text)
+		lineStart, prev, unaryPrev = false, i, unary
+		if t.kind != fmtTokenComment {
+			lastSignificant = i
+		}
+	}
+
+	if buf.Len() == 0 {
+		return ""
+	}
+	return buf.String() + "\n"
+}
+
+// fmtNeedsSpace returns whether a space should separate the token at index i
+// from the token at index prev on the same line.
+func fmtNeedsSpace(tokens []fmtToken, prev, i int, prevUnary, innermostBlock bool) bool {
+	p, t := tokens[prev], tokens[i]
+	switch {
+	case t.kind == fmtTokenComment:
+		return true
+	case p.is("{"):
+		return p.block && !t.is("}")
+	case t.is("}"):
+		return innermostBlock
+	case t.is(",") || t.is(")") || t.is("]") || t.is(".") || t.is(":"):
+		return false
+	case p.is("(") || p.is("[") || p.is(".") || prevUnary:
+		return false
+	case t.is("(") || t.is("["):
+		return !((p.kind == fmtTokenWord && !fmtIsKeyword(tokens, prev)) || p.is(")") || p.is("]"))
+	}
+	return true
+}
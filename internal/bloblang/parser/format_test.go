@@ -0,0 +1,157 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatMapping(t *testing.T) {
+	tests := map[string]struct {
+		input  string
+		output string
+	}{
+		"spacing": {
+			input: `root.foo = this.bar+5*-this.baz
+root.bar   =  this.things.map_each(t  ->   t.uppercase( )).join(",")
+root.baz = !this.a&&this.b!=-1.5
+root.buz = [ 1,2,3 ].sum( ) | { "a" : 1 , "b":{"c":[]} }
+root.named = this.foo.replace_all(old:"a",new:"b")
+`,
+			output: `root.foo = this.bar + 5 * -this.baz
+root.bar = this.things.map_each(t -> t.uppercase()).join(",")
+root.baz = !this.a && this.b != -1.5
+root.buz = [1, 2, 3].sum() | {"a": 1, "b": {"c": []}}
+root.named = this.foo.replace_all(old: "a", new: "b")
+`,
+		},
+		"comments": {
+			input: `   # A leading comment
+root.foo = this.bar    # Trailing comment
+#root.bar = "commented out"
+
+
+root.baz = this.baz`,
+			output: `# A leading comment
+root.foo = this.bar # Trailing comment
+#root.bar = "commented out"
+
+root.baz = this.baz
+`,
+		},
+		"quoted path segments": {
+			input: `root."foo"."bar baz" = this."a_b".("c")
+root."if" = @"meta key"
+`,
+			output: `root.foo."bar baz" = this.a_b.("c")
+root."if" = @"meta key"
+`,
+		},
+		"if statements": {
+			input: `if this.a {
+root.a = 1 } else if this.b
+{
+    root.b = 2
+}
+else { root.c = 3 }
+root.d = if this.d {"yes"}else{"no"}
+`,
+			output: `if this.a {
+  root.a = 1
+} else if this.b {
+  root.b = 2
+} else { root.c = 3 }
+root.d = if this.d { "yes" } else { "no" }
+`,
+		},
+		"match expressions": {
+			input: `root = match this.type {
+  "a"=>{"kind":"first"}
+    "b" => this.b.(b -> {
+  "kind": b.kind,
+      "items": [
+      1,
+          2
+      ]})
+  _=>deleted() }
+root.short = match { this.a > 10 => "big", _ => "small" }
+`,
+			output: `root = match this.type {
+  "a" => {"kind": "first"}
+  "b" => this.b.(b -> {
+    "kind": b.kind,
+    "items": [
+      1,
+      2
+    ]
+  })
+  _ => deleted()
+}
+root.short = match { this.a > 10 => "big", _ => "small" }
+`,
+		},
+		"maps and continuations": {
+			input: `map thing {
+	root.id = this.id
+	root.names = this.names.
+	filter(n -> n != "").
+	sort()
+}
+
+root = this.apply("thing")
+`,
+			output: `map thing {
+  root.id = this.id
+  root.names = this.names.
+    filter(n -> n != "").
+    sort()
+}
+
+root = this.apply("thing")
+`,
+		},
+		"triple quoted strings": {
+			input: `root.a = """
+  keep
+    this
+"""
+root.b = "foo # not a comment"
+`,
+			output: `root.a = """
+  keep
+    this
+"""
+root.b = "foo # not a comment"
+`,
+		},
+		"single expression": {
+			input:  `this.foo.uppercase( )`,
+			output: "this.foo.uppercase()\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := FormatMapping(GlobalContext(), test.input)
+			require.NoError(t, err)
+			assert.Equal(t, test.output, res)
+
+			// Formatting is idempotent.
+			again, err := FormatMapping(GlobalContext(), res)
+			require.NoError(t, err)
+			assert.Equal(t, res, again)
+		})
+	}
+}
+
+func TestFormatMappingErrors(t *testing.T) {
+	_, err := FormatMapping(GlobalContext(), "root.foo = this.bar.(\n")
+	require.Error(t, err)
+
+	var pErr *Error
+	require.ErrorAs(t, err, &pErr)
+	assert.Contains(t, pErr.ErrorAtPosition([]rune("root.foo = this.bar.(\n")), "line 2")
+}
@@ -11,7 +11,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/mapping"
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/query"
 	"github.com/redpanda-data/benthos/v4/internal/message"
+	"github.com/redpanda-data/benthos/v4/internal/value"
 )
 
 func TestMappingErrors(t *testing.T) {
@@ -589,6 +592,90 @@ from "%v"`, directMapFile),
 	}
 }
 
+func TestMappingTrace(t *testing.T) {
+	exec, perr := ParseMapping(GlobalContext(), `map upper {
+  root = if this.type == "a" { "A" } else { "B" }
+}
+let kind = match this.kind {
+  "x" => "first"
+  _ => "other"
+}
+root.kind = $kind
+if this.n > 10 {
+  root.size = "big"
+} else if this.n > 5 {
+  root.size = "medium"
+}
+root.up = this.apply("upper")
+meta foo = deleted()
+root.fails = this.n.uppercase()`)
+	require.Nil(t, perr)
+
+	msg := message.QuickBatch([][]byte{[]byte(`{"kind":"y","n":7,"type":"a"}`)})
+	part := msg.Get(0)
+	part.MetaSetMut("foo", "bar")
+
+	var result any = value.Nothing(nil)
+	vars := map[string]any{}
+	trace, err := exec.ExecOntoTraced(query.FunctionContext{
+		Maps:     exec.Maps(),
+		Vars:     vars,
+		MsgBatch: msg,
+		NewMeta:  part,
+		NewValue: &result,
+	}.WithValueFunc(func() *any {
+		v, _ := part.AsStructured()
+		return &v
+	}), mapping.AssignmentContext{
+		Vars:  vars,
+		Meta:  part,
+		Value: &result,
+	})
+	require.Error(t, err)
+	require.Len(t, trace.Statements, 7)
+
+	stmts := trace.Statements
+
+	assert.Equal(t, 4, stmts[0].Line)
+	assert.Equal(t, "let kind = match this.kind {", stmts[0].Statement)
+	assert.Equal(t, "$kind", stmts[0].Target)
+	assert.Equal(t, value.Nothing(nil), stmts[0].Input)
+	assert.Equal(t, "other", stmts[0].Result)
+	assert.Equal(t, []mapping.BranchTrace{{Expression: "match expression", Branch: "case 2"}}, stmts[0].Branches)
+	assert.Equal(t, map[string]any{"kind": "other"}, stmts[0].Vars)
+
+	assert.Equal(t, 8, stmts[1].Line)
+	assert.Equal(t, "root.kind", stmts[1].Target)
+	assert.Equal(t, "other", stmts[1].Result)
+	assert.Empty(t, stmts[1].Branches)
+
+	assert.Equal(t, 9, stmts[2].Line)
+	assert.Equal(t, "if this.n > 10 {", stmts[2].Statement)
+	assert.Empty(t, stmts[2].Target)
+	assert.Equal(t, []mapping.BranchTrace{{Expression: "if statement", Branch: "else if 1"}}, stmts[2].Branches)
+
+	assert.Equal(t, 12, stmts[3].Line)
+	assert.Equal(t, "root.size", stmts[3].Target)
+	assert.Equal(t, "medium", stmts[3].Result)
+
+	// Statements and conditionals of applied maps are not traced.
+	assert.Equal(t, 14, stmts[4].Line)
+	assert.Equal(t, "root.up", stmts[4].Target)
+	assert.Equal(t, "A", stmts[4].Result)
+	assert.Empty(t, stmts[4].Branches)
+
+	assert.Equal(t, 15, stmts[5].Line)
+	assert.Equal(t, "@foo", stmts[5].Target)
+	assert.Equal(t, "bar", stmts[5].Input)
+	assert.Equal(t, value.Delete(nil), stmts[5].Result)
+
+	assert.Equal(t, 16, stmts[6].Line)
+	assert.Equal(t, "root.fails", stmts[6].Target)
+	assert.Equal(t, value.Nothing(nil), stmts[6].Result)
+	assert.Error(t, stmts[6].Err)
+
+}
+
 func BenchmarkMappingParser(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_, err := ParseMapping(GlobalContext(), `
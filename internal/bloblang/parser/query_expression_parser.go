@@ -6,7 +6,6 @@ import (
 	"fmt"
 
 	"github.com/redpanda-data/benthos/v4/internal/bloblang/query"
-	"github.com/redpanda-data/benthos/v4/internal/value"
 )
 
 func matchCaseParser(pCtx Context) Func[query.MatchCase] {
@@ -41,26 +40,17 @@ func matchCaseParser(pCtx Context) Func[query.MatchCase] {
 			return Fail[query.MatchCase](res.Err, input)
 		}
 
-		var caseFn query.Function
+		var matchCase query.MatchCase
 
 		if p := res.Payload[0]; p == nil {
-			caseFn = query.NewLiteralFunction("", true)
+			matchCase = query.NewMatchCase(query.NewLiteralFunction("", true), res.Payload[2])
 		} else if lit, isLiteral := p.(*query.Literal); isLiteral {
-			caseFn = query.ClosureFunction("case statement", func(ctx query.FunctionContext) (any, error) {
-				v := ctx.Value()
-				if v == nil {
-					return false, nil
-				}
-				return value.ICompare(*v, lit.Value), nil
-			}, nil)
+			matchCase = query.NewValueMatchCase(lit, res.Payload[2])
 		} else {
-			caseFn = p
+			matchCase = query.NewMatchCase(p, res.Payload[2])
 		}
 
-		return Success(
-			query.NewMatchCase(caseFn, res.Payload[2]),
-			res.Remaining,
-		)
+		return Success(matchCase, res.Remaining)
 	}
 }
 
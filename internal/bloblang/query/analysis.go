@@ -0,0 +1,463 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/redpanda-data/benthos/v4/internal/value"
+)
+
+// Analysis walks the tree of a parsed query function without executing it in
+// order to infer the types of values that it produces, and collects any
+// problems that would certainly cause the query to fail or behave unexpectedly
+// at runtime.
+//
+// Analysis is only able to inspect queries parsed with a deactivated
+// environment, as otherwise the arguments of most functions and methods are
+// hidden within their implementations.
+type Analysis struct {
+	// Vars contains the types of variables known to be declared before the
+	// analysed query is executed.
+	Vars map[string]value.Type
+
+	// UsedVars contains the name of each variable referenced by an analysed
+	// query.
+	UsedVars map[string]struct{}
+
+	// Issues contains a description of each problem found.
+	Issues []string
+
+	this  *Schema
+	named map[string]*Schema
+}
+
+// NewAnalysis creates a new static analysis where the context of queries is
+// described by an optional schema.
+func NewAnalysis(this *Schema) *Analysis {
+	return &Analysis{
+		Vars:     map[string]value.Type{},
+		UsedVars: map[string]struct{}{},
+		this:     this,
+		named:    map[string]*Schema{},
+	}
+}
+
+func (a *Analysis) issuef(format string, args ...any) {
+	a.Issues = append(a.Issues, fmt.Sprintf(format, args...))
+}
+
+// withContext runs a function with the context of queries described by a
+// different schema, where nil is an unknown context.
+func (a *Analysis) withContext(this *Schema, fn func() value.Type) value.Type {
+	prev := a.this
+	a.this = this
+	defer func() {
+		a.this = prev
+	}()
+	return fn()
+}
+
+// Infer walks a query function, recording any issues found, and returns the
+// type of value that it produces, or value.TUnknown if it cannot be
+// determined.
+func (a *Analysis) Infer(fn Function) value.Type {
+	switch t := fn.(type) {
+	case *Literal:
+		return value.ITypeOf(t.Value)
+	case *mapLiteral:
+		for _, kv := range t.keyValues {
+			if keyFn, ok := kv[0].(Function); ok {
+				if k := a.Infer(keyFn); k != value.TUnknown && k != value.TString {
+					a.issuef("object keys must be strings, but %v resolves to %v", keyFn.Annotation(), withArticle(k))
+				}
+			}
+			if valueFn, ok := kv[1].(Function); ok {
+				a.Infer(valueFn)
+			}
+		}
+		return value.TObject
+	case *arrayLiteral:
+		for _, v := range t.values {
+			if valueFn, ok := v.(Function); ok {
+				a.Infer(valueFn)
+			}
+		}
+		return value.TArray
+	case *fieldFunction:
+		return schemaType(a.fieldSchema(t, true))
+	case *getMethod:
+		a.Infer(t.fn)
+		return value.TUnknown
+	case *fromMethod:
+		return a.Infer(t.target)
+	case *varFunction:
+		return a.inferVar(t.name)
+	case *notMethod:
+		if k := a.Infer(t.fn); k != value.TUnknown && k != value.TBool {
+			a.issuef("cannot negate %v as it resolves to %v rather than a bool", t.fn.Annotation(), withArticle(k))
+		}
+		return value.TBool
+	case *operatorFunction:
+		return a.inferOperator(t)
+	case *matchFunction:
+		return a.inferMatch(t)
+	case *ifFunction:
+		return a.inferIf(t)
+	case *NamedContextFunction:
+		return a.inferNamedContext(t, nil)
+	case *mapMethodFunction:
+		a.Infer(t.target)
+		targetSchema := a.schemaOf(t.target)
+		if named, ok := t.mapFn.(*NamedContextFunction); ok {
+			return a.inferNamedContext(named, targetSchema)
+		}
+		return a.withContext(targetSchema, func() value.Type {
+			return a.Infer(t.mapFn)
+		})
+	case *disabledMethod:
+		return a.inferMethod(t)
+	case *disabledFunction:
+		return a.inferFunction(t)
+	}
+	return value.TUnknown
+}
+
+func schemaType(s *Schema) value.Type {
+	if s == nil {
+		return value.TUnknown
+	}
+	return s.Type
+}
+
+func (a *Analysis) fieldSchema(f *fieldFunction, report bool) *Schema {
+	if f.fromRoot {
+		return nil
+	}
+	base := a.this
+	if f.namedContext != "" {
+		base = a.named[f.namedContext]
+	}
+	if base == nil {
+		return nil
+	}
+	s, err := base.lookup(f.path)
+	if err != nil && report {
+		a.issuef("%v %v", f.Annotation(), err)
+	}
+	return s
+}
+
+// schemaOf returns the schema of the value produced by a query function when
+// it is known.
+func (a *Analysis) schemaOf(fn Function) *Schema {
+	switch t := fn.(type) {
+	case *fieldFunction:
+		return a.fieldSchema(t, false)
+	case *getMethod:
+		if base := a.schemaOf(t.fn); base != nil {
+			s, _ := base.lookup(t.path)
+			return s
+		}
+	}
+	return nil
+}
+
+func (a *Analysis) inferVar(name string) value.Type {
+	a.UsedVars[name] = struct{}{}
+	if k, exists := a.Vars[name]; exists {
+		return k
+	}
+	return value.TUnknown
+}
+
+func (a *Analysis) inferNamedContext(n *NamedContextFunction, captured *Schema) value.Type {
+	if n.name == "_" {
+		return a.withContext(nil, func() value.Type {
+			return a.Infer(n.fn)
+		})
+	}
+	prev, existed := a.named[n.name]
+	a.named[n.name] = captured
+	defer func() {
+		if existed {
+			a.named[n.name] = prev
+		} else {
+			delete(a.named, n.name)
+		}
+	}()
+	return a.withContext(nil, func() value.Type {
+		return a.Infer(n.fn)
+	})
+}
+
+//------------------------------------------------------------------------------
+
+func isNumericType(t value.Type) bool {
+	return t == value.TNumber || t == value.TInt || t == value.TFloat
+}
+
+func isStringType(t value.Type) bool {
+	return t == value.TString || t == value.TBytes
+}
+
+// commonType returns the type shared by two values, or value.TUnknown if they
+// differ.
+func commonType(l, r value.Type) value.Type {
+	if isNumericType(l) && isNumericType(r) {
+		return value.TNumber
+	}
+	if l == r {
+		return l
+	}
+	return value.TUnknown
+}
+
+func (a *Analysis) inferOperator(o *operatorFunction) value.Type {
+	l, r := a.Infer(o.lhs), a.Infer(o.rhs)
+	switch o.op {
+	case ArithmeticEq, ArithmeticNeq:
+		return value.TBool
+	case ArithmeticGt, ArithmeticLt, ArithmeticGte, ArithmeticLte:
+		for _, operand := range []struct {
+			fn Function
+			t  value.Type
+		}{{o.lhs, l}, {o.rhs, r}} {
+			switch operand.t {
+			case value.TBool, value.TArray, value.TObject, value.TNull:
+				a.issuef("cannot compare %v as it resolves to %v", operand.fn.Annotation(), withArticle(operand.t))
+			}
+		}
+		return value.TBool
+	case ArithmeticAnd, ArithmeticOr:
+		for _, operand := range []struct {
+			fn Function
+			t  value.Type
+		}{{o.lhs, l}, {o.rhs, r}} {
+			if operand.t != value.TUnknown && operand.t != value.TBool && !isNumericType(operand.t) {
+				a.issuef("cannot %v %v as it resolves to %v rather than a bool", o.op, operand.fn.Annotation(), withArticle(operand.t))
+			}
+		}
+		return value.TBool
+	case ArithmeticPipe:
+		return commonType(l, r)
+	case ArithmeticAdd:
+		for _, operand := range []struct {
+			fn Function
+			t  value.Type
+		}{{o.lhs, l}, {o.rhs, r}} {
+			if operand.t != value.TUnknown && !isNumericType(operand.t) && !isStringType(operand.t) {
+				a.issuef("cannot add %v as it resolves to %v", operand.fn.Annotation(), withArticle(operand.t))
+				return value.TUnknown
+			}
+		}
+		switch {
+		case isNumericType(l) && isNumericType(r):
+			return value.TNumber
+		case isStringType(l) && isStringType(r):
+			return value.TString
+		case l != value.TUnknown && r != value.TUnknown:
+			a.issuef("cannot add %v (%v) and %v (%v)", o.lhs.Annotation(), l, o.rhs.Annotation(), r)
+		}
+		return value.TUnknown
+	}
+	for _, operand := range []struct {
+		fn Function
+		t  value.Type
+	}{{o.lhs, l}, {o.rhs, r}} {
+		if operand.t != value.TUnknown && !isNumericType(operand.t) {
+			a.issuef("cannot %v %v as it resolves to %v rather than a number", o.op, operand.fn.Annotation(), withArticle(operand.t))
+		}
+	}
+	return value.TNumber
+}
+
+//------------------------------------------------------------------------------
+
+func (a *Analysis) inferMatch(m *matchFunction) value.Type {
+	caseContext := a.this
+	if m.contextFn != nil {
+		a.Infer(m.contextFn)
+		caseContext = a.schemaOf(m.contextFn)
+	}
+	return a.withContext(caseContext, func() value.Type {
+		var result value.Type
+		catchAll := -1
+		for i, c := range m.cases {
+			switch {
+			case catchAll >= 0:
+				a.issuef("match case %v is unreachable as case %v always matches", i+1, catchAll+1)
+			case c.value != nil:
+				for j, prev := range m.cases[:i] {
+					if prev.value != nil && value.ICompare(prev.value.Value, c.value.Value) {
+						a.issuef("match case %v is unreachable as case %v matches the same value", i+1, j+1)
+						break
+					}
+				}
+			default:
+				if lit, ok := c.caseFn.(*Literal); ok {
+					if b, _ := lit.Value.(bool); b {
+						catchAll = i
+					} else {
+						a.issuef("match case %v is unreachable as it never matches", i+1)
+					}
+				} else if k := a.Infer(c.caseFn); k != value.TUnknown && k != value.TBool {
+					a.issuef("match case %v is unreachable as %v resolves to %v rather than a bool", i+1, c.caseFn.Annotation(), withArticle(k))
+				}
+			}
+
+			k := a.Infer(c.queryFn)
+			if i == 0 {
+				result = k
+			} else {
+				result = commonType(result, k)
+			}
+		}
+		if catchAll < 0 {
+			return value.TUnknown
+		}
+		return result
+	})
+}
+
+// CheckCondition walks a query function that is expected to resolve to a
+// boolean, and records an issue when it cannot. Conditions of if expressions
+// also accept null values, which are treated as false.
+func (a *Analysis) CheckCondition(fn Function, allowNull bool) {
+	k := a.Infer(fn)
+	if allowNull && k == value.TNull {
+		return
+	}
+	if k != value.TUnknown && k != value.TBool {
+		a.issuef("%v resolves to %v rather than a bool and cannot be used as a condition", fn.Annotation(), withArticle(k))
+	}
+}
+
+func (a *Analysis) inferIf(f *ifFunction) value.Type {
+	a.CheckCondition(f.queryFn, true)
+	result := a.Infer(f.ifFn)
+	for _, eif := range f.elseIfs {
+		a.CheckCondition(eif.QueryFn, true)
+		result = commonType(result, a.Infer(eif.MapFn))
+	}
+	if f.elseFn == nil {
+		return value.TUnknown
+	}
+	return commonType(result, a.Infer(f.elseFn))
+}
+
+//------------------------------------------------------------------------------
+
+// typeAccepts returns whether a value of a given type can be provided where a
+// value of an expected type is required, following the coercion rules of
+// parameters.
+func typeAccepts(expected, t value.Type) bool {
+	switch {
+	case t == value.TUnknown, expected == value.TUnknown, expected == t:
+		return true
+	case isNumericType(expected):
+		return isNumericType(t)
+	case expected == value.TString:
+		return t == value.TBytes
+	case expected == value.TBool:
+		return isNumericType(t)
+	case expected == value.TTimestamp:
+		return t == value.TString || isNumericType(t)
+	}
+	return false
+}
+
+// withArticle prefixes a type name with an indefinite article.
+func withArticle[T ~string](t T) string {
+	if strings.ContainsRune("aeiou", rune(t[0])) {
+		return "an " + string(t)
+	}
+	return "a " + string(t)
+}
+
+func typesString(types []value.Type) string {
+	strs := make([]string, len(types))
+	for i, t := range types {
+		strs[i] = string(t)
+	}
+	if len(strs) == 1 {
+		return strs[0]
+	}
+	return strings.Join(strs[:len(strs)-1], ", ") + " or " + strs[len(strs)-1]
+}
+
+// inferArgs walks the arguments of a function or method and returns the types
+// that the named arguments resolve to, omitting any that were not provided.
+func (a *Analysis) inferArgs(annotation string, args *ParsedParams) map[string]value.Type {
+	if args == nil {
+		return nil
+	}
+	types := map[string]value.Type{}
+	for i, v := range args.values {
+		var def ParamDefinition
+		if !args.source.Variadic && i < len(args.source.Definitions) {
+			def = args.source.Definitions[i]
+		}
+		fn, ok := v.(Function)
+		if !ok {
+			if def.Name != "" && v != nil {
+				types[def.Name] = value.ITypeOf(v)
+			}
+			continue
+		}
+		if def.ValueType == value.TQuery {
+			a.withContext(nil, func() value.Type {
+				return a.Infer(fn)
+			})
+			continue
+		}
+		k := a.Infer(fn)
+		if def.Name != "" {
+			types[def.Name] = k
+			if !typeAccepts(def.ValueType, k) {
+				a.issuef("parameter %v of %v expects %v value, but %v resolves to %v", def.Name, annotation, withArticle(def.ValueType), fn.Annotation(), withArticle(k))
+			}
+		}
+	}
+	return types
+}
+
+func (a *Analysis) inferMethod(m *disabledMethod) value.Type {
+	recv := a.Infer(m.target)
+	if len(m.spec.ReceiverTypes) > 0 && recv != value.TUnknown {
+		accepted := false
+		for _, t := range m.spec.ReceiverTypes {
+			if t == recv || (isNumericType(t) && isNumericType(recv)) {
+				accepted = true
+				break
+			}
+		}
+		if !accepted {
+			a.issuef("method %v expects %v value, but %v resolves to %v", m.spec.Name, withArticle(typesString(m.spec.ReceiverTypes)), m.target.Annotation(), withArticle(recv))
+		}
+	}
+	argTypes := a.inferArgs(m.Annotation(), m.args)
+	if m.spec.ReturnType == "" {
+		return value.TUnknown
+	}
+	// Methods with a default argument yield it in place of their result when
+	// they fail, and so the default contributes to the type returned.
+	if k, exists := argTypes["default"]; exists {
+		return commonType(m.spec.ReturnType, k)
+	}
+	return m.spec.ReturnType
+}
+
+func (a *Analysis) inferFunction(f *disabledFunction) value.Type {
+	if f.spec.Name == "var" && f.args != nil && len(f.args.values) > 0 {
+		if name, ok := f.args.values[0].(string); ok {
+			return a.inferVar(name)
+		}
+	}
+	a.inferArgs(f.Annotation(), f.args)
+	if f.spec.ReturnType == "" {
+		return value.TUnknown
+	}
+	return f.spec.ReturnType
+}
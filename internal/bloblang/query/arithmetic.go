@@ -60,7 +60,24 @@ func (o ArithmeticOperator) String() string {
 
 type arithmeticOpFunc[T any] func(lhs, rhs Function, l, r any) (T, error)
 
-func arithmeticFunc[T any](lhs, rhs Function, op arithmeticOpFunc[T]) (Function, error) {
+// operatorFunction combines the results of two functions with an operator.
+type operatorFunction struct {
+	closureFunction
+
+	op       ArithmeticOperator
+	lhs, rhs Function
+}
+
+func newOperatorFunction(op ArithmeticOperator, lhs, rhs Function, exec func(ctx FunctionContext) (any, error)) Function {
+	return &operatorFunction{
+		closureFunction: newClosureFunction(rhs.Annotation(), exec, aggregateTargetPaths(lhs, rhs)),
+		op:              op,
+		lhs:             lhs,
+		rhs:             rhs,
+	}
+}
+
+func arithmeticFunc[T any](lhs, rhs Function, operator ArithmeticOperator, op arithmeticOpFunc[T]) (Function, error) {
 	annotation := rhs.Annotation()
 
 	var litL, litR *Literal
@@ -75,7 +92,7 @@ func arithmeticFunc[T any](lhs, rhs Function, op arithmeticOpFunc[T]) (Function,
 		}
 	}
 
-	return ClosureFunction(annotation, func(ctx FunctionContext) (any, error) {
+	return newOperatorFunction(operator, lhs, rhs, func(ctx FunctionContext) (any, error) {
 		var err error
 		var leftV, rightV any
 		if leftV, err = lhs.Exec(ctx); err == nil {
@@ -85,7 +102,7 @@ func arithmeticFunc[T any](lhs, rhs Function, op arithmeticOpFunc[T]) (Function,
 			return nil, err
 		}
 		return op(lhs, rhs, leftV, rightV)
-	}, aggregateTargetPaths(lhs, rhs)), nil
+	}), nil
 }
 
 //------------------------------------------------------------------------------
@@ -394,7 +411,7 @@ func compareOp(op ArithmeticOperator) (arithmeticOpFunc[bool], bool) {
 }
 
 func boolOr(lhs, rhs Function) Function {
-	return ClosureFunction(rhs.Annotation(), func(ctx FunctionContext) (any, error) {
+	return newOperatorFunction(ArithmeticOr, lhs, rhs, func(ctx FunctionContext) (any, error) {
 		lhsV, err := lhs.Exec(ctx)
 		if err != nil {
 			return nil, err
@@ -414,11 +431,11 @@ func boolOr(lhs, rhs Function) Function {
 			return nil, err
 		}
 		return b, nil
-	}, aggregateTargetPaths(lhs, rhs))
+	})
 }
 
 func boolAnd(lhs, rhs Function) Function {
-	return ClosureFunction(rhs.Annotation(), func(ctx FunctionContext) (any, error) {
+	return newOperatorFunction(ArithmeticAnd, lhs, rhs, func(ctx FunctionContext) (any, error) {
 		lhsV, err := lhs.Exec(ctx)
 		if err != nil {
 			return nil, err
@@ -438,17 +455,17 @@ func boolAnd(lhs, rhs Function) Function {
 			return nil, err
 		}
 		return b, nil
-	}, aggregateTargetPaths(lhs, rhs))
+	})
 }
 
 func coalesce(lhs, rhs Function) Function {
-	return ClosureFunction(rhs.Annotation(), func(ctx FunctionContext) (any, error) {
+	return newOperatorFunction(ArithmeticPipe, lhs, rhs, func(ctx FunctionContext) (any, error) {
 		lhsV, err := lhs.Exec(ctx)
 		if err == nil && !value.IIsNull(lhsV) {
 			return lhsV, nil
 		}
 		return rhs.Exec(ctx)
-	}, aggregateTargetPaths(lhs, rhs))
+	})
 }
 
 // NewArithmeticExpression creates a single query function from a list of child
@@ -469,7 +486,7 @@ func NewArithmeticExpression(fns []Function, ops []ArithmeticOperator) (Function
 	for i, op := range ops {
 		leftFn, rightFn := fnsNew[len(fnsNew)-1], fns[i+1]
 		if opFunc, isProd := prodOp(op); isProd {
-			if fnsNew[len(fnsNew)-1], err = arithmeticFunc(leftFn, rightFn, opFunc); err != nil {
+			if fnsNew[len(fnsNew)-1], err = arithmeticFunc(leftFn, rightFn, op, opFunc); err != nil {
 				return nil, err
 			}
 		} else if op == ArithmeticPipe {
@@ -489,7 +506,7 @@ func NewArithmeticExpression(fns []Function, ops []ArithmeticOperator) (Function
 	for i, op := range ops {
 		leftFn, rightFn := fnsNew[len(fnsNew)-1], fns[i+1]
 		if opFunc, isSum := sumOp(op); isSum {
-			if fnsNew[len(fnsNew)-1], err = arithmeticFunc(leftFn, rightFn, opFunc); err != nil {
+			if fnsNew[len(fnsNew)-1], err = arithmeticFunc(leftFn, rightFn, op, opFunc); err != nil {
 				return nil, err
 			}
 		} else {
@@ -507,7 +524,7 @@ func NewArithmeticExpression(fns []Function, ops []ArithmeticOperator) (Function
 	for i, op := range ops {
 		leftFn, rightFn := fnsNew[len(fnsNew)-1], fns[i+1]
 		if opFunc, isCompare := compareOp(op); isCompare {
-			if fnsNew[len(fnsNew)-1], err = arithmeticFunc(leftFn, rightFn, opFunc); err != nil {
+			if fnsNew[len(fnsNew)-1], err = arithmeticFunc(leftFn, rightFn, op, opFunc); err != nil {
 				return nil, err
 			}
 		} else {
@@ -2,6 +2,10 @@
 
 package query
 
+import (
+	"github.com/redpanda-data/benthos/v4/internal/value"
+)
+
 // ExampleSpec provides a mapping example and some input/output results to
 // display.
 type ExampleSpec struct {
@@ -94,6 +98,10 @@ type FunctionSpec struct {
 
 	// Version is the Benthos version this component was introduced.
 	Version string `json:"version,omitempty"`
+
+	// ReturnType optionally describes the type of value returned by the
+	// function, which is used by static analysis of mappings.
+	ReturnType value.Type `json:"return_type,omitempty"`
 }
 
 // NewFunctionSpec creates a new function spec.
@@ -139,6 +147,12 @@ func (s FunctionSpec) Param(def ParamDefinition) FunctionSpec {
 	return s
 }
 
+// Returns sets the type of value returned by the function.
+func (s FunctionSpec) Returns(t value.Type) FunctionSpec {
+	s.ReturnType = t
+	return s
+}
+
 // NewDeprecatedFunctionSpec creates a new function spec that is deprecated.
 func NewDeprecatedFunctionSpec(name, description string, examples ...ExampleSpec) FunctionSpec {
 	return FunctionSpec{
@@ -212,6 +226,14 @@ type MethodSpec struct {
 
 	// Version is the Benthos version this component was introduced.
 	Version string `json:"version,omitempty"`
+
+	// ReceiverTypes optionally lists the types of values that the method can
+	// be executed upon, which is used by static analysis of mappings.
+	ReceiverTypes []value.Type `json:"receiver_types,omitempty"`
+
+	// ReturnType optionally describes the type of value returned by the
+	// method, which is used by static analysis of mappings.
+	ReturnType value.Type `json:"return_type,omitempty"`
 }
 
 // NewMethodSpec creates a new method spec.
@@ -277,6 +299,18 @@ func (m MethodSpec) Param(def ParamDefinition) MethodSpec {
 	return m
 }
 
+// Receives sets the types of values that the method can be executed upon.
+func (m MethodSpec) Receives(types ...value.Type) MethodSpec {
+	m.ReceiverTypes = types
+	return m
+}
+
+// Returns sets the type of value returned by the method.
+func (m MethodSpec) Returns(t value.Type) MethodSpec {
+	m.ReturnType = t
+	return m
+}
+
 // VariadicParams configures the method spec to allow variadic parameters.
 func (m MethodSpec) VariadicParams() MethodSpec {
 	m.Params = VariadicParams()
@@ -4,6 +4,7 @@ package query
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/redpanda-data/benthos/v4/internal/value"
 )
@@ -13,6 +14,9 @@ import (
 type MatchCase struct {
 	caseFn  Function
 	queryFn Function
+
+	// Set when the case matches a literal value.
+	value *Literal
 }
 
 // NewMatchCase creates a single match case of a match expression, where a case
@@ -24,9 +28,35 @@ func NewMatchCase(caseFn, queryFn Function) MatchCase {
 	}
 }
 
+// NewValueMatchCase creates a single match case of a match expression, where
+// the case matches when the context of the expression is equal to a literal
+// value.
+func NewValueMatchCase(lit *Literal, queryFn Function) MatchCase {
+	return MatchCase{
+		caseFn: ClosureFunction("case statement", func(ctx FunctionContext) (any, error) {
+			v := ctx.Value()
+			if v == nil {
+				return false, nil
+			}
+			return value.ICompare(*v, lit.Value), nil
+		}, nil),
+		queryFn: queryFn,
+		value:   lit,
+	}
+}
+
+type matchFunction struct {
+	closureFunction
+
+	// Nil when the context of the expression is unchanged.
+	contextFn Function
+	cases     []MatchCase
+}
+
 // NewMatchFunction takes a contextual mapping and a list of MatchCases, when
 // the function is executed.
 func NewMatchFunction(contextFn Function, cases ...MatchCase) Function {
+	m := &matchFunction{contextFn: contextFn, cases: cases}
 	if contextFn == nil {
 		contextFn = ClosureFunction("this", func(ctx FunctionContext) (any, error) {
 			var value any
@@ -36,7 +66,7 @@ func NewMatchFunction(contextFn Function, cases ...MatchCase) Function {
 			return value, nil
 		}, nil)
 	}
-	return ClosureFunction("match expression", func(ctx FunctionContext) (any, error) {
+	m.closureFunction = newClosureFunction("match expression", func(ctx FunctionContext) (any, error) {
 		ctxVal, err := contextFn.Exec(ctx)
 		if err != nil {
 			return nil, err
@@ -48,9 +78,11 @@ func NewMatchFunction(contextFn Function, cases ...MatchCase) Function {
 				return nil, fmt.Errorf("failed to check match case %v: %w", i, err)
 			}
 			if matched, _ := caseVal.(bool); matched {
+				ctx.traceBranch("match expression", "case "+strconv.Itoa(i+1))
 				return c.queryFn.Exec(caseCtx)
 			}
 		}
+		ctx.traceBranch("match expression", "none")
 		return value.Nothing(nil), nil
 	}, func(ctx TargetsContext) (TargetsContext, []TargetPath) {
 		contextCtx, contextTargets := contextFn.QueryTargets(ctx)
@@ -69,6 +101,7 @@ func NewMatchFunction(contextFn Function, cases ...MatchCase) Function {
 		targets = append(targets, contextTargets...)
 		return ctx, targets
 	})
+	return m
 }
 
 // ElseIf represents an else-if block in an if expression.
@@ -88,7 +121,13 @@ func NewIfFunction(queryFn, ifFn Function, elseIfs []ElseIf, elseFn Function) Fu
 		allFns = append(allFns, eIf.QueryFn, eIf.MapFn)
 	}
 
-	return ClosureFunction("if expression", func(ctx FunctionContext) (any, error) {
+	f := &ifFunction{
+		queryFn: queryFn,
+		ifFn:    ifFn,
+		elseIfs: elseIfs,
+		elseFn:  elseFn,
+	}
+	f.closureFunction = newClosureFunction("if expression", func(ctx FunctionContext) (any, error) {
 		queryVal, err := queryFn.Exec(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to check if condition: %w", err)
@@ -108,6 +147,7 @@ func NewIfFunction(queryFn, ifFn Function, elseIfs []ElseIf, elseFn Function) Fu
 			}
 		}
 		if queryRes {
+			ctx.traceBranch("if expression", "if")
 			return ifFn.Exec(ctx)
 		}
 
@@ -126,15 +166,27 @@ func NewIfFunction(queryFn, ifFn Function, elseIfs []ElseIf, elseFn Function) Fu
 				}
 			}
 			if queryRes {
+				ctx.traceBranch("if expression", "else if "+strconv.Itoa(i+1))
 				return eFn.MapFn.Exec(ctx)
 			}
 		}
 
 		if elseFn != nil {
+			ctx.traceBranch("if expression", "else")
 			return elseFn.Exec(ctx)
 		}
+		ctx.traceBranch("if expression", "none")
 		return value.Nothing(nil), nil
 	}, aggregateTargetPaths(allFns...))
+	return f
+}
+
+type ifFunction struct {
+	closureFunction
+
+	queryFn, ifFn Function
+	elseIfs       []ElseIf
+	elseFn        Function
 }
 
 // NewNamedContextFunction wraps a function and ensures that when the function
@@ -37,6 +37,14 @@ func ClosureFunction(
 	exec func(ctx FunctionContext) (any, error),
 	queryTargets func(ctx TargetsContext) (TargetsContext, []TargetPath),
 ) Function {
+	return newClosureFunction(annotation, exec, queryTargets)
+}
+
+func newClosureFunction(
+	annotation string,
+	exec func(ctx FunctionContext) (any, error),
+	queryTargets func(ctx TargetsContext) (TargetsContext, []TargetPath),
+) closureFunction {
 	if queryTargets == nil {
 		queryTargets = func(ctx TargetsContext) (TargetsContext, []TargetPath) { return ctx, nil }
 	}
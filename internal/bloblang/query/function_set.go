@@ -76,7 +76,7 @@ func (f *FunctionSet) Init(name string, args *ParsedParams) (Function, error) {
 		return nil, badFunctionErr(name)
 	}
 	if f.disableCtors {
-		return disabledFunction(name), nil
+		return &disabledFunction{spec: details.spec, args: args}, nil
 	}
 	return wrapCtorWithDynamicArgs(name, args, details.ctor)
 }
@@ -180,10 +180,23 @@ func FunctionDocs() []FunctionSpec {
 
 //------------------------------------------------------------------------------
 
-func disabledFunction(name string) Function {
-	return ClosureFunction("function "+name, func(ctx FunctionContext) (any, error) {
-		return nil, errors.New("this function has been disabled")
-	}, func(ctx TargetsContext) (TargetsContext, []TargetPath) { return ctx, nil })
+// disabledFunction is a function of a deactivated set, which retains the spec
+// and arguments of the function in order to support static analysis.
+type disabledFunction struct {
+	spec FunctionSpec
+	args *ParsedParams
+}
+
+func (d *disabledFunction) Annotation() string {
+	return "function " + d.spec.Name
+}
+
+func (d *disabledFunction) Exec(ctx FunctionContext) (any, error) {
+	return nil, errors.New("this function has been disabled")
+}
+
+func (d *disabledFunction) QueryTargets(ctx TargetsContext) (TargetsContext, []TargetPath) {
+	return ctx, nil
 }
 
 func wrapCtorWithDynamicArgs(name string, args *ParsedParams, fn FunctionCtor) (Function, error) {
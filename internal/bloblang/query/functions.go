@@ -329,7 +329,7 @@ root.bar = deleted()`,
 			`{"nums":[3,11,4,17]}`,
 			`{"new_nums":[1,7]}`,
 		),
-	),
+	).Returns(value.TDelete),
 	func(*ParsedParams) (Function, error) {
 		return NewLiteralFunction("delete", value.Delete(nil)), nil
 	},
@@ -816,7 +816,7 @@ var _ = registerFunction(
 		NewExampleSpec("",
 			`root.received_at = now().ts_format("Mon Jan 2 15:04:05 -0700 MST 2006", "UTC")`,
 		),
-	),
+	).Returns(value.TString),
 	func(args *ParsedParams) (Function, error) {
 		return ClosureFunction("function now", func(_ FunctionContext) (any, error) {
 			return time.Now().Format(time.RFC3339Nano), nil
@@ -831,7 +831,7 @@ var _ = registerSimpleFunction(
 		NewExampleSpec("",
 			`root.received_at = timestamp_unix()`,
 		),
-	),
+	).Returns(value.TNumber),
 	func(_ FunctionContext) (any, error) {
 		return time.Now().Unix(), nil
 	},
@@ -913,7 +913,7 @@ var _ = registerSimpleFunction(
 		FunctionCategoryGeneral, "uuid_v4",
 		"Generates a new RFC-4122 UUID each time it is invoked and prints a string representation.",
 		NewExampleSpec("", `root.id = uuid_v4()`),
-	),
+	).Returns(value.TString),
 	func(_ FunctionContext) (any, error) {
 		u4, err := uuid.NewV4()
 		if err != nil {
@@ -1016,9 +1016,15 @@ var _ = registerFunction(
 	},
 )
 
+type varFunction struct {
+	closureFunction
+	name string
+}
+
 // NewVarFunction creates a new variable function.
 func NewVarFunction(name string) Function {
-	return ClosureFunction("variable "+name, func(ctx FunctionContext) (any, error) {
+	f := &varFunction{name: name}
+	f.closureFunction = newClosureFunction("variable "+name, func(ctx FunctionContext) (any, error) {
 		if ctx.Vars == nil {
 			return nil, errors.New("variables were undefined")
 		}
@@ -1033,4 +1039,5 @@ func NewVarFunction(name string) Function {
 		ctx = ctx.WithValues(paths)
 		return ctx, paths
 	})
+	return f
 }
@@ -70,7 +70,7 @@ func (m *MethodSet) Init(name string, target Function, args *ParsedParams) (Func
 		return nil, badMethodErr(name)
 	}
 	if m.disableCtors {
-		return disabledMethod(name), nil
+		return &disabledMethod{spec: details.spec, target: target, args: args}, nil
 	}
 	return wrapMethodCtorWithDynamicArgs(name, target, args, details.ctor)
 }
@@ -153,10 +153,24 @@ func MethodDocs() []MethodSpec {
 
 //------------------------------------------------------------------------------
 
-func disabledMethod(name string) Function {
-	return ClosureFunction("method "+name, func(ctx FunctionContext) (any, error) {
-		return nil, errors.New("this method has been disabled")
-	}, func(ctx TargetsContext) (TargetsContext, []TargetPath) { return ctx, nil })
+// disabledMethod is a method of a deactivated set, which retains the spec,
+// target and arguments of the method in order to support static analysis.
+type disabledMethod struct {
+	spec   MethodSpec
+	target Function
+	args   *ParsedParams
+}
+
+func (d *disabledMethod) Annotation() string {
+	return "method " + d.spec.Name
+}
+
+func (d *disabledMethod) Exec(ctx FunctionContext) (any, error) {
+	return nil, errors.New("this method has been disabled")
+}
+
+func (d *disabledMethod) QueryTargets(ctx TargetsContext) (TargetsContext, []TargetPath) {
+	return ctx, nil
 }
 
 func wrapMethodCtorWithDynamicArgs(name string, target Function, args *ParsedParams, fn MethodCtor) (Function, error) {
@@ -108,7 +108,7 @@ var _ = registerSimpleMethod(
 //------------------------------------------------------------------------------
 
 var _ = registerMethod(
-	NewMethodSpec("bool", "").InCategory(
+	NewMethodSpec("bool", "").Returns(value.TBool).InCategory(
 		MethodCategoryCoercion,
 		"Attempt to parse a value into a boolean. An optional argument can be provided, in which case if the value cannot be parsed the argument will be returned instead. If the value is a number then any non-zero value will resolve to `true`, if the value is a string then any of the following values are considered valid: `1, t, T, TRUE, true, True, 0, f, F, FALSE`.",
 		NewExampleSpec("",
@@ -339,9 +339,15 @@ var _ = registerMethod(
 	mapMethod,
 )
 
+type mapMethodFunction struct {
+	closureFunction
+	target, mapFn Function
+}
+
 // NewMapMethod attempts to create a map method.
 func NewMapMethod(target, mapFn Function) (Function, error) {
-	return ClosureFunction(mapFn.Annotation(), func(ctx FunctionContext) (any, error) {
+	f := &mapMethodFunction{target: target, mapFn: mapFn}
+	f.closureFunction = newClosureFunction(mapFn.Annotation(), func(ctx FunctionContext) (any, error) {
 		res, err := target.Exec(ctx)
 		if err != nil {
 			return nil, err
@@ -353,7 +359,8 @@ func NewMapMethod(target, mapFn Function) (Function, error) {
 
 		returnCtx, mapTargets := mapFn.QueryTargets(mapCtx)
 		return returnCtx, append(targets, mapTargets...)
-	}), nil
+	})
+	return f, nil
 }
 
 func mapMethod(target Function, args *ParsedParams) (Function, error) {
@@ -434,7 +441,7 @@ var _ = registerSimpleMethod(
 var _ = registerMethod(
 	NewMethodSpec(
 		"number", "",
-	).InCategory(
+	).Returns(value.TNumber).InCategory(
 		MethodCategoryCoercion,
 		"Attempt to parse a value into a number. An optional argument can be provided, in which case if the value cannot be parsed into a number the argument will be returned instead.",
 		NewExampleSpec("",
@@ -11,7 +11,7 @@ import (
 )
 
 var _ = registerSimpleMethod(
-	NewMethodSpec("ceil", "Returns the least integer value greater than or equal to a number. If the resulting value fits within a 64-bit integer then that is returned, otherwise a new floating point number is returned.").InCategory(
+	NewMethodSpec("ceil", "Returns the least integer value greater than or equal to a number. If the resulting value fits within a 64-bit integer then that is returned, otherwise a new floating point number is returned.").Receives(value.TNumber).Returns(value.TNumber).InCategory(
 		MethodCategoryNumbers, "",
 		NewExampleSpec("",
 			`root.new_value = this.value.ceil()`,
@@ -41,7 +41,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"floor", "Returns the greatest integer value less than or equal to the target number. If the resulting value fits within a 64-bit integer then that is returned, otherwise a new floating point number is returned.",
-	).InCategory(
+	).Receives(value.TNumber).Returns(value.TNumber).InCategory(
 		MethodCategoryNumbers,
 		"",
 		NewExampleSpec("",
@@ -208,7 +208,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"round", "Rounds numbers to the nearest integer, rounding half away from zero. If the resulting value fits within a 64-bit integer then that is returned, otherwise a new floating point number is returned.",
-	).InCategory(
+	).Receives(value.TNumber).Returns(value.TNumber).InCategory(
 		MethodCategoryNumbers,
 		"",
 		NewExampleSpec("",
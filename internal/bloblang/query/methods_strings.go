@@ -63,7 +63,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"capitalize", "",
-	).InCategory(
+	).Receives(value.TString, value.TBytes).Returns(value.TString).InCategory(
 		MethodCategoryStrings,
 		"Takes a string value and returns a copy with all Unicode letters that begin words mapped to their Unicode title case.",
 		NewExampleSpec("",
@@ -691,7 +691,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"has_prefix", "",
-	).InCategory(
+	).Receives(value.TString, value.TBytes).Returns(value.TBool).InCategory(
 		MethodCategoryStrings,
 		"Checks whether a string has a prefix argument and returns a bool.",
 		NewExampleSpec("",
@@ -724,7 +724,7 @@ root.t2 = this.v2.has_prefix("foo")`,
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"has_suffix", "",
-	).InCategory(
+	).Receives(value.TString, value.TBytes).Returns(value.TBool).InCategory(
 		MethodCategoryStrings,
 		"Checks whether a string has a suffix argument and returns a bool.",
 		NewExampleSpec("",
@@ -960,7 +960,7 @@ Returns UUID version 5 for the given string.`,
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"join", "",
-	).InCategory(
+	).Receives(value.TArray).Returns(value.TString).InCategory(
 		MethodCategoryObjectAndArray,
 		"Join an array of strings with an optional delimiter into a single string.",
 		NewExampleSpec("",
@@ -1009,7 +1009,7 @@ root.joined_numbers = this.numbers.map_each(this.string()).join(",")`,
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"uppercase", "",
-	).InCategory(
+	).Receives(value.TString, value.TBytes).Returns(value.TString).InCategory(
 		MethodCategoryStrings,
 		"Convert a string value into uppercase.",
 		NewExampleSpec("",
@@ -1037,7 +1037,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"lowercase", "",
-	).InCategory(
+	).Receives(value.TString, value.TBytes).Returns(value.TString).InCategory(
 		MethodCategoryStrings,
 		"Convert a string value into lowercase.",
 		NewExampleSpec("",
@@ -1179,7 +1179,7 @@ func parseCSVMethod(args *ParsedParams) (simpleMethod, error) {
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"parse_json", "",
-	).Param(
+	).Receives(value.TString, value.TBytes).Param(
 		ParamBool("use_number", "An optional flag that when set makes parsing numbers as json.Number instead of the default float64.").Optional(),
 	).InCategory(
 		MethodCategoryParsing,
@@ -1525,7 +1525,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"replace_all", "",
-	).InCategory(
+	).Receives(value.TString, value.TBytes).Returns(value.TString).InCategory(
 		MethodCategoryStrings,
 		"Replaces all occurrences of the first argument in a target string with the second argument.",
 		NewExampleSpec("",
@@ -1953,7 +1953,7 @@ func reReplaceAllImpl(args *ParsedParams) (simpleMethod, error) {
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"split", "",
-	).InCategory(
+	).Receives(value.TString, value.TBytes).Returns(value.TArray).InCategory(
 		MethodCategoryStrings,
 		"Split a string value into an array of strings by splitting it on a string separator.",
 		NewExampleSpec("",
@@ -1995,7 +1995,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"string", "",
-	).InCategory(
+	).Returns(value.TString).InCategory(
 		MethodCategoryCoercion,
 		"Marshal a value into a string. If the value is already a string it is unchanged.",
 		NewExampleSpec("",
@@ -2021,7 +2021,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"trim", "",
-	).InCategory(
+	).Receives(value.TString, value.TBytes).Returns(value.TString).InCategory(
 		MethodCategoryStrings,
 		"Remove all leading and trailing characters from a string that are contained within an argument cutset. If no arguments are provided then whitespace is removed.",
 		NewExampleSpec("",
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/Jeffail/gabs/v2"
+	"github.com/nsf/jsondiff"
 	jsonschema "github.com/xeipuuv/gojsonschema"
 
 	"github.com/redpanda-data/benthos/v4/internal/value"
@@ -811,7 +812,7 @@ var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"keys",
 		"Returns the keys of an object as an array.",
-	).InCategory(
+	).Receives(value.TObject).Returns(value.TArray).InCategory(
 		MethodCategoryObjectAndArray, "",
 		NewExampleSpec("",
 			`root.foo_keys = this.foo.keys()`,
@@ -871,7 +872,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"length", "",
-	).InCategory(
+	).Receives(value.TString, value.TBytes, value.TArray, value.TObject).Returns(value.TNumber).InCategory(
 		MethodCategoryStrings, "Returns the length of a string.",
 		NewExampleSpec("",
 			`root.foo_len = this.foo.length()`,
@@ -1149,6 +1150,128 @@ func assigner(destination, source any) any {
 
 //------------------------------------------------------------------------------
 
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"create_json_patch", "Creates an https://datatracker.ietf.org/doc/html/rfc6902[RFC 6902 JSON Patch^] that transforms the target value into the provided value. Objects are compared key by key and arrays are compared index by index, and the resulting patch is an array of `add`, `remove` and `replace` operations that can be applied with the <<apply_json_patch, `apply_json_patch`>> method.",
+	).InCategory(
+		MethodCategoryObjectAndArray, "",
+		NewExampleSpec(``,
+			`root = this.before.create_json_patch(this.after)`,
+			`{"before":{"name":"foo","tags":["a","b"],"count":1},"after":{"name":"bar","tags":["a"],"enabled":true}}`,
+			`[{"op":"remove","path":"/count"},{"op":"replace","path":"/name","value":"bar"},{"op":"remove","path":"/tags/1"},{"op":"add","path":"/enabled","value":true}]`,
+		),
+	).Param(ParamAny("to", "The value that the patch should produce when applied to the target value.")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		to, err := args.Field("to")
+		if err != nil {
+			return nil, err
+		}
+		return func(v any, ctx FunctionContext) (any, error) {
+			return value.CreateJSONPatch(v, to), nil
+		}, nil
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"apply_json_patch", "Applies an https://datatracker.ietf.org/doc/html/rfc6902[RFC 6902 JSON Patch^] to the target value and returns the result. The patch must be an array of operation objects, and all of the operations `add`, `remove`, `replace`, `move`, `copy` and `test` are supported. An error is returned if any operation fails, including `test` operations where the values do not match.",
+	).InCategory(
+		MethodCategoryObjectAndArray, "",
+		NewExampleSpec(``,
+			`root = this.doc.apply_json_patch(this.patch)`,
+			`{"doc":{"name":"foo","tags":["a"]},"patch":[{"op":"test","path":"/name","value":"foo"},{"op":"replace","path":"/name","value":"bar"},{"op":"add","path":"/tags/-","value":"b"}]}`,
+			`{"name":"bar","tags":["a","b"]}`,
+		),
+	).Param(ParamAny("patch", "An array of JSON Patch operations to apply.")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		patch, err := args.Field("patch")
+		if err != nil {
+			return nil, err
+		}
+		return func(v any, ctx FunctionContext) (any, error) {
+			return value.ApplyJSONPatch(v, patch)
+		}, nil
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"apply_merge_patch", "Applies an https://datatracker.ietf.org/doc/html/rfc7396[RFC 7396 JSON Merge Patch^] to the target value and returns the result. Objects within the patch are merged recursively into the target, keys with a `null` value are removed, and any other value within the patch, including arrays, replaces the value of the target.",
+	).InCategory(
+		MethodCategoryObjectAndArray, "",
+		NewExampleSpec(``,
+			`root = this.doc.apply_merge_patch(this.patch)`,
+			`{"doc":{"name":"foo","meta":{"owner":"bob","draft":true},"tags":["a"]},"patch":{"meta":{"draft":null},"tags":["b","c"]}}`,
+			`{"meta":{"owner":"bob"},"name":"foo","tags":["b","c"]}`,
+		),
+	).Param(ParamAny("patch", "The merge patch to apply.")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		patch, err := args.Field("patch")
+		if err != nil {
+			return nil, err
+		}
+		return func(v any, ctx FunctionContext) (any, error) {
+			return value.ApplyMergePatch(v, patch), nil
+		}, nil
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"structural_diff", "Returns a human-readable string describing the structural differences between the target value and the provided value. The values are printed as indented JSON where removed values are wrapped in `[-` and `-]`, added values are wrapped in `{+` and `+}`, and changed values are printed as the removed value followed by the added value.",
+	).InCategory(
+		MethodCategoryObjectAndArray, "",
+		NewExampleSpec(``,
+			`root = this.before.structural_diff(to: this.after, only_changes: true)`,
+			`{"before":{"name":"foo","count":1,"id":"a"},"after":{"name":"bar","id":"a","enabled":true}}`,
+			`{
+    [-"count": 1-],
+    {+"enabled": true+},
+    ...skipped 1 object property...,
+    "name": [-"foo"-]{+"bar"+}
+}`,
+		),
+	).
+		Param(ParamAny("to", "The value to compare the target value against.")).
+		Param(ParamBool("only_changes", "Whether values that match should be omitted from the output.").Default(false)),
+	func(args *ParsedParams) (simpleMethod, error) {
+		to, err := args.Field("to")
+		if err != nil {
+			return nil, err
+		}
+		onlyChanges, err := args.FieldBool("only_changes")
+		if err != nil {
+			return nil, err
+		}
+		toBytes, err := json.Marshal(to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal comparison value: %w", err)
+		}
+		opts := jsondiff.Options{
+			Added:            jsondiff.Tag{Begin: "{+", End: "+}"},
+			Removed:          jsondiff.Tag{Begin: "[-", End: "-]"},
+			Changed:          jsondiff.Tag{Begin: "[-", End: "+}"},
+			ChangedSeparator: "-]{+",
+			Indent:           "    ",
+		}
+		if onlyChanges {
+			opts.SkipMatches = true
+			opts.SkippedArrayElement = jsondiff.SkippedArrayElement
+			opts.SkippedObjectProperty = jsondiff.SkippedObjectProperty
+		}
+		return func(v any, ctx FunctionContext) (any, error) {
+			fromBytes, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal target value: %w", err)
+			}
+			_, diff := jsondiff.Compare(fromBytes, toBytes, &opts)
+			return diff, nil
+		}, nil
+	},
+)
+
+//------------------------------------------------------------------------------
+
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"not_empty", "",
@@ -1204,7 +1327,7 @@ var _ = registerSimpleMethod(
 var _ = registerMethod(
 	NewMethodSpec(
 		"sort", "",
-	).InCategory(
+	).Receives(value.TArray).Returns(value.TArray).InCategory(
 		MethodCategoryObjectAndArray,
 		"Attempts to sort the values of an array in increasing order. The type of all values must match in order for the ordering to succeed. Supports string and number values.",
 		NewExampleSpec("",
@@ -1648,7 +1771,7 @@ func uniqueMethod(args *ParsedParams) (simpleMethod, error) {
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"values", "",
-	).InCategory(
+	).Receives(value.TObject).Returns(value.TArray).InCategory(
 		MethodCategoryObjectAndArray,
 		"Returns the values of an object as an array. The order of the resulting array will be random.",
 		NewExampleSpec("",
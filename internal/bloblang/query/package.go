@@ -55,6 +55,9 @@ type FunctionContext struct {
 	NewMeta  MetaMsg
 	NewValue *any
 
+	// Optionally notified of the branches taken by conditional expressions.
+	Tracer BranchTracer
+
 	valueFn    func() *any
 	value      *any
 	nextValue  *any
@@ -143,6 +146,18 @@ func (ctx FunctionContext) PopValue() (*any, FunctionContext) {
 	return retValue, ctx
 }
 
+// BranchTracer is notified of the branches taken by conditional expressions
+// during execution, which is used in order to trace the execution of mappings.
+type BranchTracer interface {
+	TraceBranch(expression, branch string)
+}
+
+func (ctx FunctionContext) traceBranch(expression, branch string) {
+	if ctx.Tracer != nil {
+		ctx.Tracer.TraceBranch(expression, branch)
+	}
+}
+
 //------------------------------------------------------------------------------
 
 // ExecToString returns a string from a function execution.
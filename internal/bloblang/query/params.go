@@ -27,6 +27,10 @@ type ParamDefinition struct {
 	// default.
 	IsOptional   bool `json:"is_optional,omitempty"`
 	DefaultValue *any `json:"default,omitempty"`
+
+	// IsSecret indicates whether the parameter represents information that is
+	// generally considered sensitive such as passwords or signing keys.
+	IsSecret bool `json:"is_secret,omitempty"`
 }
 
 func (d ParamDefinition) validate() error {
@@ -132,6 +136,15 @@ func (d ParamDefinition) Default(v any) ParamDefinition {
 	return d
 }
 
+// Secret marks the parameter as being a secret, which means it represents
+// information that is generally considered sensitive such as passwords or
+// signing keys. Errors returned when the arguments of secret parameters fail to
+// be parsed or resolved omit details that might contain their values.
+func (d ParamDefinition) Secret() ParamDefinition {
+	d.IsSecret = true
+	return d
+}
+
 // PrettyDefault returns a marshalled version of the parameters default value,
 // or an empty string if there isn't one.
 func (d ParamDefinition) PrettyDefault() string {
@@ -146,6 +159,33 @@ func (d ParamDefinition) PrettyDefault() string {
 }
 
 func (d ParamDefinition) parseArgValue(v any) (any, error) {
+	res, err := d.parseArgValueUnredacted(v)
+	if err != nil && d.IsSecret {
+		// Type errors usually include the offending value, which is omitted
+		// for secrets.
+		err = fmt.Errorf("wrong argument type, expected %v, got %v", d.ValueType, value.ITypeOf(v))
+	}
+	return res, err
+}
+
+// secretArgError is returned when the argument of a secret parameter fails to
+// be resolved, and omits the underlying error from its message as it might
+// contain the value of the secret.
+type secretArgError struct {
+	name string
+	from string
+	err  error
+}
+
+func (e *secretArgError) Error() string {
+	return fmt.Sprintf("failed to extract input arg '%v': %v failed, the error is omitted as the parameter is a secret", e.name, e.from)
+}
+
+func (e *secretArgError) Unwrap() error {
+	return e.err
+}
+
+func (d ParamDefinition) parseArgValueUnredacted(v any) (any, error) {
 	switch d.ValueType {
 	case value.TInt:
 		return value.IGetInt(v)
@@ -486,6 +526,9 @@ func (p *ParsedParams) ResolveDynamic(ctx FunctionContext) (*ParsedParams, error
 		}
 		tmpValue, err := dyn.fn.Exec(ctx)
 		if err != nil {
+			if sourceDef.IsSecret {
+				return nil, &secretArgError{name: sourceDef.Name, from: dyn.fn.Annotation(), err: err}
+			}
 			return nil, fmt.Errorf("failed to extract input arg '%v': %w", sourceDef.Name, err)
 		}
 		if newValues[dyn.index], err = sourceDef.parseArgValue(tmpValue); err != nil {
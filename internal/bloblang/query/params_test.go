@@ -3,6 +3,7 @@
 package query
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -666,6 +667,39 @@ func TestParsedParamsDynamicErrors(t *testing.T) {
 	}
 }
 
+func TestParsedParamsSecretErrors(t *testing.T) {
+	params := NewParams().
+		Add(ParamString("key", "").Secret()).
+		Add(ParamInt64("count", "").Secret())
+
+	_, err := params.PopulateNameless("foo", "supersecretvalue")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "wrong argument type")
+	assert.NotContains(t, err.Error(), "supersecretvalue")
+
+	parsed, err := params.PopulateNameless(NewFieldFunction("doc.key"), NewFieldFunction("doc.count"))
+	require.NoError(t, err)
+
+	_, err = parsed.ResolveDynamic(FunctionContext{}.WithValue(map[string]any{
+		"doc": map[string]any{
+			"key":   "foo",
+			"count": "supersecretvalue",
+		},
+	}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'count': wrong argument type")
+	assert.NotContains(t, err.Error(), "supersecretvalue")
+
+	_, err = parsed.ResolveDynamic(FunctionContext{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to extract input arg 'key'")
+	assert.Contains(t, err.Error(), "the error is omitted as the parameter is a secret")
+
+	var secretErr *secretArgError
+	require.ErrorAs(t, err, &secretErr)
+	assert.Error(t, errors.Unwrap(err))
+}
+
 func TestParsedParams(t *testing.T) {
 	params := NewParams().
 		Add(ParamString("first", "").Optional()).
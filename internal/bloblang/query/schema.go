@@ -0,0 +1,184 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/redpanda-data/benthos/v4/internal/value"
+)
+
+// Schema describes the structure of documents that a mapping is expected to
+// be executed upon, and is used in order to validate field references during
+// static analysis.
+type Schema struct {
+	// The type of the value, which is value.TUnknown when the value can be of
+	// multiple types.
+	Type value.Type
+
+	// The known fields of an object.
+	Properties map[string]*Schema
+
+	// Whether fields not listed within Properties are forbidden.
+	Closed bool
+
+	// The schema of the elements of an array.
+	Items *Schema
+}
+
+// NewSchemaFromJSONSchema parses a JSON Schema document into a Schema. Only a
+// subset of JSON Schema is interpreted, namely the keywords type, properties,
+// additionalProperties, items and local $ref references. Any other keywords
+// are ignored.
+func NewSchemaFromJSONSchema(jsonSchema []byte) (*Schema, error) {
+	var root map[string]any
+	if err := json.Unmarshal(jsonSchema, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON Schema: %w", err)
+	}
+	p := schemaParser{root: root, resolving: map[string]bool{}}
+	return p.parse(root)
+}
+
+type schemaParser struct {
+	root      map[string]any
+	resolving map[string]bool
+}
+
+func (p *schemaParser) resolveRef(ref string) (map[string]any, error) {
+	if ref == "#" {
+		return p.root, nil
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported schema reference %v, only local references are supported", ref)
+	}
+	var current any = p.root
+	for _, seg := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		seg = strings.ReplaceAll(strings.ReplaceAll(seg, "~1", "/"), "~0", "~")
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("schema reference %v not found", ref)
+		}
+		if current, ok = obj[seg]; !ok {
+			return nil, fmt.Errorf("schema reference %v not found", ref)
+		}
+	}
+	obj, ok := current.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("schema reference %v does not resolve to a schema", ref)
+	}
+	return obj, nil
+}
+
+func (p *schemaParser) parse(obj map[string]any) (*Schema, error) {
+	if ref, ok := obj["$ref"].(string); ok {
+		// Recursive schemas are treated as unknown beyond the first level of
+		// recursion.
+		if p.resolving[ref] {
+			return &Schema{Type: value.TUnknown}, nil
+		}
+		target, err := p.resolveRef(ref)
+		if err != nil {
+			return nil, err
+		}
+		p.resolving[ref] = true
+		defer delete(p.resolving, ref)
+		return p.parse(target)
+	}
+
+	s := &Schema{Type: value.TUnknown}
+	switch t := obj["type"].(type) {
+	case string:
+		switch t {
+		case "string":
+			s.Type = value.TString
+		case "number", "integer":
+			s.Type = value.TNumber
+		case "boolean":
+			s.Type = value.TBool
+		case "object":
+			s.Type = value.TObject
+		case "array":
+			s.Type = value.TArray
+		case "null":
+			s.Type = value.TNull
+		default:
+			return nil, fmt.Errorf("unrecognised schema type %v", t)
+		}
+	case nil:
+		if _, exists := obj["properties"]; exists {
+			s.Type = value.TObject
+		}
+	}
+
+	if props, ok := obj["properties"].(map[string]any); ok {
+		s.Properties = make(map[string]*Schema, len(props))
+		for k, v := range props {
+			propObj, ok := v.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("property %v: expected a schema object", k)
+			}
+			propSchema, err := p.parse(propObj)
+			if err != nil {
+				return nil, fmt.Errorf("property %v: %w", k, err)
+			}
+			s.Properties[k] = propSchema
+		}
+	}
+	if additional, ok := obj["additionalProperties"].(bool); ok && !additional {
+		s.Closed = true
+	}
+	if items, ok := obj["items"].(map[string]any); ok {
+		itemsSchema, err := p.parse(items)
+		if err != nil {
+			return nil, fmt.Errorf("items: %w", err)
+		}
+		s.Items = itemsSchema
+	}
+	return s, nil
+}
+
+// lookup walks a path of the schema and returns the schema of the value at the
+// end of it, or nil if it cannot be determined. An error is returned when the
+// schema states that the path cannot exist.
+func (s *Schema) lookup(path []string) (*Schema, error) {
+	current := s
+	for i, seg := range path {
+		if current == nil {
+			return nil, nil
+		}
+		switch current.Type {
+		case value.TObject:
+			next, exists := current.Properties[seg]
+			if !exists {
+				if current.Closed {
+					return nil, errors.New("is not defined by the schema")
+				}
+				return nil, nil
+			}
+			current = next
+		case value.TArray:
+			if _, err := strconv.Atoi(seg); err != nil {
+				return nil, nil
+			}
+			current = current.Items
+		case value.TUnknown:
+			if current.Properties == nil {
+				return nil, nil
+			}
+			if current = current.Properties[seg]; current == nil {
+				return nil, nil
+			}
+		default:
+			parent := "the document"
+			if i > 0 {
+				parent = "`" + SliceToDotPath(path[:i]...) + "`"
+			}
+			return nil, fmt.Errorf("is not possible as the schema defines %v as a %v", parent, current.Type)
+		}
+	}
+	return current, nil
+}
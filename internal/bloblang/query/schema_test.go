@@ -0,0 +1,83 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/internal/value"
+)
+
+func TestSchemaFromJSONSchema(t *testing.T) {
+	s, err := NewSchemaFromJSONSchema([]byte(`{
+  "$defs": {
+    "node": {
+      "type": "object",
+      "properties": {
+        "value": { "type": ["string", "null"] },
+        "children": { "type": "array", "items": { "$ref": "#/$defs/node" } }
+      },
+      "additionalProperties": false
+    }
+  },
+  "properties": {
+    "root": { "$ref": "#/$defs/node" },
+    "count": { "type": "integer" }
+  }
+}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, value.TObject, s.Type)
+	assert.False(t, s.Closed)
+	assert.Equal(t, value.TNumber, s.Properties["count"].Type)
+
+	node := s.Properties["root"]
+	require.NotNil(t, node)
+	assert.True(t, node.Closed)
+	assert.Equal(t, value.TUnknown, node.Properties["value"].Type)
+	assert.Equal(t, value.TArray, node.Properties["children"].Type)
+
+	// The recursive reference is only expanded once.
+	assert.Equal(t, value.TUnknown, node.Properties["children"].Items.Type)
+	assert.Nil(t, node.Properties["children"].Items.Properties)
+
+	for _, test := range []struct {
+		path        []string
+		typ         value.Type
+		errContains string
+	}{
+		{path: []string{"count"}, typ: value.TNumber},
+		{path: []string{"unknown"}, typ: value.TUnknown},
+		{path: []string{"root", "children", "0"}, typ: value.TUnknown},
+		{path: []string{"root", "nope"}, errContains: "is not defined by the schema"},
+		{path: []string{"count", "nope"}, errContains: "schema defines `count` as a number"},
+	} {
+		res, err := s.lookup(test.path)
+		if test.errContains != "" {
+			require.Error(t, err, test.path)
+			assert.Contains(t, err.Error(), test.errContains)
+			continue
+		}
+		require.NoError(t, err, test.path)
+		assert.Equal(t, test.typ, schemaType(res), test.path)
+	}
+}
+
+func TestSchemaFromJSONSchemaErrors(t *testing.T) {
+	for _, test := range []struct {
+		schema      string
+		errContains string
+	}{
+		{schema: `nope`, errContains: "failed to parse JSON Schema"},
+		{schema: `{"type":"nope"}`, errContains: "unrecognised schema type nope"},
+		{schema: `{"properties":{"a":{"$ref":"#/$defs/missing"}}}`, errContains: "property a: schema reference #/$defs/missing not found"},
+		{schema: `{"$ref":"http://example.com/schema.json"}`, errContains: "only local references are supported"},
+	} {
+		_, err := NewSchemaFromJSONSchema([]byte(test.schema))
+		require.Error(t, err, test.schema)
+		assert.Contains(t, err.Error(), test.errContains, test.schema)
+	}
+}
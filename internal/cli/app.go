@@ -120,6 +120,7 @@ func App(opts *common.CLIOpts) *cli.App {
 	commands := []*cli.Command{
 		echoCliCommand(opts),
 		lintCliCommand(opts),
+		encryptSecretsCliCommand(opts),
 		runCliCommand(opts),
 		streamsCliCommand(opts),
 		listCliCommand(opts),
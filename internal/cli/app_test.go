@@ -76,3 +76,41 @@ output:
 	data, _ := os.ReadFile(outPath)
 	assert.Contains(t, string(data), "foobar")
 }
+
+func TestRunCLISecretProviders(t *testing.T) {
+	tmpDir := t.TempDir()
+	confPath := filepath.Join(tmpDir, "foo.yaml")
+	secretPath := filepath.Join(tmpDir, "secret")
+	outPath := filepath.Join(tmpDir, "out.txt")
+
+	require.NoError(t, os.WriteFile(secretPath, []byte("foobar\n"), 0o600))
+	require.NoError(t, os.WriteFile(confPath, fmt.Appendf(nil, `
+input:
+  generate:
+    mapping: 'root.id = "${secret:file:%v}"'
+    interval: "100ms"
+output:
+  file:
+    codec: lines
+    path: %v
+`, secretPath, outPath), 0o644))
+
+	opts := common.NewCLIOpts("1.2.3", "aaa")
+	opts.Stdout = io.Discard
+
+	err := icli.App(opts).Run([]string{"benthos", "run", "--secret-provider", "nope", confPath})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "secret provider 'nope' cannot be enabled")
+
+	err = icli.App(opts).Run([]string{"benthos", "run", confPath})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "secret provider 'file' must be explicitly enabled")
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second))
+	defer cancel()
+
+	require.NoError(t, icli.App(opts).RunContext(ctx, []string{"benthos", "run", "--secret-provider", "file", "--secret-cache-ttl", "0s", confPath}))
+
+	data, _ := os.ReadFile(outPath)
+	assert.Contains(t, string(data), "foobar")
+}
@@ -18,7 +18,9 @@ import (
 	"github.com/redpanda-data/benthos/v4/internal/bloblang/parser"
 	"github.com/redpanda-data/benthos/v4/internal/bloblang/query"
 	"github.com/redpanda-data/benthos/v4/internal/cli/common"
+	"github.com/redpanda-data/benthos/v4/internal/cli/test"
 	"github.com/redpanda-data/benthos/v4/internal/filepath/ifs"
+	"github.com/redpanda-data/benthos/v4/internal/log"
 	"github.com/redpanda-data/benthos/v4/internal/message"
 	"github.com/redpanda-data/benthos/v4/internal/value"
 )
@@ -60,6 +62,10 @@ Find out more about Bloblang at: {{.DocumentationURL}}/guides/bloblang/about`)[1
 				Aliases: []string{"f"},
 				Usage:   "execute a mapping from a file.",
 			},
+			&cli.BoolFlag{
+				Name:  "trace",
+				Usage: "print a trace of the execution of each mapping statement to stderr, including the values assigned and the branches taken by conditionals.",
+			},
 			&cli.IntFlag{
 				Name:  "max-token-length",
 				Usage: "Set the buffer size for document lines.",
@@ -70,6 +76,60 @@ Find out more about Bloblang at: {{.DocumentationURL}}/guides/bloblang/about`)[1
 			return run(ctx, opts)
 		},
 		Subcommands: []*cli.Command{
+			{
+				Name:      "fmt",
+				Usage:     "Format Bloblang mappings in a canonical layout",
+				ArgsUsage: "[files...]",
+				Description: opts.ExecTemplate(`
+Formats Bloblang mapping files, and the mappings within the fields of YAML
+configs, in a canonical layout. Comments are preserved, and only whitespace,
+the placement of brackets and the quoting of path segments are changed:
+
+  {{.BinaryName}} blobl fmt ./mapping.blobl
+  {{.BinaryName}} blobl fmt -w ./mappings/... ./config.yaml
+  {{.BinaryName}} blobl fmt --check ./configs/...
+
+Files with the .yaml or .yml extension are treated as configs, and all other
+files as mappings. If a path ends with '...' then the target is walked and any
+files with the .blobl, .yaml or .yml extensions are formatted. When no files
+are specified a mapping is read from stdin.
+
+Mappings within configs keep the YAML style of their field where possible,
+otherwise they are rewritten as a double quoted or literal block scalar, and
+fields that cannot be rewritten are reported as errors.
+
+By default the formatted files are printed to stdout. With --check the paths
+of files that are not formatted are printed instead, and the command exits
+with a status code 1 if there are any.`)[1:],
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:    "write",
+						Aliases: []string{"w"},
+						Usage:   "write formatted mappings back to their source files instead of stdout.",
+					},
+					&cli.BoolFlag{
+						Name:  "check",
+						Usage: "list files that are not formatted and exit with a status code 1 if there are any.",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					return runFmt(c, opts)
+				},
+			},
+			{
+				Name:  "lsp",
+				Usage: "Run a language server for Bloblang mappings over stdio",
+				Description: `
+Run a server speaking the Language Server Protocol over stdin and stdout,
+providing editors with parse errors, completion and documentation of functions
+and methods, and navigation to the definitions of maps and imported files.
+
+Configure your editor to launch this command as the language server of files
+with the .blobl extension.`[1:],
+				Action: func(c *cli.Context) error {
+					return runLSP(c, opts)
+				},
+			},
 			{
 				Name:  "server",
 				Usage: "EXPERIMENTAL: Run a web server that hosts a Bloblang app",
@@ -118,6 +178,55 @@ purposes only. Do NOT expose it to the internet.`[1:],
 					},
 				},
 			},
+			{
+				Name:      "test",
+				Usage:     "Execute the tests within Bloblang mapping files",
+				ArgsUsage: "[files...]",
+				Description: opts.ExecTemplate(`
+Execute the test blocks found within the comments of Bloblang mapping files. If
+one or more tests fail the process will report the errors and exit with a
+status code 1:
+
+  {{.BinaryName}} blobl test ./mapping.blobl
+  {{.BinaryName}} blobl test ./mappings/...
+
+A test block begins with a comment line of the form '#test <name>', and the
+comment lines that follow it contain a YAML object describing the input
+document and metadata of the test, and either the conditions that the output
+must meet or a string that an expected error contains:
+
+  root.name = this.name.uppercase()
+
+  #test uppercases the name
+  #  input: {"name":"foo"}
+  #  metadata: {"topic":"bar"}
+  #  output:
+  #    json_equals: {"name":"FOO"}
+  #    metadata_equals: {"topic":"bar"}
+
+  #test fails without a name
+  #  input: {}
+  #  error: expected string value
+
+A test can also specify a mapping to execute in place of the statements of the
+file, where the maps defined by the file are available, in order to test
+mapping libraries that are imported by other mappings:
+
+  #test applies the foo map
+  #  input: {"name":"foo"}
+  #  mapping: root = this.apply("foo")
+  #  output:
+  #    json_equals: {"name":"FOO"}
+
+The test blocks of mapping files are also executed by the {{.BinaryName}} test
+subcommand.`)[1:],
+				Action: func(c *cli.Context) error {
+					if test.RunMappings(opts, c.Args().Slice(), log.Noop()) {
+						return nil
+					}
+					return &common.ErrExitCode{Err: errors.New("test failures"), Code: 1}
+				},
+			},
 		},
 	}
 }
@@ -135,6 +244,17 @@ func newExecCache() *execCache {
 }
 
 func (e *execCache) executeMapping(exec *mapping.Executor, rawInput, prettyOutput bool, input []byte) (string, error) {
+	resultStr, _, err := e.execute(exec, rawInput, prettyOutput, false, input)
+	return resultStr, err
+}
+
+// executeMappingTraced executes a mapping and also returns a trace of the
+// execution of each statement, which is returned even when the mapping fails.
+func (e *execCache) executeMappingTraced(exec *mapping.Executor, rawInput, prettyOutput bool, input []byte) (string, *mapping.Trace, error) {
+	return e.execute(exec, rawInput, prettyOutput, true, input)
+}
+
+func (e *execCache) execute(exec *mapping.Executor, rawInput, prettyOutput, traced bool, input []byte) (string, *mapping.Trace, error) {
 	e.msg.Get(0).SetBytes(input)
 
 	var valuePtr *any
@@ -165,17 +285,26 @@ func (e *execCache) executeMapping(exec *mapping.Executor, rawInput, prettyOutpu
 	}
 
 	var result any = value.Nothing(nil)
-	err := exec.ExecOnto(query.FunctionContext{
+	fnCtx := query.FunctionContext{
 		Maps:     exec.Maps(),
 		Vars:     e.vars,
 		MsgBatch: e.msg,
 		NewMeta:  e.msg.Get(0),
 		NewValue: &result,
-	}.WithValueFunc(lazyValue), mapping.AssignmentContext{
+	}.WithValueFunc(lazyValue)
+	asCtx := mapping.AssignmentContext{
 		Vars:  e.vars,
 		Meta:  e.msg.Get(0),
 		Value: &result,
-	})
+	}
+
+	var trace *mapping.Trace
+	var err error
+	if traced {
+		trace, err = exec.ExecOntoTraced(fnCtx, asCtx)
+	} else {
+		err = exec.ExecOnto(fnCtx, asCtx)
+	}
 	if err != nil {
 		var ctxErr query.ErrNoContext
 		if parseErr != nil && errors.As(err, &ctxErr) {
@@ -185,7 +314,7 @@ func (e *execCache) executeMapping(exec *mapping.Executor, rawInput, prettyOutpu
 				err = fmt.Errorf("unable to reference message as structured (with 'this'): %w", parseErr)
 			}
 		}
-		return "", err
+		return "", trace, err
 	}
 
 	var resultStr string
@@ -195,7 +324,7 @@ func (e *execCache) executeMapping(exec *mapping.Executor, rawInput, prettyOutpu
 	case []byte:
 		resultStr = string(t)
 	case value.Delete:
-		return "", nil
+		return "", trace, nil
 	case value.Nothing:
 		// Do not change the original contents
 		if v := lazyValue(); v != nil {
@@ -218,7 +347,7 @@ func (e *execCache) executeMapping(exec *mapping.Executor, rawInput, prettyOutpu
 	}
 
 	// TODO: Return metadata as well?
-	return resultStr, nil
+	return resultStr, trace, nil
 }
 
 func run(c *cli.Context, opts *common.CLIOpts) error {
@@ -228,6 +357,7 @@ func run(c *cli.Context, opts *common.CLIOpts) error {
 	}
 	raw := c.Bool("raw")
 	pretty := c.Bool("pretty")
+	trace := c.Bool("trace")
 	file := c.String("file")
 	m := c.Args().First()
 
@@ -283,7 +413,15 @@ func run(c *cli.Context, opts *common.CLIOpts) error {
 					return nil
 				}
 
-				resultStr, err := execCache.executeMapping(exec, raw, pretty, input)
+				var resultStr string
+				var err error
+				if trace {
+					var t *mapping.Trace
+					resultStr, t, err = execCache.executeMappingTraced(exec, raw, pretty, input)
+					fmt.Fprint(opts.Stderr, traceString(t))
+				} else {
+					resultStr, err = execCache.executeMapping(exec, raw, pretty, input)
+				}
 				if err != nil {
 					fmt.Fprintln(opts.Stderr, red(fmt.Sprintf("failed to execute map: %v", err)))
 					continue
@@ -0,0 +1,372 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package blobl
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/redpanda-data/benthos/v4/internal/bloblang"
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/parser"
+	"github.com/redpanda-data/benthos/v4/internal/cli/common"
+	"github.com/redpanda-data/benthos/v4/internal/docs"
+	ifilepath "github.com/redpanda-data/benthos/v4/internal/filepath"
+	"github.com/redpanda-data/benthos/v4/internal/filepath/ifs"
+)
+
+func runFmt(c *cli.Context, opts *common.CLIOpts) error {
+	write, check := c.Bool("write"), c.Bool("check")
+	if write && check {
+		return errors.New("invalid flags, unable to both write and check formatting")
+	}
+
+	if c.Args().Len() == 0 {
+		if write {
+			return errors.New("invalid flags, unable to write formatted stdin")
+		}
+		input, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		formatted, err := formatMapping(opts.BloblEnvironment, input)
+		if err != nil {
+			return fmt.Errorf("failed to format mapping: %w", err)
+		}
+		if check {
+			if !bytes.Equal(input, formatted) {
+				return &common.ErrExitCode{Err: errors.New("mapping is not formatted"), Code: 1}
+			}
+			return nil
+		}
+		_, err = opts.Stdout.Write(formatted)
+		return err
+	}
+
+	targets, err := ifilepath.GlobsAndSuperPaths(ifs.OS(), c.Args().Slice(), "blobl", "yaml", "yml")
+	if err != nil {
+		return fmt.Errorf("fmt paths error: %w", err)
+	}
+
+	var failed, unformatted bool
+	for _, target := range targets {
+		input, err := ifs.ReadFile(ifs.OS(), target)
+		if err != nil {
+			fmt.Fprintln(opts.Stderr, red(fmt.Sprintf("%v: %v", target, err)))
+			failed = true
+			continue
+		}
+
+		var formatted []byte
+		if ext := filepath.Ext(target); ext == ".yaml" || ext == ".yml" {
+			formatted, err = formatConfig(opts, target, input)
+		} else {
+			formatted, err = formatMapping(opts.BloblEnvironment.WithImporterRelativeToFile(target), input)
+		}
+		if err != nil {
+			fmt.Fprintln(opts.Stderr, red(fmt.Sprintf("%v: %v", target, err)))
+			failed = true
+			continue
+		}
+
+		switch {
+		case check:
+			if !bytes.Equal(input, formatted) {
+				fmt.Fprintln(opts.Stdout, target)
+				unformatted = true
+			}
+		case write:
+			if bytes.Equal(input, formatted) {
+				continue
+			}
+			if err := ifs.WriteFile(ifs.OS(), target, formatted, 0o644); err != nil {
+				fmt.Fprintln(opts.Stderr, red(fmt.Sprintf("%v: %v", target, err)))
+				failed = true
+			}
+		default:
+			_, _ = opts.Stdout.Write(formatted)
+		}
+	}
+
+	if failed {
+		return &common.ErrExitCode{Err: errors.New("fmt errors"), Code: 1}
+	}
+	if unformatted {
+		return &common.ErrExitCode{Err: errors.New("files are not formatted"), Code: 1}
+	}
+	return nil
+}
+
+func formatMapping(env *bloblang.Environment, input []byte) ([]byte, error) {
+	formatted, err := env.FormatMapping(string(input))
+	if err != nil {
+		var perr *parser.Error
+		if errors.As(err, &perr) {
+			return nil, errors.New(perr.ErrorAtPosition([]rune(string(input))))
+		}
+		return nil, err
+	}
+	return []byte(formatted), nil
+}
+
+//------------------------------------------------------------------------------
+
+type configMappingEdit struct {
+	path      string
+	node      *yaml.Node
+	formatted string
+}
+
+// formatConfig formats the mappings of all Bloblang fields within a YAML
+// config. The config is modified in place rather than re-encoded so that the
+// formatting of the remaining config is left untouched.
+func formatConfig(opts *common.CLIOpts, path string, input []byte) ([]byte, error) {
+	env := opts.BloblEnvironment.WithImporterRelativeToFile(path)
+	spec := opts.MainConfigSpecCtor()
+
+	var edits []configMappingEdit
+	var errs []string
+	if err := walkConfigMappings(opts, spec, input, func(fieldPath string, node *yaml.Node) {
+		if strings.TrimSpace(node.Value) == "" {
+			return
+		}
+		formatted, err := formatMapping(env, []byte(node.Value))
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("line %v: field %v: %v", node.Line, fieldPath, err))
+			return
+		}
+		if strings.TrimRight(string(formatted), "\n") != strings.TrimRight(node.Value, "\n") {
+			edits = append(edits, configMappingEdit{
+				path:      fieldPath,
+				node:      node,
+				formatted: strings.TrimRight(string(formatted), "\n"),
+			})
+		}
+	}); err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, ", "))
+	}
+	if len(edits) == 0 {
+		return input, nil
+	}
+
+	// Edits are applied from the end of the config towards the beginning so
+	// that the positions of those remaining are unchanged.
+	sort.Slice(edits, func(i, j int) bool {
+		if edits[i].node.Line == edits[j].node.Line {
+			return edits[i].node.Column > edits[j].node.Column
+		}
+		return edits[i].node.Line > edits[j].node.Line
+	})
+
+	lines := strings.Split(string(input), "\n")
+	expected := map[string]string{}
+	for _, e := range edits {
+		var ok bool
+		if lines, ok = applyConfigMappingEdit(lines, e); !ok {
+			errs = append(errs, fmt.Sprintf("line %v: field %v: unable to rewrite mapping in its current YAML style", e.node.Line, e.path))
+			continue
+		}
+		expected[e.path] = e.formatted
+	}
+	if len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, ", "))
+	}
+	output := []byte(strings.Join(lines, "\n"))
+
+	// Verify that the modified config contains exactly the mappings that we
+	// expect, as a mistake here would silently corrupt the config.
+	if err := walkConfigMappings(opts, spec, output, func(fieldPath string, node *yaml.Node) {
+		if exp, exists := expected[fieldPath]; exists {
+			if strings.TrimRight(node.Value, "\n") == exp {
+				delete(expected, fieldPath)
+			}
+		}
+	}); err != nil {
+		return nil, fmt.Errorf("failed to rewrite config: %w", err)
+	}
+	for fieldPath := range expected {
+		return nil, fmt.Errorf("failed to rewrite mapping of field %v", fieldPath)
+	}
+	return output, nil
+}
+
+func walkConfigMappings(opts *common.CLIOpts, spec docs.FieldSpecs, input []byte, fn func(path string, node *yaml.Node)) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(input, &root); err != nil {
+		return err
+	}
+	if len(root.Content) == 0 {
+		return nil
+	}
+	return spec.WalkComponentsYAML(docs.WalkComponentConfig{
+		Provider: opts.Environment,
+		Func: func(c docs.WalkedComponent) error {
+			return c.WalkBloblangFieldsYAML(func(path string, node *yaml.Node) error {
+				fn(path, node)
+				return nil
+			})
+		},
+	}, &root)
+}
+
+// applyConfigMappingEdit replaces the value of a YAML scalar with a formatted
+// mapping. Literal block scalars are rewritten line by line, and single line
+// scalars are rewritten in their original style when the formatted mapping can
+// be represented with it, otherwise as a double quoted or literal block scalar.
+// Returns false when the scalar cannot be rewritten.
+func applyConfigMappingEdit(lines []string, e configMappingEdit) ([]string, bool) {
+	line, col := e.node.Line-1, e.node.Column-1
+	if line < 0 || line >= len(lines) {
+		return lines, false
+	}
+	lineRunes := []rune(lines[line])
+	if col < 0 || col >= len(lineRunes) {
+		return lines, false
+	}
+
+	switch e.node.Style {
+	case yaml.LiteralStyle:
+		// Explicit indentation indicators are not supported.
+		if strings.ContainsAny(string(lineRunes[col:]), "123456789") {
+			return lines, false
+		}
+
+		start, indent := line+1, -1
+		end := start
+		for ; end < len(lines); end++ {
+			trimmed := strings.TrimLeft(lines[end], " ")
+			if trimmed == "" {
+				continue
+			}
+			lineIndent := len(lines[end]) - len(trimmed)
+			if indent == -1 {
+				indent = lineIndent
+			}
+			if lineIndent < indent {
+				break
+			}
+		}
+		// Trailing blank lines are left as they are.
+		for end > start && strings.TrimSpace(lines[end-1]) == "" {
+			end--
+		}
+		if indent <= 0 {
+			return lines, false
+		}
+
+		var replaced []string
+		for _, l := range strings.Split(e.formatted, "\n") {
+			if l != "" {
+				l = strings.Repeat(" ", indent) + l
+			}
+			replaced = append(replaced, l)
+		}
+		return append(lines[:start], append(replaced, lines[end:]...)...), true
+
+	case yaml.DoubleQuotedStyle, yaml.SingleQuotedStyle, 0:
+		// Scalars that span multiple lines of the config are not supported.
+		remainder := string(lineRunes[col:])
+		raw, ok := singleLineScalarRaw(e.node, remainder)
+		if !ok {
+			return lines, false
+		}
+		prefix, suffix := string(lineRunes[:col]), remainder[len(raw):]
+
+		if !strings.Contains(e.formatted, "\n") {
+			lines[line] = prefix + singleLineScalar(e.node.Style, e.formatted) + suffix
+			return lines, true
+		}
+
+		// A mapping formatted over multiple lines is rewritten as a literal
+		// block scalar indented beneath the key of the field.
+		indent := blockKeyIndent(prefix) + 2
+		replaced := []string{prefix + "|" + suffix}
+		for _, l := range strings.Split(e.formatted, "\n") {
+			if l != "" {
+				l = strings.Repeat(" ", indent) + l
+			}
+			replaced = append(replaced, l)
+		}
+		return append(lines[:line], append(replaced, lines[line+1:]...)...), true
+	}
+	return lines, false
+}
+
+// singleLineScalarRaw returns the raw representation of a scalar at the
+// beginning of a line remainder, or false if the scalar does not fit within
+// the line.
+func singleLineScalarRaw(node *yaml.Node, remainder string) (raw string, ok bool) {
+	switch node.Style {
+	case yaml.SingleQuotedStyle:
+		raw = "'" + strings.ReplaceAll(node.Value, "'", "''") + "'"
+	case yaml.DoubleQuotedStyle:
+		escaped := false
+		for i, r := range remainder {
+			if i == 0 {
+				continue
+			}
+			if r == '"' && !escaped {
+				raw = remainder[:i+1]
+				break
+			}
+			escaped = r == '\\' && !escaped
+		}
+	default:
+		raw = node.Value
+	}
+	if raw == "" || !strings.HasPrefix(remainder, raw) {
+		return "", false
+	}
+	return raw, true
+}
+
+// singleLineScalar returns the representation of a single line value in the
+// given style, or double quoted when the value cannot be represented as a valid
+// plain scalar.
+func singleLineScalar(style yaml.Style, value string) string {
+	switch style {
+	case yaml.SingleQuotedStyle:
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	case 0:
+		var v map[string]any
+		if err := yaml.Unmarshal([]byte("v: "+value), &v); err == nil && v["v"] == value {
+			return value
+		}
+	}
+
+	// JSON strings are valid double quoted YAML scalars.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(value)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// blockKeyIndent returns the indentation of the mapping key that precedes a
+// value on a line, skipping any sequence entry indicators.
+func blockKeyIndent(prefix string) int {
+	indent := 0
+	for indent < len(prefix) {
+		switch {
+		case prefix[indent] == ' ':
+			indent++
+		case strings.HasPrefix(prefix[indent:], "- "):
+			indent += 2
+		default:
+			return indent
+		}
+	}
+	return indent
+}
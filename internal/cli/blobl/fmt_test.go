@@ -0,0 +1,229 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package blobl_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+
+	"github.com/redpanda-data/benthos/v4/internal/cli/blobl"
+	"github.com/redpanda-data/benthos/v4/internal/cli/common"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+)
+
+func runFmtCmd(t testing.TB, args ...string) (stdout, stderr string, err error) {
+	t.Helper()
+
+	var outBuf, errBuf bytes.Buffer
+	opts := common.NewCLIOpts("1.2.3", "now")
+	opts.Stdout, opts.Stderr = &outBuf, &errBuf
+
+	app := &cli.App{
+		Name:     "benthos",
+		Commands: []*cli.Command{blobl.CliCommand(opts)},
+	}
+	err = app.Run(append([]string{"benthos", "blobl", "fmt"}, args...))
+	return outBuf.String(), errBuf.String(), err
+}
+
+func TestFmtMappingFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	unformatted := filepath.Join(dir, "a.blobl")
+	require.NoError(t, os.WriteFile(unformatted, []byte(`root.foo=this.foo # keep me
+root.bar = this.bar.(b->{"b":b})`), 0o644))
+
+	formatted := filepath.Join(dir, "b.blobl")
+	require.NoError(t, os.WriteFile(formatted, []byte("root = this\n"), 0o644))
+
+	_, stderr, err := runFmtCmd(t, unformatted)
+	require.Error(t, err)
+	assert.Contains(t, stderr, "expected whitespace")
+
+	require.NoError(t, os.WriteFile(unformatted, []byte(`root.foo = this.foo # keep me
+root.bar = this.bar.(b  ->   {"b":b})`), 0o644))
+
+	stdout, _, err := runFmtCmd(t, unformatted)
+	require.NoError(t, err)
+	assert.Equal(t, `root.foo = this.foo # keep me
+root.bar = this.bar.(b -> {"b": b})
+`, stdout)
+
+	stdout, _, err = runFmtCmd(t, "--check", dir+"/...")
+	require.Error(t, err)
+	assert.Equal(t, unformatted+"\n", stdout)
+
+	stdout, _, err = runFmtCmd(t, "-w", dir+"/...")
+	require.NoError(t, err)
+	assert.Empty(t, stdout)
+
+	stdout, _, err = runFmtCmd(t, "--check", dir+"/...")
+	require.NoError(t, err)
+	assert.Empty(t, stdout)
+
+	rewritten, err := os.ReadFile(unformatted)
+	require.NoError(t, err)
+	assert.Equal(t, `root.foo = this.foo # keep me
+root.bar = this.bar.(b -> {"b": b})
+`, string(rewritten))
+}
+
+func TestFmtMappingFileErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "bad.blobl")
+	require.NoError(t, os.WriteFile(path, []byte("root = this.(\n"), 0o644))
+
+	_, stderr, err := runFmtCmd(t, "-w", path)
+	require.Error(t, err)
+	assert.Contains(t, stderr, path+": line 2 char 1")
+
+	_, _, err = runFmtCmd(t, "-w", "--check", path)
+	require.Error(t, err)
+}
+
+func TestFmtConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`# A config comment
+input:
+  generate:
+    mapping: root.id   =   uuid_v4()  # Not part of the mapping
+    interval: 1s
+
+pipeline:
+  processors:
+    - mapping: |
+        root.a =  this.a+1
+        # A mapping comment
+        root.b = if this.b
+        { "yes" } else { "no" }
+
+    - mutation: 'root.c = this.c.(c -> {"c":c})'
+    - bloblang: "root.d = this.d.join(\",\")"
+    - branch:
+        request_map: |-
+            root = this
+        processors:
+          - mapping: root = this.ok
+        result_map: root.e  =  this
+
+output:
+  drop: {}
+`), 0o644))
+
+	_, stderr, err := runFmtCmd(t, "-w", path)
+	require.NoError(t, err, stderr)
+
+	rewritten, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `# A config comment
+input:
+  generate:
+    mapping: root.id = uuid_v4()  # Not part of the mapping
+    interval: 1s
+
+pipeline:
+  processors:
+    - mapping: |
+        root.a = this.a + 1
+        # A mapping comment
+        root.b = if this.b { "yes" } else { "no" }
+
+    - mutation: 'root.c = this.c.(c -> {"c": c})'
+    - bloblang: "root.d = this.d.join(\",\")"
+    - branch:
+        request_map: |-
+            root = this
+        processors:
+          - mapping: root = this.ok
+        result_map: root.e = this
+
+output:
+  drop: {}
+`, string(rewritten))
+
+	stdout, _, err := runFmtCmd(t, "--check", path)
+	require.NoError(t, err)
+	assert.Empty(t, stdout)
+}
+
+func TestFmtConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+pipeline:
+  processors:
+    - mapping: root = this.(
+`), 0o644))
+
+	_, stderr, err := runFmtCmd(t, path)
+	require.Error(t, err)
+	assert.Contains(t, stderr, "line 4: field pipeline.processors.0.mapping")
+}
+
+func TestFmtConfigRestyledScalars(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`pipeline:
+  processors:
+    - mapping: root = {"a":1}
+    - mutation: "root.a  =  1\nroot.b = 2"
+`), 0o644))
+
+	stdout, _, err := runFmtCmd(t, "--check", path)
+	require.Error(t, err)
+	assert.Equal(t, path+"\n", stdout)
+
+	_, stderr, err := runFmtCmd(t, "-w", path)
+	require.NoError(t, err, stderr)
+
+	rewritten, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `pipeline:
+  processors:
+    - mapping: "root = {\"a\": 1}"
+    - mutation: |
+        root.a = 1
+        root.b = 2
+`, string(rewritten))
+
+	stdout, _, err = runFmtCmd(t, "--check", path)
+	require.NoError(t, err)
+	assert.Empty(t, stdout)
+}
+
+func TestFmtConfigUnsupportedScalars(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "config.yaml")
+	input := []byte(`pipeline:
+  processors:
+    - mapping: 'root.a  =  1
+
+        root.b = 2'
+`)
+	require.NoError(t, os.WriteFile(path, input, 0o644))
+
+	_, stderr, err := runFmtCmd(t, "--check", path)
+	require.Error(t, err)
+	assert.Contains(t, stderr, "line 3: field pipeline.processors.0.mapping: unable to rewrite mapping")
+
+	_, stderr, err = runFmtCmd(t, "-w", path)
+	require.Error(t, err)
+	assert.Contains(t, stderr, "line 3: field pipeline.processors.0.mapping: unable to rewrite mapping")
+
+	rewritten, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(input), string(rewritten))
+}
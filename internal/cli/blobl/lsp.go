@@ -0,0 +1,675 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package blobl
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/redpanda-data/benthos/v4/internal/bloblang"
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/parser"
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/query"
+	"github.com/redpanda-data/benthos/v4/internal/cli/common"
+)
+
+func runLSP(c *cli.Context, opts *common.CLIOpts) error {
+	return newLSPServer(opts.BloblEnvironment, opts.Stdout).serve(os.Stdin)
+}
+
+//------------------------------------------------------------------------------
+
+// The subset of the Language Server Protocol used by the server, see:
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/
+
+const (
+	lspErrMethodNotFound = -32601
+	lspErrInvalidParams  = -32602
+
+	lspSeverityError = 1
+
+	lspCompletionKindMethod   = 2
+	lspCompletionKindFunction = 3
+	lspCompletionKindKeyword  = 14
+
+	lspCompletionTagDeprecated = 1
+
+	lspTextDocumentSyncFull = 1
+)
+
+type lspRequest struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type lspResponseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspLocation struct {
+	URI   string   `json:"uri"`
+	Range lspRange `json:"range"`
+}
+
+type lspTextDocumentPositionParams struct {
+	TextDocument struct {
+		URI string `json:"uri"`
+	} `json:"textDocument"`
+	Position lspPosition `json:"position"`
+}
+
+type lspDidOpenParams struct {
+	TextDocument struct {
+		URI  string `json:"uri"`
+		Text string `json:"text"`
+	} `json:"textDocument"`
+}
+
+type lspDidChangeParams struct {
+	TextDocument struct {
+		URI string `json:"uri"`
+	} `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+}
+
+type lspDidCloseParams struct {
+	TextDocument struct {
+		URI string `json:"uri"`
+	} `json:"textDocument"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspMarkupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type lspCompletionItem struct {
+	Label         string            `json:"label"`
+	Kind          int               `json:"kind"`
+	Detail        string            `json:"detail,omitempty"`
+	Documentation *lspMarkupContent `json:"documentation,omitempty"`
+	Tags          []int             `json:"tags,omitempty"`
+}
+
+type lspHover struct {
+	Contents lspMarkupContent `json:"contents"`
+	Range    *lspRange        `json:"range,omitempty"`
+}
+
+//------------------------------------------------------------------------------
+
+// lspServer provides diagnostics, completion, hover documentation and
+// go-to-definition for Bloblang mappings to editors over stdio.
+type lspServer struct {
+	env *bloblang.Environment
+	out io.Writer
+
+	docs     map[string][]rune
+	shutdown bool
+}
+
+func newLSPServer(env *bloblang.Environment, out io.Writer) *lspServer {
+	return &lspServer{
+		env:  env,
+		out:  out,
+		docs: map[string][]rune{},
+	}
+}
+
+var errLSPExit = errors.New("exit")
+
+func (l *lspServer) serve(in io.Reader) error {
+	rdr := textproto.NewReader(bufio.NewReader(in))
+	for {
+		header, err := rdr.ReadMIMEHeader()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read message header: %w", err)
+		}
+
+		length, err := strconv.Atoi(header.Get("Content-Length"))
+		if err != nil {
+			return fmt.Errorf("invalid message content length: %w", err)
+		}
+
+		body := make([]byte, length)
+		if _, err := io.ReadFull(rdr.R, body); err != nil {
+			return fmt.Errorf("failed to read message body: %w", err)
+		}
+
+		var req lspRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			return fmt.Errorf("failed to parse message: %w", err)
+		}
+
+		if err := l.handle(req); err != nil {
+			if errors.Is(err, errLSPExit) {
+				if !l.shutdown {
+					return errors.New("received exit notification without a shutdown request")
+				}
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (l *lspServer) write(msg map[string]any) error {
+	msg["jsonrpc"] = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(l.out, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = l.out.Write(body)
+	return err
+}
+
+func (l *lspServer) respond(id json.RawMessage, result any) error {
+	return l.write(map[string]any{"id": id, "result": result})
+}
+
+func (l *lspServer) respondErr(id json.RawMessage, code int, msg string) error {
+	return l.write(map[string]any{"id": id, "error": lspResponseError{Code: code, Message: msg}})
+}
+
+func (l *lspServer) notify(method string, params any) error {
+	return l.write(map[string]any{"method": method, "params": params})
+}
+
+func (l *lspServer) handle(req lspRequest) error {
+	isRequest := len(req.ID) > 0
+
+	var result any
+	var err error
+
+	switch req.Method {
+	case "initialize":
+		result = map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync": lspTextDocumentSyncFull,
+				"completionProvider": map[string]any{
+					"triggerCharacters": []string{"."},
+				},
+				"hoverProvider":      true,
+				"definitionProvider": true,
+			},
+			"serverInfo": map[string]any{
+				"name": "blobl",
+			},
+		}
+	case "shutdown":
+		l.shutdown = true
+	case "exit":
+		return errLSPExit
+	case "textDocument/didOpen":
+		var p lspDidOpenParams
+		if err = json.Unmarshal(req.Params, &p); err == nil {
+			l.docs[p.TextDocument.URI] = []rune(p.TextDocument.Text)
+			err = l.publishDiagnostics(p.TextDocument.URI)
+		}
+	case "textDocument/didChange":
+		var p lspDidChangeParams
+		if err = json.Unmarshal(req.Params, &p); err == nil && len(p.ContentChanges) > 0 {
+			l.docs[p.TextDocument.URI] = []rune(p.ContentChanges[len(p.ContentChanges)-1].Text)
+			err = l.publishDiagnostics(p.TextDocument.URI)
+		}
+	case "textDocument/didClose":
+		var p lspDidCloseParams
+		if err = json.Unmarshal(req.Params, &p); err == nil {
+			delete(l.docs, p.TextDocument.URI)
+			err = l.notify("textDocument/publishDiagnostics", map[string]any{
+				"uri":         p.TextDocument.URI,
+				"diagnostics": []lspDiagnostic{},
+			})
+		}
+	case "textDocument/completion":
+		var p lspTextDocumentPositionParams
+		if err = json.Unmarshal(req.Params, &p); err == nil {
+			result = l.completion(p)
+		}
+	case "textDocument/hover":
+		var p lspTextDocumentPositionParams
+		if err = json.Unmarshal(req.Params, &p); err == nil {
+			if h := l.hover(p); h != nil {
+				result = h
+			}
+		}
+	case "textDocument/definition":
+		var p lspTextDocumentPositionParams
+		if err = json.Unmarshal(req.Params, &p); err == nil {
+			if loc := l.definition(p); loc != nil {
+				result = loc
+			}
+		}
+	default:
+		if isRequest {
+			return l.respondErr(req.ID, lspErrMethodNotFound, fmt.Sprintf("method not supported: %v", req.Method))
+		}
+		return nil
+	}
+
+	if !isRequest {
+		// Notifications are not responded to, and therefore malformed ones
+		// are ignored.
+		return nil
+	}
+	if err != nil {
+		return l.respondErr(req.ID, lspErrInvalidParams, err.Error())
+	}
+	return l.respond(req.ID, result)
+}
+
+//------------------------------------------------------------------------------
+
+// uriToPath returns the file path of a document URI, or an empty string for
+// documents that do not exist on disk.
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+func pathToURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// offsetToPosition converts a rune offset into a position, where characters
+// are counted in UTF-16 code units as required by the protocol.
+func offsetToPosition(text []rune, offset int) lspPosition {
+	var pos lspPosition
+	for i, r := range text {
+		if i >= offset {
+			break
+		}
+		if r == '\n' {
+			pos.Line++
+			pos.Character = 0
+		} else {
+			pos.Character += utf16.RuneLen(r)
+		}
+	}
+	return pos
+}
+
+// positionToOffset converts a position into a rune offset, clamping positions
+// that exceed the length of a line or the document.
+func positionToOffset(text []rune, pos lspPosition) int {
+	line, char := 0, 0
+	for i, r := range text {
+		if line == pos.Line && (char >= pos.Character || r == '\n') {
+			return i
+		}
+		if r == '\n' {
+			line++
+			char = 0
+		} else {
+			char += utf16.RuneLen(r)
+		}
+	}
+	return len(text)
+}
+
+func (l *lspServer) envFor(uri string) *bloblang.Environment {
+	if path := uriToPath(uri); path != "" {
+		return l.env.WithImporterRelativeToFile(path)
+	}
+	return l.env
+}
+
+func (l *lspServer) diagnostics(uri string) []lspDiagnostic {
+	text := l.docs[uri]
+
+	_, err := l.envFor(uri).NewMapping(string(text))
+	if err == nil {
+		return []lspDiagnostic{}
+	}
+
+	diag := lspDiagnostic{
+		Severity: lspSeverityError,
+		Source:   "bloblang",
+		Message:  err.Error(),
+	}
+
+	var pErr *parser.Error
+	if errors.As(err, &pErr) {
+		offset := len(text) - len(pErr.Input)
+		diag.Message = pErr.ErrorMessage()
+		diag.Range.Start = offsetToPosition(text, offset)
+		diag.Range.End = diag.Range.Start
+		if offset < len(text) && text[offset] != '\n' {
+			diag.Range.End = offsetToPosition(text, offset+1)
+		}
+	}
+	return []lspDiagnostic{diag}
+}
+
+func (l *lspServer) publishDiagnostics(uri string) error {
+	return l.notify("textDocument/publishDiagnostics", map[string]any{
+		"uri":         uri,
+		"diagnostics": l.diagnostics(uri),
+	})
+}
+
+//------------------------------------------------------------------------------
+
+func isIdentRune(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// wordAt returns the bounds of the identifier surrounding an offset.
+func wordAt(text []rune, offset int) (start, end int) {
+	start, end = offset, offset
+	for start > 0 && isIdentRune(text[start-1]) {
+		start--
+	}
+	for end < len(text) && isIdentRune(text[end]) {
+		end++
+	}
+	return
+}
+
+func paramsSignature(name string, params query.Params) string {
+	if params.Variadic {
+		return name + "(...)"
+	}
+	args := make([]string, 0, len(params.Definitions))
+	for _, d := range params.Definitions {
+		arg := d.Name + ": " + string(d.ValueType)
+		if d.DefaultValue != nil {
+			if b, err := json.Marshal(*d.DefaultValue); err == nil {
+				arg += " = " + string(b)
+			}
+		} else if d.IsOptional {
+			arg += "?"
+		}
+		args = append(args, arg)
+	}
+	return name + "(" + strings.Join(args, ", ") + ")"
+}
+
+func methodDescription(spec query.MethodSpec) string {
+	if spec.Description != "" || len(spec.Categories) == 0 {
+		return spec.Description
+	}
+	return spec.Categories[0].Description
+}
+
+func markdownDocs(signature, description string) *lspMarkupContent {
+	return &lspMarkupContent{
+		Kind:  "markdown",
+		Value: "```\n" + signature + "\n```\n\n" + strings.TrimSpace(description),
+	}
+}
+
+var lspKeywords = []string{
+	"deleted", "else", "false", "if", "import", "let", "map", "match", "meta", "null", "root", "this", "true",
+}
+
+func (l *lspServer) completion(p lspTextDocumentPositionParams) []lspCompletionItem {
+	text := l.docs[p.TextDocument.URI]
+	offset := positionToOffset(text, p.Position)
+
+	start, _ := wordAt(text, offset)
+	prefix := string(text[start:offset])
+	isMethod := start > 0 && text[start-1] == '.'
+
+	items := []lspCompletionItem{}
+	if isMethod {
+		l.env.WalkMethods(func(name string, spec query.MethodSpec) {
+			if spec.Status == query.StatusHidden || !strings.HasPrefix(name, prefix) {
+				return
+			}
+			item := lspCompletionItem{
+				Label:         name,
+				Kind:          lspCompletionKindMethod,
+				Detail:        paramsSignature(name, spec.Params),
+				Documentation: markdownDocs(paramsSignature(name, spec.Params), methodDescription(spec)),
+			}
+			if spec.Status == query.StatusDeprecated {
+				item.Tags = []int{lspCompletionTagDeprecated}
+			}
+			items = append(items, item)
+		})
+	} else {
+		l.env.WalkFunctions(func(name string, spec query.FunctionSpec) {
+			if spec.Status == query.StatusHidden || !strings.HasPrefix(name, prefix) {
+				return
+			}
+			item := lspCompletionItem{
+				Label:         name,
+				Kind:          lspCompletionKindFunction,
+				Detail:        paramsSignature(name, spec.Params),
+				Documentation: markdownDocs(paramsSignature(name, spec.Params), spec.Description),
+			}
+			if spec.Status == query.StatusDeprecated {
+				item.Tags = []int{lspCompletionTagDeprecated}
+			}
+			items = append(items, item)
+		})
+		for _, k := range lspKeywords {
+			if strings.HasPrefix(k, prefix) {
+				items = append(items, lspCompletionItem{Label: k, Kind: lspCompletionKindKeyword})
+			}
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Label < items[j].Label
+	})
+	return items
+}
+
+func (l *lspServer) hover(p lspTextDocumentPositionParams) *lspHover {
+	text := l.docs[p.TextDocument.URI]
+	offset := positionToOffset(text, p.Position)
+
+	start, end := wordAt(text, offset)
+	if start == end {
+		return nil
+	}
+
+	// Only function and method calls are documented, which are identifiers
+	// followed by an opening bracket.
+	next := end
+	for next < len(text) && (text[next] == ' ' || text[next] == '\t') {
+		next++
+	}
+	if next >= len(text) || text[next] != '(' {
+		return nil
+	}
+
+	name := string(text[start:end])
+	var docs *lspMarkupContent
+	if start > 0 && text[start-1] == '.' {
+		l.env.WalkMethods(func(n string, spec query.MethodSpec) {
+			if n == name {
+				docs = markdownDocs(paramsSignature(name, spec.Params), methodDescription(spec))
+			}
+		})
+	} else {
+		l.env.WalkFunctions(func(n string, spec query.FunctionSpec) {
+			if n == name {
+				docs = markdownDocs(paramsSignature(name, spec.Params), spec.Description)
+			}
+		})
+	}
+	if docs == nil {
+		return nil
+	}
+	return &lspHover{
+		Contents: *docs,
+		Range: &lspRange{
+			Start: offsetToPosition(text, start),
+			End:   offsetToPosition(text, end),
+		},
+	}
+}
+
+//------------------------------------------------------------------------------
+
+var (
+	lspMapDeclRegexp = regexp.MustCompile(`(?m)^[ \t]*map[ \t]+(?:"([^"]+)"|([a-zA-Z0-9_-]+))`)
+	lspImportRegexp  = regexp.MustCompile(`(?m)^[ \t]*import[ \t]+"([^"]+)"`)
+	lspApplyRegexp   = regexp.MustCompile(`apply\(\s*$`)
+)
+
+// quotedStringAt returns the contents of a quoted string surrounding an
+// offset, along with the text of the line preceding it.
+func quotedStringAt(text []rune, offset int) (str, preceding string, ok bool) {
+	lineStart := offset
+	for lineStart > 0 && text[lineStart-1] != '\n' {
+		lineStart--
+	}
+
+	open := -1
+	for i := lineStart; i < offset && i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '"':
+			if open == -1 {
+				open = i
+			} else {
+				open = -1
+			}
+		}
+	}
+	if open == -1 {
+		return "", "", false
+	}
+
+	end := open + 1
+	for ; end < len(text) && text[end] != '"' && text[end] != '\n'; end++ {
+		if text[end] == '\\' {
+			end++
+		}
+	}
+	if end >= len(text) || text[end] != '"' {
+		return "", "", false
+	}
+
+	unquoted, err := strconv.Unquote(string(text[open : end+1]))
+	if err != nil {
+		return "", "", false
+	}
+	return unquoted, string(text[lineStart:open]), true
+}
+
+// resolveImport returns the path of an imported file relative to the file
+// that imports it, following the semantics of the parser.
+func resolveImport(fromPath, importPath string) string {
+	if filepath.IsAbs(importPath) || fromPath == "" {
+		return importPath
+	}
+	return filepath.Join(filepath.Dir(fromPath), importPath)
+}
+
+// findMapDecl searches a mapping for the declaration of a map, following any
+// imports when it isn't declared within the mapping itself.
+func findMapDecl(uri string, text []rune, name string, seen map[string]struct{}) *lspLocation {
+	str := string(text)
+	for _, m := range lspMapDeclRegexp.FindAllStringSubmatchIndex(str, -1) {
+		start, end := m[2], m[3]
+		if start == -1 {
+			start, end = m[4], m[5]
+		}
+		if str[start:end] != name {
+			continue
+		}
+		startRunes := len([]rune(str[:start]))
+		endRunes := startRunes + len([]rune(str[start:end]))
+		return &lspLocation{
+			URI: uri,
+			Range: lspRange{
+				Start: offsetToPosition(text, startRunes),
+				End:   offsetToPosition(text, endRunes),
+			},
+		}
+	}
+
+	fromPath := uriToPath(uri)
+	for _, m := range lspImportRegexp.FindAllStringSubmatch(str, -1) {
+		path := resolveImport(fromPath, m[1])
+		if _, exists := seen[path]; exists {
+			continue
+		}
+		seen[path] = struct{}{}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if loc := findMapDecl(pathToURI(path), []rune(string(contents)), name, seen); loc != nil {
+			return loc
+		}
+	}
+	return nil
+}
+
+func (l *lspServer) definition(p lspTextDocumentPositionParams) *lspLocation {
+	uri := p.TextDocument.URI
+	text := l.docs[uri]
+	offset := positionToOffset(text, p.Position)
+
+	str, preceding, ok := quotedStringAt(text, offset)
+	if !ok {
+		return nil
+	}
+
+	if strings.TrimSpace(preceding) == "import" {
+		path := resolveImport(uriToPath(uri), str)
+		if _, err := os.Stat(path); err != nil {
+			return nil
+		}
+		return &lspLocation{URI: pathToURI(path)}
+	}
+
+	if lspApplyRegexp.MatchString(preceding) {
+		return findMapDecl(uri, text, str, map[string]struct{}{})
+	}
+	return nil
+}
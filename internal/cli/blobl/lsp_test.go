@@ -0,0 +1,247 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package blobl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/internal/bloblang"
+)
+
+func lspFrame(t testing.TB, id int, method string, params any) string {
+	t.Helper()
+
+	msg := map[string]any{"jsonrpc": "2.0", "method": method, "params": params}
+	if id > 0 {
+		msg["id"] = id
+	}
+	b, err := json.Marshal(msg)
+	require.NoError(t, err)
+	return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(b), b)
+}
+
+func lspRun(t testing.TB, frames ...string) []map[string]any {
+	t.Helper()
+
+	var in, out bytes.Buffer
+	for _, f := range frames {
+		in.WriteString(f)
+	}
+	require.NoError(t, newLSPServer(bloblang.GlobalEnvironment(), &out).serve(&in))
+
+	var msgs []map[string]any
+	rdr := textproto.NewReader(bufio.NewReader(&out))
+	for {
+		header, err := rdr.ReadMIMEHeader()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		length, err := strconv.Atoi(header.Get("Content-Length"))
+		require.NoError(t, err)
+
+		body := make([]byte, length)
+		_, err = io.ReadFull(rdr.R, body)
+		require.NoError(t, err)
+
+		var msg map[string]any
+		require.NoError(t, json.Unmarshal(body, &msg))
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+func lspOpen(uri, text string) map[string]any {
+	return map[string]any{
+		"textDocument": map[string]any{"uri": uri, "languageId": "bloblang", "version": 1, "text": text},
+	}
+}
+
+func lspPos(uri string, line, char int) map[string]any {
+	return map[string]any{
+		"textDocument": map[string]any{"uri": uri},
+		"position":     map[string]any{"line": line, "character": char},
+	}
+}
+
+func TestLSPLifecycle(t *testing.T) {
+	msgs := lspRun(t,
+		lspFrame(t, 1, "initialize", map[string]any{}),
+		lspFrame(t, 0, "initialized", map[string]any{}),
+		lspFrame(t, 2, "nope", map[string]any{}),
+		lspFrame(t, 3, "shutdown", nil),
+		lspFrame(t, 0, "exit", nil),
+	)
+	require.Len(t, msgs, 3)
+
+	assert.Equal(t, float64(1), msgs[0]["id"])
+	caps := msgs[0]["result"].(map[string]any)["capabilities"].(map[string]any)
+	assert.Equal(t, true, caps["hoverProvider"])
+	assert.Equal(t, true, caps["definitionProvider"])
+
+	assert.Equal(t, float64(2), msgs[1]["id"])
+	assert.Equal(t, float64(lspErrMethodNotFound), msgs[1]["error"].(map[string]any)["code"])
+
+	assert.Equal(t, float64(3), msgs[2]["id"])
+	assert.Contains(t, msgs[2], "result")
+	assert.Nil(t, msgs[2]["result"])
+}
+
+func TestLSPExitWithoutShutdown(t *testing.T) {
+	var out bytes.Buffer
+	in := bytes.NewBufferString(lspFrame(t, 0, "exit", nil))
+	require.Error(t, newLSPServer(bloblang.GlobalEnvironment(), &out).serve(in))
+}
+
+func TestLSPDiagnostics(t *testing.T) {
+	uri := "untitled:mapping.blobl"
+	msgs := lspRun(t,
+		lspFrame(t, 0, "textDocument/didOpen", lspOpen(uri, "root.foo = this.bar\nroot.bar = this.baz.uppercase(\n")),
+		lspFrame(t, 0, "textDocument/didChange", map[string]any{
+			"textDocument":   map[string]any{"uri": uri, "version": 2},
+			"contentChanges": []any{map[string]any{"text": "root.foo = this.bar\n"}},
+		}),
+	)
+	require.Len(t, msgs, 2)
+
+	assert.Equal(t, "textDocument/publishDiagnostics", msgs[0]["method"])
+	diags := msgs[0]["params"].(map[string]any)["diagnostics"].([]any)
+	require.Len(t, diags, 1)
+
+	diag := diags[0].(map[string]any)
+	assert.Equal(t, map[string]any{"line": float64(2), "character": float64(0)}, diag["range"].(map[string]any)["start"])
+	assert.Contains(t, diag["message"], "expected")
+
+	diags = msgs[1]["params"].(map[string]any)["diagnostics"].([]any)
+	assert.Empty(t, diags)
+}
+
+func TestLSPCompletion(t *testing.T) {
+	uri := "untitled:mapping.blobl"
+	msgs := lspRun(t,
+		lspFrame(t, 0, "textDocument/didOpen", lspOpen(uri, "root.foo = this.bar.uppe\nroot.bar = uuid_")),
+		lspFrame(t, 1, "textDocument/completion", lspPos(uri, 0, 24)),
+		lspFrame(t, 2, "textDocument/completion", lspPos(uri, 1, 16)),
+	)
+	require.Len(t, msgs, 3)
+
+	methods := msgs[1]["result"].([]any)
+	require.NotEmpty(t, methods)
+	uppercase := methods[0].(map[string]any)
+	assert.Equal(t, "uppercase", uppercase["label"])
+	assert.Equal(t, float64(lspCompletionKindMethod), uppercase["kind"])
+	assert.Contains(t, uppercase["documentation"].(map[string]any)["value"], "uppercase()")
+
+	functions := msgs[2]["result"].([]any)
+	require.NotEmpty(t, functions)
+	for _, f := range functions {
+		assert.Contains(t, f.(map[string]any)["label"], "uuid_")
+		assert.Equal(t, float64(lspCompletionKindFunction), f.(map[string]any)["kind"])
+	}
+}
+
+func TestLSPHover(t *testing.T) {
+	uri := "untitled:mapping.blobl"
+	msgs := lspRun(t,
+		lspFrame(t, 0, "textDocument/didOpen", lspOpen(uri, `root.foo = this.bar.replace_all("a", "b")`+"\n"+`root.bar = now()`)),
+		lspFrame(t, 1, "textDocument/hover", lspPos(uri, 0, 22)),
+		lspFrame(t, 2, "textDocument/hover", lspPos(uri, 1, 12)),
+		lspFrame(t, 3, "textDocument/hover", lspPos(uri, 0, 17)),
+	)
+	require.Len(t, msgs, 4)
+
+	hover := msgs[1]["result"].(map[string]any)
+	assert.Contains(t, hover["contents"].(map[string]any)["value"], "replace_all(old: string, new: string)")
+	assert.Equal(t, map[string]any{
+		"start": map[string]any{"line": float64(0), "character": float64(20)},
+		"end":   map[string]any{"line": float64(0), "character": float64(31)},
+	}, hover["range"])
+
+	hover = msgs[2]["result"].(map[string]any)
+	assert.Contains(t, hover["contents"].(map[string]any)["value"], "now()")
+
+	// Fields are not documented.
+	assert.Nil(t, msgs[3]["result"])
+}
+
+func TestLSPDefinition(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "common.blobl"), []byte(`
+map imported {
+  root = this
+}
+`), 0o644))
+
+	mainPath := filepath.Join(dir, "main.blobl")
+	uri := pathToURI(mainPath)
+
+	msgs := lspRun(t,
+		lspFrame(t, 0, "textDocument/didOpen", lspOpen(uri, `import "./common.blobl"
+
+map local {
+  root.v = this
+}
+
+root.a = this.apply("local")
+root.b = this.apply("imported")
+`)),
+		lspFrame(t, 1, "textDocument/definition", lspPos(uri, 6, 22)),
+		lspFrame(t, 2, "textDocument/definition", lspPos(uri, 7, 22)),
+		lspFrame(t, 3, "textDocument/definition", lspPos(uri, 0, 12)),
+		lspFrame(t, 4, "textDocument/definition", lspPos(uri, 6, 3)),
+	)
+	require.Len(t, msgs, 5)
+
+	assert.Empty(t, msgs[0]["params"].(map[string]any)["diagnostics"])
+
+	assert.Equal(t, map[string]any{
+		"uri": uri,
+		"range": map[string]any{
+			"start": map[string]any{"line": float64(2), "character": float64(4)},
+			"end":   map[string]any{"line": float64(2), "character": float64(9)},
+		},
+	}, msgs[1]["result"])
+
+	loc := msgs[2]["result"].(map[string]any)
+	assert.Equal(t, pathToURI(filepath.Join(dir, "common.blobl")), loc["uri"])
+	assert.Equal(t, map[string]any{"line": float64(1), "character": float64(4)}, loc["range"].(map[string]any)["start"])
+
+	loc = msgs[3]["result"].(map[string]any)
+	assert.Equal(t, pathToURI(filepath.Join(dir, "common.blobl")), loc["uri"])
+
+	assert.Nil(t, msgs[4]["result"])
+}
+
+func TestLSPPositions(t *testing.T) {
+	text := []rune("a😀b\ncd")
+
+	for _, test := range []struct {
+		offset int
+		pos    lspPosition
+	}{
+		{offset: 0, pos: lspPosition{Line: 0, Character: 0}},
+		{offset: 2, pos: lspPosition{Line: 0, Character: 3}},
+		{offset: 4, pos: lspPosition{Line: 1, Character: 0}},
+		{offset: 6, pos: lspPosition{Line: 1, Character: 2}},
+	} {
+		assert.Equal(t, test.pos, offsetToPosition(text, test.offset), test.offset)
+		assert.Equal(t, test.offset, positionToOffset(text, test.pos), test.offset)
+	}
+
+	// Positions beyond the end of a line are clamped.
+	assert.Equal(t, 3, positionToOffset(text, lspPosition{Line: 0, Character: 10}))
+}
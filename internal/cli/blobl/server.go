@@ -162,9 +162,10 @@ func runServer(c *cli.Context) error {
 		fSync.update(req.Input, req.Mapping)
 
 		res := struct {
-			ParseError   string `json:"parse_error"`
-			MappingError string `json:"mapping_error"`
-			Result       string `json:"result"`
+			ParseError   string       `json:"parse_error"`
+			MappingError string       `json:"mapping_error"`
+			Result       string       `json:"result"`
+			Trace        []traceEntry `json:"trace"`
 		}{}
 		defer func() {
 			resBytes, err := json.Marshal(res)
@@ -186,7 +187,8 @@ func runServer(c *cli.Context) error {
 		}
 
 		execCache := newExecCache()
-		output, err := execCache.executeMapping(exec, false, true, []byte(req.Input))
+		output, trace, err := execCache.executeMappingTraced(exec, false, true, []byte(req.Input))
+		res.Trace = newTraceEntries(trace)
 		if err != nil {
 			res.MappingError = err.Error()
 		} else {
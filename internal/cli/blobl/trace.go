@@ -0,0 +1,101 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package blobl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/mapping"
+	"github.com/redpanda-data/benthos/v4/internal/value"
+)
+
+// traceEntry is a rendered representation of the execution of a single
+// mapping statement.
+type traceEntry struct {
+	Line      int      `json:"line"`
+	Statement string   `json:"statement"`
+	Target    string   `json:"target,omitempty"`
+	Input     string   `json:"input,omitempty"`
+	Result    string   `json:"result,omitempty"`
+	Branches  []string `json:"branches,omitempty"`
+	Vars      string   `json:"vars,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+func traceValueString(v any) string {
+	switch t := v.(type) {
+	case value.Delete:
+		return "deleted()"
+	case value.Nothing:
+		return "nothing"
+	case []byte:
+		v = string(t)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func newTraceEntries(t *mapping.Trace) []traceEntry {
+	if t == nil {
+		return nil
+	}
+	entries := make([]traceEntry, 0, len(t.Statements))
+	for _, s := range t.Statements {
+		e := traceEntry{
+			Line:      s.Line,
+			Statement: s.Statement,
+			Target:    s.Target,
+		}
+		if s.Target != "" {
+			e.Input = traceValueString(s.Input)
+			e.Result = traceValueString(s.Result)
+		}
+		for _, b := range s.Branches {
+			e.Branches = append(e.Branches, b.Expression+": "+b.Branch)
+		}
+		if len(s.Vars) > 0 {
+			e.Vars = traceValueString(s.Vars)
+		}
+		if s.Err != nil {
+			e.Error = s.Err.Error()
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// traceString renders a mapping trace in a human readable format, where the
+// variables of the mapping are only printed when they change.
+func traceString(t *mapping.Trace) string {
+	var b strings.Builder
+	var lastVars string
+	for _, e := range newTraceEntries(t) {
+		fmt.Fprintf(&b, "line %v: %v\n", e.Line, e.Statement)
+		if e.Target != "" {
+			fmt.Fprintf(&b, "  %v: %v -> %v\n", e.Target, e.Input, e.Result)
+		}
+		for _, branch := range e.Branches {
+			fmt.Fprintf(&b, "  %v\n", branch)
+		}
+		if e.Vars != lastVars {
+			if e.Vars == "" {
+				b.WriteString("  vars: {}\n")
+			} else {
+				fmt.Fprintf(&b, "  vars: %v\n", e.Vars)
+			}
+			lastVars = e.Vars
+		}
+		if e.Error != "" {
+			fmt.Fprintf(&b, "  error: %v\n", e.Error)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package blobl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/internal/bloblang"
+)
+
+func TestExecuteMappingTraced(t *testing.T) {
+	exec, err := bloblang.GlobalEnvironment().NewMapping(`let name = this.name.uppercase()
+root.name = $name
+root.kind = match this.kind {
+  "a" => "first"
+  _ => deleted()
+}
+if this.n > 5 {
+  root.big = true
+}
+root.fails = this.n.uppercase()`)
+	require.NoError(t, err)
+
+	_, trace, err := newExecCache().executeMappingTraced(exec, false, false, []byte(`{"name":"foo","kind":"a","n":10}`))
+	require.Error(t, err)
+
+	entries := newTraceEntries(trace)
+	require.Len(t, entries, 6)
+
+	assert.Equal(t, traceEntry{
+		Line:      1,
+		Statement: "let name = this.name.uppercase()",
+		Target:    "$name",
+		Input:     "nothing",
+		Result:    `"FOO"`,
+		Vars:      `{"name":"FOO"}`,
+	}, entries[0])
+
+	assert.Equal(t, traceEntry{
+		Line:      3,
+		Statement: "root.kind = match this.kind {",
+		Target:    "root.kind",
+		Input:     "nothing",
+		Result:    `"first"`,
+		Branches:  []string{"match expression: case 1"},
+		Vars:      `{"name":"FOO"}`,
+	}, entries[2])
+
+	assert.Equal(t, []string{"if statement: if"}, entries[3].Branches)
+	assert.Equal(t, "true", entries[4].Result)
+	assert.Contains(t, entries[5].Error, "expected string value")
+
+	assert.Equal(t, `line 1: let name = this.name.uppercase()
+  $name: nothing -> "FOO"
+  vars: {"name":"FOO"}
+line 2: root.name = $name
+  root.name: nothing -> "FOO"
+line 3: root.kind = match this.kind {
+  root.kind: nothing -> "first"
+  match expression: case 1
+line 7: if this.n > 5 {
+  if statement: if
+line 8: root.big = true
+  root.big: nothing -> true
+line 10: root.fails = this.n.uppercase()
+  root.fails: nothing -> nothing
+  error: `+entries[5].Error+"\n", traceString(trace))
+}
@@ -83,6 +83,7 @@ func CreateManager(
 			sanitSpec = config.SpecWithoutStream(cliOpts.MainConfigSpecCtor())
 		}
 		err = sanitSpec.SanitiseYAML(&sanitNode, sanitConf)
+		config.RedactSecrets(&sanitNode)
 	}
 	if err != nil {
 		err = fmt.Errorf("failed to generate sanitised config: %w", err)
@@ -12,8 +12,9 @@ import (
 
 // ReadConfig attempts to read a general service wide config via a returned
 // config.Reader based on input CLI flags. This includes applying any config
-// overrides expressed by the --set flag.
-func ReadConfig(c *cli.Context, cliOpts *CLIOpts, streamsMode bool) (mainPath string, inferred bool, conf *config.Reader) {
+// overrides expressed by the --set flag. Additional reader options are applied
+// after those derived from flags.
+func ReadConfig(c *cli.Context, cliOpts *CLIOpts, streamsMode bool, extraOpts ...config.OptFunc) (mainPath string, inferred bool, conf *config.Reader) {
 	path := cliOpts.RootFlags.GetConfig(c)
 	if path == "" {
 		// Iterate default config paths
@@ -30,7 +31,9 @@ func ReadConfig(c *cli.Context, cliOpts *CLIOpts, streamsMode bool) (mainPath st
 
 	opts := []config.OptFunc{
 		config.OptSetFullSpec(cliOpts.MainConfigSpecCtor),
+		config.OptAddOverlays(cliOpts.RootFlags.GetOverlays(c)...),
 		config.OptAddOverrides(cliOpts.RootFlags.GetSet(c)...),
+		config.OptEnableSecretProviders(cliOpts.RootFlags.GetSecretProviders(c)...),
 		config.OptTestSuffix("_benthos_test"),
 		config.OptSetLintConfig(lintConf),
 	}
@@ -40,5 +43,6 @@ func ReadConfig(c *cli.Context, cliOpts *CLIOpts, streamsMode bool) (mainPath st
 	if cliOpts.SecretAccessFn != nil {
 		opts = append(opts, config.OptUseEnvLookupFunc(cliOpts.SecretAccessFn))
 	}
+	opts = append(opts, extraOpts...)
 	return path, inferred, config.NewReader(path, cliOpts.RootFlags.GetResources(c), opts...)
 }
@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/urfave/cli/v2"
 
 	"github.com/redpanda-data/benthos/v4/internal/bloblang/parser"
+	"github.com/redpanda-data/benthos/v4/internal/config"
 	"github.com/redpanda-data/benthos/v4/internal/filepath"
 	"github.com/redpanda-data/benthos/v4/internal/filepath/ifs"
 	"github.com/redpanda-data/benthos/v4/internal/template"
@@ -23,12 +25,15 @@ import (
 // type to extract flag arguments in order to ensure that root level flags are
 // still honoured.
 type RootCommonFlags struct {
-	Config    string
-	LogLevel  string
-	Set       []string
-	Resources []string
-	Chilled   bool
-	Watcher   bool
+	Config          string
+	LogLevel        string
+	Set             []string
+	Resources       []string
+	Overlays        []string
+	SecretProviders []string
+	SecretCacheTTL  time.Duration
+	Chilled         bool
+	Watcher         bool
 }
 
 // RootCommonFlagsExtract attempts to read all common root flags from a cli
@@ -38,6 +43,9 @@ func (c *CLIOpts) RootCommonFlagsExtract(ctx *cli.Context) {
 	c.RootFlags.LogLevel = ctx.String(RootFlagLogLevel)
 	c.RootFlags.Set = ctx.StringSlice(RootFlagSet)
 	c.RootFlags.Resources = ctx.StringSlice(RootFlagResources)
+	c.RootFlags.Overlays = ctx.StringSlice(RootFlagOverlays)
+	c.RootFlags.SecretProviders = ctx.StringSlice(RootFlagSecretProviders)
+	c.RootFlags.SecretCacheTTL = ctx.Duration(RootFlagSecretCacheTTL)
 	c.RootFlags.Chilled = ctx.Bool(RootFlagChilled)
 	c.RootFlags.Watcher = ctx.Bool(RootFlagWatcher)
 }
@@ -78,6 +86,33 @@ func (r *RootCommonFlags) GetResources(c *cli.Context) []string {
 	return r.Resources
 }
 
+// GetOverlays attempts to read a config flag either from the current context,
+// or falls back to whatever the root context set it to.
+func (r *RootCommonFlags) GetOverlays(c *cli.Context) []string {
+	if v := c.StringSlice(RootFlagOverlays); len(v) > 0 {
+		return v
+	}
+	return r.Overlays
+}
+
+// GetSecretProviders attempts to read a config flag either from the current
+// context, or falls back to whatever the root context set it to.
+func (r *RootCommonFlags) GetSecretProviders(c *cli.Context) []string {
+	if v := c.StringSlice(RootFlagSecretProviders); len(v) > 0 {
+		return v
+	}
+	return r.SecretProviders
+}
+
+// GetSecretCacheTTL attempts to read a config flag either from the current
+// context, or falls back to whatever the root context set it to.
+func (r *RootCommonFlags) GetSecretCacheTTL(c *cli.Context) time.Duration {
+	if c.IsSet(RootFlagSecretCacheTTL) {
+		return c.Duration(RootFlagSecretCacheTTL)
+	}
+	return r.SecretCacheTTL
+}
+
 // GetChilled attempts to read a config flag either from the current context,
 // or falls back to whatever the root context set it to.
 func (r *RootCommonFlags) GetChilled(c *cli.Context) bool {
@@ -94,14 +129,17 @@ func (r *RootCommonFlags) GetWatcher(c *cli.Context) bool {
 
 // Common names for old root level flags
 const (
-	RootFlagConfig    = "config"
-	RootFlagLogLevel  = "log.level"
-	RootFlagSet       = "set"
-	RootFlagResources = "resources"
-	RootFlagChilled   = "chilled"
-	RootFlagWatcher   = "watcher"
-	RootFlagEnvFile   = "env-file"
-	RootFlagTemplates = "templates"
+	RootFlagConfig          = "config"
+	RootFlagLogLevel        = "log.level"
+	RootFlagSet             = "set"
+	RootFlagResources       = "resources"
+	RootFlagOverlays        = "overlay"
+	RootFlagSecretProviders = "secret-provider"
+	RootFlagSecretCacheTTL  = "secret-cache-ttl"
+	RootFlagChilled         = "chilled"
+	RootFlagWatcher         = "watcher"
+	RootFlagEnvFile         = "env-file"
+	RootFlagTemplates       = "templates"
 )
 
 // RunFlags is the full set of root level flags that have been deprecated and
@@ -126,6 +164,22 @@ func RunFlags(opts *CLIOpts, hidden bool) []cli.Flag {
 			Aliases: []string{"r"},
 			Usage:   "pull in extra resources from a file, which can be referenced the same as resources defined in the main config, supports glob patterns (requires quotes)",
 		},
+		&cli.StringSliceFlag{
+			Name:   RootFlagOverlays,
+			Hidden: hidden,
+			Usage:  "merge an overlay file into the main configuration file, overlays are merged in the order they are specified",
+		},
+		&cli.StringSliceFlag{
+			Name:   RootFlagSecretProviders,
+			Hidden: hidden,
+			Usage:  "enable an opt-in secret provider for secret references within configs, which can read arbitrary files (file) or run arbitrary commands (exec)",
+		},
+		&cli.DurationFlag{
+			Name:   RootFlagSecretCacheTTL,
+			Hidden: hidden,
+			Value:  config.DefaultSecretCacheTTL,
+			Usage:  "the period of time for which values resolved from secret references are cached before being resolved again, a zero duration disables caching",
+		},
 		&cli.BoolFlag{
 			Name:   RootFlagChilled,
 			Hidden: hidden,
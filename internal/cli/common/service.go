@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -16,6 +18,7 @@ import (
 	"github.com/redpanda-data/benthos/v4/internal/manager"
 	"github.com/redpanda-data/benthos/v4/internal/stream"
 	strmmgr "github.com/redpanda-data/benthos/v4/internal/stream/manager"
+	"github.com/redpanda-data/benthos/v4/internal/value"
 
 	"github.com/urfave/cli/v2"
 )
@@ -44,6 +47,14 @@ func RunService(c *cli.Context, cliOpts *CLIOpts, streamsMode bool) error {
 		return err
 	}
 
+	optInSecrets := config.OptInSecretProviders()
+	for _, name := range cliOpts.RootFlags.GetSecretProviders(c) {
+		if !slices.Contains(optInSecrets, name) {
+			return fmt.Errorf("secret provider '%v' cannot be enabled, options are: %v", name, strings.Join(optInSecrets, ", "))
+		}
+	}
+	config.SetSecretCacheTTL(cliOpts.RootFlags.GetSecretCacheTTL(c))
+
 	mainPath, inferredMainPath, confReader := ReadConfig(c, cliOpts, streamsMode)
 
 	conf, pConf, lints, err := confReader.Read()
@@ -96,7 +107,7 @@ func RunService(c *cli.Context, cliOpts *CLIOpts, streamsMode bool) error {
 	watching := cliOpts.RootFlags.GetWatcher(c)
 	if streamsMode {
 		enableStreamsAPI := !c.Bool("no-api")
-		stoppableStream, err = initStreamsMode(cliOpts, strict, watching, enableStreamsAPI, confReader, stoppableManager.Manager())
+		stoppableStream, err = initStreamsMode(c.Context, cliOpts, strict, watching, enableStreamsAPI, c.String("state-dir"), c.String("state-conflict"), confReader, stoppableManager.Manager())
 	} else {
 		stoppableStream, dataStreamClosedChan, err = initNormalMode(cliOpts, conf, strict, watching, confReader, stoppableManager.Manager())
 	}
@@ -135,13 +146,38 @@ func DelayShutdown(ctx context.Context, duration time.Duration) error {
 }
 
 func initStreamsMode(
+	ctx context.Context,
 	opts *CLIOpts,
 	strict, watching, enableAPI bool,
+	stateDir, stateConflict string,
 	confReader *config.Reader,
 	mgr *manager.Type,
 ) (RunningStream, error) {
 	logger := mgr.Logger()
-	streamMgr := strmmgr.New(mgr, strmmgr.OptAPIEnabled(enableAPI))
+
+	switch stateConflict {
+	case "path", "state", "error":
+	default:
+		return nil, fmt.Errorf("state conflict policy not recognised: %v", stateConflict)
+	}
+
+	strmOpts := []func(*strmmgr.Type){strmmgr.OptAPIEnabled(enableAPI)}
+	if stateDir != "" {
+		store, err := strmmgr.NewDirStore(stateDir)
+		if err != nil {
+			return nil, err
+		}
+		strmOpts = append(strmOpts, strmmgr.OptSetStore(store))
+	}
+	streamMgr := strmmgr.New(mgr, strmOpts...)
+
+	if err := streamMgr.RestoreResources(ctx); err != nil {
+		return nil, err
+	}
+	storedConfs, err := streamMgr.StoredStreams()
+	if err != nil {
+		return nil, err
+	}
 
 	streamConfs := map[string]stream.Config{}
 	lints, err := confReader.ReadStreams(streamConfs)
@@ -160,11 +196,41 @@ func initStreamsMode(
 		return nil, errors.New("shutting down due to stream linter errors, to prevent shutdown run {{.ProductName}} with --chilled")
 	}
 
-	for id, conf := range streamConfs {
+	// Only streams created or updated through the API are recorded within the
+	// state directory, as streams read from paths are persisted by their files
+	// and must not be restored once those files are removed.
+	restoredConfs := map[string]stream.Config{}
+	for id, storedConf := range storedConfs {
+		pathConf, exists := streamConfs[id]
+		if !exists {
+			restoredConfs[id] = storedConf
+			continue
+		}
+		if value.ICompare(pathConf.GetRawSource(), storedConf.GetRawSource()) {
+			continue
+		}
+		switch stateConflict {
+		case "path":
+			logger.With("stream", id).Warn("Stream config from path differs from the stored config, the path config takes precedence")
+		case "state":
+			logger.With("stream", id).Warn("Stream config from path differs from the stored config, the stored config takes precedence")
+			delete(streamConfs, id)
+			restoredConfs[id] = storedConf
+		default:
+			return nil, fmt.Errorf("stream config from path differs from the stored config (%v)", id)
+		}
+	}
+
+	for id, conf := range restoredConfs {
 		if err := streamMgr.Create(id, conf); err != nil {
 			return nil, fmt.Errorf("failed to create stream (%v): %w", id, err)
 		}
 	}
+	for id, conf := range streamConfs {
+		if err := streamMgr.CreateUnstored(id, conf); err != nil {
+			return nil, fmt.Errorf("failed to create stream (%v): %w", id, err)
+		}
+	}
 	logger.Info(opts.ExecTemplate("Launching {{.ProductName}} in streams mode, use CTRL+C to close"))
 
 	if err := confReader.SubscribeStreamChanges(func(id string, newStreamConf *stream.Config) error {
@@ -173,8 +239,8 @@ func initStreamsMode(
 
 		var updateErr error
 		if newStreamConf != nil {
-			if updateErr = streamMgr.Update(ctx, id, *newStreamConf); updateErr != nil && errors.Is(updateErr, strmmgr.ErrStreamDoesNotExist) {
-				updateErr = streamMgr.Create(id, *newStreamConf)
+			if updateErr = streamMgr.UpdateUnstored(ctx, id, *newStreamConf); updateErr != nil && errors.Is(updateErr, strmmgr.ErrStreamDoesNotExist) {
+				updateErr = streamMgr.CreateUnstored(id, *newStreamConf)
 			}
 		} else {
 			if updateErr = streamMgr.Delete(ctx, id); updateErr != nil && errors.Is(updateErr, strmmgr.ErrStreamDoesNotExist) {
@@ -227,7 +293,26 @@ func initNormalMode(
 	if err := confReader.SubscribeConfigChanges(func(newStreamConf *config.Type) error {
 		ctx, done := context.WithTimeout(context.Background(), 30*time.Second)
 		defer done()
+
 		// NOTE: We're ignoring observability field changes for now.
+		//
+		// Where possible only the components of the stream that have changed
+		// are replaced, otherwise the whole stream is restarted.
+		err := stoppableStream.Update(func(current RunningStream) error {
+			strm, ok := current.(*stream.Type)
+			if !ok {
+				return stream.ErrUpdateRequiresRestart
+			}
+			return strm.Update(ctx, newStreamConf.Config)
+		})
+		if err == nil {
+			conf.Config = newStreamConf.Config
+			return nil
+		}
+		if !errors.Is(err, stream.ErrUpdateRequiresRestart) {
+			return err
+		}
+		logger.Info("Restarting stream in order to apply updated config")
 		return stoppableStream.Replace(ctx, func() (RunningStream, error) {
 			conf.Config = newStreamConf.Config
 			return streamInit()
@@ -57,6 +57,18 @@ func (s *SwappableStopper) Stop(ctx context.Context) error {
 	return s.current.Stop(ctx)
 }
 
+// Update calls the provided closure with the current resource in order to
+// modify it in place, unless it has been stopped.
+func (s *SwappableStopper) Update(fn func(RunningStream) error) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if s.stopped {
+		return nil
+	}
+	return fn(s.current)
+}
+
 // Replace the resource with something new only once the existing one is
 // stopped. In order to avoid unnecessary start up of the swapping resource we
 // accept a closure that constructs it and is only called when we're ready.
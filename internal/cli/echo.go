@@ -10,6 +10,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/redpanda-data/benthos/v4/internal/cli/common"
+	"github.com/redpanda-data/benthos/v4/internal/config"
 	"github.com/redpanda-data/benthos/v4/internal/docs"
 )
 
@@ -25,6 +26,10 @@ func echoCliCommand(opts *common.CLIOpts) *cli.Command {
 			Aliases: []string{"r"},
 			Usage:   "pull in extra resources from a file, which can be referenced the same as resources defined in the main config, supports glob patterns (requires quotes)",
 		},
+		&cli.StringSliceFlag{
+			Name:  common.RootFlagOverlays,
+			Usage: "merge an overlay file into the main configuration file, overlays are merged in the order they are specified",
+		},
 	}
 	flags = append(flags, common.EnvFileAndTemplateFlags(opts, false)...)
 
@@ -35,10 +40,13 @@ func echoCliCommand(opts *common.CLIOpts) *cli.Command {
 		Description: opts.ExecTemplate(`
 This simple command is useful for sanity checking a config if it isn't
 behaving as expected, as it shows you a normalised version after environment
-variables have been resolved:
+variables, includes and overlays have been resolved. Secret references are
+echoed as they are written without being resolved, and fields marked as
+secrets are scrubbed:
 
   {{.BinaryName}} echo ./config.yaml | less
   {{.BinaryName}} echo --set 'input.generate.mapping=root.id = uuid_v4()'
+  {{.BinaryName}} echo --overlay ./prod.yaml ./config.yaml
   
   `)[1:],
 		Before: func(c *cli.Context) error {
@@ -52,7 +60,7 @@ variables have been resolved:
 				opts.RootFlags.Config = c.Args().First()
 			}
 
-			_, _, confReader := common.ReadConfig(c, opts, false)
+			_, _, confReader := common.ReadConfig(c, opts, false, config.OptSkipSecrets())
 			_, pConf, _, err := confReader.Read()
 			if err != nil {
 				return fmt.Errorf("configuration file read error: %w", err)
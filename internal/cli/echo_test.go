@@ -24,10 +24,11 @@ func TestEcho(t *testing.T) {
 	}
 
 	tests := []struct {
-		name     string
-		files    map[string]string
-		args     []string
-		contains []string
+		name        string
+		files       map[string]string
+		args        []string
+		contains    []string
+		notContains []string
 	}{
 		{
 			name: "echo single file",
@@ -65,6 +66,27 @@ output:
 				"drop: {}",
 			},
 		},
+		{
+			name: "echo with secret reference",
+			args: []string{"benthos", "echo", tFile("foo.yaml")},
+			files: map[string]string{
+				"db_password": "hunter2\n",
+				"foo.yaml": `
+input:
+  generate:
+    mapping: 'root.password = "${secret:file:` + tFile("db_password") + `}"'
+output:
+  drop: {}
+`,
+			},
+			contains: []string{
+				"generate:",
+				"${secret:file:" + tFile("db_password") + "}",
+			},
+			notContains: []string{
+				"hunter2",
+			},
+		},
 		{
 			name: "echo with set flag",
 			args: []string{"benthos", "echo", "--set", `input.generate.mapping=root.id = uuid_v4()`},
@@ -73,6 +95,42 @@ output:
 				"root.id = uuid_v4",
 			},
 		},
+		{
+			name: "echo with includes and overlays",
+			args: []string{"benthos", "echo", "--overlay", tFile("overlay.yaml"), tFile("included.yaml")},
+			files: map[string]string{
+				"included.yaml": `
+input: !include input_fragment.yaml
+pipeline:
+  processors:
+    - mapping: 'root = "first"'
+output:
+  drop: {}
+`,
+				"input_fragment.yaml": `
+generate:
+  mapping: 'root = "from fragment"'
+`,
+				"overlay.yaml": `
+pipeline:
+  processors: !append
+    - mapping: 'root = "second"'
+output: !replace
+  stdout: {}
+`,
+			},
+			contains: []string{
+				"root = \"from fragment\"",
+				"root = \"first\"",
+				"root = \"second\"",
+				"stdout:",
+			},
+			notContains: []string{
+				"!include",
+				"!append",
+				"drop:",
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -94,6 +152,9 @@ output:
 			for _, exp := range test.contains {
 				assert.Contains(t, stdout.String(), exp)
 			}
+			for _, exp := range test.notContains {
+				assert.NotContains(t, stdout.String(), exp)
+			}
 		})
 	}
 }
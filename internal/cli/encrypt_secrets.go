@@ -0,0 +1,80 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/redpanda-data/benthos/v4/internal/cli/common"
+	"github.com/redpanda-data/benthos/v4/internal/config"
+	"github.com/redpanda-data/benthos/v4/internal/filepath/ifs"
+)
+
+func encryptSecretsCliCommand(opts *common.CLIOpts) *cli.Command {
+	return &cli.Command{
+		Name:      "encrypt-secrets",
+		Usage:     "Encrypt a file of secrets for the encrypted secret provider",
+		ArgsUsage: "<file>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "key-file",
+				Usage: fmt.Sprintf("the path of a file containing a 32 byte key encoded as hex, defaults to the value of the %v environment variable", config.SecretsKeyFileEnvVar),
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "the path to write the encrypted file to, defaults to the input path with an .enc extension",
+			},
+		},
+		Description: opts.ExecTemplate(fmt.Sprintf(`
+Encrypts a file with AES-256-GCM in the format read by the encrypted secret
+provider. The file is either a secret itself, or a YAML or JSON document of
+secrets that are referenced by the dot path of a field:
+
+  openssl rand -hex 32 > ./secrets.key
+  {{.BinaryName}} encrypt-secrets --key-file ./secrets.key ./secrets.yaml
+
+The resulting ./secrets.yaml.enc file can then be referenced within configs
+with ${secret:encrypted:./secrets.yaml.enc#db.password}, where the environment
+variable %v is set to the path of the key file.`, config.SecretsKeyFileEnvVar))[1:],
+		Action: func(c *cli.Context) error {
+			if c.Args().Len() != 1 {
+				return errors.New("exactly one file must be specified")
+			}
+			inPath := c.Args().First()
+
+			keyPath := c.String("key-file")
+			if keyPath == "" {
+				if keyPath = os.Getenv(config.SecretsKeyFileEnvVar); keyPath == "" {
+					return fmt.Errorf("a key file must be specified with --key-file or the environment variable %v", config.SecretsKeyFileEnvVar)
+				}
+			}
+			key, err := config.ReadSecretsKeyFile(keyPath)
+			if err != nil {
+				return err
+			}
+
+			contents, err := ifs.ReadFile(ifs.OS(), inPath)
+			if err != nil {
+				return fmt.Errorf("failed to read secrets file: %w", err)
+			}
+			encrypted, err := config.EncryptSecrets(key, contents)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt secrets file: %w", err)
+			}
+
+			outPath := c.String("output")
+			if outPath == "" {
+				outPath = inPath + ".enc"
+			}
+			if err := ifs.WriteFile(ifs.OS(), outPath, encrypted, 0o600); err != nil {
+				return fmt.Errorf("failed to write encrypted file: %w", err)
+			}
+			return nil
+		},
+	}
+}
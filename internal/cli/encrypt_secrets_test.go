@@ -0,0 +1,50 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package cli_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/internal/cli"
+	"github.com/redpanda-data/benthos/v4/internal/cli/common"
+	"github.com/redpanda-data/benthos/v4/internal/config"
+)
+
+func TestEncryptSecrets(t *testing.T) {
+	tmpDir := t.TempDir()
+	keyPath := filepath.Join(tmpDir, "secrets.key")
+	secretsPath := filepath.Join(tmpDir, "secrets.yaml")
+
+	require.NoError(t, os.WriteFile(keyPath, []byte("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f\n"), 0o600))
+	require.NoError(t, os.WriteFile(secretsPath, []byte("db:\n  password: hunter22\n"), 0o600))
+
+	opts := common.NewCLIOpts("1.2.3", "now")
+	require.NoError(t, cli.App(opts).Run([]string{"benthos", "encrypt-secrets", "--key-file", keyPath, secretsPath}))
+
+	encrypted, err := os.ReadFile(secretsPath + ".enc")
+	require.NoError(t, err)
+	assert.NotContains(t, string(encrypted), "hunter22")
+
+	t.Setenv(config.SecretsKeyFileEnvVar, keyPath)
+	res, err := config.NewReader("", nil).ReplaceEnvVariables(context.Background(), []byte(`${secret:encrypted:`+secretsPath+`.enc#db.password}`))
+	require.NoError(t, err)
+	assert.Equal(t, "hunter22", string(res))
+}
+
+func TestEncryptSecretsNoKey(t *testing.T) {
+	t.Setenv(config.SecretsKeyFileEnvVar, "")
+
+	secretsPath := filepath.Join(t.TempDir(), "secrets.yaml")
+	require.NoError(t, os.WriteFile(secretsPath, []byte("foo"), 0o600))
+
+	opts := common.NewCLIOpts("1.2.3", "now")
+	err := cli.App(opts).Run([]string{"benthos", "encrypt-secrets", secretsPath})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "a key file must be specified")
+}
@@ -10,11 +10,13 @@ import (
 	"io"
 	"path"
 	"runtime"
+	"strings"
 	"sync"
 
 	"github.com/fatih/color"
 	"github.com/urfave/cli/v2"
 
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/query"
 	"github.com/redpanda-data/benthos/v4/internal/cli/common"
 	"github.com/redpanda-data/benthos/v4/internal/config"
 	"github.com/redpanda-data/benthos/v4/internal/docs"
@@ -40,6 +42,15 @@ func lintCliCommand(cliOpts *common.CLIOpts) *cli.Command {
 			Value: false,
 			Usage: "Do not produce lint errors when environment interpolations exist without defaults within configs but aren't defined.",
 		},
+		&cli.BoolFlag{
+			Name:  "bloblang",
+			Value: false,
+			Usage: "Print linting errors for problems found by a static analysis of Bloblang mappings, such as mismatched types, unreachable match cases and unused variables.",
+		},
+		&cli.StringSliceFlag{
+			Name:  "bloblang-schema",
+			Usage: "A JSON Schema file describing the documents that a Bloblang mapping is executed upon, used in order to validate field references, in the form `<path>=<file>` where the path is the dot path of the mapping field within the config (e.g. pipeline.processors.0.mapping). Can be specified multiple times. Implies --bloblang.",
+		},
 		&cli.BoolFlag{
 			Name:  "verbose",
 			Value: false,
@@ -67,7 +78,14 @@ Exits with a status code 1 if any linting errors are detected:
   {{.BinaryName}} lint ./configs/...
 
 If a path ends with '...' then {{.ProductName}} will walk the target and lint any
-files with the .yaml or .yml extension.`)[1:],
+files with the .yaml or .yml extension.
+
+The --bloblang flag enables a static analysis of Bloblang mappings, and a JSON
+Schema describing the documents that a mapping is executed upon can be provided
+with --bloblang-schema, along with the path of the mapping field, in order to
+also validate field references:
+
+  {{.BinaryName}} lint --bloblang-schema pipeline.processors.0.mapping=./schema.json ./config.yaml`)[1:],
 		Before: func(c *cli.Context) error {
 			return common.PreApplyEnvFilesAndTemplates(c, cliOpts)
 		},
@@ -89,7 +107,7 @@ type pathLint struct {
 }
 
 func lintFile(opts *common.CLIOpts, path string, skipEnvVarCheck bool, spec docs.FieldSpecs, lConf docs.LintConfig) (pathLints []pathLint) {
-	_, lints, err := config.NewReader("", nil, config.OptUseEnvLookupFunc(opts.SecretAccessFn)).
+	_, lints, err := config.NewReader("", nil, config.OptUseEnvLookupFunc(opts.SecretAccessFn), config.OptSkipSecrets()).
 		ReadYAMLFileLinted(context.TODO(), spec, path, skipEnvVarCheck, lConf)
 	if err != nil {
 		pathLints = append(pathLints, pathLint{
@@ -209,6 +227,26 @@ func LintAction(c *cli.Context, opts *common.CLIOpts, stderr io.Writer) error {
 	lConf.BloblangEnv = bloblang.XWrapEnvironment(opts.BloblEnvironment)
 	lConf.RejectDeprecated = c.Bool("deprecated")
 	lConf.RequireLabels = c.Bool("labels")
+	lConf.BloblangAnalysis = c.Bool("bloblang")
+	for _, schemaArg := range c.StringSlice("bloblang-schema") {
+		schemaField, schemaPath, ok := strings.Cut(schemaArg, "=")
+		if !ok || schemaField == "" || schemaPath == "" {
+			return fmt.Errorf("bloblang schema '%v' must be in the form <path>=<file>", schemaArg)
+		}
+		schemaBytes, err := ifs.ReadFile(ifs.OS(), schemaPath)
+		if err != nil {
+			return fmt.Errorf("failed to read bloblang schema: %w", err)
+		}
+		schema, err := query.NewSchemaFromJSONSchema(schemaBytes)
+		if err != nil {
+			return fmt.Errorf("failed to parse bloblang schema: %w", err)
+		}
+		if lConf.BloblangSchemas == nil {
+			lConf.BloblangSchemas = map[string]*query.Schema{}
+		}
+		lConf.BloblangSchemas[schemaField] = schema
+		lConf.BloblangAnalysis = true
+	}
 	skipEnvVarCheck := c.Bool("skip-env-var-check")
 	verbose := c.Bool("verbose")
 
@@ -164,6 +164,95 @@ output:
 `,
 			},
 		},
+		{
+			name: "bloblang analysis without flag",
+			args: []string{"benthos", "lint", tFile("foo.yaml")},
+			files: map[string]string{
+				"foo.yaml": `
+pipeline:
+  processors:
+    - mapping: |
+        let unused = "foo"
+        root.id = this.id.uppercase()
+        root.count = (5 + 5).uppercase()
+`,
+			},
+		},
+		{
+			name: "bloblang analysis",
+			args: []string{"benthos", "lint", "--bloblang", tFile("foo.yaml")},
+			files: map[string]string{
+				"foo.yaml": `
+pipeline:
+  processors:
+    - mapping: |
+        let unused = "foo"
+        root.id = this.id.uppercase()
+        root.count = (5 + 5).uppercase()
+`,
+			},
+			expectedErr: true,
+			expectedLints: []string{
+				"foo.yaml(5,1) variable unused is declared but never used",
+				"foo.yaml(7,1) method uppercase expects a string or bytes value, but",
+			},
+		},
+		{
+			name: "bloblang analysis with schema",
+			args: []string{"benthos", "lint", "--bloblang-schema", "pipeline.processors.0.mapping=" + tFile("schema.json"), tFile("foo.yaml")},
+			files: map[string]string{
+				"schema.json": `{
+  "type": "object",
+  "properties": {
+    "id": { "type": "string" },
+    "age": { "type": "integer" }
+  },
+  "additionalProperties": false
+}`,
+				"foo.yaml": `
+pipeline:
+  processors:
+    - mapping: |
+        root.id = this.id.uppercase()
+        root.name = this.name
+        root.age = this.age.uppercase()
+`,
+			},
+			expectedErr: true,
+			expectedLints: []string{
+				"foo.yaml(6,1) field `this.name` is not defined by the schema",
+				"foo.yaml(7,1) method uppercase expects a string or bytes value, but field `this.age` resolves to a number",
+			},
+		},
+		{
+			name: "bloblang schema only applies to its path",
+			args: []string{"benthos", "lint", "--bloblang-schema", "pipeline.processors.0.branch.request_map=" + tFile("schema.json"), tFile("foo.yaml")},
+			files: map[string]string{
+				"schema.json": `{
+  "type": "object",
+  "properties": {
+    "id": { "type": "string" }
+  },
+  "additionalProperties": false
+}`,
+				"foo.yaml": `
+pipeline:
+  processors:
+    - branch:
+        request_map: 'root.id = this.id.uppercase()'
+        processors:
+          - mapping: 'root.result = this.id.length()'
+        result_map: 'root.length = this.result'
+    - mapping: 'root.count = this.length + 1'
+`,
+			},
+		},
+		{
+			name:        "bloblang schema without path",
+			args:        []string{"benthos", "lint", "--bloblang-schema", tFile("schema.json"), tFile("foo.yaml")},
+			files:       map[string]string{"schema.json": `{}`, "foo.yaml": "{}"},
+			expectedErr: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -190,3 +279,24 @@ output:
 		})
 	}
 }
+
+func TestLintDoesNotResolveSecrets(t *testing.T) {
+	tmpDir := t.TempDir()
+	confPath := filepath.Join(tmpDir, "foo.yaml")
+	markerPath := filepath.Join(tmpDir, "marker")
+
+	require.NoError(t, os.WriteFile(confPath, []byte(`
+input:
+  generate:
+    mapping: 'root.id = "${secret:exec:touch `+markerPath+`}"'
+output:
+  drop: {}
+`), 0o644))
+
+	outStr, err := executeLintSubcmd([]string{"benthos", "lint", confPath})
+	require.NoError(t, err)
+	assert.Empty(t, outStr)
+
+	_, err = os.Stat(markerPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -24,7 +24,13 @@ func runCliCommand(opts *common.CLIOpts) *cli.Command {
 		Description: opts.ExecTemplate(`
 Run a {{.ProductName}} config.
 
-  {{.BinaryName}} run ./foo.yaml`)[1:],
+  {{.BinaryName}} run ./foo.yaml
+
+Config files are able to pull in YAML fragments from other files at any path
+with the !include tag, and overlay files can be merged into the main config in
+the order they are specified with --overlay:
+
+  {{.BinaryName}} run --overlay ./prod.yaml ./foo.yaml`)[1:],
 		Action: func(c *cli.Context) error {
 			if c.Args().Len() > 0 {
 				if c.Args().Len() > 1 || opts.RootFlags.Config != "" {
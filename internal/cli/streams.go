@@ -20,6 +20,16 @@ func streamsCliCommand(opts *common.CLIOpts) *cli.Command {
 			Value: true,
 			Usage: "Whether HTTP endpoints registered by stream configs should be prefixed with the stream ID",
 		},
+		&cli.StringFlag{
+			Name:  "state-dir",
+			Value: "",
+			Usage: "A directory in which the configs of streams and resources created, updated and deleted through the REST API are recorded, and from which they are restored at startup",
+		},
+		&cli.StringFlag{
+			Name:  "state-conflict",
+			Value: "path",
+			Usage: "How to resolve a stream defined both within the state directory and by a path config with differing configs: `path` to prefer the path config, `state` to prefer the stored config, or `error` to refuse to start",
+		},
 
 		// Observability config only
 		&cli.StringFlag{
@@ -46,11 +56,18 @@ endpoints.
   {{.BinaryName}} streams -o ./root_config.yaml
   {{.BinaryName}} streams ./path/to/stream/configs ./and/some/more
   {{.BinaryName}} streams -o ./root_config.yaml ./streams/*.yaml
+  {{.BinaryName}} streams --state-dir ./state ./path/to/stream/configs
 
 The config field specified with the --observability/-o flag is known as the root
 config and should only contain observability and service-wide config fields such
 as http, metrics, logger, resources, and so on.
 
+When the --state-dir flag is set the configs of streams and resources created,
+updated and deleted through the REST API are recorded within the directory and
+restored at startup. Streams read from paths are not recorded, and therefore
+removing their files whilst the service is stopped removes them. Stored configs
+contain the values of interpolated environment variables.
+
 For more information check out the docs at:
 {{.DocumentationURL}}/guides/streams_mode/about`)[1:],
 		Before: func(c *cli.Context) error {
@@ -94,3 +94,58 @@ logger:
 
 	assert.Contains(t, stdout.String(), "level=trace")
 }
+
+func TestStreamsModeStateDirPathStreams(t *testing.T) {
+	tmpDir := t.TempDir()
+	obsPath := filepath.Join(tmpDir, "o11y.yaml")
+	confPath := filepath.Join(tmpDir, "foo.yaml")
+	outPath := filepath.Join(tmpDir, "out.txt")
+	stateDir := filepath.Join(tmpDir, "state")
+
+	require.NoError(t, os.WriteFile(confPath, fmt.Appendf(nil, `
+input:
+  generate:
+    mapping: 'root.id = "foobar"'
+    interval: "100ms"
+output:
+  file:
+    codec: lines
+    path: %v
+`, outPath), 0o644))
+
+	require.NoError(t, os.WriteFile(obsPath, []byte(`
+http:
+  enabled: false
+`), 0o644))
+
+	runStreams := func(args ...string) {
+		t.Helper()
+
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second))
+		defer cancel()
+
+		opts := common.NewCLIOpts("1.2.3", "aaa")
+		opts.Stdout = &bytes.Buffer{}
+
+		require.NoError(t, icli.App(opts).RunContext(ctx, append([]string{"benthos", "streams", "-o", obsPath, "--state-dir", stateDir}, args...)))
+	}
+
+	runStreams(confPath)
+
+	data, _ := os.ReadFile(outPath)
+	assert.Contains(t, string(data), "foobar")
+
+	stored, err := os.ReadDir(filepath.Join(stateDir, "streams"))
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+
+	// Removing the config file whilst the service is stopped removes the
+	// stream.
+	require.NoError(t, os.Remove(confPath))
+	require.NoError(t, os.Remove(outPath))
+
+	runStreams()
+
+	_, err = os.Stat(outPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -32,13 +32,18 @@ func (c CaseFailure) String() string {
 type ProcProvider interface {
 	Provide(jsonPtr string, environment map[string]string, mocks map[string]any) ([]iprocessor.V1, error)
 	ProvideBloblang(path string) ([]iprocessor.V1, error)
+	ProvideBloblangImport(path, mapping string) ([]iprocessor.V1, error)
 }
 
 // ExecuteFrom executes a test case from the perspective of a given directory,
 // which is used for obtaining relative condition file imports.
 func ExecuteFrom(fs fs.FS, dir string, c test.Case, provider ProcProvider) (failures []CaseFailure, err error) {
 	var procSet []iprocessor.V1
-	if c.TargetMapping != "" {
+	if c.TargetMapping != "" && c.Mapping != "" {
+		if procSet, err = provider.ProvideBloblangImport(c.TargetMapping, c.Mapping); err != nil {
+			return nil, fmt.Errorf("failed to initialise Bloblang mapping importing '%v': %v", c.TargetMapping, err)
+		}
+	} else if c.TargetMapping != "" {
 		if procSet, err = provider.ProvideBloblang(c.TargetMapping); err != nil {
 			return nil, fmt.Errorf("failed to initialise Bloblang mapping '%v': %v", c.TargetMapping, err)
 		}
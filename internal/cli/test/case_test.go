@@ -39,6 +39,10 @@ func (m mockProvider) ProvideBloblang(name string) ([]processor.V1, error) {
 	return nil, errors.New("mapping not found")
 }
 
+func (m mockProvider) ProvideBloblangImport(name, mapping string) ([]processor.V1, error) {
+	return m.ProvideBloblang(name)
+}
+
 func TestCase(t *testing.T) {
 	color.NoColor = true
 
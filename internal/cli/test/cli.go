@@ -43,6 +43,9 @@ fail the process will report the errors and exit with a status code 1.
   {{.BinaryName}} test ./foo_configs/*.yaml ./bar_configs/*.yaml
   {{.BinaryName}} test ./foo.yaml
 
+Bloblang mapping files with the .blobl extension are also searched for test
+blocks, which are described by the help of the blobl test subcommand.
+
 For more information check out the docs at:
 {{.DocumentationURL}}/configuration/unit_testing`)[1:],
 		Before: func(c *cli.Context) error {
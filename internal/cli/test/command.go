@@ -77,16 +77,60 @@ func getDefinition(targetPath, definitionPath string) ([]test.Case, error) {
 	return cases, nil
 }
 
+func getMappingDefinition(mappingPath string) ([]test.Case, error) {
+	mappingBytes, err := ifs.ReadFile(ifs.OS(), mappingPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mapping '%v': %v", mappingPath, err)
+	}
+	cases, err := test.MappingCasesFromBloblang(mappingPath, mappingBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tests from mapping '%v': %v", mappingPath, err)
+	}
+	return cases, nil
+}
+
+// GetMappingTestTargets searches for Bloblang mapping files containing test
+// blocks in a path.
+func GetMappingTestTargets(targetPaths []string) (map[string][]test.Case, error) {
+	targetPaths, err := ifilepath.GlobsAndSuperPaths(ifs.OS(), targetPaths, "blobl")
+	if err != nil {
+		return nil, err
+	}
+
+	targetDefinitions := map[string][]test.Case{}
+	for _, tPath := range targetPaths {
+		def, err := getMappingDefinition(tPath)
+		if err != nil {
+			return nil, err
+		}
+		if len(def) == 0 {
+			continue
+		}
+		targetDefinitions[filepath.Clean(tPath)] = def
+	}
+	return targetDefinitions, nil
+}
+
 // GetTestTargets searches for test definition targets in a path with a given
-// test suffix.
+// test suffix, including Bloblang mapping files containing test blocks.
 func GetTestTargets(targetPaths []string, testSuffix string) (map[string][]test.Case, error) {
-	targetPaths, err := ifilepath.GlobsAndSuperPaths(ifs.OS(), targetPaths, "yaml", "yml")
+	targetPaths, err := ifilepath.GlobsAndSuperPaths(ifs.OS(), targetPaths, "yaml", "yml", "blobl")
 	if err != nil {
 		return nil, err
 	}
 
 	targetDefinitions := map[string][]test.Case{}
 	for _, tPath := range targetPaths {
+		if isMappingPath(tPath) {
+			def, err := getMappingDefinition(tPath)
+			if err != nil {
+				return nil, err
+			}
+			if len(def) > 0 {
+				targetDefinitions[filepath.Clean(tPath)] = def
+			}
+			continue
+		}
 		configPath, definitionPath := GetPathPair(tPath, testSuffix)
 		def, err := getDefinition(configPath, definitionPath)
 		if err != nil {
@@ -100,6 +144,10 @@ func GetTestTargets(targetPaths []string, testSuffix string) (map[string][]test.
 	return targetDefinitions, nil
 }
 
+func isMappingPath(path string) bool {
+	return filepath.Ext(path) == ".blobl"
+}
+
 // Lints the config target of a test definition and either returns linting
 // errors (false for failed) or returns an error.
 func lintTarget(opts *common.CLIOpts, spec docs.FieldSpecs, path, testSuffix string) ([]docs.Lint, error) {
@@ -112,7 +160,7 @@ func lintTarget(opts *common.CLIOpts, spec docs.FieldSpecs, path, testSuffix str
 	// environment variables, so in order to test env vars properly we would
 	// need to lint for each case.
 	skipEnvVarCheck := true
-	_, lints, err := config.NewReader("", nil, config.OptUseEnvLookupFunc(opts.SecretAccessFn)).
+	_, lints, err := config.NewReader("", nil, config.OptUseEnvLookupFunc(opts.SecretAccessFn), config.OptSkipSecrets()).
 		ReadYAMLFileLinted(context.TODO(), spec, confPath, skipEnvVarCheck, lintConf)
 	if err != nil {
 		return nil, err
@@ -131,6 +179,22 @@ func RunAll(opts *common.CLIOpts, paths []string, testSuffix string, lint bool,
 		fmt.Fprintf(opts.Stderr, "Failed to obtain test targets: %v\n", err)
 		return false
 	}
+	return runTargets(opts, targets, testSuffix, lint, logger, resourcesPaths)
+}
+
+// RunMappings executes the test blocks of Bloblang mapping files for a slice
+// of paths. The path can either be a mapping file, a directory, or the
+// wildcard pattern './...'.
+func RunMappings(opts *common.CLIOpts, paths []string, logger log.Modular) bool {
+	targets, err := GetMappingTestTargets(paths)
+	if err != nil {
+		fmt.Fprintf(opts.Stderr, "Failed to obtain test targets: %v\n", err)
+		return false
+	}
+	return runTargets(opts, targets, "", false, logger, nil)
+}
+
+func runTargets(opts *common.CLIOpts, targets map[string][]test.Case, testSuffix string, lint bool, logger log.Modular, resourcesPaths []string) bool {
 	if len(targets) == 0 {
 		fmt.Fprintf(opts.Stdout, "%v\n", yellow("No tests were found"))
 		return false
@@ -152,7 +216,8 @@ func RunAll(opts *common.CLIOpts, paths []string, testSuffix string, lint bool,
 	for _, target := range targetPaths {
 		var lints []docs.Lint
 		var failCases []CaseFailure
-		if lint {
+		var err error
+		if lint && !isMappingPath(target) {
 			if lints, err = lintTarget(opts, opts.MainConfigSpecCtor(), target, testSuffix); err != nil {
 				fmt.Fprintf(opts.Stderr, "Failed to execute test target '%v': %v\n", target, err)
 				return false
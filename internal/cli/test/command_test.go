@@ -3,11 +3,15 @@
 package test_test
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/redpanda-data/benthos/v4/internal/cli/common"
 	"github.com/redpanda-data/benthos/v4/internal/cli/test"
 	"github.com/redpanda-data/benthos/v4/internal/log"
@@ -259,3 +263,70 @@ tests:
 		t.Error("Unexpected result")
 	}
 }
+
+func TestCommandRunMappings(t *testing.T) {
+	testDir, err := initTestFiles(t, map[string]string{
+		"lib.blobl": `map upper_name {
+  root.name = this.name.uppercase()
+}
+
+#test applies the map
+#  input: {"name":"foo"}
+#  mapping: root = this.apply("upper_name")
+#  output:
+#    json_equals: {"name":"FOO"}
+`,
+		"mapping.blobl": `import "./lib.blobl"
+
+root = this.apply("upper_name")
+meta topic = @topic.uppercase()
+
+#test uppercases the name
+#  input: {"name":"foo"}
+#  metadata:
+#    topic: bar
+#  output:
+#    json_equals: {"name":"FOO"}
+#    metadata_equals:
+#      topic: BAR
+
+#test fails without a name
+#  input: {}
+#  metadata: {"topic":"bar"}
+#  error: expected string value
+`,
+		"no_tests.blobl": `root = this`,
+	})
+	require.NoError(t, err)
+
+	targets, err := test.GetMappingTestTargets([]string{testDir + "/..."})
+	require.NoError(t, err)
+	require.Len(t, targets, 2)
+	require.Len(t, targets[filepath.Join(testDir, "mapping.blobl")], 2)
+
+	var stdout bytes.Buffer
+	opts := common.NewCLIOpts("", "")
+	opts.Stdout = &stdout
+
+	assert.True(t, test.RunMappings(opts, []string{testDir + "/..."}, log.Noop()), stdout.String())
+	assert.True(t, test.RunAll(opts, []string{filepath.Join(testDir, "mapping.blobl")}, "_benthos_test", true, log.Noop(), nil), stdout.String())
+
+	require.NoError(t, os.WriteFile(filepath.Join(testDir, "mapping.blobl"), []byte(`root.name = this.name.lowercase()
+
+#test uppercases the name
+#  input: {"name":"foo"}
+#  output:
+#    json_equals: {"name":"FOO"}
+
+#test fails without a name
+#  input: {"name":"foo"}
+#  error: expected string value
+`), 0o644))
+
+	stdout.Reset()
+	assert.False(t, test.RunMappings(opts, []string{filepath.Join(testDir, "mapping.blobl")}, log.Noop()))
+	assert.Contains(t, stdout.String(), "uppercases the name [line 3]:")
+	assert.Contains(t, stdout.String(), "JSON content mismatch")
+	assert.Contains(t, stdout.String(), "fails without a name [line 8]:")
+	assert.Contains(t, stdout.String(), "expected an error containing expected string value, but no error occurred")
+}
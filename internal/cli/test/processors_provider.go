@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/Jeffail/gabs/v2"
@@ -83,8 +84,21 @@ func (p *ProcessorsProvider) ProvideBloblang(pathStr string) ([]processor.V1, er
 		return nil, err
 	}
 
+	return p.provideMapping(pathStr, string(mappingBytes))
+}
+
+// ProvideBloblangImport parses a Bloblang mapping that imports the maps of a
+// Bloblang file and returns a processor slice that executes it.
+func (p *ProcessorsProvider) ProvideBloblangImport(pathStr, mapping string) ([]processor.V1, error) {
+	if !filepath.IsAbs(pathStr) {
+		pathStr = filepath.Join(filepath.Dir(p.targetPath), pathStr)
+	}
+	return p.provideMapping(pathStr, "import "+strconv.Quote(filepath.Base(pathStr))+"\n"+mapping)
+}
+
+func (p *ProcessorsProvider) provideMapping(pathStr, mapping string) ([]processor.V1, error) {
 	pCtx := parser.GlobalContext().WithImporterRelativeToFile(pathStr)
-	exec, mapErr := parser.ParseMapping(pCtx, string(mappingBytes))
+	exec, mapErr := parser.ParseMapping(pCtx, mapping)
 	if mapErr != nil {
 		return nil, mapErr
 	}
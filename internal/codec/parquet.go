@@ -0,0 +1,474 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+
+	"github.com/redpanda-data/benthos/v4/internal/value"
+)
+
+// ParquetTypes lists the column types supported by ParquetColumn.
+var ParquetTypes = []string{"BOOLEAN", "INT32", "INT64", "FLOAT", "DOUBLE", "BYTE_ARRAY", "UTF8"}
+
+// ParquetCompressionCodecs lists the compression codecs supported by parquet
+// writers.
+var ParquetCompressionCodecs = []string{"uncompressed", "snappy", "gzip", "brotli", "zstd", "lz4_raw"}
+
+// ParquetColumn describes a column of a parquet schema, where a column with
+// fields is a group of nested columns.
+type ParquetColumn struct {
+	Name     string
+	Type     string
+	Optional bool
+	Repeated bool
+	Fields   []ParquetColumn
+}
+
+func (c ParquetColumn) node() (parquet.Node, error) {
+	var n parquet.Node
+	if len(c.Fields) > 0 {
+		if c.Type != "" {
+			return nil, fmt.Errorf("column %v must not specify both a type and fields", c.Name)
+		}
+		group := parquet.Group{}
+		for _, f := range c.Fields {
+			fn, err := f.node()
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := group[f.Name]; exists {
+				return nil, fmt.Errorf("duplicate column name %v", f.Name)
+			}
+			group[f.Name] = fn
+		}
+		n = group
+	} else {
+		switch strings.ToUpper(c.Type) {
+		case "BOOLEAN":
+			n = parquet.Leaf(parquet.BooleanType)
+		case "INT32":
+			n = parquet.Int(32)
+		case "INT64":
+			n = parquet.Int(64)
+		case "FLOAT":
+			n = parquet.Leaf(parquet.FloatType)
+		case "DOUBLE":
+			n = parquet.Leaf(parquet.DoubleType)
+		case "BYTE_ARRAY":
+			n = parquet.Leaf(parquet.ByteArrayType)
+		case "UTF8":
+			n = parquet.String()
+		case "":
+			return nil, fmt.Errorf("column %v must specify either a type or fields", c.Name)
+		default:
+			return nil, fmt.Errorf("column %v has unsupported type %v, expected one of %v", c.Name, c.Type, ParquetTypes)
+		}
+	}
+	if c.Optional && c.Repeated {
+		return nil, fmt.Errorf("column %v cannot be both optional and repeated", c.Name)
+	}
+	if c.Optional {
+		n = parquet.Optional(n)
+	}
+	if c.Repeated {
+		n = parquet.Repeated(n)
+	}
+	return n, nil
+}
+
+// NewParquetSchema creates a parquet schema from a list of columns.
+func NewParquetSchema(columns []ParquetColumn) (*parquet.Schema, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("schema must contain at least one column")
+	}
+	root, err := ParquetColumn{Name: "root", Fields: columns}.node()
+	if err != nil {
+		return nil, err
+	}
+	return parquet.NewSchema("benthos", root), nil
+}
+
+// InferParquetSchema creates a parquet schema from the shape of a structured
+// value, which must be an object. All inferred columns are optional, arrays
+// become repeated columns and objects become groups. Fields with a null value,
+// an empty array or an empty object are inferred as strings.
+func InferParquetSchema(v any) (*parquet.Schema, error) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an object in order to infer a schema, got %v", value.ITypeOf(v))
+	}
+	root, err := inferParquetGroup(obj)
+	if err != nil {
+		return nil, err
+	}
+	if len(root) == 0 {
+		return nil, errors.New("unable to infer a schema from an empty object")
+	}
+	return parquet.NewSchema("benthos", root), nil
+}
+
+func inferParquetGroup(obj map[string]any) (parquet.Group, error) {
+	group := parquet.Group{}
+	for k, v := range obj {
+		if arr, ok := v.([]any); ok {
+			var elem any
+			for _, e := range arr {
+				if e != nil {
+					elem = e
+					break
+				}
+			}
+			if _, nested := elem.([]any); nested {
+				return nil, fmt.Errorf("field %v: nested arrays are not supported", k)
+			}
+			n, err := inferParquetNode(elem)
+			if err != nil {
+				return nil, fmt.Errorf("field %v: %w", k, err)
+			}
+			group[k] = parquet.Repeated(n)
+			continue
+		}
+		n, err := inferParquetNode(v)
+		if err != nil {
+			return nil, fmt.Errorf("field %v: %w", k, err)
+		}
+		group[k] = parquet.Optional(n)
+	}
+	return group, nil
+}
+
+func inferParquetNode(v any) (parquet.Node, error) {
+	switch t := v.(type) {
+	case bool:
+		return parquet.Leaf(parquet.BooleanType), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return parquet.Int(64), nil
+	case float32, float64:
+		return parquet.Leaf(parquet.DoubleType), nil
+	case json.Number:
+		if _, err := t.Int64(); err == nil {
+			return parquet.Int(64), nil
+		}
+		return parquet.Leaf(parquet.DoubleType), nil
+	case []byte:
+		return parquet.Leaf(parquet.ByteArrayType), nil
+	case map[string]any:
+		if len(t) == 0 {
+			return parquet.String(), nil
+		}
+		return inferParquetGroup(t)
+	case string, time.Time, nil:
+		return parquet.String(), nil
+	}
+	return nil, fmt.Errorf("unable to infer a column type from %T", v)
+}
+
+// ParquetCompression returns a compression codec by name.
+func ParquetCompression(name string) (compress.Codec, error) {
+	switch name {
+	case "uncompressed":
+		return &parquet.Uncompressed, nil
+	case "snappy":
+		return &parquet.Snappy, nil
+	case "gzip":
+		return &parquet.Gzip, nil
+	case "brotli":
+		return &parquet.Brotli, nil
+	case "zstd":
+		return &parquet.Zstd, nil
+	case "lz4_raw":
+		return &parquet.Lz4Raw, nil
+	}
+	return nil, fmt.Errorf("compression codec not recognised: %v, expected one of %v", name, ParquetCompressionCodecs)
+}
+
+//------------------------------------------------------------------------------
+
+// coerceParquetRow converts a structured value into a row matching the schema
+// exactly, since the parquet library panics when it encounters a value of the
+// wrong type.
+func coerceParquetRow(schema *parquet.Schema, v any) (map[string]any, error) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an object, got %v", value.ITypeOf(v))
+	}
+	return coerceParquetGroup(schema.Fields(), obj, "")
+}
+
+func coerceParquetGroup(fields []parquet.Field, obj map[string]any, path string) (map[string]any, error) {
+	row := make(map[string]any, len(fields))
+	for _, f := range fields {
+		fPath := f.Name()
+		if path != "" {
+			fPath = path + "." + fPath
+		}
+		v, err := coerceParquetField(f, obj[f.Name()], fPath)
+		if err != nil {
+			return nil, err
+		}
+		row[f.Name()] = v
+	}
+	return row, nil
+}
+
+func coerceParquetField(node parquet.Node, v any, path string) (any, error) {
+	if v == nil {
+		if node.Required() {
+			return nil, fmt.Errorf("field %v: required value is missing", path)
+		}
+		return nil, nil
+	}
+	if !node.Repeated() {
+		return coerceParquetValue(node, v, path)
+	}
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("field %v: expected an array, got %v", path, value.ITypeOf(v))
+	}
+	res := make([]any, len(arr))
+	for i, e := range arr {
+		if e == nil {
+			return nil, fmt.Errorf("field %v: null values within arrays are not supported", path)
+		}
+		var err error
+		if res[i], err = coerceParquetValue(node, e, path); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func coerceParquetValue(node parquet.Node, v any, path string) (res any, err error) {
+	if !node.Leaf() {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("field %v: expected an object, got %v", path, value.ITypeOf(v))
+		}
+		return coerceParquetGroup(node.Fields(), obj, path)
+	}
+	switch node.Type().Kind() {
+	case parquet.Boolean:
+		res, err = value.IToBool(v)
+	case parquet.Int32:
+		res, err = value.IToInt32(v)
+	case parquet.Int64:
+		res, err = value.IToInt(v)
+	case parquet.Float:
+		res, err = value.IToFloat32(v)
+	case parquet.Double:
+		res, err = value.IToFloat64(v)
+	case parquet.ByteArray:
+		if t, ok := v.(time.Time); ok {
+			v = t.Format(time.RFC3339Nano)
+		}
+		res = value.IToBytes(v)
+	default:
+		err = fmt.Errorf("unsupported column type %v", node.Type())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("field %v: %w", path, err)
+	}
+	return res, nil
+}
+
+//------------------------------------------------------------------------------
+
+// ParquetWriterConfig contains the settings of a parquet writer.
+type ParquetWriterConfig struct {
+	// Schema of the written rows, when nil the schema is inferred from the
+	// first row.
+	Schema *parquet.Schema
+
+	// Compression is the name of the codec used to compress pages.
+	Compression string
+
+	// RowGroupSize is the maximum number of rows within each row group, a row
+	// group is flushed once it reaches this size. Zero means the library
+	// default is used.
+	RowGroupSize int
+}
+
+// ParquetWriter encodes structured values as rows of a parquet file.
+type ParquetWriter struct {
+	w     io.Writer
+	conf  ParquetWriterConfig
+	codec compress.Codec
+
+	pw      *parquet.GenericWriter[map[string]any]
+	pending int
+}
+
+// NewParquetWriter creates a writer of parquet rows to an io.Writer. The
+// output is only a valid parquet file once the writer is closed.
+func NewParquetWriter(w io.Writer, conf ParquetWriterConfig) (*ParquetWriter, error) {
+	codec, err := ParquetCompression(conf.Compression)
+	if err != nil {
+		return nil, err
+	}
+	return &ParquetWriter{w: w, conf: conf, codec: codec}, nil
+}
+
+// Write a structured value as a row.
+func (p *ParquetWriter) Write(v any) (err error) {
+	if p.pw == nil {
+		schema := p.conf.Schema
+		if schema == nil {
+			if schema, err = InferParquetSchema(v); err != nil {
+				return err
+			}
+		}
+		p.pw = parquet.NewGenericWriter[map[string]any](p.w, schema, parquet.Compression(p.codec))
+	}
+
+	row, err := coerceParquetRow(p.pw.Schema(), v)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to encode row: %v", r)
+		}
+	}()
+	if _, err = p.pw.Write([]map[string]any{row}); err != nil {
+		return err
+	}
+	if p.pending++; p.conf.RowGroupSize > 0 && p.pending >= p.conf.RowGroupSize {
+		p.pending = 0
+		return p.pw.Flush()
+	}
+	return nil
+}
+
+// Close flushes any pending rows and writes the file footer, the underlying
+// io.Writer is not closed.
+func (p *ParquetWriter) Close() error {
+	if p.pw == nil {
+		// No rows were written and therefore a schema is not available when
+		// it's inferred, in which case there is nothing we can write.
+		if p.conf.Schema == nil {
+			return nil
+		}
+		p.pw = parquet.NewGenericWriter[map[string]any](p.w, p.conf.Schema, parquet.Compression(p.codec))
+	}
+	return p.pw.Close()
+}
+
+//------------------------------------------------------------------------------
+
+// ParquetReader reads the rows of a parquet file one row group at a time,
+// which means only a limited number of rows are held in memory at once.
+type ParquetReader struct {
+	groups []parquet.RowGroup
+	rgr    *parquet.GenericReader[any]
+
+	buf    []any
+	bufIdx int
+}
+
+const parquetReadBufferSize = 128
+
+// NewParquetReader creates a reader of the rows of a parquet file.
+func NewParquetReader(r io.ReaderAt, size int64) (pr *ParquetReader, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to open parquet file: %v", r)
+		}
+	}()
+	f, err := parquet.OpenFile(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return &ParquetReader{groups: f.RowGroups()}, nil
+}
+
+// NewParquetReaderFromBytes creates a reader of the rows of a parquet file held
+// in memory.
+func NewParquetReaderFromBytes(b []byte) (*ParquetReader, error) {
+	return NewParquetReader(bytes.NewReader(b), int64(len(b)))
+}
+
+// Next returns the next row of the file, or io.EOF once all rows have been
+// read.
+func (p *ParquetReader) Next() (row any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to read row: %v", r)
+		}
+	}()
+	for p.bufIdx >= len(p.buf) {
+		if err := p.fill(); err != nil {
+			return nil, err
+		}
+	}
+	row = p.buf[p.bufIdx]
+	p.buf[p.bufIdx] = nil
+	p.bufIdx++
+	return row, nil
+}
+
+func (p *ParquetReader) fill() error {
+	if p.rgr == nil {
+		if len(p.groups) == 0 {
+			return io.EOF
+		}
+		p.rgr = parquet.NewGenericRowGroupReader[any](p.groups[0])
+		p.groups = p.groups[1:]
+	}
+
+	if cap(p.buf) == 0 {
+		p.buf = make([]any, parquetReadBufferSize)
+	}
+	p.buf = p.buf[:cap(p.buf)]
+
+	n, err := p.rgr.Read(p.buf)
+	p.buf, p.bufIdx = p.buf[:n], 0
+	if errors.Is(err, io.EOF) {
+		err = p.rgr.Close()
+		p.rgr = nil
+	}
+	return err
+}
+
+// Close the reader.
+func (p *ParquetReader) Close() error {
+	p.groups = nil
+	if p.rgr != nil {
+		err := p.rgr.Close()
+		p.rgr = nil
+		return err
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// readerAtSize attempts to obtain an io.ReaderAt and the size of its content
+// from a reader, which is possible without buffering when reading from a file.
+// Otherwise the content is read fully into memory.
+func readerAtSize(r io.Reader) (io.ReaderAt, int64, error) {
+	if f, ok := r.(interface {
+		io.ReaderAt
+		Stat() (fs.FileInfo, error)
+	}); ok {
+		if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+			return f, info.Size(), nil
+		}
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bytes.NewReader(b), int64(len(b)), nil
+}
@@ -0,0 +1,200 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readAllParquetRows(t *testing.T, b []byte) []any {
+	t.Helper()
+
+	r, err := NewParquetReaderFromBytes(b)
+	require.NoError(t, err)
+
+	var rows []any
+	for {
+		row, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+	require.NoError(t, r.Close())
+	return rows
+}
+
+func TestParquetSchemaErrors(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		columns []ParquetColumn
+		err     string
+	}{
+		{
+			name: "empty",
+			err:  "schema must contain at least one column",
+		},
+		{
+			name:    "no type",
+			columns: []ParquetColumn{{Name: "foo"}},
+			err:     "column foo must specify either a type or fields",
+		},
+		{
+			name:    "bad type",
+			columns: []ParquetColumn{{Name: "foo", Type: "NOPE"}},
+			err:     "column foo has unsupported type NOPE, expected one of [BOOLEAN INT32 INT64 FLOAT DOUBLE BYTE_ARRAY UTF8]",
+		},
+		{
+			name:    "optional and repeated",
+			columns: []ParquetColumn{{Name: "foo", Type: "UTF8", Optional: true, Repeated: true}},
+			err:     "column foo cannot be both optional and repeated",
+		},
+		{
+			name:    "duplicate",
+			columns: []ParquetColumn{{Name: "foo", Type: "UTF8"}, {Name: "foo", Type: "INT64"}},
+			err:     "duplicate column name foo",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewParquetSchema(test.columns)
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestParquetWriterSchema(t *testing.T) {
+	schema, err := NewParquetSchema([]ParquetColumn{
+		{Name: "id", Type: "INT64"},
+		{Name: "score", Type: "FLOAT", Optional: true},
+		{Name: "active", Type: "BOOLEAN", Optional: true},
+		{Name: "labels", Type: "UTF8", Repeated: true},
+		{Name: "doc", Optional: true, Fields: []ParquetColumn{
+			{Name: "count", Type: "INT32"},
+			{Name: "raw", Type: "BYTE_ARRAY", Optional: true},
+		}},
+	})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, ParquetWriterConfig{
+		Schema:      schema,
+		Compression: "zstd",
+	})
+	require.NoError(t, err)
+
+	// Values are coerced to the column types and unknown fields are ignored.
+	require.NoError(t, w.Write(map[string]any{
+		"id":     "10",
+		"score":  1,
+		"active": "true",
+		"labels": []any{"a", 5},
+		"doc":    map[string]any{"count": 3.0, "raw": "hello"},
+		"nope":   "ignored",
+	}))
+	require.NoError(t, w.Write(map[string]any{
+		"id": 11,
+	}))
+
+	assert.EqualError(t, w.Write(map[string]any{"score": 1}), "field id: required value is missing")
+	assert.EqualError(t, w.Write(map[string]any{"id": 1.5}), "field id: float value contains decimals and therefore cannot be cast as a signed integer, if you intend to round the value then call `.round()` explicitly before this cast")
+	assert.EqualError(t, w.Write(map[string]any{"id": 1, "labels": "a"}), "field labels: expected an array, got string")
+	assert.EqualError(t, w.Write(map[string]any{"id": 1, "doc": map[string]any{}}), "field doc.count: required value is missing")
+	assert.EqualError(t, w.Write("nope"), "expected an object, got string")
+
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []any{
+		map[string]any{
+			"id":     int64(10),
+			"score":  float32(1),
+			"active": true,
+			"labels": []any{"a", "5"},
+			"doc":    map[string]any{"count": int32(3), "raw": "hello"},
+		},
+		map[string]any{
+			"id":     int64(11),
+			"score":  nil,
+			"active": nil,
+			"labels": []any{},
+			"doc":    nil,
+		},
+	}, readAllParquetRows(t, buf.Bytes()))
+}
+
+func TestParquetWriterInferred(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, ParquetWriterConfig{Compression: "snappy"})
+	require.NoError(t, err)
+
+	require.NoError(t, w.Write(map[string]any{
+		"name":   "foo",
+		"count":  5,
+		"ratio":  0.5,
+		"ok":     true,
+		"empty":  nil,
+		"nested": map[string]any{"tags": []any{"a"}},
+	}))
+	require.NoError(t, w.Write(map[string]any{
+		"name":  "bar",
+		"count": "6",
+		"empty": 7,
+	}))
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []any{
+		map[string]any{
+			"name":   "foo",
+			"count":  int64(5),
+			"ratio":  0.5,
+			"ok":     true,
+			"empty":  nil,
+			"nested": map[string]any{"tags": []any{"a"}},
+		},
+		map[string]any{
+			"name":   "bar",
+			"count":  int64(6),
+			"ratio":  nil,
+			"ok":     nil,
+			"empty":  "7",
+			"nested": nil,
+		},
+	}, readAllParquetRows(t, buf.Bytes()))
+
+	_, err = InferParquetSchema(map[string]any{"foo": []any{[]any{"bar"}}})
+	require.EqualError(t, err, "field foo: nested arrays are not supported")
+
+	_, err = InferParquetSchema(map[string]any{})
+	require.EqualError(t, err, "unable to infer a schema from an empty object")
+}
+
+func TestParquetStreamWriterOptions(t *testing.T) {
+	for _, codec := range []string{"parquet", "parquet:compression=gzip", "parquet:compression=lz4_raw,row_group_size=10"} {
+		_, ok, err := GetStreamWriter(codec)
+		require.NoError(t, err, codec)
+		assert.True(t, ok, codec)
+	}
+
+	for codec, errStr := range map[string]string{
+		"parquet:compression=nope":   "compression codec not recognised: nope, expected one of [uncompressed snappy gzip brotli zstd lz4_raw]",
+		"parquet:row_group_size=0":   "invalid row_group_size for parquet codec: 0",
+		"parquet:nope=10":            "parquet codec option not recognised: nope=10",
+		"parquet:row_group_size=abc": "invalid row_group_size for parquet codec: abc",
+	} {
+		_, _, err := GetStreamWriter(codec)
+		require.EqualError(t, err, errStr, codec)
+	}
+
+	_, ok, err := GetStreamWriter("lines")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = GetWriter("parquet")
+	require.EqualError(t, err, "codec parquet is only supported by file based outputs")
+}
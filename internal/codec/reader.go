@@ -44,6 +44,7 @@ func NewReaderDocs(name string) docs.FieldSpec {
 		"pgzip", "Decompress a gzip file in parallel, this codec should precede another codec, e.g. `pgzip/all-bytes`, `pgzip/tar`, `pgzip/csv`, etc.",
 		"lines", "Consume the file in segments divided by linebreaks.",
 		"multipart", "Consumes the output of another codec and batches messages together. A batch ends when an empty message is consumed. For example, the codec `lines/multipart` could be used to consume multipart messages where an empty line indicates the end of each batch.",
+		"parquet", "EXPERIMENTAL: Consume the rows of a Parquet file as structured messages, reading one row group at a time. Reading from a file directly avoids loading it into memory, but when preceded by another codec such as `gzip` the entire file is buffered in memory first.",
 		"regex:(?m)^\\d\\d:\\d\\d:\\d\\d", "Consume the file in segments divided by regular expression.",
 		"skipbom", "Skip one or more byte order marks for each opened reader, this codec should precede another codec, e.g. `skipbom/csv`, etc.",
 		"tar", "Parse the file as a tar archive, and consume each file of the archive as a message.",
@@ -254,6 +255,10 @@ func partReader(codec string, conf ReaderConfig) (ReaderConstructor, bool, error
 		return func(path string, r io.ReadCloser, fn ReaderAckFn) (Reader, error) {
 			return newCSVSafeReader(r, fn, nil)
 		}, true, nil
+	case "parquet":
+		return func(path string, r io.ReadCloser, fn ReaderAckFn) (Reader, error) {
+			return newParquetReader(r, fn)
+		}, true, nil
 	case "tar":
 		return newTarReader, true, nil
 	}
@@ -361,6 +366,8 @@ func autoCodec(conf ReaderConfig) ReaderConstructor {
 			codec = "csv"
 		case ".csv.gz", ".csv.gzip":
 			codec = "gzip/csv"
+		case ".parquet":
+			codec = "parquet"
 		case ".tar":
 			codec = "tar"
 		case ".tgz":
@@ -541,6 +548,86 @@ func (a *avroOCFReader) Close(ctx context.Context) error {
 
 //------------------------------------------------------------------------------
 
+type parquetReader struct {
+	pr        *ParquetReader
+	r         io.ReadCloser
+	sourceAck ReaderAckFn
+
+	mut      sync.Mutex
+	finished bool
+	pending  int32
+}
+
+func newParquetReader(r io.ReadCloser, ackFn ReaderAckFn) (Reader, error) {
+	ra, size, err := readerAtSize(r)
+	if err != nil {
+		return nil, err
+	}
+	pr, err := NewParquetReader(ra, size)
+	if err != nil {
+		return nil, err
+	}
+	return &parquetReader{
+		pr:        pr,
+		r:         r,
+		sourceAck: ackOnce(ackFn),
+	}, nil
+}
+
+func (a *parquetReader) ack(ctx context.Context, err error) error {
+	a.mut.Lock()
+	a.pending--
+	doAck := a.pending == 0 && a.finished
+	a.mut.Unlock()
+
+	if err != nil {
+		return a.sourceAck(ctx, err)
+	}
+	if doAck {
+		return a.sourceAck(ctx, nil)
+	}
+	return nil
+}
+
+func (a *parquetReader) Next(ctx context.Context) ([]*message.Part, ReaderAckFn, error) {
+	row, err := a.pr.Next()
+
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			a.finished = true
+		} else {
+			_ = a.sourceAck(ctx, err)
+		}
+		return nil, nil, err
+	}
+
+	a.pending++
+
+	part := message.NewPart(nil)
+	part.SetStructuredMut(row)
+
+	return []*message.Part{part}, a.ack, nil
+}
+
+func (a *parquetReader) Close(ctx context.Context) error {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	if !a.finished {
+		_ = a.sourceAck(ctx, errors.New("service shutting down"))
+	}
+	if a.pending == 0 {
+		_ = a.sourceAck(ctx, nil)
+	}
+	_ = a.pr.Close()
+	return a.r.Close()
+}
+
+//------------------------------------------------------------------------------
+
 type linesReader struct {
 	buf       *bufio.Scanner
 	r         io.ReadCloser
@@ -458,6 +458,65 @@ func TestPSVReader(t *testing.T) {
 	testReaderSuite(t, "csv:|", "", data)
 }
 
+func parquetTestData(t *testing.T, codec string, rows ...any) []byte {
+	t.Helper()
+
+	ctor, ok, err := GetStreamWriter(codec)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	var buf bytes.Buffer
+	w, err := ctor(&buf)
+	require.NoError(t, err)
+	for _, r := range rows {
+		require.NoError(t, w.Write(r))
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestParquetReader(t *testing.T) {
+	data := parquetTestData(t, "parquet:row_group_size=2",
+		map[string]any{"id": 1, "name": "foo", "tags": []any{"a", "b"}},
+		map[string]any{"id": 2, "name": "bar"},
+		map[string]any{"id": 3, "tags": []any{"c"}},
+	)
+	testReaderSuite(
+		t, "parquet", "", data,
+		`{"id":1,"name":"foo","tags":["a","b"]}`,
+		`{"id":2,"name":"bar","tags":[]}`,
+		`{"id":3,"name":null,"tags":["c"]}`,
+	)
+	testReaderSuite(
+		t, "auto", "foo.parquet", data,
+		`{"id":1,"name":"foo","tags":["a","b"]}`,
+		`{"id":2,"name":"bar","tags":[]}`,
+		`{"id":3,"name":null,"tags":["c"]}`,
+	)
+
+	var gzipBuf bytes.Buffer
+	zw := gzip.NewWriter(&gzipBuf)
+	_, _ = zw.Write(data)
+	zw.Close()
+
+	testReaderSuite(
+		t, "gzip/parquet", "", gzipBuf.Bytes(),
+		`{"id":1,"name":"foo","tags":["a","b"]}`,
+		`{"id":2,"name":"bar","tags":[]}`,
+		`{"id":3,"name":null,"tags":["c"]}`,
+	)
+}
+
+func TestParquetReaderInvalid(t *testing.T) {
+	ctor, err := GetReader("parquet", NewReaderConfig())
+	require.NoError(t, err)
+
+	_, err = ctor("", noopCloser{bytes.NewReader([]byte("not a parquet file")), false}, func(ctx context.Context, err error) error {
+		return nil
+	})
+	require.Error(t, err)
+}
+
 func TestAutoReader(t *testing.T) {
 	data := []byte("col1,col2,col3\nfoo1,bar1,baz1\nfoo2,bar2,baz2\nfoo3,bar3,baz3")
 	testReaderSuite(
@@ -6,6 +6,8 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
 
 	"github.com/redpanda-data/benthos/v4/internal/docs"
@@ -20,6 +22,7 @@ func NewWriterDocs(name string) docs.FieldSpec {
 		"append", "Append each message to the output stream without any delimiter or special encoding.",
 		"lines", "Append each message to the output stream followed by a line break.",
 		"delim:x", "Append each message to the output stream followed by a custom delimiter.",
+		"parquet", "EXPERIMENTAL: Only applicable to file based outputs. Writes structured messages as the rows of a Parquet file, with a schema inferred from the first message written to each file. The file is only valid once it is closed, which happens when the path changes or the output shuts down. Options can be added as comma separated `key=value` pairs, e.g. `parquet:compression=zstd,row_group_size=10000`, where `compression` is one of `uncompressed` (default), `snappy`, `gzip`, `brotli`, `zstd` or `lz4_raw` and `row_group_size` is the maximum number of rows within each row group.",
 	).LinterBlobl("")
 }
 
@@ -51,9 +54,64 @@ func GetWriter(codec string) (sFn SuffixFn, appendMode bool, err error) {
 		}
 		return customDelimSuffixFn(by), true, nil
 	}
+	if isParquetCodec(codec) {
+		return nil, false, fmt.Errorf("codec %v is only supported by file based outputs", codec)
+	}
 	return nil, false, fmt.Errorf("codec was not recognised: %v", codec)
 }
 
+//------------------------------------------------------------------------------
+
+// Writer is a codec type that encodes structured values into a single stream
+// that must be finalised once writing is complete, such as a file format with
+// a footer.
+type Writer interface {
+	Write(v any) error
+	Close() error
+}
+
+// WriterConstructor creates a Writer that encodes into an io.Writer.
+type WriterConstructor func(io.Writer) (Writer, error)
+
+// GetStreamWriter returns a constructor for codec writers that encode
+// structured values into a stream, or false if the codec is not of that kind,
+// in which case GetWriter should be used instead.
+func GetStreamWriter(codec string) (WriterConstructor, bool, error) {
+	if !isParquetCodec(codec) {
+		return nil, false, nil
+	}
+
+	conf := ParquetWriterConfig{Compression: "uncompressed"}
+	if opts, ok := strings.CutPrefix(codec, "parquet:"); ok {
+		for _, opt := range strings.Split(opts, ",") {
+			k, v, _ := strings.Cut(opt, "=")
+			switch k {
+			case "compression":
+				conf.Compression = v
+			case "row_group_size":
+				n, err := strconv.Atoi(v)
+				if err != nil || n <= 0 {
+					return nil, false, fmt.Errorf("invalid row_group_size for parquet codec: %v", v)
+				}
+				conf.RowGroupSize = n
+			default:
+				return nil, false, fmt.Errorf("parquet codec option not recognised: %v", opt)
+			}
+		}
+	}
+	if _, err := ParquetCompression(conf.Compression); err != nil {
+		return nil, false, err
+	}
+
+	return func(w io.Writer) (Writer, error) {
+		return NewParquetWriter(w, conf)
+	}, true, nil
+}
+
+func isParquetCodec(codec string) bool {
+	return codec == "parquet" || strings.HasPrefix(codec, "parquet:")
+}
+
 func customDelimSuffixFn(suffix string) SuffixFn {
 	suffixB := []byte(suffix)
 	return func(data []byte) ([]byte, bool) {
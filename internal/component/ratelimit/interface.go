@@ -20,3 +20,11 @@ type V1 interface {
 	// is cancelled.
 	Close(ctx context.Context) error
 }
+
+// KeyedV1 is an optional interface implemented by rate limits that are able to
+// track a separate limit for each of an arbitrary number of keys.
+type KeyedV1 interface {
+	// AccessKey is equivalent to Access but the limit is applied only to
+	// requests that share the same key.
+	AccessKey(ctx context.Context, key string) (time.Duration, error)
+}
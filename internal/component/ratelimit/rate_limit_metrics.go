@@ -40,6 +40,23 @@ func (r *metricsRateLimit) Access(ctx context.Context) (time.Duration, error) {
 	return tout, err
 }
 
+// AccessKey forwards the request to the wrapped rate limit when it supports
+// keyed access, otherwise the key is ignored and the shared limit is used.
+func (r *metricsRateLimit) AccessKey(ctx context.Context, key string) (time.Duration, error) {
+	kr, ok := r.r.(KeyedV1)
+	if !ok {
+		return r.Access(ctx)
+	}
+	r.mChecked.Incr(1)
+	tout, err := kr.AccessKey(ctx, key)
+	if err != nil {
+		r.mErr.Incr(1)
+	} else if tout > 0 {
+		r.mLimited.Incr(1)
+	}
+	return tout, err
+}
+
 func (r *metricsRateLimit) Close(ctx context.Context) error {
 	return r.r.Close(ctx)
 }
@@ -5,6 +5,7 @@ package config
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -41,13 +42,34 @@ func (e *ErrMissingEnvVars) Error() string {
 // respective environment variable will be read and will replace the pattern. If
 // the environment variable is empty or does not exist then either the default
 // value is used or the field will be left empty.
+//
+// Patterns of the form `${secret:provider:key}` are instead resolved by the
+// registered secret provider of the given name, and an error is returned if
+// any of them fail. Readers created with OptSkipSecrets leave these patterns
+// as they are, and readers created with OptRejectSecrets return
+// ErrSecretsRejected if any are found.
 func (r *Reader) ReplaceEnvVariables(ctx context.Context, inBytes []byte) (replaced []byte, err error) {
 	var missingVarsErr ErrMissingEnvVars
+	var secretErrs []error
+	var secretsRejected bool
 
 	replaced = envRegex.ReplaceAllFunc(inBytes, func(content []byte) []byte {
 		var value string
 		var ok bool
-		if len(content) > 3 {
+		if bytes.HasPrefix(content, []byte(secretReferencePrefix)) {
+			switch r.secretsMode {
+			case secretsModeSkip:
+				return content
+			case secretsModeReject:
+				secretsRejected = true
+				return content
+			}
+			var sErr error
+			if value, sErr = r.resolveSecret(ctx, string(content[len(secretReferencePrefix):len(content)-1])); sErr != nil {
+				secretErrs = append(secretErrs, sErr)
+			}
+			value = strings.ReplaceAll(value, "\n", "\\n")
+		} else if len(content) > 3 {
 			if colonIndex := bytes.IndexByte(content, ':'); colonIndex == -1 {
 				varName := string(content[2 : len(content)-1])
 				if value, ok = r.envLookupFunc(ctx, varName); !ok {
@@ -69,6 +91,12 @@ func (r *Reader) ReplaceEnvVariables(ctx context.Context, inBytes []byte) (repla
 	})
 	replaced = escapedEnvRegex.ReplaceAll(replaced, []byte("$$$1"))
 
+	if secretsRejected {
+		return nil, ErrSecretsRejected
+	}
+	if len(secretErrs) > 0 {
+		return nil, fmt.Errorf("failed to resolve secrets: %w", errors.Join(secretErrs...))
+	}
 	if len(missingVarsErr.Variables) > 0 {
 		missingVarsErr.BestAttempt = replaced
 		err = &missingVarsErr
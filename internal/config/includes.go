@@ -0,0 +1,330 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/redpanda-data/benthos/v4/internal/docs"
+)
+
+const (
+	// A scalar tagged with this is replaced by the contents of the YAML file at
+	// the path it contains, relative to the directory of the including file.
+	// When the including node is an element of a sequence and the file
+	// contains a sequence then its elements are spliced into the parent.
+	includeTag = "!include"
+
+	// Tags that change the way in which a node of an overlay is merged into
+	// the config. By default mappings are merged key by key, and any other
+	// node replaces the existing value.
+	overlayAppendTag  = "!append"
+	overlayPrependTag = "!prepend"
+	overlayReplaceTag = "!replace"
+
+	// Lines of nodes are offset by this amount multiplied by the index of the
+	// file they originated from whilst linting, which allows us to derive the
+	// original file and line of each lint.
+	sourceLineStride = 1 << 20
+)
+
+// configSources tracks the files that a config was assembled from with include
+// directives and overlays, and the file that each node originated from.
+type configSources struct {
+	// The first path is the root file of the config, which might be empty.
+	paths []string
+
+	// For each file the line within the root file of the directive that
+	// included it, either directly or transitively.
+	rootLines []int
+
+	// Nodes that did not originate from the root file.
+	nodes map[*yaml.Node]int
+
+	// Lints from reading files other than the root file, which should be
+	// formatted with either lintString or rootLint.
+	lints []docs.Lint
+}
+
+func newConfigSources(rootPath string) *configSources {
+	return &configSources{
+		paths:     []string{rootPath},
+		rootLines: []int{0},
+		nodes:     map[*yaml.Node]int{},
+	}
+}
+
+func (s *configSources) add(path string, rootLine int) int {
+	s.paths = append(s.paths, path)
+	s.rootLines = append(s.rootLines, rootLine)
+	return len(s.paths) - 1
+}
+
+// includedPaths returns the paths of all files other than the root file.
+func (s *configSources) includedPaths() []string {
+	return s.paths[1:]
+}
+
+func (s *configSources) mark(node *yaml.Node, index int) {
+	if _, exists := s.nodes[node]; !exists {
+		s.nodes[node] = index
+	}
+	for _, c := range node.Content {
+		s.mark(c, index)
+	}
+}
+
+func (s *configSources) offsetLines(node *yaml.Node, multiplier int) {
+	if index := s.nodes[node]; index > 0 {
+		node.Line += multiplier * index * sourceLineStride
+	}
+	for _, c := range node.Content {
+		s.offsetLines(c, multiplier)
+	}
+}
+
+// lintYAML lints a config node, where the lines of lints are encoded with the
+// file they refer to and should therefore be formatted with either lintString
+// or rootLint.
+func (s *configSources) lintYAML(ctx docs.LintContext, spec docs.FieldSpecs, root *yaml.Node) []docs.Lint {
+	if len(s.paths) == 1 {
+		return spec.LintYAML(ctx, root)
+	}
+
+	s.offsetLines(root, 1)
+	defer s.offsetLines(root, -1)
+	return spec.LintYAML(ctx, root)
+}
+
+func (s *configSources) decode(l docs.Lint) (docs.Lint, int) {
+	index := l.Line / sourceLineStride
+	if index <= 0 || index >= len(s.paths) {
+		return l, 0
+	}
+	l.Line -= index * sourceLineStride
+	return l, index
+}
+
+// lintString formats a lint returned by lintYAML prefixed with the path of the
+// file it refers to.
+func (s *configSources) lintString(l docs.Lint) string {
+	l, index := s.decode(l)
+	return fmt.Sprintf("%v%v", s.paths[index], l.Error())
+}
+
+// rootLint converts a lint returned by lintYAML into a lint of the root file,
+// where lints of included files are given the line of the directive that
+// included them and describe their original location.
+func (s *configSources) rootLint(l docs.Lint) docs.Lint {
+	l, index := s.decode(l)
+	if index == 0 {
+		return l
+	}
+	l.What = fmt.Sprintf("%v%v", s.paths[index], l.Error())
+	l.Line, l.Column = s.rootLines[index], 1
+	return l
+}
+
+//------------------------------------------------------------------------------
+
+// setIncludedPaths records the files included by a config file, replacing
+// those recorded from any previous read, so that changes to them can be
+// watched.
+func (r *Reader) setIncludedPaths(path string, included []string) {
+	for p, owners := range r.includedBy {
+		delete(owners, path)
+		if len(owners) == 0 {
+			delete(r.includedBy, p)
+		}
+	}
+	for _, p := range included {
+		owners, exists := r.includedBy[p]
+		if !exists {
+			owners = map[string]struct{}{}
+			r.includedBy[p] = owners
+		}
+		owners[path] = struct{}{}
+	}
+}
+
+// readSourceFile reads a config file, replaces environment variable
+// interpolations and resolves any include directives within it.
+func (r *Reader) readSourceFile(ctx context.Context, src *configSources, path string, index, rootLine int, stack []string) (node *yaml.Node, confBytes []byte, lints []docs.Lint, err error) {
+	var modTime time.Time
+	if confBytes, lints, modTime, err = r.ReadFileEnvSwap(ctx, path); err != nil {
+		return
+	}
+	r.modTimeLastRead[path] = modTime
+
+	if node, err = docs.UnmarshalYAML(confBytes); err != nil {
+		return
+	}
+	if node.Kind == 0 && index > 0 {
+		// Empty fragments are included as a null value.
+		node = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+
+	stack = append(stack, path)
+	if node, err = r.resolveIncludes(ctx, src, node, filepath.Dir(path), rootLine, stack); err != nil {
+		return
+	}
+	if index > 0 {
+		src.mark(node, index)
+		for i := range lints {
+			lints[i].Line += index * sourceLineStride
+		}
+	}
+	return
+}
+
+// readInclude reads the file referenced by an include directive.
+func (r *Reader) readInclude(ctx context.Context, src *configSources, directive *yaml.Node, dir string, rootLine int, stack []string) (*yaml.Node, error) {
+	if directive.Kind != yaml.ScalarNode || directive.Value == "" {
+		return nil, fmt.Errorf("line %v: include directive must be a file path", directive.Line)
+	}
+
+	path := directive.Value
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	path = filepath.Clean(path)
+	if slices.Contains(stack, path) {
+		return nil, fmt.Errorf("line %v: include of file %v is cyclic", directive.Line, path)
+	}
+
+	// The root line of files included by the root file is the line of the
+	// directive itself, otherwise it's inherited.
+	if rootLine == 0 {
+		rootLine = directive.Line
+	}
+
+	index := src.add(path, rootLine)
+	node, _, lints, err := r.readSourceFile(ctx, src, path, index, rootLine, stack)
+	if err != nil {
+		// We deliberately do not wrap the error here as a missing include must
+		// not be mistaken for a missing root file.
+		return nil, fmt.Errorf("line %v: failed to include %v: %v", directive.Line, path, err)
+	}
+	src.lints = append(src.lints, lints...)
+	return node, nil
+}
+
+func (r *Reader) resolveIncludes(ctx context.Context, src *configSources, node *yaml.Node, dir string, rootLine int, stack []string) (*yaml.Node, error) {
+	if node.Tag == includeTag {
+		return r.readInclude(ctx, src, node, dir, rootLine, stack)
+	}
+
+	switch node.Kind {
+	case yaml.SequenceNode:
+		content := make([]*yaml.Node, 0, len(node.Content))
+		for _, c := range node.Content {
+			resolved, err := r.resolveIncludes(ctx, src, c, dir, rootLine, stack)
+			if err != nil {
+				return nil, err
+			}
+			if c.Tag == includeTag && resolved.Kind == yaml.SequenceNode {
+				content = append(content, resolved.Content...)
+			} else {
+				content = append(content, resolved)
+			}
+		}
+		node.Content = content
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			resolved, err := r.resolveIncludes(ctx, src, node.Content[i], dir, rootLine, stack)
+			if err != nil {
+				return nil, err
+			}
+			node.Content[i] = resolved
+		}
+	}
+	return node, nil
+}
+
+// readConfigSources reads a root config file followed by a series of overlays,
+// resolving the include directives of each and merging the overlays in order.
+// The root path can be empty, in which case the overlays are merged into an
+// empty config.
+func (r *Reader) readConfigSources(ctx context.Context, rootPath string, overlayPaths []string) (node *yaml.Node, confBytes []byte, rootLints []docs.Lint, src *configSources, err error) {
+	src = newConfigSources(rootPath)
+	if rootPath != "" {
+		if node, confBytes, rootLints, err = r.readSourceFile(ctx, src, rootPath, 0, 0, nil); err != nil {
+			return
+		}
+	} else {
+		node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+
+	for _, p := range overlayPaths {
+		p = filepath.Clean(p)
+
+		var overlay *yaml.Node
+		var lints []docs.Lint
+		if overlay, _, lints, err = r.readSourceFile(ctx, src, p, src.add(p, 1), 1, nil); err != nil {
+			err = fmt.Errorf("failed to read overlay %v: %v", p, err)
+			return
+		}
+		src.lints = append(src.lints, lints...)
+		node = mergeOverlay(node, overlay)
+	}
+	stripOverlayTags(node)
+	return
+}
+
+// mergeOverlay merges an overlay node into a base node and returns the result.
+// Mappings are merged key by key, sequences tagged with !append or !prepend are
+// added to the end or start of the base sequence respectively, and all other
+// nodes, including those tagged with !replace, replace the base node.
+func mergeOverlay(base, overlay *yaml.Node) *yaml.Node {
+	switch overlay.Tag {
+	case overlayReplaceTag:
+		return overlay
+	case overlayAppendTag, overlayPrependTag:
+		if base.Kind != yaml.SequenceNode || overlay.Kind != yaml.SequenceNode {
+			return overlay
+		}
+		if overlay.Tag == overlayAppendTag {
+			base.Content = append(base.Content, overlay.Content...)
+		} else {
+			base.Content = append(slices.Clone(overlay.Content), base.Content...)
+		}
+		return base
+	}
+
+	if base.Kind != yaml.MappingNode || overlay.Kind != yaml.MappingNode {
+		return overlay
+	}
+
+	for i := 0; i < len(overlay.Content)-1; i += 2 {
+		key, value := overlay.Content[i], overlay.Content[i+1]
+
+		var merged bool
+		for j := 0; j < len(base.Content)-1; j += 2 {
+			if base.Content[j].Value == key.Value {
+				base.Content[j+1] = mergeOverlay(base.Content[j+1], value)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			base.Content = append(base.Content, key, value)
+		}
+	}
+	return base
+}
+
+func stripOverlayTags(node *yaml.Node) {
+	switch node.Tag {
+	case overlayAppendTag, overlayPrependTag, overlayReplaceTag:
+		node.Tag = ""
+	}
+	for _, c := range node.Content {
+		stripOverlayTags(c)
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/Jeffail/gabs/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/redpanda-data/benthos/v4/internal/manager"
+	"github.com/redpanda-data/benthos/v4/internal/stream"
+)
+
+func TestReaderIncludes(t *testing.T) {
+	testFS := &testFS{m: fstest.MapFS{
+		"main.yaml": &fstest.MapFile{
+			Data: []byte(`
+input: !include fragments/input.yaml
+pipeline:
+  processors:
+    - !include fragments/processors.yaml
+    - mapping: 'root = content() + " c"'
+output:
+  label: fooout
+  drop: {}
+`),
+		},
+		"fragments/input.yaml": &fstest.MapFile{
+			Data: []byte(`
+label: fooin
+generate:
+  mapping: !include ./mapping.blobl
+`),
+		},
+		"fragments/mapping.blobl": &fstest.MapFile{
+			Data: []byte(`'root = "foo"'`),
+		},
+		"fragments/processors.yaml": &fstest.MapFile{
+			Data: []byte(`
+- mapping: 'root = content() + " a"'
+- mapping: 'root = content() + " b"'
+`),
+		},
+	}}
+
+	rdr := newDummyReader("main.yaml", nil, OptUseFS(testFS))
+
+	conf, _, lints, err := rdr.Read()
+	require.NoError(t, err)
+	require.Empty(t, lints)
+
+	assert.Equal(t, "fooin", conf.Input.Label)
+	assert.Equal(t, "generate", conf.Input.Type)
+	assert.Equal(t, "fooout", conf.Output.Label)
+
+	require.Len(t, conf.Pipeline.Processors, 3)
+	for i, exp := range []string{
+		`root = content() + " a"`,
+		`root = content() + " b"`,
+		`root = content() + " c"`,
+	} {
+		assert.Equal(t, exp, conf.Pipeline.Processors[i].Plugin.(*yaml.Node).Value, i)
+	}
+
+	assert.Equal(t, map[string]map[string]struct{}{
+		"fragments/input.yaml":      {"main.yaml": {}},
+		"fragments/mapping.blobl":   {"main.yaml": {}},
+		"fragments/processors.yaml": {"main.yaml": {}},
+	}, rdr.includedBy)
+}
+
+func TestReaderIncludeLints(t *testing.T) {
+	testFS := &testFS{m: fstest.MapFS{
+		"main.yaml": &fstest.MapFile{
+			Data: []byte(`
+input:
+  label: fooin
+  generate:
+    mapping: 'root = "foo"'
+output: !include fragments/output.yaml
+nope: true
+`),
+		},
+		"fragments/output.yaml": &fstest.MapFile{
+			Data: []byte(`
+label: fooout
+drop: {}
+nope: true
+`),
+		},
+	}}
+
+	rdr := newDummyReader("main.yaml", nil, OptUseFS(testFS))
+
+	_, _, lints, err := rdr.Read()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"main.yaml(7,1) field nope not recognised",
+		"fragments/output.yaml(4,1) field nope is invalid when the component type is drop (output)",
+	}, lints)
+
+	_, dLints, err := rdr.ReadYAMLFileLinted(context.Background(), rdr.specFullConfig, "main.yaml", false, rdr.lintConf)
+	require.NoError(t, err)
+
+	var lintStrs []string
+	for _, l := range dLints {
+		lintStrs = append(lintStrs, l.Error())
+	}
+	assert.ElementsMatch(t, []string{
+		"(7,1) field nope not recognised",
+		"(6,1) fragments/output.yaml(4,1) field nope is invalid when the component type is drop (output)",
+	}, lintStrs)
+}
+
+func TestReaderIncludeErrors(t *testing.T) {
+	testFS := &testFS{m: fstest.MapFS{
+		"cyclic.yaml": &fstest.MapFile{
+			Data: []byte(`
+input: !include fragments/a.yaml
+`),
+		},
+		"fragments/a.yaml": &fstest.MapFile{
+			Data: []byte(`
+generate: !include b.yaml
+`),
+		},
+		"fragments/b.yaml": &fstest.MapFile{
+			Data: []byte(`
+mapping: !include a.yaml
+`),
+		},
+		"missing.yaml": &fstest.MapFile{
+			Data: []byte(`
+input: !include fragments/nope.yaml
+`),
+		},
+		"mapping.yaml": &fstest.MapFile{
+			Data: []byte(`
+input: !include
+  foo: bar
+`),
+		},
+	}}
+
+	for path, errContains := range map[string]string{
+		"cyclic.yaml":  "include of file fragments/a.yaml is cyclic",
+		"missing.yaml": "line 2: failed to include fragments/nope.yaml",
+		"mapping.yaml": "line 2: include directive must be a file path",
+	} {
+		_, _, _, err := newDummyReader(path, nil, OptUseFS(testFS)).Read()
+		require.Error(t, err, path)
+		assert.Contains(t, err.Error(), errContains, path)
+	}
+}
+
+func TestReaderOverlays(t *testing.T) {
+	testFS := &testFS{m: fstest.MapFS{
+		"main.yaml": &fstest.MapFile{
+			Data: []byte(`
+input:
+  label: fooin
+  generate:
+    mapping: 'root = "foo"'
+    interval: 1s
+pipeline:
+  processors:
+    - mapping: 'root = content() + " a"'
+output:
+  label: fooout
+  drop: {}
+logger:
+  level: INFO
+`),
+		},
+		"first.yaml": &fstest.MapFile{
+			Data: []byte(`
+input:
+  generate:
+    interval: 5s
+pipeline:
+  processors: !append
+    - mapping: 'root = content() + " b"'
+output: !replace
+  label: barout
+  stdout: {}
+`),
+		},
+		"second.yaml": &fstest.MapFile{
+			Data: []byte(`
+pipeline:
+  processors: !prepend
+    - !include fragments/processor.yaml
+logger:
+  level: DEBUG
+`),
+		},
+		"fragments/processor.yaml": &fstest.MapFile{
+			Data: []byte(`
+mapping: 'root = content() + " c"'
+nope: true
+`),
+		},
+	}}
+
+	rdr := newDummyReader("main.yaml", nil, OptUseFS(testFS), OptAddOverlays("first.yaml", "second.yaml"))
+
+	conf, _, lints, err := rdr.Read()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"fragments/processor.yaml(3,1) field nope is invalid when the component type is mapping (processor)",
+	}, lints)
+
+	assert.Equal(t, "fooin", conf.Input.Label)
+	assert.Equal(t, "barout", conf.Output.Label)
+	assert.Equal(t, "stdout", conf.Output.Type)
+	assert.Equal(t, "DEBUG", conf.Logger.LogLevel)
+
+	require.Len(t, conf.Pipeline.Processors, 3)
+	for i, exp := range []string{
+		`root = content() + " c"`,
+		`root = content() + " a"`,
+		`root = content() + " b"`,
+	} {
+		assert.Equal(t, exp, conf.Pipeline.Processors[i].Plugin.(*yaml.Node).Value, i)
+	}
+
+	assert.Equal(t, map[string]any{
+		"mapping":  `root = "foo"`,
+		"interval": "5s",
+	}, gabs.Wrap(conf.GetRawSource()).S("input", "generate").Data())
+}
+
+func TestReaderFileWatchingIncludes(t *testing.T) {
+	confDir := t.TempDir()
+
+	confFilePath := filepath.Join(confDir, "main.yaml")
+	require.NoError(t, os.WriteFile(confFilePath, []byte(`
+input: !include input.yaml
+output:
+  drop: {}
+`), 0o644))
+
+	inputFilePath := filepath.Join(confDir, "input.yaml")
+	require.NoError(t, os.WriteFile(inputFilePath, []byte(`
+label: first
+generate:
+  mapping: 'root = "foo"'
+`), 0o644))
+
+	rdr := newDummyReader(confFilePath, nil)
+
+	conf, _, lints, err := rdr.Read()
+	require.NoError(t, err)
+	require.Empty(t, lints)
+	assert.Equal(t, "first", conf.Input.Label)
+
+	changeChan := make(chan struct{})
+	once := sync.Once{}
+	var updatedConf stream.Config
+	require.NoError(t, rdr.SubscribeConfigChanges(func(conf *Type) error {
+		updatedConf = conf.Config
+		once.Do(func() { close(changeChan) })
+		return nil
+	}))
+
+	testMgr, err := manager.New(manager.ResourceConfig{})
+	require.NoError(t, err)
+	require.NoError(t, rdr.BeginFileWatching(testMgr, true))
+	t.Cleanup(func() {
+		_ = rdr.Close(context.Background())
+	})
+
+	require.NoError(t, os.WriteFile(inputFilePath, []byte(`
+label: second
+generate:
+  mapping: 'root = "bar"'
+`), 0o644))
+
+	select {
+	case <-changeChan:
+	case <-time.After(time.Second * 5):
+		require.FailNow(t, "Expected a config change to be triggered")
+	}
+
+	assert.Equal(t, "second", updatedConf.Input.Label)
+}
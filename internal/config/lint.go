@@ -17,10 +17,13 @@ import (
 // ReadYAMLFileLinted will attempt to read a configuration file path into a
 // structure. Returns an array of lint messages or an error.
 func (r *Reader) ReadYAMLFileLinted(ctx context.Context, spec docs.FieldSpecs, path string, skipEnvVarCheck bool, lConf docs.LintConfig) (Type, []docs.Lint, error) {
-	configBytes, lints, _, err := r.ReadFileEnvSwap(ctx, path)
+	cNode, configBytes, lints, src, err := r.readConfigSources(ctx, path, nil)
 	if err != nil {
 		return Type{}, nil, err
 	}
+	for _, l := range src.lints {
+		lints = append(lints, src.rootLint(l))
+	}
 
 	if skipEnvVarCheck {
 		var newLints []docs.Lint
@@ -32,11 +35,6 @@ func (r *Reader) ReadYAMLFileLinted(ctx context.Context, spec docs.FieldSpecs, p
 		lints = newLints
 	}
 
-	cNode, err := docs.UnmarshalYAML(configBytes)
-	if err != nil {
-		return Type{}, nil, err
-	}
-
 	var rawSource any
 	_ = cNode.Decode(&rawSource)
 
@@ -51,7 +49,9 @@ func (r *Reader) ReadYAMLFileLinted(ctx context.Context, spec docs.FieldSpecs, p
 	}
 
 	if !bytes.HasPrefix(configBytes, []byte("# BENTHOS LINT DISABLE")) {
-		lints = append(lints, spec.LintYAML(docs.NewLintContext(lConf), cNode)...)
+		for _, l := range src.lintYAML(docs.NewLintContext(lConf), spec, cNode) {
+			lints = append(lints, src.rootLint(l))
+		}
 	}
 	return conf, lints, nil
 }
@@ -63,13 +63,23 @@ type Reader struct {
 	mainPath      string
 	resourcePaths []string
 	streamsPaths  []string
+	overlayPaths  []string
 	overrides     []string
 
+	// Maps the files included by config files, either with include directives
+	// or as overlays, to the paths of the config files that included them.
+	includedBy map[string]map[string]struct{}
+
 	modTimeLastRead map[string]time.Time
 
 	// Controls whether the main config should include input, output, etc.
 	streamsMode bool
 
+	// Controls how secret references within configs are handled, and which
+	// opt-in secret providers they are able to use.
+	secretsMode     secretsMode
+	secretProviders map[string]struct{}
+
 	// Tracks the details of the config file when we last read it.
 	configFileInfo resourceFileInfo
 
@@ -106,6 +116,8 @@ func NewReader(mainPath string, resourcePaths []string, opts ...OptFunc) *Reader
 		mainPath:           mainPath,
 		resourcePaths:      resourcePaths,
 		modTimeLastRead:    map[string]time.Time{},
+		includedBy:         map[string]map[string]struct{}{},
+		secretProviders:    map[string]struct{}{},
 		streamFileInfo:     map[string]streamFileInfo{},
 		resourceFileInfo:   map[string]resourceFileInfo{},
 		resourceSources:    newResourceSourceInfo(),
@@ -155,6 +167,18 @@ func OptAddOverrides(overrides ...string) OptFunc {
 	}
 }
 
+// OptAddOverlays adds one or more overlay files to the config reader, which
+// are merged in order into the main config after it has been read. Mappings
+// are merged key by key, and all other values replace the existing value
+// unless tagged with `!append` or `!prepend`, in which case sequences are added
+// to the end or start of the existing sequence respectively. Mappings tagged
+// with `!replace` replace the existing mapping rather than being merged.
+func OptAddOverlays(paths ...string) OptFunc {
+	return func(r *Reader) {
+		r.overlayPaths = append(r.overlayPaths, paths...)
+	}
+}
+
 // OptSetLintConfig sets the config used for linting files.
 func OptSetLintConfig(lConf docs.LintConfig) OptFunc {
 	return func(r *Reader) {
@@ -187,6 +211,35 @@ func OptUseEnvLookupFunc(fn func(context.Context, string) (string, bool)) OptFun
 	}
 }
 
+// OptEnableSecretProviders enables secret providers that are able to read
+// arbitrary files or run arbitrary commands, which are otherwise unavailable
+// to secret references. The names of these providers are listed by
+// OptInSecretProviders.
+func OptEnableSecretProviders(names ...string) OptFunc {
+	return func(r *Reader) {
+		for _, n := range names {
+			r.secretProviders[n] = struct{}{}
+		}
+	}
+}
+
+// OptSkipSecrets leaves secret references within configs as they are without
+// resolving them, which is useful when configs are only linted or printed.
+func OptSkipSecrets() OptFunc {
+	return func(r *Reader) {
+		r.secretsMode = secretsModeSkip
+	}
+}
+
+// OptRejectSecrets causes configs containing secret references to be rejected
+// with ErrSecretsRejected, which is useful when reading configs from sources
+// that must not be able to access secrets, such as HTTP requests.
+func OptRejectSecrets() OptFunc {
+	return func(r *Reader) {
+		r.secretsMode = secretsModeReject
+	}
+}
+
 //------------------------------------------------------------------------------
 
 func (r *Reader) lintCtx() docs.LintContext {
@@ -299,28 +352,16 @@ func (r *Reader) readMain(mainPath string) (conf Type, pConf *docs.ParsedConfig,
 		}
 	}()
 
-	var rawNode *yaml.Node
-	var confBytes []byte
-	if mainPath != "" {
-		var dLints []docs.Lint
-		var modTime time.Time
-		if confBytes, dLints, modTime, err = r.ReadFileEnvSwap(context.TODO(), mainPath); err != nil {
-			return
-		}
-		for _, l := range dLints {
-			lints = append(lints, l.Error())
-		}
-		r.modTimeLastRead[mainPath] = modTime
-
-		if rawNode, err = docs.UnmarshalYAML(confBytes); err != nil {
-			return
-		}
-	} else {
-		var tmpNode yaml.Node
-		if err = tmpNode.Encode(map[string]any{}); err != nil {
-			return
-		}
-		rawNode = &tmpNode
+	rawNode, confBytes, dLints, src, err := r.readConfigSources(context.TODO(), mainPath, r.overlayPaths)
+	r.setIncludedPaths(mainPath, src.includedPaths())
+	if err != nil {
+		return
+	}
+	for _, l := range dLints {
+		lints = append(lints, l.Error())
+	}
+	for _, l := range src.lints {
+		lints = append(lints, src.lintString(l))
 	}
 
 	confSpec := r.specFullConfig
@@ -334,9 +375,8 @@ func (r *Reader) readMain(mainPath string) (conf Type, pConf *docs.ParsedConfig,
 	}
 
 	if !bytes.HasPrefix(confBytes, []byte("# BENTHOS LINT DISABLE")) {
-		lintFilePrefix := mainPath
-		for _, lint := range confSpec.LintYAML(r.lintCtx(), rawNode) {
-			lints = append(lints, fmt.Sprintf("%v%v", lintFilePrefix, lint.Error()))
+		for _, lint := range src.lintYAML(r.lintCtx(), confSpec, rawNode) {
+			lints = append(lints, src.lintString(lint))
 		}
 	}
 
@@ -358,8 +398,11 @@ func (r *Reader) readMain(mainPath string) (conf Type, pConf *docs.ParsedConfig,
 
 // TriggerMainUpdate attempts to re-read the main configuration file, trigger
 // the provided main update func, and apply changes to resources to the provided
-// manager as appropriate.
+// manager as appropriate. Cached secrets are cleared beforehand in order to
+// refresh them.
 func (r *Reader) TriggerMainUpdate(mgr bundle.NewManagement, strict bool, newPath string) error {
+	ClearSecretCache()
+
 	conf, _, lints, err := r.readMain(newPath)
 	if errors.Is(err, fs.ErrNotExist) {
 		if r.mainPath != newPath {
@@ -11,8 +11,6 @@ import (
 	"path/filepath"
 	"time"
 
-	"gopkg.in/yaml.v3"
-
 	"github.com/redpanda-data/benthos/v4/internal/bundle"
 	"github.com/redpanda-data/benthos/v4/internal/component/cache"
 	"github.com/redpanda-data/benthos/v4/internal/component/input"
@@ -206,28 +204,24 @@ func (r *Reader) readResource(path string) (conf manager.ResourceConfig, lints [
 		}
 	}()
 
-	var confBytes []byte
-	var dLints []docs.Lint
-	var modTime time.Time
-	if confBytes, dLints, modTime, err = r.ReadFileEnvSwap(context.TODO(), path); err != nil {
+	rawNode, confBytes, dLints, src, err := r.readConfigSources(context.TODO(), path, nil)
+	r.setIncludedPaths(path, src.includedPaths())
+	if err != nil {
 		return
 	}
 	for _, l := range dLints {
 		lints = append(lints, l.Error())
 	}
-	r.modTimeLastRead[path] = modTime
-
-	var rawNode *yaml.Node
-	if rawNode, err = docs.UnmarshalYAML(confBytes); err != nil {
-		return
+	for _, l := range src.lints {
+		lints = append(lints, src.lintString(l))
 	}
 
 	spec := append(docs.FieldSpecs{
 		test.ConfigSpec(),
 	}, r.specResources...)
 	if !bytes.HasPrefix(confBytes, []byte("# BENTHOS LINT DISABLE")) {
-		for _, lint := range spec.LintYAML(r.lintCtx(), rawNode) {
-			lints = append(lints, fmt.Sprintf("%v%v", path, lint.Error()))
+		for _, lint := range src.lintYAML(r.lintCtx(), spec, rawNode) {
+			lints = append(lints, src.lintString(lint))
 		}
 	}
 
@@ -241,8 +235,11 @@ func (r *Reader) readResource(path string) (conf manager.ResourceConfig, lints [
 }
 
 // TriggerResourceUpdate attempts to re-read a resource configuration file and
-// apply changes to the provided manager as appropriate.
+// apply changes to the provided manager as appropriate. Cached secrets are
+// cleared beforehand in order to refresh them.
 func (r *Reader) TriggerResourceUpdate(mgr bundle.NewManagement, strict bool, path string) error {
+	ClearSecretCache()
+
 	newResConf, lints, err := r.readResource(path)
 	if errors.Is(err, fs.ErrNotExist) {
 		return r.TriggerResourceDelete(mgr, path)
@@ -300,6 +297,9 @@ func (r *Reader) applyResourceChanges(path string, mgr bundle.NewManagement, cur
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
 	defer cancel()
 
+	// Resources are only replaced when their config has changed, leaving
+	// unchanged resources and their connections intact.
+	//
 	// WARNING: The order here is actually kind of important, we want to start
 	// with components that could be dependencies of other components. This is
 	// a "best attempt", so not all edge cases need to be accounted for.
@@ -310,6 +310,9 @@ func (r *Reader) applyResourceChanges(path string, mgr bundle.NewManagement, cur
 	}
 	for k, v := range currentInfo.rateLimits {
 		delete(unaccounted, k)
+		if prev, exists := prevInfo.rateLimits[k]; exists && docs.EqualYAML(prev, v) {
+			continue
+		}
 		if err := mgr.StoreRateLimit(ctx, k, *v); err != nil {
 			mgr.Logger().Error("Failed to update resource %v: %v", k, err)
 			return fmt.Errorf("resource %v: %w", k, err)
@@ -326,6 +329,9 @@ func (r *Reader) applyResourceChanges(path string, mgr bundle.NewManagement, cur
 	}
 	for k, v := range currentInfo.caches {
 		delete(unaccounted, k)
+		if prev, exists := prevInfo.caches[k]; exists && docs.EqualYAML(prev, v) {
+			continue
+		}
 		if err := mgr.StoreCache(ctx, k, *v); err != nil {
 			mgr.Logger().Error("Failed to update resource %v: %v", k, err)
 			return fmt.Errorf("resource %v: %w", k, err)
@@ -342,6 +348,9 @@ func (r *Reader) applyResourceChanges(path string, mgr bundle.NewManagement, cur
 	}
 	for k, v := range currentInfo.processors {
 		delete(unaccounted, k)
+		if prev, exists := prevInfo.processors[k]; exists && docs.EqualYAML(prev, v) {
+			continue
+		}
 		if err := mgr.StoreProcessor(ctx, k, *v); err != nil {
 			mgr.Logger().Error("Failed to update resource %v: %v", k, err)
 			return fmt.Errorf("resource %v: %w", k, err)
@@ -358,6 +367,9 @@ func (r *Reader) applyResourceChanges(path string, mgr bundle.NewManagement, cur
 	}
 	for k, v := range currentInfo.inputs {
 		delete(unaccounted, k)
+		if prev, exists := prevInfo.inputs[k]; exists && docs.EqualYAML(prev, v) {
+			continue
+		}
 		if err := mgr.StoreInput(ctx, k, *v); err != nil {
 			mgr.Logger().Error("Failed to update resource %v: %v", k, err)
 			return fmt.Errorf("resource %v: %w", k, err)
@@ -374,6 +386,9 @@ func (r *Reader) applyResourceChanges(path string, mgr bundle.NewManagement, cur
 	}
 	for k, v := range currentInfo.outputs {
 		delete(unaccounted, k)
+		if prev, exists := prevInfo.outputs[k]; exists && docs.EqualYAML(prev, v) {
+			continue
+		}
 		if err := mgr.StoreOutput(ctx, k, *v); err != nil {
 			mgr.Logger().Error("Failed to update resource %v: %v", k, err)
 			return fmt.Errorf("resource %v: %w", k, err)
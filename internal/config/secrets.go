@@ -0,0 +1,399 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/gabs/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/parser"
+	"github.com/redpanda-data/benthos/v4/internal/value"
+)
+
+// SecretProvider resolves the key of a secret reference of the form
+// `${secret:<provider>:<key>}` within a config into the value of the secret.
+type SecretProvider interface {
+	LookupSecret(ctx context.Context, key string) (string, error)
+}
+
+// SecretProviderFunc is a closure that implements SecretProvider.
+type SecretProviderFunc func(ctx context.Context, key string) (string, error)
+
+// LookupSecret calls the closure.
+func (f SecretProviderFunc) LookupSecret(ctx context.Context, key string) (string, error) {
+	return f(ctx, key)
+}
+
+const (
+	secretReferencePrefix = "${secret:"
+
+	// The value that resolved secrets are replaced with when redacted.
+	secretRedacted = "!!!SECRET_SCRUBBED!!!"
+
+	// DefaultSecretCacheTTL is the period of time for which resolved secrets
+	// are cached by default.
+	DefaultSecretCacheTTL = 5 * time.Minute
+
+	// Resolved secrets shorter than this are only redacted from values that
+	// match them entirely, as short values such as `1` or `true` would
+	// otherwise redact unrelated fields.
+	minSecretRedactSubstringLen = 8
+)
+
+type secretsMode int
+
+const (
+	secretsModeResolve secretsMode = iota
+	secretsModeSkip
+	secretsModeReject
+)
+
+type cachedSecret struct {
+	value   string
+	expires time.Time
+}
+
+// secretRegistry holds the registered secret providers along with a cache of
+// the values they have resolved.
+type secretRegistry struct {
+	mut       sync.Mutex
+	providers map[string]SecretProvider
+	cache     map[string]cachedSecret
+	cacheTTL  time.Duration
+
+	// All values that have been resolved, which are redacted from configs
+	// that are printed.
+	resolved map[string]struct{}
+}
+
+var globalSecrets = &secretRegistry{
+	providers: map[string]SecretProvider{
+		"dotenv":    SecretProviderFunc(dotEnvSecretLookup),
+		"encrypted": SecretProviderFunc(encryptedFileSecretLookup),
+	},
+	cache:    map[string]cachedSecret{},
+	cacheTTL: DefaultSecretCacheTTL,
+	resolved: map[string]struct{}{},
+}
+
+// Providers that are able to read arbitrary files or run arbitrary commands,
+// which are only available to readers that explicitly enable them with
+// OptEnableSecretProviders.
+var optInSecretProviders = map[string]SecretProvider{
+	"file": SecretProviderFunc(fileSecretLookup),
+	"exec": SecretProviderFunc(execSecretLookup),
+}
+
+// OptInSecretProviders returns the names of the secret providers that must be
+// explicitly enabled for a reader with OptEnableSecretProviders.
+func OptInSecretProviders() []string {
+	names := make([]string, 0, len(optInSecretProviders))
+	for k := range optInSecretProviders {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ErrSecretsRejected is returned when reading a config containing secret
+// references with a reader created with OptRejectSecrets.
+var ErrSecretsRejected = errors.New("secret references are not permitted within this config")
+
+// RegisterSecretProvider adds a provider that resolves secret references of the
+// form `${secret:<name>:<key>}` within configs. An error is returned if a
+// provider of the same name is already registered.
+func RegisterSecretProvider(name string, p SecretProvider) error {
+	if name == "" || strings.Contains(name, ":") {
+		return fmt.Errorf("secret provider name '%v' is invalid", name)
+	}
+
+	globalSecrets.mut.Lock()
+	defer globalSecrets.mut.Unlock()
+
+	if _, exists := globalSecrets.providers[name]; exists {
+		return fmt.Errorf("secret provider '%v' is already registered", name)
+	}
+	if _, exists := optInSecretProviders[name]; exists {
+		return fmt.Errorf("secret provider '%v' is already registered", name)
+	}
+	globalSecrets.providers[name] = p
+	return nil
+}
+
+// SetSecretCacheTTL sets the period of time for which resolved secrets are
+// cached before they are resolved again. A zero duration disables caching.
+func SetSecretCacheTTL(ttl time.Duration) {
+	globalSecrets.mut.Lock()
+	globalSecrets.cacheTTL = ttl
+	globalSecrets.mut.Unlock()
+}
+
+// ClearSecretCache removes all cached secrets, causing them to be resolved
+// again the next time they are referenced. This is called whenever configs are
+// reloaded.
+func ClearSecretCache() {
+	globalSecrets.mut.Lock()
+	globalSecrets.cache = map[string]cachedSecret{}
+	globalSecrets.resolved = map[string]struct{}{}
+	globalSecrets.mut.Unlock()
+}
+
+// resolveSecret resolves the contents of a secret reference of the form
+// `<provider>:<key>`.
+func (r *Reader) resolveSecret(ctx context.Context, ref string) (string, error) {
+	providerName, key, ok := strings.Cut(ref, ":")
+	if !ok || key == "" {
+		return "", fmt.Errorf("secret reference '%v' must be of the form <provider>:<key>", ref)
+	}
+
+	globalSecrets.mut.Lock()
+	p, exists := globalSecrets.providers[providerName]
+	cached, isCached := globalSecrets.cache[ref]
+	ttl := globalSecrets.cacheTTL
+	globalSecrets.mut.Unlock()
+
+	if !exists {
+		if p, exists = optInSecretProviders[providerName]; exists {
+			if _, enabled := r.secretProviders[providerName]; !enabled {
+				return "", fmt.Errorf("secret provider '%v' must be explicitly enabled", providerName)
+			}
+		}
+	}
+	if !exists {
+		return "", fmt.Errorf("secret provider '%v' was not recognised", providerName)
+	}
+	if isCached && time.Now().Before(cached.expires) {
+		return cached.value, nil
+	}
+
+	v, err := p.LookupSecret(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("secret provider '%v': %w", providerName, err)
+	}
+
+	globalSecrets.mut.Lock()
+	if ttl > 0 {
+		globalSecrets.cache[ref] = cachedSecret{value: v, expires: time.Now().Add(ttl)}
+	}
+	if v != "" {
+		globalSecrets.resolved[v] = struct{}{}
+		globalSecrets.resolved[strings.ReplaceAll(v, "\n", "\\n")] = struct{}{}
+	}
+	globalSecrets.mut.Unlock()
+	return v, nil
+}
+
+// RedactSecrets walks a YAML node and replaces any scalar value that matches,
+// or for longer secrets contains, the value of a secret resolved from a secret
+// reference, ensuring that it is not printed.
+func RedactSecrets(node *yaml.Node) {
+	globalSecrets.mut.Lock()
+	defer globalSecrets.mut.Unlock()
+
+	if len(globalSecrets.resolved) == 0 {
+		return
+	}
+	redactSecretsNode(node)
+}
+
+func redactSecretsNode(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode {
+		for v := range globalSecrets.resolved {
+			if node.Value == v || (len(v) >= minSecretRedactSubstringLen && strings.Contains(node.Value, v)) {
+				node.Value = secretRedacted
+				node.Tag = "!!str"
+				node.Style = 0
+				return
+			}
+		}
+		return
+	}
+	for _, c := range node.Content {
+		redactSecretsNode(c)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// splitSecretField splits a key of the form `<path>#<field>`.
+func splitSecretField(key string) (path, field string) {
+	if i := strings.LastIndexByte(key, '#'); i >= 0 {
+		return key[:i], key[i+1:]
+	}
+	return key, ""
+}
+
+// secretFromContents extracts a secret from the contents of a file, where the
+// contents are either the secret itself, or when a field is specified a YAML
+// or JSON document containing the secret at the dot path of the field.
+func secretFromContents(contents []byte, field string) (string, error) {
+	if field == "" {
+		return string(bytes.TrimRight(contents, "\r\n")), nil
+	}
+
+	var root any
+	if err := yaml.Unmarshal(contents, &root); err != nil {
+		return "", fmt.Errorf("failed to parse contents as a structured document: %w", err)
+	}
+	v := gabs.Wrap(root).Path(field).Data()
+	if v == nil {
+		return "", fmt.Errorf("field '%v' was not found", field)
+	}
+	return value.IToString(v), nil
+}
+
+// fileSecretLookup reads a secret from a file, with a key of the form
+// `<path>` or `<path>#<field>`.
+func fileSecretLookup(ctx context.Context, key string) (string, error) {
+	path, field := splitSecretField(key)
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return secretFromContents(contents, field)
+}
+
+// dotEnvSecretLookup reads a variable from a dotenv file, with a key of the
+// form `<path>#<variable>`.
+func dotEnvSecretLookup(ctx context.Context, key string) (string, error) {
+	path, name := splitSecretField(key)
+	if name == "" {
+		return "", fmt.Errorf("key '%v' must be of the form <path>#<variable>", key)
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	vars, err := parser.ParseDotEnvFile(contents)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse dotenv file: %w", err)
+	}
+	v, exists := vars[name]
+	if !exists {
+		return "", fmt.Errorf("variable '%v' was not found", name)
+	}
+	return v, nil
+}
+
+// SecretsKeyFileEnvVar is the environment variable containing the path of the
+// key file used by the encrypted secret provider.
+const SecretsKeyFileEnvVar = "SECRETS_KEY_FILE"
+
+func readSecretsKey() ([]byte, error) {
+	keyPath := os.Getenv(SecretsKeyFileEnvVar)
+	if keyPath == "" {
+		return nil, fmt.Errorf("the environment variable %v must be set to the path of a key file", SecretsKeyFileEnvVar)
+	}
+	return ReadSecretsKeyFile(keyPath)
+}
+
+// ReadSecretsKeyFile reads a key used by the encrypted secret provider from a
+// file containing 32 hex encoded bytes.
+func ReadSecretsKeyFile(keyPath string) ([]byte, error) {
+	keyHex, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	key, err := hex.DecodeString(string(bytes.TrimSpace(keyHex)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode key file as hex: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes, got %v", len(key))
+	}
+	return key, nil
+}
+
+// EncryptSecrets encrypts the contents of a secrets file with a 32 byte key in
+// the format read by the encrypted secret provider, which is a 12 byte nonce
+// followed by the AES-256-GCM sealed contents.
+func EncryptSecrets(key, contents []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, contents, nil), nil
+}
+
+func decryptSecrets(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("encrypted file is too short")
+	}
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+}
+
+// encryptedFileSecretLookup reads a secret from a file encrypted with
+// EncryptSecrets, with a key of the form `<path>` or `<path>#<field>`.
+func encryptedFileSecretLookup(ctx context.Context, key string) (string, error) {
+	encKey, err := readSecretsKey()
+	if err != nil {
+		return "", err
+	}
+	path, field := splitSecretField(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	contents, err := decryptSecrets(encKey, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt file: %w", err)
+	}
+	return secretFromContents(contents, field)
+}
+
+const execSecretTimeout = 30 * time.Second
+
+// execSecretLookup runs a command, with a key of the form `<command> [args...]`,
+// and reads the secret from its stdout.
+func execSecretLookup(ctx context.Context, key string) (string, error) {
+	args := strings.Fields(key)
+	if len(args) == 0 {
+		return "", errors.New("a command must be specified")
+	}
+
+	ctx, done := context.WithTimeout(ctx, execSecretTimeout)
+	defer done()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if stderr.Len() > 0 {
+			return "", fmt.Errorf("command '%v' failed: %w: %s", args[0], err, bytes.TrimSpace(stderr.Bytes()))
+		}
+		return "", fmt.Errorf("command '%v' failed: %w", args[0], err)
+	}
+	return string(bytes.TrimRight(stdout.Bytes(), "\r\n")), nil
+}
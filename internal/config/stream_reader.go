@@ -10,9 +10,6 @@ import (
 	"io/fs"
 	"path/filepath"
 	"strings"
-	"time"
-
-	"gopkg.in/yaml.v3"
 
 	"github.com/redpanda-data/benthos/v4/internal/bundle"
 	"github.com/redpanda-data/benthos/v4/internal/config/test"
@@ -46,20 +43,16 @@ func inferStreamID(dir, path string) (string, error) {
 }
 
 func (r *Reader) readStreamFileConfig(path string) (conf stream.Config, lints []string, err error) {
-	var confBytes []byte
-	var dLints []docs.Lint
-	var modTime time.Time
-	if confBytes, dLints, modTime, err = r.ReadFileEnvSwap(context.TODO(), path); err != nil {
+	rawNode, confBytes, dLints, src, err := r.readConfigSources(context.TODO(), path, nil)
+	r.setIncludedPaths(path, src.includedPaths())
+	if err != nil {
 		return
 	}
 	for _, l := range dLints {
 		lints = append(lints, l.Error())
 	}
-	r.modTimeLastRead[path] = modTime
-
-	var rawNode *yaml.Node
-	if rawNode, err = docs.UnmarshalYAML(confBytes); err != nil {
-		return
+	for _, l := range src.lints {
+		lints = append(lints, src.lintString(l))
 	}
 
 	var rawSource any
@@ -69,8 +62,8 @@ func (r *Reader) readStreamFileConfig(path string) (conf stream.Config, lints []
 	confSpec = append(confSpec, test.ConfigSpec())
 
 	if !bytes.HasPrefix(confBytes, []byte("# BENTHOS LINT DISABLE")) {
-		for _, lint := range confSpec.LintYAML(r.lintCtx(), rawNode) {
-			lints = append(lints, fmt.Sprintf("%v%v", path, lint.Error()))
+		for _, lint := range src.lintYAML(r.lintCtx(), confSpec, rawNode) {
+			lints = append(lints, src.lintString(lint))
 		}
 	}
 
@@ -185,8 +178,11 @@ func (r *Reader) findStreamPathWalkedDir(streamPath string) (dir string) {
 }
 
 // TriggerStreamUpdate attempts to re-read a stream configuration file, and
-// trigger the provided stream update func.
+// trigger the provided stream update func. Cached secrets are cleared
+// beforehand in order to refresh them.
 func (r *Reader) TriggerStreamUpdate(mgr bundle.NewManagement, strict bool, path string) error {
+	ClearSecretCache()
+
 	if r.streamUpdateFn == nil {
 		return nil
 	}
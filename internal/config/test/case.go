@@ -27,6 +27,10 @@ type Case struct {
 	InputBatches     [][]InputConfig
 	OutputBatches    [][]OutputConditionsMap
 
+	// Mapping is an optional mapping executed in place of the target mapping,
+	// where the maps of the target mapping are available.
+	Mapping string
+
 	line int
 }
 
@@ -0,0 +1,208 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/redpanda-data/benthos/v4/internal/docs"
+	"github.com/redpanda-data/benthos/v4/internal/message"
+)
+
+const (
+	fieldMappingTestInput    = "input"
+	fieldMappingTestMetadata = "metadata"
+	fieldMappingTestMapping  = "mapping"
+	fieldMappingTestOutput   = "output"
+	fieldMappingTestError    = "error"
+)
+
+// MappingTestPrefix is the comment prefix that begins a test block within a
+// Bloblang mapping file, followed by the name of the test.
+const MappingTestPrefix = "#test"
+
+func mappingTestFields() docs.FieldSpecs {
+	return docs.FieldSpecs{
+		docs.FieldAnything(fieldMappingTestInput, "The input document of the test. Strings are used as the raw content of the message, and any other value is encoded as a JSON document.",
+			map[string]any{"name": "foo"},
+		).Optional(),
+		docs.FieldAnything(fieldMappingTestMetadata, "A map of metadata key/values to add to the input message.").Map().Optional(),
+		docs.FieldBloblang(fieldMappingTestMapping, "An optional mapping to execute instead of the statements of the mapping file, where the maps defined by the file are available. This allows tests to exercise mapping libraries that are imported by other mappings.",
+			`root = this.apply("foo")`,
+		).Optional(),
+		docs.FieldObject(fieldMappingTestOutput, "Conditions that the output message must meet.").
+			Optional().WithChildren(outputFields()...),
+		docs.FieldString(fieldMappingTestError, "Expect the mapping to fail with an error containing this string.").Optional(),
+	}
+}
+
+// ErrorContainsCondition represents a test condition that checks a message is
+// flagged with an error containing a string.
+type ErrorContainsCondition string
+
+// Check runs the ErrorContains condition check.
+func (c ErrorContainsCondition) Check(fs fs.FS, dir string, p *message.Part) error {
+	err := p.ErrorGet()
+	if err == nil {
+		return fmt.Errorf("expected an error containing %v, but no error occurred", blue(string(c)))
+	}
+	if !strings.Contains(err.Error(), string(c)) {
+		return fmt.Errorf("error mismatch\n  expected: %v\n  received: %v", blue(string(c)), red(err.Error()))
+	}
+	return nil
+}
+
+type mappingTestBlock struct {
+	name string
+	line int
+	body []string
+}
+
+// extractMappingTestBlocks finds test blocks within the comments of a mapping.
+// A block begins with a comment line consisting of the test prefix and a name,
+// and continues with each following comment line until a line that is not a
+// comment or another block begins.
+func extractMappingTestBlocks(mapping string) []mappingTestBlock {
+	var blocks []mappingTestBlock
+	var current *mappingTestBlock
+	for i, line := range strings.Split(mapping, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if name, isHeader := strings.CutPrefix(trimmed, MappingTestPrefix); isHeader && (name == "" || name[0] == ' ' || name[0] == '\t') {
+			blocks = append(blocks, mappingTestBlock{
+				name: strings.TrimSpace(name),
+				line: i + 1,
+			})
+			current = &blocks[len(blocks)-1]
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if !strings.HasPrefix(trimmed, "#") {
+			current = nil
+			continue
+		}
+		current.body = append(current.body, strings.TrimPrefix(trimmed, "#"))
+	}
+	return blocks
+}
+
+// MappingCasesFromBloblang extracts test cases from the test blocks within the
+// comments of a Bloblang mapping file. Each block begins with a comment line
+// of the form `#test <name>`, and the following comment lines contain a YAML
+// object describing the input and expected output of the test:
+//
+//	#test uppercases names
+//	#  input: {"name":"foo"}
+//	#  output:
+//	#    json_equals: {"name":"FOO"}
+//
+// The path of the mapping file, relative to the directory of the test, is set
+// as the target mapping of each case.
+func MappingCasesFromBloblang(mappingPath string, mapping []byte) ([]Case, error) {
+	var cases []Case
+	for _, block := range extractMappingTestBlocks(string(mapping)) {
+		c, err := mappingCaseFromBlock(block)
+		if err != nil {
+			return nil, fmt.Errorf("test block at line %v: %w", block.line, err)
+		}
+		c.TargetMapping = filepath.Base(mappingPath)
+		cases = append(cases, c)
+	}
+	return cases, nil
+}
+
+func mappingCaseFromBlock(block mappingTestBlock) (c Case, err error) {
+	if block.name == "" {
+		err = errors.New("a test name must follow the " + MappingTestPrefix + " prefix")
+		return
+	}
+	c.Name = block.name
+	c.line = block.line
+
+	node, err := docs.UnmarshalYAML([]byte(strings.Join(block.body, "\n")))
+	if err != nil {
+		return
+	}
+	if lints := mappingTestFields().LintYAML(docs.NewLintContext(docs.NewLintConfig(docs.NewMappedDocsProvider())), node); len(lints) > 0 {
+		err = lints[0]
+		return
+	}
+
+	// Map fields are given an empty default when absent, and so the presence
+	// of the expectations is determined from the keys of the block instead.
+	specified := map[string]bool{}
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			specified[node.Content[i].Value] = true
+		}
+	}
+
+	var pConf *docs.ParsedConfig
+	if pConf, err = mappingTestFields().ParsedConfigFromAny(node); err != nil {
+		return
+	}
+
+	var input InputConfig
+	if pConf.Contains(fieldMappingTestInput) {
+		var v any
+		if v, err = pConf.FieldAny(fieldMappingTestInput); err != nil {
+			return
+		}
+		if str, isStr := v.(string); isStr {
+			input.Content = str
+		} else {
+			var jBytes []byte
+			if jBytes, err = json.Marshal(v); err != nil {
+				return
+			}
+			input.Content = string(jBytes)
+		}
+	}
+	if specified[fieldMappingTestMetadata] {
+		var tmpMap map[string]*docs.ParsedConfig
+		if tmpMap, err = pConf.FieldAnyMap(fieldMappingTestMetadata); err != nil {
+			return
+		}
+		input.Metadata = map[string]any{}
+		for k, v := range tmpMap {
+			if input.Metadata[k], err = v.FieldAny(); err != nil {
+				return
+			}
+		}
+	}
+	c.InputBatches = [][]InputConfig{{input}}
+
+	if pConf.Contains(fieldMappingTestMapping) {
+		if c.Mapping, err = pConf.FieldString(fieldMappingTestMapping); err != nil {
+			return
+		}
+	}
+
+	conds := OutputConditionsMap{}
+	if specified[fieldMappingTestOutput] {
+		if conds, err = OutputConditionsFromParsed(pConf.Namespace(fieldMappingTestOutput)); err != nil {
+			return
+		}
+	}
+	if specified[fieldMappingTestError] {
+		var errStr string
+		if errStr, err = pConf.FieldString(fieldMappingTestError); err != nil {
+			return
+		}
+		conds[fieldMappingTestError] = ErrorContainsCondition(errStr)
+	}
+	if !specified[fieldMappingTestOutput] && !specified[fieldMappingTestError] {
+		err = fmt.Errorf("either an %v or an %v must be specified", fieldMappingTestOutput, fieldMappingTestError)
+		return
+	}
+	c.OutputBatches = [][]OutputConditionsMap{{conds}}
+	return
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/internal/message"
+)
+
+func TestMappingCasesFromBloblang(t *testing.T) {
+	cases, err := MappingCasesFromBloblang("foo/bar.blobl", []byte(`root = this.name.uppercase()
+
+#test uppercases
+#  input: {"name":"foo"}
+#  metadata:
+#    topic: baz
+#  output:
+#    content_equals: FOO
+# this comment is not part of a test
+root.nope = "nope"
+  #test raw input
+  #  input: hello world
+  #  error: expected string value
+#testing is not a test block
+`))
+	require.NoError(t, err)
+	require.Len(t, cases, 2)
+
+	assert.Equal(t, "uppercases", cases[0].Name)
+	assert.Equal(t, 3, cases[0].Line())
+	assert.Equal(t, "bar.blobl", cases[0].TargetMapping)
+	assert.Equal(t, [][]InputConfig{{{
+		Content:  `{"name":"foo"}`,
+		Metadata: map[string]any{"topic": "baz"},
+	}}}, cases[0].InputBatches)
+	require.Len(t, cases[0].OutputBatches, 1)
+	require.Len(t, cases[0].OutputBatches[0], 1)
+	assert.Equal(t, ContentEqualsCondition("FOO"), cases[0].OutputBatches[0][0]["content_equals"])
+
+	assert.Equal(t, "raw input", cases[1].Name)
+	assert.Equal(t, 11, cases[1].Line())
+	assert.Equal(t, "hello world", cases[1].InputBatches[0][0].Content)
+	assert.Equal(t, [][]OutputConditionsMap{{{
+		"error": ErrorContainsCondition("expected string value"),
+	}}}, cases[1].OutputBatches)
+}
+
+func TestMappingCasesFromBloblangErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		mapping     string
+		errContains string
+	}{
+		{
+			name: "missing name",
+			mapping: `root = this
+#test
+#  output:
+#    content_equals: foo
+`,
+			errContains: "test block at line 2: a test name must follow",
+		},
+		{
+			name: "missing expectations",
+			mapping: `root = this
+#test foo
+#  input: {}
+`,
+			errContains: "test block at line 2: either an output or an error must be specified",
+		},
+		{
+			name: "unknown output condition",
+			mapping: `#test foo
+#  output:
+#    nope: foo
+`,
+			errContains: "test block at line 1:",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := MappingCasesFromBloblang("foo.blobl", []byte(test.mapping))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.errContains)
+		})
+	}
+}
+
+func TestErrorContainsCondition(t *testing.T) {
+	cond := ErrorContainsCondition("bar")
+
+	part := message.NewPart([]byte("foo"))
+	require.Error(t, cond.Check(nil, "", part))
+
+	part.ErrorSet(errors.New("foo bar baz"))
+	require.NoError(t, cond.Check(nil, "", part))
+
+	part.ErrorSet(errors.New("foo baz"))
+	require.Error(t, cond.Check(nil, "", part))
+}
@@ -6,7 +6,9 @@ package config
 
 import (
 	"errors"
+	"maps"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/redpanda-data/benthos/v4/internal/bundle"
@@ -61,6 +63,18 @@ func (r *Reader) modifiedSinceLastRead(name string) bool {
 	return info.ModTime().After(r.modTimeLastRead[name])
 }
 
+// triggerUpdate re-reads a changed config file, returning false if the attempt
+// should be made again.
+func (r *Reader) triggerUpdate(mgr bundle.NewManagement, strict bool, name string) bool {
+	if name == r.mainPath {
+		return !ShouldReread(r.TriggerMainUpdate(mgr, strict, r.mainPath))
+	}
+	if _, exists := r.streamFileInfo[name]; exists {
+		return !ShouldReread(r.TriggerStreamUpdate(mgr, strict, name))
+	}
+	return !ShouldReread(r.TriggerResourceUpdate(mgr, strict, name))
+}
+
 // BeginFileWatching creates a goroutine that watches all active configuration
 // files for changes. If a resource is changed then it is swapped out
 // automatically through the provided manager. If a main config or stream config
@@ -127,7 +141,14 @@ func (r *Reader) BeginFileWatching(mgr bundle.NewManagement, strict bool) error
 		if err := addNotWatching(resourcePaths); err != nil {
 			return err
 		}
-		return nil
+
+		var includedPaths []string
+		for p := range r.includedBy {
+			if _, err := r.fs.Stat(p); err == nil {
+				includedPaths = append(includedPaths, p)
+			}
+		}
+		return addNotWatching(includedPaths)
 	}
 
 	if err := refreshFiles(); err != nil {
@@ -173,13 +194,17 @@ func (r *Reader) BeginFileWatching(mgr bundle.NewManagement, strict bool) error
 					if time.Since(change.at) < r.changeDelayPeriod {
 						continue
 					}
-					var succeeded bool
-					if nameClean == r.mainPath {
-						succeeded = !ShouldReread(r.TriggerMainUpdate(mgr, strict, r.mainPath))
-					} else if _, exists := r.streamFileInfo[nameClean]; exists {
-						succeeded = !ShouldReread(r.TriggerStreamUpdate(mgr, strict, nameClean))
+					succeeded := true
+					if owners, exists := r.includedBy[nameClean]; exists {
+						// Changes to included files trigger an update of each
+						// file that includes them.
+						for _, owner := range slices.Collect(maps.Keys(owners)) {
+							if !r.triggerUpdate(mgr, strict, owner) {
+								succeeded = false
+							}
+						}
 					} else {
-						succeeded = !ShouldReread(r.TriggerResourceUpdate(mgr, strict, nameClean))
+						succeeded = r.triggerUpdate(mgr, strict, nameClean)
 					}
 					if succeeded {
 						delete(collapsedChanges, nameClean)
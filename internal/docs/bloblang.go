@@ -3,6 +3,9 @@
 package docs
 
 import (
+	"strings"
+
+	ibloblang "github.com/redpanda-data/benthos/v4/internal/bloblang"
 	"github.com/redpanda-data/benthos/v4/public/bloblang"
 )
 
@@ -18,7 +21,7 @@ func LintBloblangMapping(ctx LintContext, line, col int, v any) []Lint {
 	}
 	_, err := ctx.conf.BloblangEnv.Parse(str)
 	if err == nil {
-		return nil
+		return lintBloblangAnalysis(ctx, line, str)
 	}
 	if mErr, ok := err.(*bloblang.ParseError); ok {
 		lint := NewLintError(line+mErr.Line-1, LintBadBloblang, mErr)
@@ -28,6 +31,33 @@ func LintBloblangMapping(ctx LintContext, line, col int, v any) []Lint {
 	return []Lint{NewLintError(line, LintBadBloblang, err)}
 }
 
+// lintBloblangAnalysis reports the problems found by a static analysis of a
+// valid mapping as linting warnings, when enabled. Field references are only
+// validated when a schema has been provided for the path of the mapping, as
+// mappings elsewhere within a config may execute upon documents of a
+// different shape.
+func lintBloblangAnalysis(ctx LintContext, line int, mapping string) []Lint {
+	if !ctx.conf.BloblangAnalysis {
+		return nil
+	}
+	unwrapper, ok := ctx.conf.BloblangEnv.XUnwrapper().(interface {
+		Unwrap() *ibloblang.Environment
+	})
+	if !ok {
+		return nil
+	}
+	schema := ctx.conf.BloblangSchemas[strings.Join(ctx.path, ".")]
+	warnings, err := unwrapper.Unwrap().AnalyseMapping(mapping, schema)
+	if err != nil {
+		return nil
+	}
+	var lints []Lint
+	for _, w := range warnings {
+		lints = append(lints, NewLintWarning(line+w.Line-1, LintBadBloblang, w.Message))
+	}
+	return lints
+}
+
 // LintBloblangField is function for linting a config field expected to be an
 // interpolation string.
 func LintBloblangField(ctx LintContext, line, col int, v any) []Lint {
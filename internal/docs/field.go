@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/query"
 	"github.com/redpanda-data/benthos/v4/internal/value"
 	"github.com/redpanda-data/benthos/v4/public/bloblang"
 )
@@ -684,6 +685,15 @@ type LintConfig struct {
 
 	// Require labels for components.
 	RequireLabels bool
+
+	// Report problems found by a static analysis of Bloblang mappings.
+	BloblangAnalysis bool
+
+	// Optionally describes the documents that Bloblang mappings are executed
+	// upon, keyed by the dot path of the mapping field within the config, which
+	// allows the static analysis of those mappings to validate field
+	// references.
+	BloblangSchemas map[string]*query.Schema
 }
 
 // NewLintConfig creates a default linting config.
@@ -700,6 +710,9 @@ type LintContext struct {
 	// A map of label names to the line they were defined at.
 	labelsToLine map[string]int
 
+	// The path of the field being linted.
+	path []string
+
 	conf LintConfig
 }
 
@@ -711,6 +724,14 @@ func NewLintContext(conf LintConfig) LintContext {
 	}
 }
 
+// withPath returns a copy of the context for linting a child of the current
+// field.
+func (l LintContext) withPath(name string) LintContext {
+	path := make([]string, 0, len(l.path)+1)
+	l.path = append(append(path, l.path...), name)
+	return l
+}
+
 // LintFunc is a common linting function for field values.
 type LintFunc func(ctx LintContext, line, col int, value any) []Lint
 
@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"gopkg.in/yaml.v3"
 
@@ -34,6 +35,22 @@ func MarshalYAML(v yaml.Node) ([]byte, error) {
 	return cbytes.Bytes(), nil
 }
 
+// EqualYAML returns true if two config structures are equivalent, which is
+// determined by comparing their YAML representations. Configs parsed from YAML
+// may contain nodes that carry line numbers, which are ignored, whereas changes
+// to the style of nodes are not.
+func EqualYAML(a, b any) bool {
+	aBytes, err := yaml.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bBytes, err := yaml.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(aBytes, bBytes)
+}
+
 // FieldsFromYAML walks the children of a YAML node and returns a list of fields
 // extracted from it. This can be used in order to infer a field spec for a
 // parsed component.
@@ -550,7 +567,7 @@ func LintYAML(ctx LintContext, cType Type, node *yaml.Node) []Lint {
 	for i := 0; i < len(node.Content)-1; i += 2 {
 		if node.Content[i].Value == name {
 			nameFound = true
-			lints = append(lints, cSpec.Config.LintYAML(ctx, node.Content[i+1])...)
+			lints = append(lints, cSpec.Config.LintYAML(ctx.withPath(name), node.Content[i+1])...)
 			break
 		}
 	}
@@ -567,14 +584,14 @@ func LintYAML(ctx LintContext, cType Type, node *yaml.Node) []Lint {
 			if nameFound || !cSpec.Plugin {
 				lints = append(lints, NewLintError(node.Content[i].Line, LintShouldOmit, errors.New("plugin object is ineffective")))
 			} else {
-				lints = append(lints, cSpec.Config.LintYAML(ctx, node.Content[i+1])...)
+				lints = append(lints, cSpec.Config.LintYAML(ctx.withPath(key), node.Content[i+1])...)
 			}
 		}
 		spec, exists := reservedFields[key]
 		hasLabel = hasLabel || (key == "label")
 		if exists {
 			lints = append(lints, lintYAMLFromOmit(cSpec.Config.Children, spec, node, node.Content[i+1])...)
-			lints = append(lints, spec.LintYAML(ctx, node.Content[i+1])...)
+			lints = append(lints, spec.LintYAML(ctx.withPath(key), node.Content[i+1])...)
 		} else {
 			lints = append(lints, NewLintError(
 				node.Content[i].Line,
@@ -615,7 +632,7 @@ func (f FieldSpec) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 			return lints
 		}
 		for i := 0; i < len(node.Content); i++ {
-			lints = append(lints, f.Array().LintYAML(ctx, node.Content[i])...)
+			lints = append(lints, f.Array().LintYAML(ctx.withPath(strconv.Itoa(i)), node.Content[i])...)
 		}
 		return lints
 	case KindArray:
@@ -624,7 +641,7 @@ func (f FieldSpec) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 			return lints
 		}
 		for i := 0; i < len(node.Content); i++ {
-			lints = append(lints, f.Scalar().LintYAML(ctx, node.Content[i])...)
+			lints = append(lints, f.Scalar().LintYAML(ctx.withPath(strconv.Itoa(i)), node.Content[i])...)
 		}
 		return lints
 	case KindMap:
@@ -633,7 +650,7 @@ func (f FieldSpec) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 			return lints
 		}
 		for i := 0; i < len(node.Content)-1; i += 2 {
-			lints = append(lints, f.Scalar().LintYAML(ctx, node.Content[i+1])...)
+			lints = append(lints, f.Scalar().LintYAML(ctx.withPath(node.Content[i].Value), node.Content[i+1])...)
 		}
 		return lints
 	}
@@ -698,7 +715,7 @@ func (f FieldSpecs) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 				continue
 			}
 			lints = append(lints, lintYAMLFromOmit(f, spec, walkNode, walkNode.Content[i+1])...)
-			lints = append(lints, spec.LintYAML(ctx, walkNode.Content[i+1])...)
+			lints = append(lints, spec.LintYAML(ctx.withPath(walkNode.Content[i].Value), walkNode.Content[i+1])...)
 			delete(specNamesMissing, walkNode.Content[i].Value)
 		}
 	}
@@ -1419,3 +1419,28 @@ processors:
 		})
 	}
 }
+
+func TestEqualYAML(t *testing.T) {
+	a, err := docs.UnmarshalYAML([]byte(`foo: bar
+baz: [ 1, 2 ]
+`))
+	require.NoError(t, err)
+
+	b, err := docs.UnmarshalYAML([]byte(`
+
+foo: bar
+
+baz: [ 1, 2 ]
+`))
+	require.NoError(t, err)
+
+	c, err := docs.UnmarshalYAML([]byte(`foo: bar
+baz: [ 1, 3 ]
+`))
+	require.NoError(t, err)
+
+	assert.True(t, docs.EqualYAML(a, b))
+	assert.False(t, docs.EqualYAML(a, c))
+	assert.True(t, docs.EqualYAML(map[string]any{"foo": "bar"}, map[string]any{"foo": "bar"}))
+	assert.False(t, docs.EqualYAML(map[string]any{"foo": "bar"}, map[string]any{"foo": "baz"}))
+}
@@ -384,3 +384,57 @@ input:
 		"input.processors.0.switch.1.processors.1": {33, 35},
 	}, res)
 }
+
+func TestWalkBloblangFieldsYAML(t *testing.T) {
+	mockProv := getMockProv(t)
+	mockProv.RegisterDocs(docs.ComponentSpec{
+		Name:   "mapping",
+		Type:   docs.TypeProcessor,
+		Config: docs.FieldBloblang("", ""),
+	})
+	mockProv.RegisterDocs(docs.ComponentSpec{
+		Name: "branch",
+		Type: docs.TypeProcessor,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldBloblang("request_map", ""),
+			docs.FieldProcessor("processors", "").Array(),
+			docs.FieldObject("results", "").WithChildren(
+				docs.FieldBloblang("maps", "").Array(),
+			),
+		),
+	})
+
+	input := &yaml.Node{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+input:
+  kafka:
+    addresses: [ "foo" ]
+  processors:
+    - mapping: 'root = "a"'
+    - branch:
+        request_map: 'root = "b"'
+        processors:
+          - mapping: 'root = "c"'
+        results:
+          maps: [ 'root = "d"', 'root = "e"' ]
+`), input))
+
+	res := map[string]string{}
+	require.NoError(t, configSpec.WalkComponentsYAML(docs.WalkComponentConfig{
+		Provider: mockProv,
+		Func: func(c docs.WalkedComponent) error {
+			return c.WalkBloblangFieldsYAML(func(path string, node *yaml.Node) error {
+				res[path] = node.Value
+				return nil
+			})
+		},
+	}, input))
+
+	assert.Equal(t, map[string]string{
+		"input.processors.0.mapping":                     `root = "a"`,
+		"input.processors.1.branch.request_map":          `root = "b"`,
+		"input.processors.1.branch.processors.0.mapping": `root = "c"`,
+		"input.processors.1.branch.results.maps.0":       `root = "d"`,
+		"input.processors.1.branch.results.maps.1":       `root = "e"`,
+	}, res)
+}
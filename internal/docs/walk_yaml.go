@@ -184,3 +184,93 @@ func (f FieldSpecs) WalkComponentsYAML(conf WalkComponentConfig, node *yaml.Node
 	}
 	return nil
 }
+
+// WalkBloblangFieldsYAML calls the provided func for each field of a walked
+// component that contains a Bloblang mapping, along with the path of the field.
+// Fields of child components are not included, as those components are walked
+// separately.
+func (w WalkedComponent) WalkBloblangFieldsYAML(fn func(path string, node *yaml.Node) error) error {
+	node, ok := w.Value.(*yaml.Node)
+	if !ok {
+		return nil
+	}
+
+	reservedFields := ReservedFieldsByType(w.spec.Type)
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		key := node.Content[i].Value
+		spec, exists := reservedFields[key]
+		if key == w.Name {
+			spec, exists = w.spec.Config, true
+		}
+		if !exists || key == "type" || key == "label" {
+			continue
+		}
+		if err := walkBloblangFieldYAML(spec, w.intoPath(key), node.Content[i+1], fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w WalkedComponent) intoPath(str string) string {
+	if w.Path == "" {
+		return str
+	}
+	return w.Path + "." + str
+}
+
+func walkBloblangFieldYAML(f FieldSpec, path string, node *yaml.Node, fn func(path string, node *yaml.Node) error) error {
+	node = unwrapDocumentNode(node)
+	if _, isCore := f.Type.IsCoreComponent(); isCore {
+		return nil
+	}
+	if !f.Bloblang && len(f.Children) == 0 {
+		return nil
+	}
+
+	walkValue := func(path string, node *yaml.Node) error {
+		if f.Bloblang {
+			if node.Kind == yaml.ScalarNode {
+				return fn(path, node)
+			}
+			return nil
+		}
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			for _, child := range f.Children {
+				if child.Name != node.Content[i].Value {
+					continue
+				}
+				if err := walkBloblangFieldYAML(child, path+"."+child.Name, node.Content[i+1], fn); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	switch f.Kind {
+	case Kind2DArray:
+		for i := 0; i < len(node.Content); i++ {
+			for j := 0; j < len(node.Content[i].Content); j++ {
+				if err := walkValue(fmt.Sprintf("%v.%v.%v", path, i, j), node.Content[i].Content[j]); err != nil {
+					return err
+				}
+			}
+		}
+	case KindArray:
+		for i := 0; i < len(node.Content); i++ {
+			if err := walkValue(path+"."+strconv.Itoa(i), node.Content[i]); err != nil {
+				return err
+			}
+		}
+	case KindMap:
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			if err := walkValue(path+"."+node.Content[i].Value, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+	default:
+		return walkValue(path, node)
+	}
+	return nil
+}
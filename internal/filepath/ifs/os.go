@@ -59,6 +59,18 @@ func FileWrite(file fs.File, data []byte) (int, error) {
 	return writer.Write(data)
 }
 
+// Rename attempts to rename (move) a file, provided the fs.FS implementation
+// supports it.
+func Rename(f fs.FS, oldpath, newpath string) error {
+	rf, ok := f.(interface {
+		Rename(oldpath, newpath string) error
+	})
+	if !ok {
+		return errors.New("filesystem does not support renaming files")
+	}
+	return rf.Rename(oldpath, newpath)
+}
+
 // OS implements fs.FS as if calls were being made directly via the os package,
 // with which relative paths are resolved from the directory the process is
 // executed from.
@@ -95,3 +107,7 @@ func (o *osPT) Remove(name string) error {
 func (o *osPT) MkdirAll(path string, perm fs.FileMode) error {
 	return os.MkdirAll(path, perm)
 }
+
+func (o *osPT) Rename(oldpath, newpath string) error {
+	return os.Rename(oldpath, newpath)
+}
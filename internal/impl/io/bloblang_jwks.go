@@ -0,0 +1,216 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package io
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/query"
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+type jwksKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+
+	// RSA public key parameters.
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// ECDSA public key parameters.
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+
+	// Symmetric key parameters.
+	K string `json:"k"`
+}
+
+type jwksVerificationKey struct {
+	kid string
+	alg string
+	key any
+}
+
+func jwksDecodeParam(name, v string) ([]byte, error) {
+	if v == "" {
+		return nil, fmt.Errorf("missing parameter %v", name)
+	}
+	b, err := base64.RawURLEncoding.DecodeString(v)
+	if err != nil {
+		return nil, fmt.Errorf("parameter %v: %w", name, err)
+	}
+	return b, nil
+}
+
+func (k jwksKey) verificationKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := jwksDecodeParam("n", k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := jwksDecodeParam("e", k.E)
+		if err != nil {
+			return nil, err
+		}
+		eInt := new(big.Int).SetBytes(e)
+		if !eInt.IsInt64() || eInt.Int64() < 2 || eInt.Int64() > (1<<31-1) {
+			return nil, errors.New("parameter e is not a valid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(eInt.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := jwksDecodeParam("x", k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := jwksDecodeParam("y", k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "oct":
+		return jwksDecodeParam("k", k.K)
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func parseJWKS(data []byte) ([]jwksVerificationKey, error) {
+	var set struct {
+		Keys []jwksKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	var keys []jwksVerificationKey
+	for i, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.verificationKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWKS key %v: %w", i, err)
+		}
+		keys = append(keys, jwksVerificationKey{kid: k.Kid, alg: k.Alg, key: key})
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS does not contain any signature verification keys")
+	}
+	return keys, nil
+}
+
+// jwksKeyFunc selects the key of a set that matches the `kid` header of a
+// token, or the only key of the set when the token has no `kid` header. The
+// signing method of the token is responsible for rejecting keys of the wrong
+// type.
+func jwksKeyFunc(keys []jwksVerificationKey) jwt.Keyfunc {
+	return func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" && len(keys) > 1 {
+			return nil, errors.New("token does not specify a kid header and the key set contains multiple keys")
+		}
+		for _, k := range keys {
+			if kid != "" && k.kid != kid {
+				continue
+			}
+			if k.alg != "" && k.alg != token.Method.Alg() {
+				return nil, fmt.Errorf("key %q does not support algorithm %v", k.kid, token.Method.Alg())
+			}
+			return k.key, nil
+		}
+		return nil, fmt.Errorf("key %q was not found in the key set", kid)
+	}
+}
+
+func init() {
+	if err := bloblang.RegisterMethodV2("parse_jwt_jwks",
+		bloblang.NewPluginSpec().
+			Impure().
+			Category(query.MethodCategoryParsing).
+			Description("Parses a JSON Web Token, verifies its signature with a key selected by the `kid` header of the token from a JSON Web Key Set file, and returns the claims of the token. Tokens signed with the HS256, HS384, HS512, RS256, RS384, RS512, ES256, ES384 and ES512 algorithms are supported, and the `exp` and `nbf` claims are validated when they are present. The file is read once and relative paths are resolved from the directory of the mapping.").
+			Param(bloblang.NewStringParam("path").
+				Description("The path of a JSON Web Key Set file.")).
+			Param(bloblang.NewStringParam("audience").
+				Description("An optional audience that the `aud` claim of the token must contain.").
+				Optional()).
+			Param(bloblang.NewBoolParam("require_expiry").
+				Description("Whether tokens without an `exp` claim should be rejected.").
+				Default(false)).
+			ExampleNotTested("", `root.claims = this.token.parse_jwt_jwks(path: "./keys/jwks.json", audience: "orders")`),
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			path, err := args.GetString("path")
+			if err != nil {
+				return nil, err
+			}
+			jwksBytes, err := args.ImportFile(path)
+			if err != nil {
+				return nil, err
+			}
+			keys, err := parseJWKS(jwksBytes)
+			if err != nil {
+				return nil, err
+			}
+
+			opts := []jwt.ParserOption{jwt.WithValidMethods([]string{
+				"HS256", "HS384", "HS512",
+				"RS256", "RS384", "RS512",
+				"ES256", "ES384", "ES512",
+			})}
+
+			audience, err := args.GetOptionalString("audience")
+			if err != nil {
+				return nil, err
+			}
+			if audience != nil {
+				opts = append(opts, jwt.WithAudience(*audience))
+			}
+
+			requireExpiry, err := args.GetBool("require_expiry")
+			if err != nil {
+				return nil, err
+			}
+			if requireExpiry {
+				opts = append(opts, jwt.WithExpirationRequired())
+			}
+
+			parser := jwt.NewParser(opts...)
+			keyFn := jwksKeyFunc(keys)
+			return bloblang.StringMethod(func(s string) (any, error) {
+				token, err := parser.Parse(s, keyFn)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse JWT: %w", err)
+				}
+				claims, ok := token.Claims.(jwt.MapClaims)
+				if !ok {
+					return nil, errors.New("failed to parse JWT: unexpected claims type")
+				}
+				return map[string]any(claims), nil
+			}), nil
+		},
+	); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package io_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+func TestParseJWTJWKS(t *testing.T) {
+	b64 := base64.RawURLEncoding.EncodeToString
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	jwks, err := json.Marshal(map[string]any{
+		"keys": []any{
+			map[string]any{
+				"kty": "RSA", "kid": "rsa", "alg": "RS256", "use": "sig",
+				"n": b64(rsaKey.N.Bytes()),
+				"e": b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+			},
+			map[string]any{
+				"kty": "EC", "kid": "ec", "crv": "P-384",
+				"x": b64(ecKey.X.Bytes()),
+				"y": b64(ecKey.Y.Bytes()),
+			},
+			map[string]any{
+				"kty": "oct", "kid": "hmac", "alg": "HS512",
+				"k": b64([]byte("foo")),
+			},
+			map[string]any{
+				"kty": "RSA", "kid": "encryption", "use": "enc",
+				"n": "ignored", "e": "ignored",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(jwksPath, jwks, 0o644))
+
+	sign := func(method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+		t.Helper()
+		token := jwt.NewWithClaims(method, claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		s, err := token.SignedString(key)
+		require.NoError(t, err)
+		return s
+	}
+
+	exec, err := bloblang.Parse(`root = this.parse_jwt_jwks(path: "` + jwksPath + `", audience: "orders")`)
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{"sub": "user123", "aud": "orders"}
+	for _, token := range []string{
+		sign(jwt.SigningMethodRS256, "rsa", rsaKey, claims),
+		sign(jwt.SigningMethodES384, "ec", ecKey, claims),
+		sign(jwt.SigningMethodHS512, "hmac", []byte("foo"), claims),
+	} {
+		res, err := exec.Query(token)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"sub": "user123", "aud": "orders"}, res)
+	}
+
+	for _, test := range []struct {
+		name        string
+		token       string
+		errContains string
+	}{
+		{
+			name:        "missing kid",
+			token:       sign(jwt.SigningMethodRS256, "", rsaKey, claims),
+			errContains: "token does not specify a kid header",
+		},
+		{
+			name:        "unknown kid",
+			token:       sign(jwt.SigningMethodRS256, "nope", rsaKey, claims),
+			errContains: `key "nope" was not found`,
+		},
+		{
+			name:        "key algorithm mismatch",
+			token:       sign(jwt.SigningMethodRS512, "rsa", rsaKey, claims),
+			errContains: `key "rsa" does not support algorithm RS512`,
+		},
+		{
+			name:        "key type mismatch",
+			token:       sign(jwt.SigningMethodHS256, "ec", []byte("foo"), claims),
+			errContains: "key is of invalid type",
+		},
+		{
+			name:        "wrong audience",
+			token:       sign(jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{"aud": "payments"}),
+			errContains: "token has invalid audience",
+		},
+		{
+			name:        "expired",
+			token:       sign(jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{"aud": "orders", "exp": time.Now().Add(-time.Hour).Unix()}),
+			errContains: "token is expired",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := exec.Query(test.token)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.errContains)
+		})
+	}
+}
+
+func TestParseJWTJWKSErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, test := range []struct {
+		name        string
+		jwks        string
+		errContains string
+	}{
+		{
+			name:        "invalid json",
+			jwks:        `{"keys":`,
+			errContains: "failed to parse JWKS",
+		},
+		{
+			name:        "no keys",
+			jwks:        `{"keys":[]}`,
+			errContains: "does not contain any signature verification keys",
+		},
+		{
+			name:        "unsupported key type",
+			jwks:        `{"keys":[{"kty":"OKP","kid":"foo"}]}`,
+			errContains: `failed to parse JWKS key 0: unsupported key type "OKP"`,
+		},
+		{
+			name:        "missing parameter",
+			jwks:        `{"keys":[{"kty":"EC","crv":"P-256","x":"AQ"}]}`,
+			errContains: "failed to parse JWKS key 0: missing parameter y",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			jwksPath := filepath.Join(tmpDir, "jwks.json")
+			require.NoError(t, os.WriteFile(jwksPath, []byte(test.jwks), 0o644))
+
+			_, err := bloblang.Parse(`root = this.parse_jwt_jwks("` + jwksPath + `")`)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.errContains)
+		})
+	}
+}
@@ -0,0 +1,961 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package io
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/shutdown"
+
+	"github.com/redpanda-data/benthos/v4/internal/component"
+	"github.com/redpanda-data/benthos/v4/internal/filepath/ifs"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	dbFieldDirectory    = "directory"
+	dbFieldMaxBytes     = "max_bytes"
+	dbFieldMaxAge       = "max_age"
+	dbFieldSegmentSize  = "segment_size"
+	dbFieldSync         = "sync"
+	dbFieldSyncInterval = "sync_interval"
+)
+
+const (
+	diskSyncAlways   = "always"
+	diskSyncInterval = "interval"
+	diskSyncNone     = "none"
+)
+
+func diskBufferConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Utility").
+		Summary("Stores consumed messages in a segmented write-ahead log on disk, and only acknowledges them at the input level once they have been persisted according to a sync policy. Messages that have not been acknowledged downstream are replayed after a restart.").
+		Description(`
+This buffer is appropriate when consuming messages from inputs that do not gracefully handle back pressure, but where losing buffered messages in the event of a crash is unacceptable.
+
+Each batch written to the buffer is appended as a record to the current segment file within the configured directory, and once a segment exceeds `+"`segment_size`"+` a new segment is started. Segments are deleted once all of their records have been acknowledged downstream.
+
+== Delivery guarantees
+
+The progress of consumers is tracked in a checkpoint file alongside the segments. When Redpanda Connect restarts, any records after the checkpoint are replayed, which means messages that were acknowledged out of order prior to a crash might be delivered more than once.
+
+The `+"`sync`"+` field determines when records are flushed to stable storage with an fsync, and therefore when the input is acknowledged:
+
+- `+"`always`"+`: Each batch is synced before it is acknowledged, which is the safest and slowest option.
+- `+"`interval`"+`: Batches are acknowledged once the next periodic sync, determined by `+"`sync_interval`"+`, has completed.
+- `+"`none`"+`: Batches are acknowledged as soon as they are written and syncing is left to the operating system, data can therefore be lost if the host (rather than the process) crashes.
+
+== Retention
+
+The field `+"`max_bytes`"+` limits the total size of unacknowledged records, and once reached back pressure is applied upstream. Records that are older than `+"`max_age`"+` when they are read are dropped rather than delivered.
+
+Records that fail their checksum or cannot be decoded when they are read are logged and skipped rather than delivered. When the length of a corrupt record cannot be trusted the remainder of its segment is skipped.
+
+== Metrics
+
+This buffer emits the gauges `+"`buffer_disk_bytes` and `buffer_disk_segments`"+`, the counter `+"`buffer_disk_dropped`"+` which tracks records dropped due to exceeding `+"`max_age`"+`, and the counter `+"`buffer_disk_corrupt`"+` which tracks corrupt records that were skipped.`).
+		Fields(
+			service.NewStringField(dbFieldDirectory).
+				Description("The directory within which to store segment files and the checkpoint file. The directory is created if it does not exist."),
+			service.NewIntField(dbFieldMaxBytes).
+				Description("The maximum total size (in bytes) of unacknowledged records to store before applying backpressure upstream.").
+				Default(1073741824),
+			service.NewDurationField(dbFieldMaxAge).
+				Description("An optional maximum age of records, records older than this when read from the buffer are dropped.").
+				Example("24h").
+				Optional(),
+			service.NewIntField(dbFieldSegmentSize).
+				Description("The size (in bytes) after which a new segment file is started.").
+				Advanced().
+				Default(67108864),
+			service.NewStringEnumField(dbFieldSync, diskSyncAlways, diskSyncInterval, diskSyncNone).
+				Description("The policy that determines when written records are synced to disk and therefore when the input is acknowledged.").
+				Default(diskSyncAlways),
+			service.NewDurationField(dbFieldSyncInterval).
+				Description("The period of time between each sync when the `sync` policy is `interval`. The checkpoint of acknowledged records is also written on this interval for the `interval` and `none` policies.").
+				Advanced().
+				Default("1s"),
+		)
+}
+
+func init() {
+	err := service.RegisterBatchBuffer(
+		"disk", diskBufferConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchBuffer, error) {
+			return newDiskBufferFromConfig(conf, mgr)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type diskBufferConfigStruct struct {
+	dir          string
+	maxBytes     int64
+	maxAge       time.Duration
+	segmentSize  int64
+	syncPolicy   string
+	syncInterval time.Duration
+}
+
+func newDiskBufferFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*diskBuffer, error) {
+	var c diskBufferConfigStruct
+	var err error
+	if c.dir, err = conf.FieldString(dbFieldDirectory); err != nil {
+		return nil, err
+	}
+	if c.dir == "" {
+		return nil, errors.New("a directory must be specified")
+	}
+
+	var maxBytes, segmentSize int
+	if maxBytes, err = conf.FieldInt(dbFieldMaxBytes); err != nil {
+		return nil, err
+	}
+	if segmentSize, err = conf.FieldInt(dbFieldSegmentSize); err != nil {
+		return nil, err
+	}
+	if maxBytes <= 0 || segmentSize <= 0 {
+		return nil, errors.New("max_bytes and segment_size must be greater than zero")
+	}
+	c.maxBytes, c.segmentSize = int64(maxBytes), int64(segmentSize)
+
+	if conf.Contains(dbFieldMaxAge) {
+		if c.maxAge, err = conf.FieldDuration(dbFieldMaxAge); err != nil {
+			return nil, err
+		}
+	}
+	if c.syncPolicy, err = conf.FieldString(dbFieldSync); err != nil {
+		return nil, err
+	}
+	if c.syncInterval, err = conf.FieldDuration(dbFieldSyncInterval); err != nil {
+		return nil, err
+	}
+	if c.syncInterval <= 0 {
+		return nil, errors.New("sync_interval must be greater than zero")
+	}
+	return newDiskBuffer(c, mgr)
+}
+
+//------------------------------------------------------------------------------
+
+const (
+	diskSegmentExt      = ".seg"
+	diskCheckpointFile  = "checkpoint"
+	diskRecordHeaderLen = 8
+)
+
+var diskCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+// diskFile is the subset of *os.File capabilities required by the buffer.
+type diskFile interface {
+	io.Writer
+	io.ReaderAt
+	Sync() error
+	Truncate(size int64) error
+	Close() error
+}
+
+// diskPosition points to the beginning of a record within a segment.
+type diskPosition struct {
+	segment uint64
+	offset  int64
+}
+
+type diskSegment struct {
+	id   uint64
+	size int64
+}
+
+// diskRecord is a batch that has been read from the log and is yet to be
+// acknowledged.
+type diskRecord struct {
+	pos   diskPosition
+	next  diskPosition
+	batch service.MessageBatch
+	acked bool
+}
+
+type diskBuffer struct {
+	conf diskBufferConfigStruct
+	fs   *service.FS
+	log  *service.Logger
+
+	mBytes    *service.MetricGauge
+	mSegments *service.MetricGauge
+	mDropped  *service.MetricCounter
+	mCorrupt  *service.MetricCounter
+
+	cond *sync.Cond
+
+	segments []*diskSegment
+	head     diskFile
+
+	readPos  diskPosition
+	readFile diskFile
+	readID   uint64
+
+	checkpoint      diskPosition
+	checkpointDirty bool
+
+	// Records that have been read but not yet acknowledged, in the order in
+	// which they were read.
+	pending []*diskRecord
+
+	// Records that have been rejected downstream and must be redelivered.
+	retries []*diskRecord
+
+	// Acknowledgements to call once the next sync has completed.
+	pendingAcks []service.AckFunc
+
+	endOfInput bool
+	closed     bool
+
+	shutSig *shutdown.Signaller
+}
+
+func newDiskBuffer(conf diskBufferConfigStruct, mgr *service.Resources) (*diskBuffer, error) {
+	d := &diskBuffer{
+		conf:      conf,
+		fs:        mgr.FS(),
+		log:       mgr.Logger(),
+		mBytes:    mgr.Metrics().NewGauge("buffer_disk_bytes"),
+		mSegments: mgr.Metrics().NewGauge("buffer_disk_segments"),
+		mDropped:  mgr.Metrics().NewCounter("buffer_disk_dropped"),
+		mCorrupt:  mgr.Metrics().NewCounter("buffer_disk_corrupt"),
+		cond:      sync.NewCond(&sync.Mutex{}),
+		shutSig:   shutdown.NewSignaller(),
+	}
+	if err := d.recover(); err != nil {
+		return nil, err
+	}
+	d.updateMetrics()
+
+	if conf.syncPolicy != diskSyncAlways {
+		go d.syncLoop()
+	} else {
+		d.shutSig.TriggerHasStopped()
+	}
+	return d, nil
+}
+
+func (d *diskBuffer) segmentPath(id uint64) string {
+	return filepath.Join(d.conf.dir, fmt.Sprintf("%020d%v", id, diskSegmentExt))
+}
+
+func (d *diskBuffer) openFile(path string, flag int) (diskFile, error) {
+	f, err := d.fs.OpenFile(path, flag, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	df, ok := f.(diskFile)
+	if !ok {
+		_ = f.Close()
+		return nil, errors.New("filesystem does not support the file operations required by the disk buffer")
+	}
+	return df, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (d *diskBuffer) readCheckpoint() (pos diskPosition, exists bool, err error) {
+	var b []byte
+	if b, err = ifs.ReadFile(d.fs, filepath.Join(d.conf.dir, diskCheckpointFile)); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			err = nil
+		}
+		return
+	}
+	if len(b) != 20 || crc32.Checksum(b[:16], diskCRCTable) != binary.BigEndian.Uint32(b[16:]) {
+		d.log.Warn("Disk buffer checkpoint is corrupt, all records will be replayed")
+		return
+	}
+	pos.segment = binary.BigEndian.Uint64(b[:8])
+	pos.offset = int64(binary.BigEndian.Uint64(b[8:16]))
+	exists = true
+	return
+}
+
+func (d *diskBuffer) writeCheckpoint(sync bool) error {
+	b := make([]byte, 20)
+	binary.BigEndian.PutUint64(b[:8], d.checkpoint.segment)
+	binary.BigEndian.PutUint64(b[8:16], uint64(d.checkpoint.offset))
+	binary.BigEndian.PutUint32(b[16:], crc32.Checksum(b[:16], diskCRCTable))
+
+	finalPath := filepath.Join(d.conf.dir, diskCheckpointFile)
+	tmpPath := finalPath + ".tmp"
+
+	f, err := d.openFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(b); err == nil && sync {
+		err = f.Sync()
+	}
+	if cErr := f.Close(); cErr != nil && err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return err
+	}
+	if err = d.fs.Rename(tmpPath, finalPath); err != nil {
+		return err
+	}
+	d.checkpointDirty = false
+	return nil
+}
+
+// scanSegment walks the records of a segment and returns the offset following
+// the last valid record.
+func scanSegment(f io.ReaderAt) (validSize int64, err error) {
+	header := make([]byte, diskRecordHeaderLen)
+	for {
+		if _, err = f.ReadAt(header, validSize); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			return
+		}
+		length := int64(binary.BigEndian.Uint32(header[:4]))
+		payload := make([]byte, length)
+		if _, err = f.ReadAt(payload, validSize+diskRecordHeaderLen); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			return
+		}
+		if crc32.Checksum(payload, diskCRCTable) != binary.BigEndian.Uint32(header[4:]) {
+			return
+		}
+		validSize += diskRecordHeaderLen + length
+	}
+}
+
+// recover loads any existing segments and the checkpoint from the directory,
+// truncating torn writes at the end of segments.
+func (d *diskBuffer) recover() error {
+	if err := d.fs.MkdirAll(d.conf.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create buffer directory: %w", err)
+	}
+
+	entries, err := fs.ReadDir(d.fs, d.conf.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read buffer directory: %w", err)
+	}
+
+	var ids []uint64
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, diskSegmentExt) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, diskSegmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	checkpoint, hasCheckpoint, err := d.readCheckpoint()
+	if err != nil {
+		return fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	for _, id := range ids {
+		path := d.segmentPath(id)
+		if hasCheckpoint && id < checkpoint.segment {
+			if err := d.fs.Remove(path); err != nil {
+				return fmt.Errorf("failed to remove acknowledged segment: %w", err)
+			}
+			continue
+		}
+
+		f, err := d.openFile(path, os.O_RDWR)
+		if err != nil {
+			return fmt.Errorf("failed to open segment: %w", err)
+		}
+		validSize, err := scanSegment(f)
+		if err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to scan segment: %w", err)
+		}
+
+		info, err := d.fs.Stat(path)
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+		if info.Size() > validSize {
+			d.log.Warnf("Truncating %v bytes of incomplete or corrupt records from segment %v", info.Size()-validSize, path)
+			if err := f.Truncate(validSize); err != nil {
+				_ = f.Close()
+				return fmt.Errorf("failed to truncate segment: %w", err)
+			}
+		}
+		_ = f.Close()
+
+		d.segments = append(d.segments, &diskSegment{id: id, size: validSize})
+	}
+
+	if len(d.segments) == 0 {
+		var id uint64
+		if hasCheckpoint {
+			id = checkpoint.segment
+		}
+		d.segments = append(d.segments, &diskSegment{id: id})
+	}
+
+	first := d.segments[0]
+	if !hasCheckpoint || checkpoint.segment != first.id || checkpoint.offset > first.size {
+		checkpoint = diskPosition{segment: first.id}
+	}
+	d.checkpoint = checkpoint
+	d.readPos = checkpoint
+
+	head := d.segments[len(d.segments)-1]
+	if d.head, err = d.openFile(d.segmentPath(head.id), os.O_WRONLY|os.O_CREATE|os.O_APPEND); err != nil {
+		return fmt.Errorf("failed to open head segment: %w", err)
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+func (d *diskBuffer) unackedBytes() int64 {
+	var total int64
+	for _, s := range d.segments {
+		total += s.size
+	}
+	return total - d.checkpoint.offset
+}
+
+func (d *diskBuffer) updateMetrics() {
+	d.mBytes.Set(d.unackedBytes())
+	d.mSegments.Set(int64(len(d.segments)))
+}
+
+func (d *diskBuffer) syncLoop() {
+	defer d.shutSig.TriggerHasStopped()
+
+	t := time.NewTicker(d.conf.syncInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+		case <-d.shutSig.SoftStopChan():
+			return
+		}
+
+		d.cond.L.Lock()
+		acks, err := d.syncLocked()
+		d.cond.L.Unlock()
+
+		if err != nil {
+			d.log.Errorf("Failed to sync disk buffer: %v", err)
+			continue
+		}
+		for _, aFn := range acks {
+			_ = aFn(context.Background(), nil)
+		}
+	}
+}
+
+// syncLocked flushes the head segment and checkpoint to disk and returns any
+// acknowledgements that are now safe to call.
+func (d *diskBuffer) syncLocked() ([]service.AckFunc, error) {
+	if d.conf.syncPolicy == diskSyncInterval && len(d.pendingAcks) > 0 {
+		if err := d.head.Sync(); err != nil {
+			return nil, err
+		}
+	}
+	if d.checkpointDirty {
+		if err := d.writeCheckpoint(d.conf.syncPolicy == diskSyncInterval); err != nil {
+			return nil, err
+		}
+	}
+	acks := d.pendingAcks
+	d.pendingAcks = nil
+	return acks, nil
+}
+
+//------------------------------------------------------------------------------
+
+func encodeDiskBatch(ts time.Time, batch service.MessageBatch) ([]byte, error) {
+	buf := make([]byte, 8, 256)
+	binary.BigEndian.PutUint64(buf, uint64(ts.UnixNano()))
+	buf = binary.AppendUvarint(buf, uint64(len(batch)))
+
+	appendBytes := func(b []byte) {
+		buf = binary.AppendUvarint(buf, uint64(len(b)))
+		buf = append(buf, b...)
+	}
+
+	for _, msg := range batch {
+		mBytes, err := msg.AsBytes()
+		if err != nil {
+			return nil, err
+		}
+		appendBytes(mBytes)
+
+		type kv struct {
+			k string
+			v any
+		}
+		var meta []kv
+		_ = msg.MetaWalkMut(func(k string, v any) error {
+			meta = append(meta, kv{k, v})
+			return nil
+		})
+
+		buf = binary.AppendUvarint(buf, uint64(len(meta)))
+		for _, e := range meta {
+			appendBytes([]byte(e.k))
+			if s, isStr := e.v.(string); isStr {
+				buf = append(buf, 0)
+				appendBytes([]byte(s))
+				continue
+			}
+			jBytes, err := json.Marshal(e.v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode metadata key %v: %w", e.k, err)
+			}
+			buf = append(buf, 1)
+			appendBytes(jBytes)
+		}
+	}
+	return buf, nil
+}
+
+var errDiskRecordCorrupt = errors.New("disk buffer record is corrupt")
+
+func decodeDiskBatch(b []byte) (ts time.Time, batch service.MessageBatch, err error) {
+	if len(b) < 8 {
+		err = errDiskRecordCorrupt
+		return
+	}
+	ts = time.Unix(0, int64(binary.BigEndian.Uint64(b[:8])))
+	b = b[8:]
+
+	readUvarint := func() uint64 {
+		v, n := binary.Uvarint(b)
+		if n <= 0 {
+			err = errDiskRecordCorrupt
+			return 0
+		}
+		b = b[n:]
+		return v
+	}
+	readBytes := func() []byte {
+		l := readUvarint()
+		if err != nil {
+			return nil
+		}
+		if uint64(len(b)) < l {
+			err = errDiskRecordCorrupt
+			return nil
+		}
+		v := b[:l]
+		b = b[l:]
+		return v
+	}
+
+	count := readUvarint()
+	for i := uint64(0); i < count && err == nil; i++ {
+		msg := service.NewMessage(readBytes())
+		metaCount := readUvarint()
+		for j := uint64(0); j < metaCount && err == nil; j++ {
+			k := string(readBytes())
+			if err != nil || len(b) == 0 {
+				err = errDiskRecordCorrupt
+				break
+			}
+			kind := b[0]
+			b = b[1:]
+			v := readBytes()
+			if err != nil {
+				break
+			}
+			if kind == 0 {
+				msg.MetaSetMut(k, string(v))
+				continue
+			}
+			var jv any
+			if err = json.Unmarshal(v, &jv); err != nil {
+				break
+			}
+			msg.MetaSetMut(k, jv)
+		}
+		batch = append(batch, msg)
+	}
+	return
+}
+
+//------------------------------------------------------------------------------
+
+func (d *diskBuffer) WriteBatch(ctx context.Context, msgBatch service.MessageBatch, aFn service.AckFunc) error {
+	payload, err := encodeDiskBatch(time.Now(), msgBatch)
+	if err != nil {
+		return err
+	}
+
+	record := make([]byte, diskRecordHeaderLen+len(payload))
+	binary.BigEndian.PutUint32(record[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.Checksum(payload, diskCRCTable))
+	copy(record[diskRecordHeaderLen:], payload)
+
+	recordSize := int64(len(record))
+	if recordSize > d.conf.maxBytes {
+		return component.ErrMessageTooLarge
+	}
+
+	d.cond.L.Lock()
+	ackNow, err := d.writeRecordLocked(record, aFn)
+	d.cond.L.Unlock()
+	if err != nil || !ackNow {
+		return err
+	}
+
+	// The upstream acknowledgement is called without holding the lock in
+	// order to avoid blocking readers.
+	return aFn(ctx, nil)
+}
+
+// writeRecordLocked appends a record to the head segment, returning true if the
+// record should be acknowledged upstream immediately, or false if the
+// acknowledgement has been deferred until the next sync.
+func (d *diskBuffer) writeRecordLocked(record []byte, aFn service.AckFunc) (bool, error) {
+	recordSize := int64(len(record))
+	for !d.closed && d.unackedBytes()+recordSize > d.conf.maxBytes {
+		d.cond.Wait()
+	}
+	if d.closed {
+		return false, component.ErrTypeClosed
+	}
+
+	head := d.segments[len(d.segments)-1]
+	if head.size > 0 && head.size+recordSize > d.conf.segmentSize {
+		if err := d.rotateLocked(); err != nil {
+			return false, err
+		}
+		head = d.segments[len(d.segments)-1]
+	}
+
+	if _, err := d.head.Write(record); err != nil {
+		// A partial write would corrupt subsequent records, so we attempt to
+		// remove it and rely on recovery otherwise.
+		_ = d.head.Truncate(head.size)
+		return false, fmt.Errorf("failed to write record: %w", err)
+	}
+	head.size += recordSize
+	d.updateMetrics()
+	d.cond.Broadcast()
+
+	switch d.conf.syncPolicy {
+	case diskSyncAlways:
+		if err := d.head.Sync(); err != nil {
+			return false, fmt.Errorf("failed to sync record: %w", err)
+		}
+	case diskSyncInterval:
+		d.pendingAcks = append(d.pendingAcks, aFn)
+		return false, nil
+	}
+	return true, nil
+}
+
+func (d *diskBuffer) rotateLocked() error {
+	if err := d.head.Sync(); err != nil {
+		return fmt.Errorf("failed to sync segment: %w", err)
+	}
+	if err := d.head.Close(); err != nil {
+		return fmt.Errorf("failed to close segment: %w", err)
+	}
+
+	next := &diskSegment{id: d.segments[len(d.segments)-1].id + 1}
+	head, err := d.openFile(d.segmentPath(next.id), os.O_WRONLY|os.O_CREATE|os.O_TRUNC|os.O_APPEND)
+	if err != nil {
+		return fmt.Errorf("failed to create segment: %w", err)
+	}
+	d.head = head
+	d.segments = append(d.segments, next)
+	return nil
+}
+
+func (d *diskBuffer) segmentIndex(id uint64) int {
+	for i, s := range d.segments {
+		if s.id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// readNextLocked attempts to read the record at the current read position,
+// returns nil if there are no further records to read. Corrupt records are
+// returned already acknowledged in order for them to be skipped.
+func (d *diskBuffer) readNextLocked() (*diskRecord, error) {
+	var seg *diskSegment
+	for {
+		i := d.segmentIndex(d.readPos.segment)
+		if i == -1 {
+			return nil, fmt.Errorf("segment %v is missing", d.readPos.segment)
+		}
+		if d.readPos.offset < d.segments[i].size {
+			seg = d.segments[i]
+			break
+		}
+		if i == len(d.segments)-1 {
+			return nil, nil
+		}
+		d.readPos = diskPosition{segment: d.segments[i+1].id}
+	}
+
+	if d.readFile == nil || d.readID != d.readPos.segment {
+		if d.readFile != nil {
+			_ = d.readFile.Close()
+			d.readFile = nil
+		}
+		f, err := d.openFile(d.segmentPath(d.readPos.segment), os.O_RDONLY)
+		if err != nil {
+			return nil, err
+		}
+		d.readFile, d.readID = f, d.readPos.segment
+	}
+
+	segEnd := diskPosition{segment: seg.id, offset: seg.size}
+	if d.readPos.offset+diskRecordHeaderLen > seg.size {
+		return d.skipCorruptLocked(segEnd, errDiskRecordCorrupt), nil
+	}
+
+	header := make([]byte, diskRecordHeaderLen)
+	if _, err := d.readFile.ReadAt(header, d.readPos.offset); err != nil {
+		return nil, err
+	}
+	length := int64(binary.BigEndian.Uint32(header[:4]))
+	if d.readPos.offset+diskRecordHeaderLen+length > seg.size {
+		// The length of the record cannot be trusted, and therefore neither
+		// can the position of any records that follow it.
+		return d.skipCorruptLocked(segEnd, errDiskRecordCorrupt), nil
+	}
+
+	payload := make([]byte, length)
+	if _, err := d.readFile.ReadAt(payload, d.readPos.offset+diskRecordHeaderLen); err != nil {
+		return nil, err
+	}
+	next := diskPosition{segment: seg.id, offset: d.readPos.offset + diskRecordHeaderLen + length}
+	if crc32.Checksum(payload, diskCRCTable) != binary.BigEndian.Uint32(header[4:]) {
+		return d.skipCorruptLocked(next, errDiskRecordCorrupt), nil
+	}
+
+	ts, batch, err := decodeDiskBatch(payload)
+	if err != nil {
+		return d.skipCorruptLocked(next, err), nil
+	}
+
+	r := &diskRecord{pos: d.readPos, next: next}
+	d.readPos = next
+	if d.conf.maxAge > 0 && time.Since(ts) > d.conf.maxAge {
+		r.acked = true
+		d.mDropped.Incr(1)
+	} else {
+		r.batch = batch
+	}
+	d.pending = append(d.pending, r)
+	return r, nil
+}
+
+// skipCorruptLocked moves the read position past a corrupt record and returns
+// it as an acknowledged record.
+func (d *diskBuffer) skipCorruptLocked(next diskPosition, err error) *diskRecord {
+	d.log.Errorf("Skipping corrupt record at offset %v of segment %v: %v", d.readPos.offset, d.segmentPath(d.readPos.segment), err)
+	d.mCorrupt.Incr(1)
+
+	r := &diskRecord{pos: d.readPos, next: next, acked: true}
+	d.readPos = next
+	d.pending = append(d.pending, r)
+	return r
+}
+
+// advanceCheckpointLocked moves the checkpoint past any contiguous run of
+// acknowledged records, and removes segments that are no longer needed.
+func (d *diskBuffer) advanceCheckpointLocked() {
+	moved := false
+	for len(d.pending) > 0 && d.pending[0].acked {
+		d.checkpoint = d.pending[0].next
+		d.pending[0] = nil
+		d.pending = d.pending[1:]
+		moved = true
+	}
+	if len(d.pending) > 0 {
+		if d.pending[0].pos != d.checkpoint {
+			d.checkpoint = d.pending[0].pos
+			moved = true
+		}
+	} else if d.checkpoint != d.readPos {
+		d.checkpoint = d.readPos
+		moved = true
+	}
+	if !moved {
+		return
+	}
+
+	// Normalise a checkpoint at the end of a full segment to the start of the
+	// next so that the segment can be removed.
+	if i := d.segmentIndex(d.checkpoint.segment); i != -1 && i < len(d.segments)-1 &&
+		d.checkpoint.offset >= d.segments[i].size {
+		d.checkpoint = diskPosition{segment: d.segments[i+1].id}
+	}
+
+	for len(d.segments) > 1 && d.segments[0].id < d.checkpoint.segment {
+		if d.readFile != nil && d.readID == d.segments[0].id {
+			_ = d.readFile.Close()
+			d.readFile = nil
+		}
+		if err := d.fs.Remove(d.segmentPath(d.segments[0].id)); err != nil {
+			d.log.Errorf("Failed to remove acknowledged segment: %v", err)
+		}
+		d.segments = d.segments[1:]
+	}
+	if d.readPos.segment < d.segments[0].id {
+		d.readPos = diskPosition{segment: d.segments[0].id}
+	}
+
+	d.checkpointDirty = true
+	if d.conf.syncPolicy == diskSyncAlways {
+		if err := d.writeCheckpoint(true); err != nil {
+			d.log.Errorf("Failed to write checkpoint: %v", err)
+		}
+	}
+	d.updateMetrics()
+}
+
+func (d *diskBuffer) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	ctx, done := context.WithCancel(ctx)
+	defer done()
+
+	go func() {
+		<-ctx.Done()
+		d.cond.Broadcast()
+	}()
+
+	d.cond.L.Lock()
+	defer d.cond.L.Unlock()
+
+	var r *diskRecord
+	for {
+		if d.closed {
+			return nil, nil, service.ErrEndOfBuffer
+		}
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+
+		if len(d.retries) > 0 {
+			r = d.retries[0]
+			d.retries[0] = nil
+			d.retries = d.retries[1:]
+			break
+		}
+
+		var err error
+		if r, err = d.readNextLocked(); err != nil {
+			return nil, nil, err
+		}
+		if r != nil {
+			if r.acked {
+				// Expired and corrupt records are dropped without being
+				// delivered.
+				d.advanceCheckpointLocked()
+				d.cond.Broadcast()
+				continue
+			}
+			break
+		}
+
+		if d.endOfInput && len(d.pending) == 0 {
+			return nil, nil, service.ErrEndOfBuffer
+		}
+		d.cond.Wait()
+	}
+
+	outBatch := make(service.MessageBatch, len(r.batch))
+	for i, m := range r.batch {
+		outBatch[i] = m.Copy()
+	}
+	return outBatch, func(ctx context.Context, err error) error {
+		d.cond.L.Lock()
+		defer d.cond.L.Unlock()
+		if err == nil {
+			r.acked = true
+			d.advanceCheckpointLocked()
+		} else {
+			d.retries = append(d.retries, r)
+		}
+		d.cond.Broadcast()
+		return nil
+	}, nil
+}
+
+func (d *diskBuffer) EndOfInput() {
+	d.cond.L.Lock()
+	d.endOfInput = true
+	d.cond.Broadcast()
+	d.cond.L.Unlock()
+}
+
+func (d *diskBuffer) Close(ctx context.Context) error {
+	d.shutSig.TriggerSoftStop()
+	select {
+	case <-d.shutSig.HasStoppedChan():
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	d.cond.L.Lock()
+	if d.closed {
+		d.cond.L.Unlock()
+		return nil
+	}
+	d.closed = true
+	d.cond.Broadcast()
+
+	err := d.head.Sync()
+	var acks []service.AckFunc
+	if err == nil {
+		acks = d.pendingAcks
+		d.pendingAcks = nil
+		if d.checkpointDirty {
+			err = d.writeCheckpoint(true)
+		}
+	}
+	if cErr := d.head.Close(); cErr != nil && err == nil {
+		err = cErr
+	}
+	if d.readFile != nil {
+		_ = d.readFile.Close()
+		d.readFile = nil
+	}
+	d.cond.L.Unlock()
+
+	for _, aFn := range acks {
+		_ = aFn(ctx, nil)
+	}
+	return err
+}
@@ -0,0 +1,417 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package io
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func diskBufFromConf(t *testing.T, conf string) *diskBuffer {
+	t.Helper()
+
+	parsedConf, err := diskBufferConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	buf, err := newDiskBufferFromConfig(parsedConf, service.MockResources())
+	require.NoError(t, err)
+	return buf
+}
+
+func noopAck(context.Context, error) error { return nil }
+
+func diskBufRead(t *testing.T, buf *diskBuffer) ([]string, service.AckFunc) {
+	t.Helper()
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*5)
+	defer done()
+
+	batch, aFn, err := buf.ReadBatch(ctx)
+	require.NoError(t, err)
+
+	var strs []string
+	for _, m := range batch {
+		b, err := m.AsBytes()
+		require.NoError(t, err)
+		strs = append(strs, string(b))
+	}
+	return strs, aFn
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+diskSegmentExt))
+	require.NoError(t, err)
+	return matches
+}
+
+func TestDiskBufferBasic(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	buf := diskBufFromConf(t, fmt.Sprintf(`
+directory: %v
+segment_size: 100
+`, dir))
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, buf.WriteBatch(ctx, service.MessageBatch{
+			service.NewMessage([]byte(fmt.Sprintf("hello%v", i))),
+			service.NewMessage([]byte(fmt.Sprintf("world%v", i))),
+		}, noopAck))
+	}
+	assert.Greater(t, len(segmentFiles(t, dir)), 1)
+
+	for i := 0; i < 10; i++ {
+		strs, aFn := diskBufRead(t, buf)
+		assert.Equal(t, []string{fmt.Sprintf("hello%v", i), fmt.Sprintf("world%v", i)}, strs)
+		require.NoError(t, aFn(ctx, nil))
+	}
+
+	assert.Len(t, segmentFiles(t, dir), 1)
+	assert.Equal(t, int64(0), buf.unackedBytes())
+
+	buf.EndOfInput()
+	_, _, err := buf.ReadBatch(ctx)
+	assert.Equal(t, service.ErrEndOfBuffer, err)
+
+	require.NoError(t, buf.Close(ctx))
+}
+
+func TestDiskBufferReplay(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	conf := fmt.Sprintf(`
+directory: %v
+segment_size: 50
+`, dir)
+
+	buf := diskBufFromConf(t, conf)
+	for i := 0; i < 5; i++ {
+		msg := service.NewMessage([]byte(fmt.Sprintf("msg%v", i)))
+		msg.MetaSetMut("str", fmt.Sprintf("value%v", i))
+		msg.MetaSetMut("num", i)
+		require.NoError(t, buf.WriteBatch(ctx, service.MessageBatch{msg}, noopAck))
+	}
+
+	strs, aFn := diskBufRead(t, buf)
+	assert.Equal(t, []string{"msg0"}, strs)
+	require.NoError(t, aFn(ctx, nil))
+
+	// Read but never acknowledge, this should be replayed.
+	strs, _ = diskBufRead(t, buf)
+	assert.Equal(t, []string{"msg1"}, strs)
+
+	// Acknowledged out of order, this will also be replayed.
+	strs, aFn = diskBufRead(t, buf)
+	assert.Equal(t, []string{"msg2"}, strs)
+	require.NoError(t, aFn(ctx, nil))
+
+	require.NoError(t, buf.Close(ctx))
+
+	buf = diskBufFromConf(t, conf)
+	for i := 1; i < 5; i++ {
+		readCtx, done := context.WithTimeout(ctx, time.Second*5)
+		batch, aFn, err := buf.ReadBatch(readCtx)
+		done()
+		require.NoError(t, err)
+		require.Len(t, batch, 1)
+
+		b, err := batch[0].AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("msg%v", i), string(b))
+
+		v, _ := batch[0].MetaGetMut("str")
+		assert.Equal(t, fmt.Sprintf("value%v", i), v)
+
+		v, _ = batch[0].MetaGetMut("num")
+		assert.Equal(t, float64(i), v)
+
+		require.NoError(t, aFn(ctx, nil))
+	}
+	require.NoError(t, buf.Close(ctx))
+
+	buf = diskBufFromConf(t, conf)
+	assert.Equal(t, int64(0), buf.unackedBytes())
+	require.NoError(t, buf.Close(ctx))
+}
+
+func TestDiskBufferTornWrite(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	conf := fmt.Sprintf(`directory: %v`, dir)
+
+	buf := diskBufFromConf(t, conf)
+	require.NoError(t, buf.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte("foo"))}, noopAck))
+	require.NoError(t, buf.Close(ctx))
+
+	segs := segmentFiles(t, dir)
+	require.Len(t, segs, 1)
+
+	f, err := os.OpenFile(segs[0], os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0, 0, 1, 0, 1, 2, 3, 4, 5, 6})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	buf = diskBufFromConf(t, conf)
+	require.NoError(t, buf.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte("bar"))}, noopAck))
+
+	strs, aFn := diskBufRead(t, buf)
+	assert.Equal(t, []string{"foo"}, strs)
+	require.NoError(t, aFn(ctx, nil))
+
+	strs, aFn = diskBufRead(t, buf)
+	assert.Equal(t, []string{"bar"}, strs)
+	require.NoError(t, aFn(ctx, nil))
+
+	require.NoError(t, buf.Close(ctx))
+}
+
+func TestDiskBufferCorruptChecksum(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	buf := diskBufFromConf(t, fmt.Sprintf(`directory: %v`, dir))
+	t.Cleanup(func() {
+		_ = buf.Close(ctx)
+	})
+
+	for _, v := range []string{"foo", "bar", "baz"} {
+		require.NoError(t, buf.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte(v))}, noopAck))
+	}
+
+	segs := segmentFiles(t, dir)
+	require.Len(t, segs, 1)
+
+	// Flip a byte within the payload of the first record.
+	f, err := os.OpenFile(segs[0], os.O_RDWR, 0o644)
+	require.NoError(t, err)
+	b := make([]byte, 1)
+	_, err = f.ReadAt(b, diskRecordHeaderLen+1)
+	require.NoError(t, err)
+	_, err = f.WriteAt([]byte{^b[0]}, diskRecordHeaderLen+1)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	strs, aFn := diskBufRead(t, buf)
+	assert.Equal(t, []string{"bar"}, strs)
+	require.NoError(t, aFn(ctx, nil))
+
+	strs, aFn = diskBufRead(t, buf)
+	assert.Equal(t, []string{"baz"}, strs)
+	require.NoError(t, aFn(ctx, nil))
+
+	assert.Equal(t, int64(0), buf.unackedBytes())
+}
+
+func TestDiskBufferCorruptPayload(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	conf := fmt.Sprintf(`directory: %v`, dir)
+
+	buf := diskBufFromConf(t, conf)
+	require.NoError(t, buf.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte("foo"))}, noopAck))
+	require.NoError(t, buf.Close(ctx))
+
+	segs := segmentFiles(t, dir)
+	require.Len(t, segs, 1)
+
+	// A record with a valid checksum but a payload that cannot be decoded.
+	payload := []byte("nope")
+	record := make([]byte, diskRecordHeaderLen, diskRecordHeaderLen+len(payload))
+	binary.BigEndian.PutUint32(record[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.Checksum(payload, diskCRCTable))
+	record = append(record, payload...)
+
+	f, err := os.OpenFile(segs[0], os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+	_, err = f.Write(record)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	buf = diskBufFromConf(t, conf)
+	t.Cleanup(func() {
+		_ = buf.Close(ctx)
+	})
+	require.NoError(t, buf.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte("bar"))}, noopAck))
+
+	strs, aFn := diskBufRead(t, buf)
+	assert.Equal(t, []string{"foo"}, strs)
+	require.NoError(t, aFn(ctx, nil))
+
+	strs, aFn = diskBufRead(t, buf)
+	assert.Equal(t, []string{"bar"}, strs)
+	require.NoError(t, aFn(ctx, nil))
+
+	assert.Equal(t, int64(0), buf.unackedBytes())
+}
+
+func TestDiskBufferAckWithoutLock(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	buf := diskBufFromConf(t, fmt.Sprintf(`
+directory: %v
+sync: none
+`, dir))
+	t.Cleanup(func() {
+		_ = buf.Close(ctx)
+	})
+
+	// The upstream acknowledgement must not block readers of the buffer.
+	writeErr := make(chan error, 1)
+	go func() {
+		writeErr <- buf.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte("foo"))}, func(ctx context.Context, err error) error {
+			batch, aFn, err := buf.ReadBatch(ctx)
+			if err != nil {
+				return err
+			}
+			assert.Len(t, batch, 1)
+			return aFn(ctx, nil)
+		})
+	}()
+
+	select {
+	case err := <-writeErr:
+		require.NoError(t, err)
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for write")
+	}
+}
+
+func TestDiskBufferNack(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	buf := diskBufFromConf(t, fmt.Sprintf(`directory: %v`, dir))
+	t.Cleanup(func() {
+		_ = buf.Close(ctx)
+	})
+
+	require.NoError(t, buf.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte("foo"))}, noopAck))
+	require.NoError(t, buf.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte("bar"))}, noopAck))
+
+	strs, aFn := diskBufRead(t, buf)
+	assert.Equal(t, []string{"foo"}, strs)
+	require.NoError(t, aFn(ctx, errors.New("nope")))
+
+	strs, aFn = diskBufRead(t, buf)
+	assert.Equal(t, []string{"foo"}, strs)
+	require.NoError(t, aFn(ctx, nil))
+
+	strs, aFn = diskBufRead(t, buf)
+	assert.Equal(t, []string{"bar"}, strs)
+	require.NoError(t, aFn(ctx, nil))
+}
+
+func TestDiskBufferMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	buf := diskBufFromConf(t, fmt.Sprintf(`
+directory: %v
+max_age: 50ms
+`, dir))
+	t.Cleanup(func() {
+		_ = buf.Close(ctx)
+	})
+
+	require.NoError(t, buf.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte("old"))}, noopAck))
+	<-time.After(time.Millisecond * 100)
+	require.NoError(t, buf.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte("new"))}, noopAck))
+
+	strs, aFn := diskBufRead(t, buf)
+	assert.Equal(t, []string{"new"}, strs)
+	require.NoError(t, aFn(ctx, nil))
+	assert.Equal(t, int64(0), buf.unackedBytes())
+}
+
+func TestDiskBufferBackpressure(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	buf := diskBufFromConf(t, fmt.Sprintf(`
+directory: %v
+max_bytes: 60
+`, dir))
+	t.Cleanup(func() {
+		_ = buf.Close(ctx)
+	})
+
+	require.NoError(t, buf.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte("first message"))}, noopAck))
+
+	writeErr := make(chan error)
+	go func() {
+		writeErr <- buf.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte("second message"))}, noopAck)
+	}()
+
+	select {
+	case <-writeErr:
+		t.Fatal("write should have blocked")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	strs, aFn := diskBufRead(t, buf)
+	assert.Equal(t, []string{"first message"}, strs)
+	require.NoError(t, aFn(ctx, nil))
+
+	select {
+	case err := <-writeErr:
+		require.NoError(t, err)
+	case <-time.After(time.Second * 5):
+		t.Fatal("write should have unblocked")
+	}
+
+	err := buf.WriteBatch(ctx, service.MessageBatch{service.NewMessage(make([]byte, 100))}, noopAck)
+	assert.Error(t, err)
+}
+
+func TestDiskBufferSyncInterval(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	buf := diskBufFromConf(t, fmt.Sprintf(`
+directory: %v
+sync: interval
+sync_interval: 50ms
+`, dir))
+
+	acked := make(chan struct{})
+	require.NoError(t, buf.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte("foo"))}, func(ctx context.Context, err error) error {
+		close(acked)
+		return err
+	}))
+
+	select {
+	case <-acked:
+	case <-time.After(time.Second * 5):
+		t.Fatal("input was not acknowledged")
+	}
+
+	strs, aFn := diskBufRead(t, buf)
+	assert.Equal(t, []string{"foo"}, strs)
+	require.NoError(t, aFn(ctx, nil))
+
+	require.NoError(t, buf.Close(ctx))
+
+	_, err := os.Stat(filepath.Join(dir, diskCheckpointFile))
+	require.NoError(t, err)
+
+	buf = diskBufFromConf(t, fmt.Sprintf(`directory: %v`, dir))
+	assert.Equal(t, int64(0), buf.unackedBytes())
+	require.NoError(t, buf.Close(ctx))
+}
@@ -0,0 +1,378 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package io
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/Jeffail/shutdown"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	bbcFieldPath                = "path"
+	bbcFieldBucket              = "bucket"
+	bbcFieldDefaultTTL          = "default_ttl"
+	bbcFieldExpirySweepInterval = "expiry_sweep_interval"
+	bbcFieldOpenTimeout         = "open_timeout"
+	bbcFieldNoSync              = "no_sync"
+)
+
+func bboltCacheConfig() *service.ConfigSpec {
+	spec := service.NewConfigSpec().
+		Beta().
+		Summary(`Stores key/value pairs in a single database file on disk using an embedded https://github.com/etcd-io/bbolt[bbolt^] B+tree store, and therefore items persist across restarts.`).
+		Description(`
+Each write is committed within a transaction before it is acknowledged, and `+"`add`"+` operations check for an existing key and write the new item within the same transaction, which makes them atomic.
+
+The database file is locked while in use, and therefore it cannot be shared between multiple caches or processes. If the file is already locked then the cache waits for the `+"`open_timeout`"+` before failing.
+
+== Expiry
+
+Items can be written with a TTL, either provided with the write or the `+"`default_ttl`"+` of the cache. Expired items are never returned, and are removed from the database periodically according to the `+"`expiry_sweep_interval`"+`. The space occupied by removed items is reused for future writes, although the database file itself is not compacted and therefore does not shrink.`).
+		Fields(
+			service.NewStringField(bbcFieldPath).
+				Description("The path of the database file, which is created if it does not already exist.").
+				Example("./cache.db"),
+			service.NewStringField(bbcFieldBucket).
+				Description("The name of the bucket within the database to store items within.").
+				Default("benthos").
+				Advanced(),
+			service.NewDurationField(bbcFieldDefaultTTL).
+				Description("An optional default TTL to set for items, calculated from the moment the item is written. Items written without a TTL do not expire when this field is omitted.").
+				Example("60s").
+				Optional(),
+			service.NewDurationField(bbcFieldExpirySweepInterval).
+				Description("The period of time to wait between each removal of expired items from the database. This field can be set to an empty string in order to disable periodic removals, in which case expired items are only removed when they are next accessed.").
+				Default("60s").
+				Advanced(),
+			service.NewDurationField(bbcFieldOpenTimeout).
+				Description("The maximum period of time to wait for a lock on the database file when opening it.").
+				Default("5s").
+				Advanced(),
+			service.NewBoolField(bbcFieldNoSync).
+				Description("Whether to skip syncing the database file to disk after each write. This significantly increases write throughput at the cost of losing recent writes in the event of a crash or power loss.").
+				Default(false).
+				Advanced(),
+		)
+
+	return spec
+}
+
+func init() {
+	err := service.RegisterCache(
+		"bbolt", bboltCacheConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Cache, error) {
+			c, err := newBboltCacheFromConfig(conf, mgr)
+			if err != nil {
+				return nil, err
+			}
+			return c, nil
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type bboltCacheOptions struct {
+	path          string
+	bucket        string
+	defaultTTL    time.Duration
+	sweepInterval time.Duration
+	openTimeout   time.Duration
+	noSync        bool
+}
+
+func newBboltCacheFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*bboltCache, error) {
+	var opts bboltCacheOptions
+	var err error
+	if opts.path, err = conf.FieldString(bbcFieldPath); err != nil {
+		return nil, err
+	}
+	if opts.bucket, err = conf.FieldString(bbcFieldBucket); err != nil {
+		return nil, err
+	}
+	if opts.bucket == "" {
+		return nil, errors.New("bucket must not be empty")
+	}
+	if conf.Contains(bbcFieldDefaultTTL) {
+		if opts.defaultTTL, err = conf.FieldDuration(bbcFieldDefaultTTL); err != nil {
+			return nil, err
+		}
+	}
+	if test, _ := conf.FieldString(bbcFieldExpirySweepInterval); test != "" {
+		if opts.sweepInterval, err = conf.FieldDuration(bbcFieldExpirySweepInterval); err != nil {
+			return nil, err
+		}
+	}
+	if opts.openTimeout, err = conf.FieldDuration(bbcFieldOpenTimeout); err != nil {
+		return nil, err
+	}
+	if opts.noSync, err = conf.FieldBool(bbcFieldNoSync); err != nil {
+		return nil, err
+	}
+	return newBboltCache(opts, mgr)
+}
+
+//------------------------------------------------------------------------------
+
+// Items are stored within the bucket prefixed with an 8 byte expiry timestamp
+// in unix nanoseconds, where zero means the item never expires. A second
+// bucket indexes items that expire by their expiry timestamp followed by their
+// key, allowing sweeps to visit expired items in order without scanning
+// the whole database.
+const (
+	bboltExpiryLen          = 8
+	bboltExpiryBucketSuffix = "_expiry"
+)
+
+type bboltCache struct {
+	log           *service.Logger
+	db            *bolt.DB
+	bucket        []byte
+	expiryBucket  []byte
+	defaultTTL    time.Duration
+	sweepInterval time.Duration
+
+	mExpired *service.MetricCounter
+
+	shutSig *shutdown.Signaller
+}
+
+func newBboltCache(opts bboltCacheOptions, mgr *service.Resources) (*bboltCache, error) {
+	if dir := filepath.Dir(opts.path); dir != "" {
+		if err := mgr.FS().MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
+		}
+	}
+
+	// The database is memory mapped by bbolt and therefore must be opened
+	// directly from the local filesystem.
+	db, err := bolt.Open(opts.path, 0o644, &bolt.Options{
+		Timeout: opts.openTimeout,
+		NoSync:  opts.noSync,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	c := &bboltCache{
+		log:           mgr.Logger(),
+		db:            db,
+		bucket:        []byte(opts.bucket),
+		expiryBucket:  []byte(opts.bucket + bboltExpiryBucketSuffix),
+		defaultTTL:    opts.defaultTTL,
+		sweepInterval: opts.sweepInterval,
+		mExpired:      mgr.Metrics().NewCounter("cache_bbolt_expired"),
+		shutSig:       shutdown.NewSignaller(),
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(c.bucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(c.expiryBucket)
+		return err
+	}); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create buckets: %w", err)
+	}
+
+	if c.sweepInterval > 0 {
+		go c.expirySweepLoop()
+	} else {
+		c.shutSig.TriggerHasStopped()
+	}
+	return c, nil
+}
+
+func (c *bboltCache) expiresFrom(ttl *time.Duration) time.Time {
+	if ttl != nil {
+		return time.Now().Add(*ttl)
+	}
+	if c.defaultTTL > 0 {
+		return time.Now().Add(c.defaultTTL)
+	}
+	return time.Time{}
+}
+
+func decodeBboltExpiry(b []byte) time.Time {
+	if n := int64(binary.BigEndian.Uint64(b)); n != 0 {
+		return time.Unix(0, n)
+	}
+	return time.Time{}
+}
+
+func bboltExpiryKey(expires time.Time, key []byte) []byte {
+	b := make([]byte, bboltExpiryLen, bboltExpiryLen+len(key))
+	binary.BigEndian.PutUint64(b, uint64(expires.UnixNano()))
+	return append(b, key...)
+}
+
+func bboltIsExpired(v []byte, now time.Time) bool {
+	expires := decodeBboltExpiry(v)
+	return !expires.IsZero() && !expires.After(now)
+}
+
+// deleteTx removes an item along with its entry in the expiry index.
+func (c *bboltCache) deleteTx(tx *bolt.Tx, key []byte) error {
+	b := tx.Bucket(c.bucket)
+	v := b.Get(key)
+	if v == nil {
+		return nil
+	}
+	if expires := decodeBboltExpiry(v); !expires.IsZero() {
+		if err := tx.Bucket(c.expiryBucket).Delete(bboltExpiryKey(expires, key)); err != nil {
+			return err
+		}
+	}
+	return b.Delete(key)
+}
+
+func (c *bboltCache) setTx(tx *bolt.Tx, key, value []byte, expires time.Time) error {
+	if err := c.deleteTx(tx, key); err != nil {
+		return err
+	}
+
+	v := make([]byte, bboltExpiryLen, bboltExpiryLen+len(value))
+	if !expires.IsZero() {
+		binary.BigEndian.PutUint64(v, uint64(expires.UnixNano()))
+		if err := tx.Bucket(c.expiryBucket).Put(bboltExpiryKey(expires, key), nil); err != nil {
+			return err
+		}
+	}
+	v = append(v, value...)
+	return tx.Bucket(c.bucket).Put(key, v)
+}
+
+// sweepExpired removes all expired items from the database.
+func (c *bboltCache) sweepExpired() (removed int, err error) {
+	now := time.Now()
+	err = c.db.Update(func(tx *bolt.Tx) error {
+		b, eb := tx.Bucket(c.bucket), tx.Bucket(c.expiryBucket)
+
+		var nowBytes [bboltExpiryLen]byte
+		binary.BigEndian.PutUint64(nowBytes[:], uint64(now.UnixNano()))
+
+		var expired [][]byte
+		cur := eb.Cursor()
+		for k, _ := cur.First(); k != nil && bytes.Compare(k[:bboltExpiryLen], nowBytes[:]) <= 0; k, _ = cur.Next() {
+			expired = append(expired, bytes.Clone(k))
+		}
+
+		for _, k := range expired {
+			if err := eb.Delete(k); err != nil {
+				return err
+			}
+			if err := b.Delete(k[bboltExpiryLen:]); err != nil {
+				return err
+			}
+		}
+		removed = len(expired)
+		return nil
+	})
+	return
+}
+
+func (c *bboltCache) expirySweepLoop() {
+	defer c.shutSig.TriggerHasStopped()
+
+	t := time.NewTicker(c.sweepInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+		case <-c.shutSig.SoftStopChan():
+			return
+		}
+
+		removed, err := c.sweepExpired()
+		if err != nil {
+			c.log.Errorf("Failed to remove expired cache items: %v", err)
+			continue
+		}
+		c.mExpired.Incr(int64(removed))
+	}
+}
+
+func (c *bboltCache) Get(_ context.Context, key string) (value []byte, err error) {
+	var expired bool
+	if err = c.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(c.bucket).Get([]byte(key))
+		if v == nil {
+			return service.ErrKeyNotFound
+		}
+		if bboltIsExpired(v, time.Now()) {
+			expired = true
+			return service.ErrKeyNotFound
+		}
+		value = bytes.Clone(v[bboltExpiryLen:])
+		return nil
+	}); err != nil {
+		if expired {
+			// Remove the expired item eagerly, the item may have been replaced
+			// since and therefore the expiry is checked again.
+			_ = c.db.Update(func(tx *bolt.Tx) error {
+				if v := tx.Bucket(c.bucket).Get([]byte(key)); v != nil && bboltIsExpired(v, time.Now()) {
+					return c.deleteTx(tx, []byte(key))
+				}
+				return nil
+			})
+		}
+		return nil, err
+	}
+	return value, nil
+}
+
+func (c *bboltCache) Set(_ context.Context, key string, value []byte, ttl *time.Duration) error {
+	expires := c.expiresFrom(ttl)
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return c.setTx(tx, []byte(key), value, expires)
+	})
+}
+
+func (c *bboltCache) SetMulti(_ context.Context, keyValues ...service.CacheItem) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		for _, kv := range keyValues {
+			if err := c.setTx(tx, []byte(kv.Key), kv.Value, c.expiresFrom(kv.TTL)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (c *bboltCache) Add(_ context.Context, key string, value []byte, ttl *time.Duration) error {
+	expires := c.expiresFrom(ttl)
+	return c.db.Update(func(tx *bolt.Tx) error {
+		if v := tx.Bucket(c.bucket).Get([]byte(key)); v != nil && !bboltIsExpired(v, time.Now()) {
+			return service.ErrKeyAlreadyExists
+		}
+		return c.setTx(tx, []byte(key), value, expires)
+	})
+}
+
+func (c *bboltCache) Delete(_ context.Context, key string) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return c.deleteTx(tx, []byte(key))
+	})
+}
+
+func (c *bboltCache) Close(ctx context.Context) error {
+	c.shutSig.TriggerSoftStop()
+	select {
+	case <-c.shutSig.HasStoppedChan():
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return c.db.Close()
+}
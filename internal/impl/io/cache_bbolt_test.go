@@ -0,0 +1,181 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package io
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func bboltCacheFromConf(t *testing.T, conf string) *bboltCache {
+	t.Helper()
+
+	parsedConf, err := bboltCacheConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	c, err := newBboltCacheFromConfig(parsedConf, service.MockResources())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = c.Close(context.Background())
+	})
+	return c
+}
+
+func TestBboltCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foo", "cache.db")
+
+	tCtx := context.Background()
+	c := bboltCacheFromConf(t, fmt.Sprintf(`path: %v`, path))
+
+	_, err := c.Get(tCtx, "foo")
+	assert.Equal(t, service.ErrKeyNotFound, err)
+
+	require.NoError(t, c.Set(tCtx, "foo", []byte("1"), nil))
+
+	act, err := c.Get(tCtx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(act))
+
+	require.NoError(t, c.Add(tCtx, "bar", []byte("2"), nil))
+
+	act, err = c.Get(tCtx, "bar")
+	require.NoError(t, err)
+	assert.Equal(t, "2", string(act))
+
+	assert.Equal(t, service.ErrKeyAlreadyExists, c.Add(tCtx, "foo", []byte("2"), nil))
+
+	require.NoError(t, c.Set(tCtx, "foo", []byte("3"), nil))
+
+	act, err = c.Get(tCtx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "3", string(act))
+
+	require.NoError(t, c.Delete(tCtx, "foo"))
+
+	_, err = c.Get(tCtx, "foo")
+	assert.Equal(t, service.ErrKeyNotFound, err)
+
+	require.NoError(t, c.SetMulti(tCtx,
+		service.CacheItem{Key: "baz", Value: []byte("4")},
+		service.CacheItem{Key: "buz", Value: []byte("5")},
+	))
+	require.NoError(t, c.Close(tCtx))
+
+	// Items persist after a restart.
+	c = bboltCacheFromConf(t, fmt.Sprintf(`path: %v`, path))
+	for k, v := range map[string]string{
+		"bar": "2",
+		"baz": "4",
+		"buz": "5",
+	} {
+		act, err = c.Get(tCtx, k)
+		require.NoError(t, err, k)
+		assert.Equal(t, v, string(act), k)
+	}
+
+	_, err = c.Get(tCtx, "foo")
+	assert.Equal(t, service.ErrKeyNotFound, err)
+}
+
+func TestBboltCacheTTL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	tCtx := context.Background()
+	c := bboltCacheFromConf(t, fmt.Sprintf(`
+path: %v
+default_ttl: 50ms
+expiry_sweep_interval: ""
+`, path))
+
+	hour := time.Hour
+	require.NoError(t, c.Set(tCtx, "foo", []byte("1"), nil))
+	require.NoError(t, c.Set(tCtx, "bar", []byte("2"), &hour))
+	require.NoError(t, c.SetMulti(tCtx, service.CacheItem{Key: "baz", Value: []byte("3")}))
+
+	<-time.After(time.Millisecond * 100)
+
+	for _, k := range []string{"foo", "baz"} {
+		_, err := c.Get(tCtx, k)
+		assert.Equal(t, service.ErrKeyNotFound, err, k)
+	}
+
+	act, err := c.Get(tCtx, "bar")
+	require.NoError(t, err)
+	assert.Equal(t, "2", string(act))
+
+	assert.Equal(t, service.ErrKeyAlreadyExists, c.Add(tCtx, "bar", []byte("4"), nil))
+
+	// Expired items can be added again.
+	require.NoError(t, c.Set(tCtx, "buz", []byte("5"), nil))
+	<-time.After(time.Millisecond * 100)
+	require.NoError(t, c.Add(tCtx, "buz", []byte("6"), &hour))
+
+	act, err = c.Get(tCtx, "buz")
+	require.NoError(t, err)
+	assert.Equal(t, "6", string(act))
+}
+
+func TestBboltCacheExpirySweep(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	tCtx := context.Background()
+	c := bboltCacheFromConf(t, fmt.Sprintf(`
+path: %v
+expiry_sweep_interval: ""
+`, path))
+
+	short, long := time.Millisecond*10, time.Hour
+	require.NoError(t, c.Set(tCtx, "foo", []byte("1"), &short))
+	require.NoError(t, c.Set(tCtx, "bar", []byte("2"), &long))
+	require.NoError(t, c.Set(tCtx, "baz", []byte("3"), nil))
+
+	// Replacing an item also replaces its expiry.
+	require.NoError(t, c.Set(tCtx, "buz", []byte("4"), &short))
+	require.NoError(t, c.Set(tCtx, "buz", []byte("5"), &long))
+
+	<-time.After(time.Millisecond * 50)
+
+	removed, err := c.sweepExpired()
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	keys := map[string]int{}
+	require.NoError(t, c.db.View(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{c.bucket, c.expiryBucket} {
+			keys[string(b)] = tx.Bucket(b).Stats().KeyN
+		}
+		return nil
+	}))
+	assert.Equal(t, map[string]int{
+		"benthos":        3,
+		"benthos_expiry": 2,
+	}, keys)
+
+	act, err := c.Get(tCtx, "buz")
+	require.NoError(t, err)
+	assert.Equal(t, "5", string(act))
+}
+
+func TestBboltCacheLocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	_ = bboltCacheFromConf(t, fmt.Sprintf(`path: %v`, path))
+
+	parsedConf, err := bboltCacheConfig().ParseYAML(fmt.Sprintf(`
+path: %v
+open_timeout: 10ms
+`, path), nil)
+	require.NoError(t, err)
+
+	_, err = newBboltCacheFromConfig(parsedConf, service.MockResources())
+	require.Error(t, err)
+}
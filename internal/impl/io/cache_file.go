@@ -4,23 +4,73 @@ package io
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/Jeffail/shutdown"
+
 	"github.com/redpanda-data/benthos/v4/internal/filepath/ifs"
 	"github.com/redpanda-data/benthos/v4/public/service"
 )
 
+const (
+	fcFieldDirectory          = "directory"
+	fcFieldDefaultTTL         = "default_ttl"
+	fcFieldCompactionInterval = "compaction_interval"
+	fcFieldMaxBytes           = "max_bytes"
+	fcFieldMaxItems           = "max_items"
+)
+
 func fileCacheConfig() *service.ConfigSpec {
 	spec := service.NewConfigSpec().
 		Stable().
-		Summary(`Stores each item in a directory as a file, where an item ID is the path relative to the configured directory.`).
-		Description(`This type currently offers no form of item expiry or garbage collection, and is intended to be used for development and debugging purposes only.`).
-		Field(service.NewStringField("directory").
-			Description("The directory within which to store items."))
+		Summary(`Stores each item in a directory as a file, where an item ID is the name of the file relative to the configured directory.`).
+		Description(`
+Keys consisting only of alphanumeric characters, underscores, hyphens and dots (and not beginning with a dot) are used as file names directly. All other keys, including those containing path separators or relative path segments such as `+"`..`"+`, are encoded into a file name prefixed with `+"`~`"+`, and therefore items can never be written outside of the configured directory.
+
+Items are written to a temporary file which is then renamed, and therefore readers never observe a partially written item.
+
+== Expiry
+
+Each item can be written with an expiry, which is either the TTL provided with the write or the `+"`default_ttl`"+` of the cache. The expiry of an item is stored alongside it in a hidden file, expired items are never returned and are removed from the directory periodically according to the `+"`compaction_interval`"+`.
+
+== Size Limits
+
+When either `+"`max_bytes`"+` or `+"`max_items`"+` are set the cache evicts the least recently used items whenever a write would exceed the limits.
+
+Existing items are loaded from the directory when the cache is created, after which only writes made by the cache itself are tracked. Multiple caches, or multiple processes, should therefore not share a directory.`).
+		Fields(
+			service.NewStringField(fcFieldDirectory).
+				Description("The directory within which to store items."),
+			service.NewDurationField(fcFieldDefaultTTL).
+				Description("An optional default TTL to set for items, calculated from the moment the item is written. Items written without a TTL do not expire when this field is omitted.").
+				Example("60s").
+				Optional(),
+			service.NewDurationField(fcFieldCompactionInterval).
+				Description("The period of time to wait between each removal of expired items from the directory. This field can be set to an empty string in order to disable periodic removals, in which case expired items are only removed when they are next accessed.").
+				Default("60s").
+				Advanced(),
+			service.NewIntField(fcFieldMaxBytes).
+				Description("The maximum total size in bytes of all items within the cache, once exceeded the least recently used items are evicted. Set to zero in order to disable this limit.").
+				Default(0).
+				Advanced(),
+			service.NewIntField(fcFieldMaxItems).
+				Description("The maximum number of items within the cache, once exceeded the least recently used items are evicted. Set to zero in order to disable this limit.").
+				Default(0).
+				Advanced(),
+		)
 
 	return spec
 }
@@ -40,56 +90,425 @@ func init() {
 	}
 }
 
+type fileCacheOptions struct {
+	dir          string
+	defaultTTL   time.Duration
+	compInterval time.Duration
+	maxBytes     int64
+	maxItems     int
+}
+
 func newFileCacheFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*fileCache, error) {
-	directory, err := conf.FieldString("directory")
+	var opts fileCacheOptions
+	var err error
+	if opts.dir, err = conf.FieldString(fcFieldDirectory); err != nil {
+		return nil, err
+	}
+	if conf.Contains(fcFieldDefaultTTL) {
+		if opts.defaultTTL, err = conf.FieldDuration(fcFieldDefaultTTL); err != nil {
+			return nil, err
+		}
+	}
+	if test, _ := conf.FieldString(fcFieldCompactionInterval); test != "" {
+		if opts.compInterval, err = conf.FieldDuration(fcFieldCompactionInterval); err != nil {
+			return nil, err
+		}
+	}
+	maxBytes, err := conf.FieldInt(fcFieldMaxBytes)
 	if err != nil {
 		return nil, err
 	}
-	return newFileCache(directory, mgr), nil
+	opts.maxBytes = int64(maxBytes)
+	if opts.maxItems, err = conf.FieldInt(fcFieldMaxItems); err != nil {
+		return nil, err
+	}
+	if opts.maxBytes < 0 || opts.maxItems < 0 {
+		return nil, errors.New("max_bytes and max_items must not be negative")
+	}
+	return newFileCache(opts, mgr)
 }
 
 //------------------------------------------------------------------------------
 
-func newFileCache(dir string, mgr *service.Resources) *fileCache {
-	return &fileCache{mgr: mgr, dir: dir}
+const (
+	// Names that would exceed this length are replaced with a hash of the key.
+	fileCacheMaxNameLen = 200
+
+	fileCacheEncodedPrefix = "~"
+	fileCacheHashedPrefix  = "~~"
+
+	// Hidden files are never used for items.
+	fileCacheExpiryPrefix = ".ttl-"
+	fileCacheTmpPrefix    = ".tmp-"
+
+	fileCacheExpiryLen = 8
+)
+
+func isSafeFileCacheKey(key string) bool {
+	if key == "" || len(key) > fileCacheMaxNameLen || key[0] == '.' {
+		return false
+	}
+	for _, c := range key {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '_', c == '-', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// fileCacheName returns the name of the file within the cache directory that
+// an item of a given key is stored as. Keys that are not safe to use directly
+// are encoded, where the encoding alphabet never includes path separators or
+// the encoded prefix, and therefore encoded names never collide with each
+// other or with the names of safe keys.
+func fileCacheName(key string) string {
+	if isSafeFileCacheKey(key) {
+		return key
+	}
+	name := fileCacheEncodedPrefix + base64.RawURLEncoding.EncodeToString([]byte(key))
+	if len(name) > fileCacheMaxNameLen {
+		sum := sha256.Sum256([]byte(key))
+		name = fileCacheHashedPrefix + hex.EncodeToString(sum[:])
+	}
+	return name
+}
+
+type fileCacheItem struct {
+	size     int64
+	expires  time.Time
+	lastUsed time.Time
+}
+
+func (i fileCacheItem) expired(now time.Time) bool {
+	return !i.expires.IsZero() && !i.expires.After(now)
 }
 
 type fileCache struct {
-	mgr *service.Resources
-	dir string
+	mgr  *service.Resources
+	fs   *service.FS
+	opts fileCacheOptions
+
+	mut        sync.Mutex
+	items      map[string]fileCacheItem
+	totalBytes int64
+
+	shutSig *shutdown.Signaller
 }
 
-func (f *fileCache) Get(_ context.Context, key string) ([]byte, error) {
-	b, err := ifs.ReadFile(f.mgr.FS(), filepath.Join(f.dir, key))
-	if errors.Is(err, fs.ErrNotExist) {
-		return nil, service.ErrKeyNotFound
+func newFileCache(opts fileCacheOptions, mgr *service.Resources) (*fileCache, error) {
+	f := &fileCache{
+		mgr:     mgr,
+		fs:      mgr.FS(),
+		opts:    opts,
+		items:   map[string]fileCacheItem{},
+		shutSig: shutdown.NewSignaller(),
+	}
+	if err := f.loadItems(); err != nil {
+		return nil, err
 	}
-	return b, err
+
+	if opts.compInterval > 0 {
+		go f.compactionLoop()
+	} else {
+		f.shutSig.TriggerHasStopped()
+	}
+	return f, nil
 }
 
-func (f *fileCache) Set(_ context.Context, key string, value []byte, _ *time.Duration) error {
-	return ifs.WriteFile(f.mgr.FS(), filepath.Join(f.dir, key), value, 0o644)
+func (f *fileCache) path(name string) string {
+	return filepath.Join(f.opts.dir, name)
 }
 
-func (f *fileCache) Add(_ context.Context, key string, value []byte, _ *time.Duration) error {
-	file, err := f.mgr.FS().OpenFile(filepath.Join(f.dir, key), os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+func (f *fileCache) removeIfExists(name string) error {
+	if err := f.fs.Remove(f.path(name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// loadItems scans the cache directory for existing items and their expiry
+// files, removing any temporary files left behind by interrupted writes and
+// any expiry files without a corresponding item.
+func (f *fileCache) loadItems() error {
+	if err := f.fs.MkdirAll(f.opts.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	entries, err := fs.ReadDir(f.fs, f.opts.dir)
 	if err != nil {
-		if errors.Is(err, fs.ErrExist) {
-			return service.ErrKeyAlreadyExists
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var expiryFiles []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() {
+			continue
+		}
+		if strings.HasPrefix(name, fileCacheTmpPrefix) {
+			if err := f.removeIfExists(name); err != nil {
+				f.mgr.Logger().Warnf("Failed to remove temporary cache file '%v': %v", name, err)
+			}
+			continue
 		}
+		if strings.HasPrefix(name, fileCacheExpiryPrefix) {
+			expiryFiles = append(expiryFiles, name)
+			continue
+		}
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		f.items[name] = fileCacheItem{
+			size:     info.Size(),
+			lastUsed: info.ModTime(),
+		}
+		f.totalBytes += info.Size()
+	}
+
+	for _, expName := range expiryFiles {
+		name := strings.TrimPrefix(expName, fileCacheExpiryPrefix)
+		item, exists := f.items[name]
+		if !exists {
+			if err := f.removeIfExists(expName); err != nil {
+				f.mgr.Logger().Warnf("Failed to remove orphaned cache expiry file '%v': %v", expName, err)
+			}
+			continue
+		}
+
+		b, err := ifs.ReadFile(f.fs, f.path(expName))
+		if err != nil {
+			return fmt.Errorf("failed to read cache expiry file '%v': %w", expName, err)
+		}
+		if len(b) != fileCacheExpiryLen {
+			f.mgr.Logger().Warnf("Ignoring corrupted cache expiry file '%v'", expName)
+			continue
+		}
+		item.expires = time.Unix(0, int64(binary.BigEndian.Uint64(b)))
+		item.size += fileCacheExpiryLen
+		f.items[name] = item
+		f.totalBytes += fileCacheExpiryLen
+	}
+
+	f.enforceLimitsLocked()
+	return nil
+}
+
+// writeAtomic writes data to a temporary file and then renames it into place.
+func (f *fileCache) writeAtomic(name string, data []byte) error {
+	var suffix [8]byte
+	if _, err := rand.Read(suffix[:]); err != nil {
 		return err
 	}
-	if _, err = ifs.FileWrite(file, value); err != nil {
-		file.Close()
+	tmpPath := f.path(fileCacheTmpPrefix + hex.EncodeToString(suffix[:]))
+
+	file, err := f.fs.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
 		return err
 	}
-	return file.Close()
+	if _, err = ifs.FileWrite(file, data); err == nil {
+		if syncer, ok := file.(interface{ Sync() error }); ok {
+			err = syncer.Sync()
+		}
+	}
+	if cErr := file.Close(); cErr != nil && err == nil {
+		err = cErr
+	}
+	if err == nil {
+		err = f.fs.Rename(tmpPath, f.path(name))
+	}
+	if err != nil {
+		_ = f.fs.Remove(tmpPath)
+	}
+	return err
+}
+
+func (f *fileCache) writeItemLocked(key string, value []byte, ttl *time.Duration) error {
+	var expires time.Time
+	if ttl != nil {
+		expires = time.Now().Add(*ttl)
+	} else if f.opts.defaultTTL > 0 {
+		expires = time.Now().Add(f.opts.defaultTTL)
+	}
+
+	name := fileCacheName(key)
+	size := int64(len(value))
+
+	// The expiry is written before the item so that an interrupted write
+	// never leaves a new item without its expiry.
+	if !expires.IsZero() {
+		var expBytes [fileCacheExpiryLen]byte
+		binary.BigEndian.PutUint64(expBytes[:], uint64(expires.UnixNano()))
+		if err := f.writeAtomic(fileCacheExpiryPrefix+name, expBytes[:]); err != nil {
+			return err
+		}
+		size += fileCacheExpiryLen
+	}
+	if err := f.writeAtomic(name, value); err != nil {
+		return err
+	}
+	if expires.IsZero() {
+		if err := f.removeIfExists(fileCacheExpiryPrefix + name); err != nil {
+			return err
+		}
+	}
+
+	if existing, exists := f.items[name]; exists {
+		f.totalBytes -= existing.size
+	}
+	f.items[name] = fileCacheItem{
+		size:     size,
+		expires:  expires,
+		lastUsed: time.Now(),
+	}
+	f.totalBytes += size
+
+	f.enforceLimitsLocked()
+	return nil
+}
+
+func (f *fileCache) removeLocked(name string) error {
+	if item, exists := f.items[name]; exists {
+		delete(f.items, name)
+		f.totalBytes -= item.size
+	}
+	err := f.fs.Remove(f.path(name))
+	if eErr := f.removeIfExists(fileCacheExpiryPrefix + name); eErr != nil && err == nil {
+		err = eErr
+	}
+	return err
+}
+
+// enforceLimitsLocked evicts the least recently used items until the cache is
+// within its configured size limits.
+func (f *fileCache) enforceLimitsLocked() {
+	overLimits := func() bool {
+		return (f.opts.maxItems > 0 && len(f.items) > f.opts.maxItems) ||
+			(f.opts.maxBytes > 0 && f.totalBytes > f.opts.maxBytes)
+	}
+	if !overLimits() {
+		return
+	}
+
+	names := make([]string, 0, len(f.items))
+	for k := range f.items {
+		names = append(names, k)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return f.items[names[i]].lastUsed.Before(f.items[names[j]].lastUsed)
+	})
+
+	for _, name := range names {
+		if !overLimits() {
+			return
+		}
+		if err := f.removeLocked(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			f.mgr.Logger().Errorf("Failed to evict cache item: %v", err)
+		}
+	}
+}
+
+func (f *fileCache) compact() {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	now := time.Now()
+	for name, item := range f.items {
+		if !item.expired(now) {
+			continue
+		}
+		if err := f.removeLocked(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			f.mgr.Logger().Errorf("Failed to remove expired cache item: %v", err)
+		}
+	}
+}
+
+func (f *fileCache) compactionLoop() {
+	defer f.shutSig.TriggerHasStopped()
+
+	t := time.NewTicker(f.opts.compInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+		case <-f.shutSig.SoftStopChan():
+			return
+		}
+		f.compact()
+	}
+}
+
+func (f *fileCache) Get(_ context.Context, key string) ([]byte, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	name := fileCacheName(key)
+	item, tracked := f.items[name]
+	if tracked && item.expired(time.Now()) {
+		if err := f.removeLocked(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		return nil, service.ErrKeyNotFound
+	}
+
+	b, err := ifs.ReadFile(f.fs, f.path(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, service.ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if tracked {
+		item.lastUsed = time.Now()
+		f.items[name] = item
+	}
+	return b, nil
+}
+
+func (f *fileCache) Set(_ context.Context, key string, value []byte, ttl *time.Duration) error {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	return f.writeItemLocked(key, value, ttl)
+}
+
+func (f *fileCache) Add(_ context.Context, key string, value []byte, ttl *time.Duration) error {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	name := fileCacheName(key)
+	if item, tracked := f.items[name]; tracked {
+		if !item.expired(time.Now()) {
+			return service.ErrKeyAlreadyExists
+		}
+	} else if _, err := f.fs.Stat(f.path(name)); err == nil {
+		return service.ErrKeyAlreadyExists
+	}
+	return f.writeItemLocked(key, value, ttl)
 }
 
 func (f *fileCache) Delete(_ context.Context, key string) error {
-	return f.mgr.FS().Remove(filepath.Join(f.dir, key))
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	return f.removeLocked(fileCacheName(key))
 }
 
-func (f *fileCache) Close(context.Context) error {
+func (f *fileCache) Close(ctx context.Context) error {
+	f.shutSig.TriggerSoftStop()
+	select {
+	case <-f.shutSig.HasStoppedChan():
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 	return nil
 }
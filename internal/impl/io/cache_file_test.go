@@ -4,7 +4,12 @@ package io
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -12,11 +17,25 @@ import (
 	"github.com/redpanda-data/benthos/v4/public/service"
 )
 
+func fileCacheFromConf(t *testing.T, conf string) *fileCache {
+	t.Helper()
+
+	parsedConf, err := fileCacheConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	c, err := newFileCacheFromConfig(parsedConf, service.MockResources())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = c.Close(context.Background())
+	})
+	return c
+}
+
 func TestFileCache(t *testing.T) {
 	dir := t.TempDir()
 
 	tCtx := context.Background()
-	c := newFileCache(dir, service.MockResources())
+	c := fileCacheFromConf(t, fmt.Sprintf(`directory: %v`, dir))
 
 	_, err := c.Get(tCtx, "foo")
 	assert.Equal(t, service.ErrKeyNotFound, err)
@@ -46,3 +65,220 @@ func TestFileCache(t *testing.T) {
 	_, err = c.Get(tCtx, "foo")
 	assert.Equal(t, service.ErrKeyNotFound, err)
 }
+
+func TestFileCacheKeyEncoding(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "cache")
+
+	tCtx := context.Background()
+	c := fileCacheFromConf(t, fmt.Sprintf(`directory: %v`, dir))
+
+	keys := []string{
+		"",
+		"..",
+		"../escaped",
+		"/etc/passwd",
+		"foo/bar/baz",
+		".tmp-foo",
+		strings.Repeat("long", 100),
+	}
+	for i, k := range keys {
+		require.NoError(t, c.Set(tCtx, k, []byte(fmt.Sprintf("value%v", i)), nil), k)
+	}
+	for i, k := range keys {
+		act, err := c.Get(tCtx, k)
+		require.NoError(t, err, k)
+		assert.Equal(t, fmt.Sprintf("value%v", i), string(act), k)
+	}
+
+	// Safe keys are used as file names directly.
+	require.NoError(t, c.Set(tCtx, "foo.bar-baz_1", []byte("safe"), nil))
+	b, err := os.ReadFile(filepath.Join(dir, "foo.bar-baz_1"))
+	require.NoError(t, err)
+	assert.Equal(t, "safe", string(b))
+
+	parentEntries, err := os.ReadDir(parent)
+	require.NoError(t, err)
+	require.Len(t, parentEntries, 1)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, len(keys)+1)
+	for _, e := range entries {
+		assert.False(t, e.IsDir(), e.Name())
+		assert.False(t, strings.HasPrefix(e.Name(), "."), e.Name())
+	}
+}
+
+func TestFileCacheTTL(t *testing.T) {
+	dir := t.TempDir()
+
+	tCtx := context.Background()
+	c := fileCacheFromConf(t, fmt.Sprintf(`
+directory: %v
+default_ttl: 50ms
+compaction_interval: ""
+`, dir))
+
+	noTTL := time.Hour
+	require.NoError(t, c.Set(tCtx, "foo", []byte("1"), nil))
+	require.NoError(t, c.Set(tCtx, "bar", []byte("2"), &noTTL))
+
+	<-time.After(time.Millisecond * 100)
+
+	_, err := c.Get(tCtx, "foo")
+	assert.Equal(t, service.ErrKeyNotFound, err)
+
+	act, err := c.Get(tCtx, "bar")
+	require.NoError(t, err)
+	assert.Equal(t, "2", string(act))
+
+	// An expired item can be added again.
+	require.NoError(t, c.Set(tCtx, "baz", []byte("3"), nil))
+	<-time.After(time.Millisecond * 100)
+	require.NoError(t, c.Add(tCtx, "baz", []byte("4"), &noTTL))
+
+	act, err = c.Get(tCtx, "baz")
+	require.NoError(t, err)
+	assert.Equal(t, "4", string(act))
+
+	// The TTL is persisted and respected after a restart.
+	require.NoError(t, c.Set(tCtx, "qux", []byte("5"), nil))
+	require.NoError(t, c.Close(tCtx))
+
+	c = fileCacheFromConf(t, fmt.Sprintf(`directory: %v`, dir))
+	<-time.After(time.Millisecond * 100)
+
+	_, err = c.Get(tCtx, "qux")
+	assert.Equal(t, service.ErrKeyNotFound, err)
+
+	assert.Equal(t, service.ErrKeyAlreadyExists, c.Add(tCtx, "bar", []byte("6"), nil))
+}
+
+func TestFileCacheCompaction(t *testing.T) {
+	dir := t.TempDir()
+
+	tCtx := context.Background()
+	c := fileCacheFromConf(t, fmt.Sprintf(`
+directory: %v
+default_ttl: 10ms
+compaction_interval: 10ms
+`, dir))
+
+	require.NoError(t, c.Set(tCtx, "foo", []byte("1"), nil))
+
+	assert.Eventually(t, func() bool {
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		return len(entries) == 0
+	}, time.Second*5, time.Millisecond*10)
+}
+
+func TestFileCacheMaxItems(t *testing.T) {
+	dir := t.TempDir()
+
+	tCtx := context.Background()
+	c := fileCacheFromConf(t, fmt.Sprintf(`
+directory: %v
+max_items: 2
+`, dir))
+
+	require.NoError(t, c.Set(tCtx, "foo", []byte("1"), nil))
+	<-time.After(time.Millisecond * 5)
+	require.NoError(t, c.Set(tCtx, "bar", []byte("2"), nil))
+	<-time.After(time.Millisecond * 5)
+
+	// Accessing foo makes bar the least recently used.
+	_, err := c.Get(tCtx, "foo")
+	require.NoError(t, err)
+	<-time.After(time.Millisecond * 5)
+
+	require.NoError(t, c.Set(tCtx, "baz", []byte("3"), nil))
+
+	_, err = c.Get(tCtx, "bar")
+	assert.Equal(t, service.ErrKeyNotFound, err)
+
+	for _, k := range []string{"foo", "baz"} {
+		_, err = c.Get(tCtx, k)
+		assert.NoError(t, err, k)
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestFileCacheMaxBytes(t *testing.T) {
+	dir := t.TempDir()
+
+	tCtx := context.Background()
+
+	itemSize := 10
+	c := fileCacheFromConf(t, fmt.Sprintf(`
+directory: %v
+max_bytes: %v
+`, dir, itemSize*2))
+
+	for _, k := range []string{"foo", "bar", "baz"} {
+		require.NoError(t, c.Set(tCtx, k, []byte("0123456789"), nil))
+		<-time.After(time.Millisecond * 5)
+	}
+
+	_, err := c.Get(tCtx, "foo")
+	assert.Equal(t, service.ErrKeyNotFound, err)
+
+	for _, k := range []string{"bar", "baz"} {
+		_, err = c.Get(tCtx, k)
+		assert.NoError(t, err, k)
+	}
+	require.NoError(t, c.Close(tCtx))
+
+	// Limits are also applied to existing items on startup.
+	c = fileCacheFromConf(t, fmt.Sprintf(`
+directory: %v
+max_items: 1
+`, dir))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestFileCacheRecovery(t *testing.T) {
+	dir := t.TempDir()
+
+	tCtx := context.Background()
+	c := fileCacheFromConf(t, fmt.Sprintf(`directory: %v`, dir))
+	require.NoError(t, c.Set(tCtx, "foo", []byte("1"), nil))
+	require.NoError(t, c.Close(tCtx))
+
+	for _, name := range []string{
+		fileCacheTmpPrefix + "abc",
+		fileCacheExpiryPrefix + "bar",
+		".unrelated",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("nope"), 0o644))
+	}
+
+	// Items written directly to the directory are also recognised.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "baz"), []byte("2"), 0o644))
+
+	c = fileCacheFromConf(t, fmt.Sprintf(`directory: %v`, dir))
+
+	act, err := c.Get(tCtx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(act))
+
+	act, err = c.Get(tCtx, "baz")
+	require.NoError(t, err)
+	assert.Equal(t, "2", string(act))
+
+	for _, name := range []string{fileCacheTmpPrefix + "abc", fileCacheExpiryPrefix + "bar"} {
+		_, err = os.Stat(filepath.Join(dir, name))
+		assert.True(t, os.IsNotExist(err), name)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, ".unrelated"))
+	assert.NoError(t, err)
+	assert.Len(t, c.items, 2)
+}
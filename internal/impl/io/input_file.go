@@ -16,8 +16,13 @@ import (
 )
 
 const (
-	fileInputFieldPaths          = "paths"
-	fileInputFieldDeleteOnFinish = "delete_on_finish"
+	fileInputFieldPaths                = "paths"
+	fileInputFieldDeleteOnFinish       = "delete_on_finish"
+	fileInputFieldFollow               = "follow"
+	fileInputFieldFollowEnabled        = "enabled"
+	fileInputFieldFollowPollInterval   = "poll_interval"
+	fileInputFieldFollowRescanInterval = "rescan_interval"
+	fileInputFieldFollowCache          = "cache"
 )
 
 func fileInputSpec() *service.ConfigSpec {
@@ -37,7 +42,17 @@ This input adds the following metadata fields to each message:
 `+"```"+`
 
 You can access these metadata fields using
-xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].`).
+xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].
+
+== Following files
+
+By default each file is consumed until its end, and once all files are consumed the input finishes. When `+"`follow.enabled`"+` is set to `+"`true`"+` the input instead behaves like `+"`tail -F`"+`: files are polled for appended data indefinitely, and the paths are periodically expanded again in order to pick up files created after the input started.
+
+When following a path that is rotated, which is detected by the path referring to a different file (inode) than before, the previous file is read to its end before it is closed, and the new file is consumed from its beginning. A file that shrinks is assumed to have been truncated and is also consumed again from its beginning. Files that are renamed to another path matched by the input continue to be consumed from where they left off.
+
+The position of each file up to which all messages have been acknowledged is stored in the cache resource named by `+"`follow.cache`"+`, and therefore when the input is restarted files are resumed without consuming lines a second time. These positions are keyed by the device and inode of each file, and the first kilobyte of each file is fingerprinted in order to detect positions that belong to a deleted file whose inode has been reused.
+
+When following files only the `+"`lines`"+` scanner is supported, and a final line without a trailing delimiter is only emitted once its file has been rotated away.`).
 		Example(
 			"Read a Bunch of CSVs",
 			"If we wished to consume a directory of CSV files as structured documents we can use a glob pattern and the `csv` scanner:",
@@ -59,14 +74,58 @@ input:
 				Description("Whether to delete input files from the disk once they are fully consumed.").
 				Advanced().
 				Default(false),
+			service.NewObjectField(fileInputFieldFollow,
+				service.NewBoolField(fileInputFieldFollowEnabled).
+					Description("Whether to follow files for appended data rather than finishing once they are consumed.").
+					Default(false),
+				service.NewDurationField(fileInputFieldFollowPollInterval).
+					Description("The period of time to wait before checking files for appended data, rotation and truncation once all of them have been consumed.").
+					Default("1s"),
+				service.NewDurationField(fileInputFieldFollowRescanInterval).
+					Description("The period of time between expansions of the paths, which is when new files matching a glob pattern are found.").
+					Default("10s"),
+				service.NewStringField(fileInputFieldFollowCache).
+					Description("An optional xref:components:caches/about.adoc[cache resource] used to store the position of each file, allowing the input to resume files after a restart.").
+					Optional(),
+			).
+				Description("Follow files for appended data, similar to `tail -F`.").
+				Advanced().
+				Version("4.49.0"),
 			service.NewAutoRetryNacksToggleField(),
+		).
+		Example(
+			"Follow Log Files",
+			"Consumes the lines appended to a directory of rotated log files, resuming from the last acknowledged line of each file after a restart:",
+			`
+input:
+  file:
+    paths: [ /var/log/app/*.log ]
+    follow:
+      enabled: true
+      cache: offsets
+
+cache_resources:
+  - label: offsets
+    file:
+      directory: /var/lib/connect/offsets
+`,
 		)
 }
 
 func init() {
 	err := service.RegisterBatchInput("file", fileInputSpec(),
 		func(pConf *service.ParsedConfig, res *service.Resources) (service.BatchInput, error) {
-			r, err := fileConsumerFromParsed(pConf, res)
+			follow, err := pConf.FieldBool(fileInputFieldFollow, fileInputFieldFollowEnabled)
+			if err != nil {
+				return nil, err
+			}
+
+			var r service.BatchInput
+			if follow {
+				r, err = fileFollowerFromParsed(pConf, res)
+			} else {
+				r, err = fileConsumerFromParsed(pConf, res)
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -0,0 +1,687 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package io
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/internal/component"
+	"github.com/redpanda-data/benthos/v4/internal/filepath"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	// The number of bytes from the beginning of a file that are used in order
+	// to detect that a checkpoint belongs to a different file, which happens
+	// when an inode is reused.
+	fileFollowFingerprintSize = 1024
+
+	fileFollowReadSize = 32 * 1024
+)
+
+type fileIdentity struct {
+	dev, ino uint64
+}
+
+// fileCheckpoint is the committed progress of a followed file, which is stored
+// in a cache as JSON.
+type fileCheckpoint struct {
+	Offset         int64  `json:"offset"`
+	Fingerprint    uint32 `json:"fingerprint"`
+	FingerprintLen int    `json:"fingerprint_len"`
+}
+
+type followRecord struct {
+	end   int64
+	acked bool
+}
+
+// followedFile is an open file that is being consumed line by line.
+type followedFile struct {
+	path    string
+	file    fs.File
+	key     string
+	id      fileIdentity
+	hasID   bool
+	modTime time.Time
+
+	offset int64  // The position within the file of the start of buf
+	buf    []byte // Data read from the file that has not yet been emitted
+	eof    bool
+
+	// Set once the file is no longer found at any of the followed paths, at
+	// which point any remaining data is emitted as a final line once the end
+	// of the file is reached.
+	draining bool
+	done     bool
+
+	// Protected by the ackMut of the follower.
+	head      []byte
+	pending   []*followRecord
+	committed int64
+	retired   bool
+}
+
+func (ff *followedFile) readPos() int64 {
+	return ff.offset + int64(len(ff.buf))
+}
+
+type followedPath struct {
+	path string
+	file *followedFile
+}
+
+type fileLinesConfig struct {
+	delim       []byte
+	customDelim bool
+	maxLineSize int
+	omitEmpty   bool
+}
+
+type fileFollower struct {
+	log *service.Logger
+	nm  *service.Resources
+
+	patterns       []string
+	lines          fileLinesConfig
+	pollInterval   time.Duration
+	rescanInterval time.Duration
+	cache          string
+
+	mut      sync.Mutex
+	entries  []*followedPath
+	orphans  []*followedFile
+	cursor   int
+	lastScan time.Time
+	closed   bool
+
+	ackMut      sync.Mutex
+	checkpoints map[string]fileCheckpoint
+	cacheMut    sync.Mutex
+}
+
+func fileFollowerFromParsed(conf *service.ParsedConfig, nm *service.Resources) (*fileFollower, error) {
+	f := &fileFollower{
+		log:         nm.Logger(),
+		nm:          nm,
+		checkpoints: map[string]fileCheckpoint{},
+	}
+
+	var err error
+	if f.patterns, err = conf.FieldStringList(fileInputFieldPaths); err != nil {
+		return nil, err
+	}
+
+	deleteOnFinish, err := conf.FieldBool(fileInputFieldDeleteOnFinish)
+	if err != nil {
+		return nil, err
+	}
+	if deleteOnFinish {
+		return nil, fmt.Errorf("field %v is not supported when following files", fileInputFieldDeleteOnFinish)
+	}
+
+	if f.lines, err = fileLinesConfigFromParsed(conf); err != nil {
+		return nil, err
+	}
+
+	fConf := conf.Namespace(fileInputFieldFollow)
+	if f.pollInterval, err = fConf.FieldDuration(fileInputFieldFollowPollInterval); err != nil {
+		return nil, err
+	}
+	if f.rescanInterval, err = fConf.FieldDuration(fileInputFieldFollowRescanInterval); err != nil {
+		return nil, err
+	}
+	if fConf.Contains(fileInputFieldFollowCache) {
+		if f.cache, err = fConf.FieldString(fileInputFieldFollowCache); err != nil {
+			return nil, err
+		}
+		if !nm.HasCache(f.cache) {
+			return nil, fmt.Errorf("cache named %v not found", f.cache)
+		}
+	}
+	return f, nil
+}
+
+// fileLinesConfigFromParsed extracts the options of the lines scanner, which is
+// the only scanner supported when following files as the position of each
+// message within a file must be known in order to resume from it.
+func fileLinesConfigFromParsed(conf *service.ParsedConfig) (l fileLinesConfig, err error) {
+	l = fileLinesConfig{
+		delim:       []byte("\n"),
+		maxLineSize: 64 * 1024,
+	}
+	if conf.Contains("codec") {
+		return l, errors.New("the codec field is not supported when following files, use the lines scanner instead")
+	}
+
+	scanners, err := conf.FieldAny("scanner")
+	if err != nil {
+		return l, err
+	}
+	if sMap, ok := scanners.(map[string]any); !ok || len(sMap) != 1 || sMap["lines"] == nil {
+		return l, errors.New("only the lines scanner is supported when following files")
+	}
+
+	lConf := conf.Namespace("scanner", "lines")
+	if lConf.Contains("custom_delimiter") {
+		var delim string
+		if delim, err = lConf.FieldString("custom_delimiter"); err != nil {
+			return
+		}
+		if delim != "" {
+			l.delim, l.customDelim = []byte(delim), true
+		}
+	}
+	if lConf.Contains("max_buffer_size") {
+		if l.maxLineSize, err = lConf.FieldInt("max_buffer_size"); err != nil {
+			return
+		}
+	}
+	if lConf.Contains("omit_empty") {
+		if l.omitEmpty, err = lConf.FieldBool("omit_empty"); err != nil {
+			return
+		}
+	}
+	return
+}
+
+//------------------------------------------------------------------------------
+
+func (f *fileFollower) Connect(ctx context.Context) error {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	if f.closed {
+		return component.ErrTypeClosed
+	}
+	return f.rescan(ctx)
+}
+
+// rescan expands the followed paths, opening files that have appeared since
+// the last scan and claiming files that have been renamed.
+func (f *fileFollower) rescan(ctx context.Context) error {
+	f.lastScan = time.Now()
+
+	paths, err := filepath.Globs(f.nm.FS(), f.patterns)
+	if err != nil {
+		return err
+	}
+
+	matched := make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		matched[p] = struct{}{}
+	}
+
+	// Files of paths that no longer match are orphaned first so that they can
+	// be claimed by new paths in the case of a rename.
+	existing := make(map[string]*followedPath, len(f.entries))
+	for _, e := range f.entries {
+		if _, exists := matched[e.path]; exists {
+			existing[e.path] = e
+		} else if e.file != nil {
+			f.orphans = append(f.orphans, e.file)
+		}
+	}
+
+	entries := make([]*followedPath, 0, len(paths))
+	for _, p := range paths {
+		e, exists := existing[p]
+		if !exists {
+			e = &followedPath{path: p}
+			f.openEntry(ctx, e)
+		}
+		entries = append(entries, e)
+	}
+	f.entries = entries
+
+	// Any orphans that weren't claimed by a new path during this scan are
+	// drained and then closed.
+	for _, o := range f.orphans {
+		o.draining = true
+	}
+	return nil
+}
+
+// claimOrphan returns a previously orphaned file with the given identity,
+// which is the case when a followed file is renamed to another followed path.
+func (f *fileFollower) claimOrphan(id fileIdentity) *followedFile {
+	for i, o := range f.orphans {
+		if o.hasID && o.id == id && !o.done {
+			f.orphans = append(f.orphans[:i], f.orphans[i+1:]...)
+			o.draining = false
+			return o
+		}
+	}
+	return nil
+}
+
+// openEntry attempts to open the file of a followed path, which might not
+// exist yet.
+func (f *fileFollower) openEntry(ctx context.Context, e *followedPath) {
+	info, err := f.nm.FS().Stat(e.path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			f.log.Errorf("Failed to stat file '%v': %v", e.path, err)
+		}
+		return
+	}
+	if info.IsDir() {
+		return
+	}
+	if id, hasID := fileIdentityOf(info); hasID {
+		if o := f.claimOrphan(id); o != nil {
+			f.log.Debugf("Following renamed file '%v' at '%v'", o.path, e.path)
+			o.path = e.path
+			e.file = o
+			return
+		}
+	}
+	if e.file, err = f.openFile(ctx, e.path); err != nil {
+		f.log.Errorf("Failed to open file '%v': %v", e.path, err)
+	}
+}
+
+func (f *fileFollower) checkpointKey(path string, id fileIdentity, hasID bool) string {
+	if hasID {
+		return strconv.FormatUint(id.dev, 10) + ":" + strconv.FormatUint(id.ino, 10)
+	}
+	return path
+}
+
+func (f *fileFollower) loadCheckpoint(ctx context.Context, key string) (cp fileCheckpoint, exists bool) {
+	f.ackMut.Lock()
+	cp, exists = f.checkpoints[key]
+	f.ackMut.Unlock()
+	if exists || f.cache == "" {
+		return
+	}
+
+	var cpBytes []byte
+	var cErr error
+	if err := f.nm.AccessCache(ctx, f.cache, func(c service.Cache) {
+		cpBytes, cErr = c.Get(ctx, key)
+	}); err != nil {
+		cErr = err
+	}
+	if cErr != nil {
+		if !errors.Is(cErr, service.ErrKeyNotFound) {
+			f.log.Errorf("Failed to read checkpoint of file from cache: %v", cErr)
+		}
+		return
+	}
+	if err := json.Unmarshal(cpBytes, &cp); err != nil {
+		f.log.Errorf("Failed to parse checkpoint of file from cache: %v", err)
+		return
+	}
+	return cp, true
+}
+
+// openFile opens a file for following, resuming from its checkpoint when one
+// exists and still matches the contents of the file.
+func (f *fileFollower) openFile(ctx context.Context, path string) (*followedFile, error) {
+	file, err := f.nm.FS().Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	ff := &followedFile{
+		path:    path,
+		file:    file,
+		modTime: info.ModTime().UTC(),
+	}
+	ff.id, ff.hasID = fileIdentityOf(info)
+	ff.key = f.checkpointKey(path, ff.id, ff.hasID)
+
+	cp, exists := f.loadCheckpoint(ctx, ff.key)
+	seeker, canSeek := file.(io.Seeker)
+	if !exists || !canSeek || cp.Offset > info.Size() || cp.FingerprintLen > fileFollowFingerprintSize {
+		return ff, nil
+	}
+
+	head := make([]byte, cp.FingerprintLen)
+	if _, err := io.ReadFull(file, head); err != nil || crc32.ChecksumIEEE(head) != cp.Fingerprint {
+		f.log.Debugf("Checkpoint of file '%v' does not match its contents, consuming from the beginning", path)
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		return ff, nil
+	}
+	if _, err := seeker.Seek(cp.Offset, io.SeekStart); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	f.log.Debugf("Resuming file '%v' from offset %v", path, cp.Offset)
+	ff.offset, ff.committed, ff.head = cp.Offset, cp.Offset, head
+	return ff, nil
+}
+
+// retire stops a file from contributing to checkpoints, which is necessary
+// once its position no longer reflects the content found under its identity.
+func (f *fileFollower) retire(ff *followedFile) {
+	f.ackMut.Lock()
+	ff.retired = true
+	f.ackMut.Unlock()
+}
+
+//------------------------------------------------------------------------------
+
+// nextLine attempts to extract a complete line from a file, reading more data
+// from it when necessary. Returns false when there is no complete line
+// available yet.
+func (f *fileFollower) nextLine(ff *followedFile) ([]byte, bool, error) {
+	for {
+		if i := bytes.Index(ff.buf, f.lines.delim); i >= 0 {
+			line := ff.buf[:i]
+			f.consume(ff, i+len(f.lines.delim))
+			if !f.lines.customDelim {
+				line = bytes.TrimSuffix(line, []byte("\r"))
+			}
+			if f.lines.omitEmpty && len(line) == 0 {
+				continue
+			}
+			return line, true, nil
+		}
+
+		if len(ff.buf) >= f.lines.maxLineSize {
+			f.log.Warnf("Line of file '%v' exceeds the maximum buffer size and is emitted in parts", ff.path)
+			line := ff.buf
+			f.consume(ff, len(line))
+			return line, true, nil
+		}
+
+		if ff.eof {
+			if ff.draining {
+				ff.done = true
+				if line := ff.buf; len(line) > 0 {
+					f.consume(ff, len(line))
+					return line, true, nil
+				}
+			}
+			return nil, false, nil
+		}
+
+		chunk := make([]byte, fileFollowReadSize)
+		n, err := ff.file.Read(chunk)
+		if n > 0 {
+			f.appendHead(ff, chunk[:n])
+			ff.buf = append(ff.buf, chunk[:n]...)
+		}
+		if errors.Is(err, io.EOF) || (err == nil && n == 0) {
+			ff.eof = true
+		} else if err != nil {
+			return nil, false, err
+		}
+	}
+}
+
+// appendHead records data from the beginning of a file used to fingerprint it.
+func (f *fileFollower) appendHead(ff *followedFile, data []byte) {
+	f.ackMut.Lock()
+	defer f.ackMut.Unlock()
+
+	if int64(len(ff.head)) != ff.readPos() || len(ff.head) >= fileFollowFingerprintSize {
+		return
+	}
+	n := min(len(data), fileFollowFingerprintSize-len(ff.head))
+	ff.head = append(ff.head, data[:n]...)
+}
+
+func (f *fileFollower) consume(ff *followedFile, n int) {
+	ff.buf = ff.buf[n:]
+	ff.offset += int64(n)
+	if len(ff.buf) == 0 {
+		ff.buf = nil
+	}
+}
+
+// readers returns all open files in a stable order.
+func (f *fileFollower) readers() []*followedFile {
+	readers := make([]*followedFile, 0, len(f.entries)+len(f.orphans))
+	for _, e := range f.entries {
+		if e.file != nil && !e.file.done {
+			readers = append(readers, e.file)
+		}
+	}
+	for _, o := range f.orphans {
+		if !o.done {
+			readers = append(readers, o)
+		}
+	}
+	return readers
+}
+
+// checkFiles detects files that have been rotated, truncated or created since
+// the last check, and prepares them for reading again.
+func (f *fileFollower) checkFiles(ctx context.Context) {
+	for _, e := range f.entries {
+		if e.file != nil && e.file.done {
+			f.retire(e.file)
+			_ = e.file.file.Close()
+			e.file = nil
+		}
+		if e.file == nil {
+			f.openEntry(ctx, e)
+			continue
+		}
+		e.file.eof = false
+
+		info, err := f.nm.FS().Stat(e.path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				f.log.Debugf("File '%v' was removed", e.path)
+				f.orphans = append(f.orphans, e.file)
+				e.file = nil
+			} else {
+				f.log.Errorf("Failed to stat file '%v': %v", e.path, err)
+			}
+			continue
+		}
+		e.file.modTime = info.ModTime().UTC()
+
+		if id, hasID := fileIdentityOf(info); hasID && id != e.file.id {
+			// The path refers to a new file, the previous one is drained until
+			// it is either claimed by a new path or the next scan.
+			f.log.Debugf("File '%v' was rotated", e.path)
+			f.orphans = append(f.orphans, e.file)
+			f.openEntry(ctx, e)
+			continue
+		}
+
+		if info.Size() < e.file.readPos() {
+			f.log.Debugf("File '%v' was truncated", e.path)
+			if err := f.truncated(e); err != nil {
+				f.log.Errorf("Failed to reset truncated file '%v': %v", e.path, err)
+			}
+		}
+	}
+	for _, o := range f.orphans {
+		o.eof = false
+	}
+}
+
+// truncated resets a file to its beginning, with the contents read prior to
+// the truncation no longer contributing to its checkpoint.
+func (f *fileFollower) truncated(e *followedPath) error {
+	old := e.file
+	f.retire(old)
+
+	e.file = &followedFile{
+		path:    old.path,
+		file:    old.file,
+		key:     old.key,
+		id:      old.id,
+		hasID:   old.hasID,
+		modTime: old.modTime,
+	}
+	seeker, ok := old.file.(io.Seeker)
+	if !ok {
+		return errors.New("file does not support seeking")
+	}
+	_, err := seeker.Seek(0, io.SeekStart)
+	return err
+}
+
+// pruneOrphans closes orphaned files that have been fully drained.
+func (f *fileFollower) pruneOrphans() {
+	orphans := f.orphans[:0]
+	for _, o := range f.orphans {
+		if o.done || (o.draining && o.eof && len(o.buf) == 0) {
+			f.retire(o)
+			_ = o.file.Close()
+			continue
+		}
+		orphans = append(orphans, o)
+	}
+	clear(f.orphans[len(orphans):])
+	f.orphans = orphans
+}
+
+func (f *fileFollower) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	for {
+		if f.closed {
+			return nil, nil, component.ErrTypeClosed
+		}
+
+		readers := f.readers()
+		for i := range readers {
+			ff := readers[(f.cursor+i)%len(readers)]
+
+			line, ok, err := f.nextLine(ff)
+			if err != nil {
+				f.log.Errorf("Failed to read file '%v': %v", ff.path, err)
+				ff.draining, ff.done = true, true
+				continue
+			}
+			if ok {
+				f.cursor = (f.cursor + i + 1) % len(readers)
+				return f.emit(ff, line)
+			}
+		}
+
+		f.pruneOrphans()
+		if time.Since(f.lastScan) >= f.rescanInterval {
+			if err := f.rescan(ctx); err != nil {
+				f.log.Errorf("Failed to expand paths: %v", err)
+			}
+		}
+
+		select {
+		case <-time.After(f.pollInterval):
+		case <-ctx.Done():
+			return nil, nil, component.ErrTimeout
+		}
+		f.checkFiles(ctx)
+	}
+}
+
+func (f *fileFollower) emit(ff *followedFile, line []byte) (service.MessageBatch, service.AckFunc, error) {
+	rec := &followRecord{end: ff.offset}
+
+	f.ackMut.Lock()
+	ff.pending = append(ff.pending, rec)
+	f.ackMut.Unlock()
+
+	part := service.NewMessage(bytes.Clone(line))
+	part.MetaSetMut("path", ff.path)
+	part.MetaSetMut("mod_time_unix", ff.modTime.Unix())
+	part.MetaSetMut("mod_time", ff.modTime.Format(time.RFC3339))
+
+	return service.MessageBatch{part}, func(ctx context.Context, err error) error {
+		// Rejected messages are not consumed again, and therefore count
+		// towards the checkpoint of the file the same as delivered ones.
+		return f.ack(ctx, ff, rec)
+	}, nil
+}
+
+// ack marks a record as delivered and commits the checkpoint of its file when
+// all prior records have also been delivered.
+func (f *fileFollower) ack(ctx context.Context, ff *followedFile, rec *followRecord) error {
+	f.ackMut.Lock()
+	rec.acked = true
+	advanced := false
+	for len(ff.pending) > 0 && ff.pending[0].acked {
+		ff.committed = ff.pending[0].end
+		ff.pending[0] = nil
+		ff.pending = ff.pending[1:]
+		advanced = true
+	}
+	if !advanced || ff.retired {
+		f.ackMut.Unlock()
+		return nil
+	}
+
+	fpLen := int(min(ff.committed, int64(len(ff.head))))
+	f.checkpoints[ff.key] = fileCheckpoint{
+		Offset:         ff.committed,
+		Fingerprint:    crc32.ChecksumIEEE(ff.head[:fpLen]),
+		FingerprintLen: fpLen,
+	}
+	f.ackMut.Unlock()
+
+	if f.cache == "" {
+		return nil
+	}
+
+	// Checkpoints are written in order by reading the latest one for the file
+	// once the cache lock is held.
+	f.cacheMut.Lock()
+	defer f.cacheMut.Unlock()
+
+	f.ackMut.Lock()
+	cp := f.checkpoints[ff.key]
+	f.ackMut.Unlock()
+
+	cpBytes, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	var cErr error
+	if err := f.nm.AccessCache(ctx, f.cache, func(c service.Cache) {
+		cErr = c.Set(ctx, ff.key, cpBytes, nil)
+	}); err != nil {
+		cErr = err
+	}
+	if cErr != nil {
+		f.log.Errorf("Failed to store checkpoint of file %v: %v", ff.key, cErr)
+	}
+	return nil
+}
+
+func (f *fileFollower) Close(ctx context.Context) error {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.closed = true
+	for _, e := range f.entries {
+		if e.file != nil {
+			_ = e.file.file.Close()
+		}
+	}
+	for _, o := range f.orphans {
+		_ = o.file.Close()
+	}
+	f.entries, f.orphans = nil, nil
+	return nil
+}
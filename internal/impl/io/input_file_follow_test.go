@@ -0,0 +1,185 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package io_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/internal/component/input"
+	"github.com/redpanda-data/benthos/v4/internal/component/testutil"
+	"github.com/redpanda-data/benthos/v4/internal/manager/mock"
+	"github.com/redpanda-data/benthos/v4/internal/message"
+)
+
+func followInput(t testing.TB, mgr *mock.Manager, extra, path string) input.Streamed {
+	t.Helper()
+
+	conf, err := testutil.InputFromYAML(fmt.Sprintf(`
+file:
+  paths: [ %q ]
+  follow:
+    enabled: true
+    poll_interval: 5ms
+    rescan_interval: 20ms
+%v
+`, path, extra))
+	require.NoError(t, err)
+
+	i, err := mgr.NewInput(conf)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		i.TriggerStopConsuming()
+		ctx, done := context.WithTimeout(context.Background(), time.Second*5)
+		defer done()
+		assert.NoError(t, i.WaitForClose(ctx))
+	})
+	return i
+}
+
+func readFollowed(t testing.TB, i input.Streamed, n int) []string {
+	t.Helper()
+
+	var lines []string
+	for len(lines) < n {
+		var tran message.Transaction
+		select {
+		case tran = <-i.TransactionChan():
+		case <-time.After(time.Second * 5):
+			t.Fatalf("timed out after reading lines: %v", lines)
+		}
+		lines = append(lines, string(tran.Payload.Get(0).AsBytes()))
+		require.NoError(t, tran.Ack(context.Background(), nil))
+	}
+	return lines
+}
+
+func assertNoneFollowed(t testing.TB, i input.Streamed) {
+	t.Helper()
+
+	select {
+	case tran := <-i.TransactionChan():
+		t.Fatalf("unexpected line: %s", tran.Payload.Get(0).AsBytes())
+	case <-time.After(time.Millisecond * 100):
+	}
+}
+
+func appendFile(t testing.TB, path, data string) {
+	t.Helper()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString(data)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
+
+func TestFileFollowAppendAndRotate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	appendFile(t, path, "foo\nbar\r\nbaz")
+
+	i := followInput(t, mock.NewManager(), "", filepath.Join(dir, "*.log"))
+
+	// Incomplete lines are not emitted until they're terminated.
+	assert.Equal(t, []string{"foo", "bar"}, readFollowed(t, i, 2))
+	assertNoneFollowed(t, i)
+
+	appendFile(t, path, "\nbuz\n")
+	assert.Equal(t, []string{"baz", "buz"}, readFollowed(t, i, 2))
+
+	// The rotated file is drained, including lines written to it after the
+	// rotation, before it is closed.
+	require.NoError(t, os.Rename(path, path+".1"))
+	appendFile(t, path+".1", "old\nunterminated")
+	appendFile(t, path, "new\n")
+	assert.ElementsMatch(t, []string{"old", "unterminated", "new"}, readFollowed(t, i, 3))
+
+	// Truncated files are consumed from the beginning.
+	require.NoError(t, os.WriteFile(path, []byte("a\n"), 0o644))
+	assert.Equal(t, []string{"a"}, readFollowed(t, i, 1))
+	assertNoneFollowed(t, i)
+}
+
+func TestFileFollowNewAndRenamedFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	i := followInput(t, mock.NewManager(), "", filepath.Join(dir, "*.log"))
+	assertNoneFollowed(t, i)
+
+	appendFile(t, filepath.Join(dir, "a.log"), "foo\n")
+	assert.Equal(t, []string{"foo"}, readFollowed(t, i, 1))
+
+	// Renaming a file to another matched path continues where it left off.
+	require.NoError(t, os.Rename(filepath.Join(dir, "a.log"), filepath.Join(dir, "b.log")))
+	assertNoneFollowed(t, i)
+
+	appendFile(t, filepath.Join(dir, "b.log"), "bar\n")
+	assert.Equal(t, []string{"bar"}, readFollowed(t, i, 1))
+	assertNoneFollowed(t, i)
+}
+
+func TestFileFollowResume(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	mgr := mock.NewManager()
+	mgr.Caches["offsets"] = map[string]mock.CacheItem{}
+
+	appendFile(t, path, "foo\nbar\n")
+
+	i := followInput(t, mgr, "    cache: offsets", path)
+	assert.Equal(t, []string{"foo", "bar"}, readFollowed(t, i, 2))
+
+	i.TriggerStopConsuming()
+	ctx, done := context.WithTimeout(context.Background(), time.Second*5)
+	defer done()
+	require.NoError(t, i.WaitForClose(ctx))
+
+	appendFile(t, path, "baz\n")
+
+	i = followInput(t, mgr, "    cache: offsets", path)
+	assert.Equal(t, []string{"baz"}, readFollowed(t, i, 1))
+	assertNoneFollowed(t, i)
+
+	i.TriggerStopConsuming()
+	require.NoError(t, i.WaitForClose(ctx))
+
+	// A checkpoint is discarded when the file it belongs to was replaced.
+	require.NoError(t, os.Remove(path))
+	appendFile(t, path, "new\n")
+
+	i = followInput(t, mgr, "    cache: offsets", path)
+	assert.Equal(t, []string{"new"}, readFollowed(t, i, 1))
+}
+
+func TestFileFollowConfigErrors(t *testing.T) {
+	for name, extra := range map[string]string{
+		"delete on finish": "  delete_on_finish: true",
+		"scanner":          "  scanner:\n    to_the_end: {}",
+		"missing cache":    "    cache: nope",
+	} {
+		t.Run(name, func(t *testing.T) {
+			conf, err := testutil.InputFromYAML(fmt.Sprintf(`
+file:
+  paths: [ ./foo.log ]
+  follow:
+    enabled: true
+%v
+`, extra))
+			require.NoError(t, err)
+
+			_, err = mock.NewManager().NewInput(conf)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+//go:build !unix
+
+package io
+
+import (
+	"io/fs"
+)
+
+// fileIdentityOf is not supported on this platform, and therefore files are
+// identified by their path alone.
+func fileIdentityOf(info fs.FileInfo) (fileIdentity, bool) {
+	return fileIdentity{}, false
+}
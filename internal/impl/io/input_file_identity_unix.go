@@ -0,0 +1,20 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+//go:build unix
+
+package io
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// fileIdentityOf returns the device and inode of a file, which remain the same
+// when the file is renamed.
+func fileIdentityOf(info fs.FileInfo) (fileIdentity, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileIdentity{}, false
+	}
+	return fileIdentity{dev: uint64(st.Dev), ino: uint64(st.Ino)}, true //nolint:unconvert // Types differ between platforms
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/redpanda-data/benthos/v4/internal/codec"
 	"github.com/redpanda-data/benthos/v4/internal/component/testutil"
 	"github.com/redpanda-data/benthos/v4/internal/manager/mock"
 	"github.com/redpanda-data/benthos/v4/internal/message"
@@ -195,6 +196,54 @@ file:
 	}
 }
 
+func TestFileParquetCodec(t *testing.T) {
+	tmpFile, err := os.Create(filepath.Join(t.TempDir(), "data.parquet"))
+	require.NoError(t, err)
+
+	pw, err := codec.NewParquetWriter(tmpFile, codec.ParquetWriterConfig{
+		Compression:  "snappy",
+		RowGroupSize: 2,
+	})
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, pw.Write(map[string]any{"id": i}))
+	}
+	require.NoError(t, pw.Close())
+	require.NoError(t, tmpFile.Close())
+
+	conf, err := testutil.InputFromYAML(fmt.Sprintf(`
+file:
+  paths: [ "%v" ]
+  codec: parquet
+`, tmpFile.Name()))
+	require.NoError(t, err)
+
+	i, err := mock.NewManager().NewInput(conf)
+	require.NoError(t, err)
+
+	for n := 0; n < 5; n++ {
+		var tran message.Transaction
+		var open bool
+		select {
+		case tran, open = <-i.TransactionChan():
+			require.True(t, open)
+		case <-time.After(time.Second):
+			t.Fatal("timed out")
+		}
+
+		assert.Equal(t, fmt.Sprintf(`{"id":%v}`, n), string(tran.Payload.Get(0).AsBytes()))
+		require.NoError(t, tran.Ack(context.Background(), nil))
+	}
+
+	var open bool
+	select {
+	case _, open = <-i.TransactionChan():
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+	assert.False(t, open)
+}
+
 func assertValidMetaData(t *testing.T, res *message.Part, tmpFile *os.File) {
 	assert.Equal(t, tmpFile.Name(), res.MetaGetStr("path"))
 	assert.Equal(t, mockTime().Format(time.RFC3339), res.MetaGetStr("mod_time"))
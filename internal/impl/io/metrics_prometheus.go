@@ -0,0 +1,613 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package io
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	gmetrics "github.com/rcrowley/go-metrics"
+
+	"github.com/Jeffail/shutdown"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	pmFieldUseHistogramTiming = "use_histogram_timing"
+	pmFieldHistogramBuckets   = "histogram_buckets"
+	pmFieldSummaryQuantiles   = "summary_quantiles"
+	pmFieldPushURL            = "push_url"
+	pmFieldPushInterval       = "push_interval"
+	pmFieldPushJobName        = "push_job_name"
+	pmFieldPushBasicAuth      = "push_basic_auth"
+	pmFieldPushBasicAuthUser  = "username"
+	pmFieldPushBasicAuthPass  = "password"
+)
+
+// The same defaults used by the official Prometheus client libraries, measured
+// in seconds.
+var prometheusDefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+func prometheusMetricsSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Stable().
+		Summary(`Host endpoints (`+"`/metrics` and `/stats`"+`) for Prometheus scraping.`).
+		Description(`
+Metrics are served in the Prometheus text exposition format by the service-wide HTTP server. Timing metrics are converted from nanoseconds into seconds and are exposed as summaries by default, or as histograms when `+"`use_histogram_timing`"+` is enabled.
+
+Metric names and label names are sanitised so that any characters not supported by Prometheus are replaced with underscores. Renaming or dropping metrics can be achieved with the `+"xref:components:metrics/about.adoc#metric-mapping[`mapping` field]"+`.
+
+== Push Gateway
+
+The field `+"`push_url`"+` is optional and when set will trigger a push of metrics to a https://prometheus.io/docs/instrumenting/pushing/[Prometheus Push Gateway^] once Redpanda Connect shuts down. It is also possible to specify a `+"`push_interval`"+` which results in periodic pushes.
+
+The Push Gateway is useful for when Redpanda Connect instances are short lived. Do not include the "/metrics/jobs/..." path in the push URL.`).
+		Fields(
+			service.NewBoolField(pmFieldUseHistogramTiming).
+				Description("Whether to export timing metrics as a histogram, if `false` a summary is used instead.").
+				Advanced().
+				Default(false),
+			service.NewFloatListField(pmFieldHistogramBuckets).
+				Description("Timing metrics histogram buckets (in seconds). If left empty defaults to the same buckets as the official Prometheus client libraries. Only applicable when `use_histogram_timing` is set to `true`.").
+				Advanced().
+				Default([]any{}),
+			service.NewFloatListField(pmFieldSummaryQuantiles).
+				Description("The quantiles to calculate for timing metrics exposed as summaries. Only applicable when `use_histogram_timing` is set to `false`.").
+				Advanced().
+				Default([]any{0.5, 0.9, 0.99}),
+			service.NewURLField(pmFieldPushURL).
+				Description("An optional <<push-gateway, Push Gateway URL>> to push metrics to.").
+				Advanced().
+				Optional(),
+			service.NewDurationField(pmFieldPushInterval).
+				Description("The period of time between each push when sending metrics to a Push Gateway.").
+				Advanced().
+				Optional(),
+			service.NewStringField(pmFieldPushJobName).
+				Description("An identifier for push jobs.").
+				Advanced().
+				Default("benthos_push"),
+			service.NewObjectField(pmFieldPushBasicAuth,
+				service.NewStringField(pmFieldPushBasicAuthUser).
+					Description("The Basic Authentication username.").
+					Default(""),
+				service.NewStringField(pmFieldPushBasicAuthPass).
+					Description("The Basic Authentication password.").
+					Secret().
+					Default(""),
+			).
+				Description("The Basic Authentication credentials.").
+				Advanced(),
+		)
+}
+
+func init() {
+	err := service.RegisterMetricsExporter("prometheus", prometheusMetricsSpec(),
+		func(conf *service.ParsedConfig, log *service.Logger) (service.MetricsExporter, error) {
+			return newPrometheusFromParsed(conf, log)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type promMetricKind int
+
+const (
+	promKindCounter promMetricKind = iota
+	promKindGauge
+	promKindSummary
+	promKindHistogram
+)
+
+func (k promMetricKind) String() string {
+	switch k {
+	case promKindCounter:
+		return "counter"
+	case promKindGauge:
+		return "gauge"
+	case promKindSummary:
+		return "summary"
+	case promKindHistogram:
+		return "histogram"
+	}
+	return "untyped"
+}
+
+// promFloat is a float64 that can be atomically modified.
+type promFloat struct {
+	bits uint64
+}
+
+func (f *promFloat) Add(v float64) {
+	for {
+		oldBits := atomic.LoadUint64(&f.bits)
+		newBits := math.Float64bits(math.Float64frombits(oldBits) + v)
+		if atomic.CompareAndSwapUint64(&f.bits, oldBits, newBits) {
+			return
+		}
+	}
+}
+
+func (f *promFloat) Store(v float64) {
+	atomic.StoreUint64(&f.bits, math.Float64bits(v))
+}
+
+func (f *promFloat) Load() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&f.bits))
+}
+
+type promCounter struct {
+	v promFloat
+}
+
+func (c *promCounter) Incr(count int64) {
+	c.v.Add(float64(count))
+}
+
+func (c *promCounter) IncrFloat64(count float64) {
+	c.v.Add(count)
+}
+
+type promGauge struct {
+	v promFloat
+}
+
+func (g *promGauge) Set(value int64) {
+	g.v.Store(float64(value))
+}
+
+func (g *promGauge) SetFloat64(value float64) {
+	g.v.Store(value)
+}
+
+type promSummary struct {
+	t   gmetrics.Timer
+	sum promFloat
+}
+
+func (s *promSummary) Timing(delta int64) {
+	s.t.Update(time.Duration(delta))
+	s.sum.Add(float64(delta) / float64(time.Second))
+}
+
+type promHistogram struct {
+	upperBounds []float64
+	buckets     []uint64
+	count       uint64
+	sum         promFloat
+}
+
+func (h *promHistogram) Timing(delta int64) {
+	v := float64(delta) / float64(time.Second)
+	if i := sort.SearchFloat64s(h.upperBounds, v); i < len(h.buckets) {
+		atomic.AddUint64(&h.buckets[i], 1)
+	}
+	h.sum.Add(v)
+	atomic.AddUint64(&h.count, 1)
+}
+
+type promSeries struct {
+	labelValues []string
+	metric      any
+}
+
+type promFamily struct {
+	name       string
+	kind       promMetricKind
+	labelNames []string
+
+	mut    sync.Mutex
+	series map[string]*promSeries
+}
+
+func (f *promFamily) get(labelValues []string, ctor func() any) any {
+	key := strings.Join(labelValues, "\xff")
+
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	if s, exists := f.series[key]; exists {
+		return s.metric
+	}
+	s := &promSeries{
+		labelValues: append([]string(nil), labelValues...),
+		metric:      ctor(),
+	}
+	f.series[key] = s
+	return s.metric
+}
+
+//------------------------------------------------------------------------------
+
+type prometheusMetrics struct {
+	useHistogramTiming bool
+	buckets            []float64
+	quantiles          []float64
+
+	pushURL      string
+	pushUser     string
+	pushPass     string
+	pushInterval time.Duration
+	pushClient   *http.Client
+
+	log     *service.Logger
+	shutSig *shutdown.Signaller
+
+	mut      sync.Mutex
+	families map[string]*promFamily
+}
+
+func newPrometheusFromParsed(conf *service.ParsedConfig, log *service.Logger) (p *prometheusMetrics, err error) {
+	p = &prometheusMetrics{
+		log:        log,
+		shutSig:    shutdown.NewSignaller(),
+		families:   map[string]*promFamily{},
+		pushClient: &http.Client{Timeout: 10 * time.Second},
+	}
+
+	if p.useHistogramTiming, err = conf.FieldBool(pmFieldUseHistogramTiming); err != nil {
+		return
+	}
+	if p.buckets, err = conf.FieldFloatList(pmFieldHistogramBuckets); err != nil {
+		return
+	}
+	if len(p.buckets) == 0 {
+		p.buckets = prometheusDefaultBuckets
+	}
+	p.buckets = append([]float64(nil), p.buckets...)
+	sort.Float64s(p.buckets)
+
+	if p.quantiles, err = conf.FieldFloatList(pmFieldSummaryQuantiles); err != nil {
+		return
+	}
+	for _, q := range p.quantiles {
+		if q < 0 || q > 1 {
+			return nil, fmt.Errorf("summary quantile %v must be between 0 and 1", q)
+		}
+	}
+
+	if conf.Contains(pmFieldPushURL) {
+		var pushURL *url.URL
+		if pushURL, err = conf.FieldURL(pmFieldPushURL); err != nil {
+			return
+		}
+
+		var jobName string
+		if jobName, err = conf.FieldString(pmFieldPushJobName); err != nil {
+			return
+		}
+		if jobName == "" {
+			return nil, errors.New("a push job name must be provided when pushing metrics")
+		}
+		p.pushURL = strings.TrimSuffix(pushURL.String(), "/") + "/metrics/job/" + url.PathEscape(jobName)
+
+		if p.pushUser, err = conf.FieldString(pmFieldPushBasicAuth, pmFieldPushBasicAuthUser); err != nil {
+			return
+		}
+		if p.pushPass, err = conf.FieldString(pmFieldPushBasicAuth, pmFieldPushBasicAuthPass); err != nil {
+			return
+		}
+
+		if conf.Contains(pmFieldPushInterval) {
+			if p.pushInterval, err = conf.FieldDuration(pmFieldPushInterval); err != nil {
+				return
+			}
+		}
+		if p.pushInterval > 0 {
+			go p.pushLoop()
+		}
+	}
+	return
+}
+
+//------------------------------------------------------------------------------
+
+func (p *prometheusMetrics) pushLoop() {
+	for {
+		select {
+		case <-p.shutSig.SoftStopChan():
+			return
+		case <-time.After(p.pushInterval):
+			ctx, done := p.shutSig.SoftStopCtx(context.Background())
+			if err := p.push(ctx); err != nil {
+				p.log.Errorf("Failed to push metrics: %v", err)
+			}
+			done()
+		}
+	}
+}
+
+func (p *prometheusMetrics) push(ctx context.Context) error {
+	var buf bytes.Buffer
+	p.writeTo(&buf)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, p.pushURL, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", prometheusContentType)
+	if p.pushUser != "" || p.pushPass != "" {
+		req.SetBasicAuth(p.pushUser, p.pushPass)
+	}
+
+	res, err := p.pushClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("unexpected status code %v from push gateway: %s", res.StatusCode, body)
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// sanitisePromName replaces any characters that are not supported within a
+// Prometheus metric or label name with underscores.
+func sanitisePromName(name string, allowColon bool) string {
+	var b strings.Builder
+	b.Grow(len(name))
+	for i, r := range name {
+		switch {
+		case r == '_',
+			r >= 'a' && r <= 'z',
+			r >= 'A' && r <= 'Z',
+			r == ':' && allowColon,
+			r >= '0' && r <= '9' && i > 0:
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+func (p *prometheusMetrics) getFamily(name string, kind promMetricKind, labelNames []string) (*promFamily, error) {
+	name = sanitisePromName(name, true)
+	sanitisedLabels := make([]string, len(labelNames))
+	for i, l := range labelNames {
+		sanitisedLabels[i] = sanitisePromName(l, false)
+	}
+
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	if f, exists := p.families[name]; exists {
+		if f.kind != kind {
+			return nil, fmt.Errorf("metric %v already registered as a %v", name, f.kind)
+		}
+		if !slices.Equal(f.labelNames, sanitisedLabels) {
+			return nil, fmt.Errorf("metric %v already registered with labels %v", name, f.labelNames)
+		}
+		return f, nil
+	}
+
+	f := &promFamily{
+		name:       name,
+		kind:       kind,
+		labelNames: sanitisedLabels,
+		series:     map[string]*promSeries{},
+	}
+	p.families[name] = f
+	return f, nil
+}
+
+type promDudStat struct{}
+
+func (promDudStat) Incr(int64)          {}
+func (promDudStat) IncrFloat64(float64) {}
+func (promDudStat) Set(int64)           {}
+func (promDudStat) SetFloat64(float64)  {}
+func (promDudStat) Timing(int64)        {}
+
+func (p *prometheusMetrics) NewCounterCtor(path string, n ...string) service.MetricsExporterCounterCtor {
+	f, err := p.getFamily(path, promKindCounter, n)
+	if err != nil {
+		p.log.Errorf("Failed to register counter metric: %v", err)
+		return func(labelValues ...string) service.MetricsExporterCounter {
+			return promDudStat{}
+		}
+	}
+	return func(labelValues ...string) service.MetricsExporterCounter {
+		return f.get(labelValues, func() any {
+			return &promCounter{}
+		}).(*promCounter)
+	}
+}
+
+func (p *prometheusMetrics) NewTimerCtor(path string, n ...string) service.MetricsExporterTimerCtor {
+	kind := promKindSummary
+	if p.useHistogramTiming {
+		kind = promKindHistogram
+	}
+	f, err := p.getFamily(path, kind, n)
+	if err != nil {
+		p.log.Errorf("Failed to register timing metric: %v", err)
+		return func(labelValues ...string) service.MetricsExporterTimer {
+			return promDudStat{}
+		}
+	}
+	if p.useHistogramTiming {
+		return func(labelValues ...string) service.MetricsExporterTimer {
+			return f.get(labelValues, func() any {
+				return &promHistogram{
+					upperBounds: p.buckets,
+					buckets:     make([]uint64, len(p.buckets)),
+				}
+			}).(*promHistogram)
+		}
+	}
+	return func(labelValues ...string) service.MetricsExporterTimer {
+		return f.get(labelValues, func() any {
+			return &promSummary{t: gmetrics.NewTimer()}
+		}).(*promSummary)
+	}
+}
+
+func (p *prometheusMetrics) NewGaugeCtor(path string, n ...string) service.MetricsExporterGaugeCtor {
+	f, err := p.getFamily(path, promKindGauge, n)
+	if err != nil {
+		p.log.Errorf("Failed to register gauge metric: %v", err)
+		return func(labelValues ...string) service.MetricsExporterGauge {
+			return promDudStat{}
+		}
+	}
+	return func(labelValues ...string) service.MetricsExporterGauge {
+		return f.get(labelValues, func() any {
+			return &promGauge{}
+		}).(*promGauge)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var promLabelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func formatPromFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func writePromLabels(w *bytes.Buffer, names, values []string, extraName, extraValue string) {
+	if len(names) == 0 && extraName == "" {
+		return
+	}
+	w.WriteByte('{')
+	for i, n := range names {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		var v string
+		if i < len(values) {
+			v = values[i]
+		}
+		w.WriteString(n)
+		w.WriteString(`="`)
+		_, _ = promLabelValueEscaper.WriteString(w, v)
+		w.WriteByte('"')
+	}
+	if extraName != "" {
+		if len(names) > 0 {
+			w.WriteByte(',')
+		}
+		w.WriteString(extraName)
+		w.WriteString(`="`)
+		w.WriteString(extraValue)
+		w.WriteByte('"')
+	}
+	w.WriteByte('}')
+}
+
+func writePromSample(w *bytes.Buffer, name string, f *promFamily, s *promSeries, extraName, extraValue string, v float64) {
+	w.WriteString(name)
+	writePromLabels(w, f.labelNames, s.labelValues, extraName, extraValue)
+	w.WriteByte(' ')
+	w.WriteString(formatPromFloat(v))
+	w.WriteByte('\n')
+}
+
+func (p *prometheusMetrics) writeTo(w *bytes.Buffer) {
+	p.mut.Lock()
+	families := make([]*promFamily, 0, len(p.families))
+	for _, f := range p.families {
+		families = append(families, f)
+	}
+	p.mut.Unlock()
+
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].name < families[j].name
+	})
+
+	for _, f := range families {
+		f.mut.Lock()
+		series := make([]*promSeries, 0, len(f.series))
+		for _, s := range f.series {
+			series = append(series, s)
+		}
+		f.mut.Unlock()
+
+		if len(series) == 0 {
+			continue
+		}
+		sort.Slice(series, func(i, j int) bool {
+			return strings.Join(series[i].labelValues, "\xff") < strings.Join(series[j].labelValues, "\xff")
+		})
+
+		fmt.Fprintf(w, "# TYPE %v %v\n", f.name, f.kind)
+		for _, s := range series {
+			switch m := s.metric.(type) {
+			case *promCounter:
+				writePromSample(w, f.name, f, s, "", "", m.v.Load())
+			case *promGauge:
+				writePromSample(w, f.name, f, s, "", "", m.v.Load())
+			case *promSummary:
+				snap := m.t.Snapshot()
+				ps := snap.Percentiles(p.quantiles)
+				for i, q := range p.quantiles {
+					writePromSample(w, f.name, f, s, "quantile", formatPromFloat(q), ps[i]/float64(time.Second))
+				}
+				writePromSample(w, f.name+"_sum", f, s, "", "", m.sum.Load())
+				writePromSample(w, f.name+"_count", f, s, "", "", float64(snap.Count()))
+			case *promHistogram:
+				var cumulative uint64
+				for i, b := range m.upperBounds {
+					cumulative += atomic.LoadUint64(&m.buckets[i])
+					writePromSample(w, f.name+"_bucket", f, s, "le", formatPromFloat(b), float64(cumulative))
+				}
+				count := atomic.LoadUint64(&m.count)
+				writePromSample(w, f.name+"_bucket", f, s, "le", "+Inf", float64(count))
+				writePromSample(w, f.name+"_sum", f, s, "", "", m.sum.Load())
+				writePromSample(w, f.name+"_count", f, s, "", "", float64(count))
+			}
+		}
+	}
+}
+
+func (p *prometheusMetrics) HandlerFunc() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		p.writeTo(&buf)
+
+		w.Header().Set("Content-Type", prometheusContentType)
+		_, _ = w.Write(buf.Bytes())
+	}
+}
+
+func (p *prometheusMetrics) Close(ctx context.Context) error {
+	p.shutSig.TriggerHardStop()
+	if p.pushURL != "" {
+		if err := p.push(ctx); err != nil {
+			return fmt.Errorf("failed to push metrics: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package io
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/internal/bundle"
+	"github.com/redpanda-data/benthos/v4/internal/component/metrics"
+	"github.com/redpanda-data/benthos/v4/internal/manager/mock"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func promFromYAML(t *testing.T, confStr string) *prometheusMetrics {
+	t.Helper()
+
+	pConf, err := prometheusMetricsSpec().ParseYAML(confStr, nil)
+	require.NoError(t, err)
+
+	p, err := newPrometheusFromParsed(pConf, service.MockResources().Logger())
+	require.NoError(t, err)
+	return p
+}
+
+func getPromPage(t *testing.T, handler http.HandlerFunc) string {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/metrics", http.NoBody)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, prometheusContentType, w.Result().Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(w.Result().Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	p := promFromYAML(t, ``)
+
+	p.NewCounterCtor("counter.one")().Incr(10)
+	p.NewCounterCtor("counter.one")().Incr(11)
+
+	ctrTwo := p.NewCounterCtor("countertwo", "label1")
+	ctrTwo("value1").Incr(10)
+	ctrTwo("value\"2").Incr(11)
+
+	p.NewGaugeCtor("gaugeone")().Set(12)
+	p.NewGaugeCtor("gaugetwo", "label2")("value3").Set(13)
+
+	tmr := p.NewTimerCtor("timerone", "label3")("value4")
+	tmr.Timing(int64(time.Second))
+	tmr.Timing(int64(time.Second * 3))
+
+	body := getPromPage(t, p.HandlerFunc())
+
+	assert.Contains(t, body, "# TYPE counter_one counter\ncounter_one 21\n")
+	assert.Contains(t, body, "# TYPE countertwo counter\n")
+	assert.Contains(t, body, `countertwo{label1="value1"} 10`)
+	assert.Contains(t, body, `countertwo{label1="value\"2"} 11`)
+	assert.Contains(t, body, "# TYPE gaugeone gauge\ngaugeone 12\n")
+	assert.Contains(t, body, `gaugetwo{label2="value3"} 13`)
+	assert.Contains(t, body, "# TYPE timerone summary\n")
+	assert.Contains(t, body, `timerone{label3="value4",quantile="0.5"} 2`)
+	assert.Contains(t, body, `timerone{label3="value4",quantile="0.99"} 3`)
+	assert.Contains(t, body, `timerone_sum{label3="value4"} 4`)
+	assert.Contains(t, body, `timerone_count{label3="value4"} 2`)
+
+	require.NoError(t, p.Close(context.Background()))
+}
+
+func TestPrometheusHistogram(t *testing.T) {
+	p := promFromYAML(t, `
+use_histogram_timing: true
+histogram_buckets: [ 2, 0.5, 1 ]
+`)
+
+	tmr := p.NewTimerCtor("timerone", "label")("foo")
+	tmr.Timing(int64(time.Millisecond * 100))
+	tmr.Timing(int64(time.Millisecond * 800))
+	tmr.Timing(int64(time.Second * 5))
+
+	body := getPromPage(t, p.HandlerFunc())
+
+	assert.Contains(t, body, `# TYPE timerone histogram
+timerone_bucket{label="foo",le="0.5"} 1
+timerone_bucket{label="foo",le="1"} 2
+timerone_bucket{label="foo",le="2"} 2
+timerone_bucket{label="foo",le="+Inf"} 3
+timerone_sum{label="foo"} 5.9
+timerone_count{label="foo"} 3
+`)
+}
+
+func TestPrometheusConflictingTypes(t *testing.T) {
+	p := promFromYAML(t, ``)
+
+	p.NewCounterCtor("foo", "a")("1").Incr(1)
+	p.NewGaugeCtor("foo", "a")("1").Set(10)
+	p.NewCounterCtor("foo", "b")("1").Incr(1)
+
+	body := getPromPage(t, p.HandlerFunc())
+	assert.Equal(t, "# TYPE foo counter\nfoo{a=\"1\"} 1\n", body)
+}
+
+func TestPrometheusPushGateway(t *testing.T) {
+	var mut sync.Mutex
+	var pushedPath, pushedBody, pushedUser string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		user, _, _ := r.BasicAuth()
+
+		mut.Lock()
+		pushedPath, pushedBody, pushedUser = r.URL.Path, string(body), user
+		mut.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	p := promFromYAML(t, `
+push_url: `+server.URL+`
+push_job_name: foo job
+push_basic_auth:
+  username: meow
+  password: woof
+`)
+
+	p.NewCounterCtor("counterone")().Incr(5)
+	require.NoError(t, p.Close(context.Background()))
+
+	mut.Lock()
+	defer mut.Unlock()
+	assert.Equal(t, "/metrics/job/foo job", pushedPath)
+	assert.Equal(t, "meow", pushedUser)
+	assert.Equal(t, "# TYPE counterone counter\ncounterone 5\n", pushedBody)
+}
+
+func TestPrometheusPushInterval(t *testing.T) {
+	pushed := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		select {
+		case pushed <- string(body):
+		default:
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	p := promFromYAML(t, `
+push_url: `+server.URL+`
+push_interval: 10ms
+`)
+	p.NewGaugeCtor("gaugeone")().Set(3)
+
+	select {
+	case body := <-pushed:
+		assert.Contains(t, body, "gaugeone 3")
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for push")
+	}
+	require.NoError(t, p.Close(context.Background()))
+}
+
+func TestPrometheusWithMapping(t *testing.T) {
+	conf := metrics.NewConfig()
+	conf.Type = "prometheus"
+	conf.Plugin = map[string]any{}
+	conf.Mapping = `
+root = if this == "drop_me" { deleted() } else { "renamed_" + this }
+meta env = "prod"
+`
+
+	ns, err := bundle.AllMetrics.Init(conf, mock.NewManager())
+	require.NoError(t, err)
+
+	ns.GetCounterVec("counter", "label").With("value").Incr(2)
+	ns.GetCounter("drop_me").Incr(2)
+
+	body := getPromPage(t, ns.HandlerFunc())
+	assert.Equal(t, "# TYPE renamed_counter counter\nrenamed_counter{env=\"prod\",label=\"value\"} 2\n", body)
+}
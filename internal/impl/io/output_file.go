@@ -10,15 +10,27 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/redpanda-data/benthos/v4/internal/codec"
+	"github.com/redpanda-data/benthos/v4/internal/impl/pure"
 	"github.com/redpanda-data/benthos/v4/public/service"
 )
 
 const (
-	fileOutputFieldPath  = "path"
-	fileOutputFieldCodec = "codec"
+	fileOutputFieldPath                   = "path"
+	fileOutputFieldCodec                  = "codec"
+	fileOutputFieldRolling                = "rolling"
+	fileOutputFieldRollingEnabled         = "enabled"
+	fileOutputFieldRollingMaxBytes        = "max_bytes"
+	fileOutputFieldRollingMaxMessages     = "max_messages"
+	fileOutputFieldRollingMaxAge          = "max_age"
+	fileOutputFieldRollingFileName        = "file_name"
+	fileOutputFieldRollingTimestampFormat = "timestamp_format"
+	fileOutputFieldRollingCompression     = "compression"
 )
 
 func fileOutputSpec() *service.ConfigSpec {
@@ -26,7 +38,13 @@ func fileOutputSpec() *service.ConfigSpec {
 		Stable().
 		Categories("Local").
 		Summary(`Writes messages to files on disk based on a chosen codec.`).
-		Description(`Messages can be written to different files by using xref:configuration:interpolation.adoc#bloblang-queries[interpolation functions] in the path field. However, only one file is ever open at a given time, and therefore when the path changes the previously open file is closed.`).
+		Description(`Messages can be written to different files by using xref:configuration:interpolation.adoc#bloblang-queries[interpolation functions] in the path field. However, only one file is ever open at a given time, and therefore when the path changes the previously open file is closed.
+
+== Rolling files
+
+When the field `+"`rolling.enabled`"+` is set to `+"`true`"+` messages are not written to the path directly. Instead, they are written to a hidden temporary file within the same directory, named after the path with a `+"`.`"+` prefix and a `+"`.tmp`"+` suffix, and once that file is closed it is renamed to a name derived from the path and the `+"<<rolling-file_name, `rolling.file_name`>>"+` template. Since a rename is atomic, processes watching the directory never observe a partially written file.
+
+A file is closed, and therefore rolled, once it reaches any of the configured limits, when the path of a message differs from the currently open file, and when the output shuts down. A temporary file left behind by a previous run that was not shut down gracefully is rolled before it would be overwritten.`).
 		Fields(
 			service.NewInterpolatedStringField(fileOutputFieldPath).
 				Description("The file to write to, if the file does not yet exist it will be created.").
@@ -37,12 +55,66 @@ func fileOutputSpec() *service.ConfigSpec {
 				).
 				Version("3.33.0"),
 			service.NewInternalField(codec.NewWriterDocs(fileOutputFieldCodec)).Version("3.33.0").Default("lines"),
+			service.NewObjectField(fileOutputFieldRolling,
+				service.NewBoolField(fileOutputFieldRollingEnabled).
+					Description("Whether files are rolled.").
+					Default(false),
+				service.NewIntField(fileOutputFieldRollingMaxBytes).
+					Description("The number of bytes after which a file is rolled, or `0` to disable this limit. Sizes are measured before any compression is applied, and a single message larger than this limit is written to a file of its own.").
+					Default(0),
+				service.NewIntField(fileOutputFieldRollingMaxMessages).
+					Description("The number of messages after which a file is rolled, or `0` to disable this limit.").
+					Default(0),
+				service.NewDurationField(fileOutputFieldRollingMaxAge).
+					Description("The maximum period of time a file remains open after its first message is written before it is rolled, or `0s` to disable this limit. Files are rolled once this period elapses even when no further messages arrive.").
+					Default("0s").
+					Example("1h"),
+				service.NewStringField(fileOutputFieldRollingFileName).
+					Description("A template for the name given to a file once it is rolled. The placeholders `{dir}`, `{base}`, `{name}` and `{ext}` are replaced with the directory, file name, file name without extension and extension of the path respectively, `{timestamp}` is replaced with the time at which the file was opened and `{seq}` is replaced with a sequence number that is incremented until the name does not clash with an existing file. When the template does not contain `{seq}` existing files of the same name are overwritten. If a compression algorithm is configured its file extension is appended to the name.").
+					Default("{dir}/{name}-{timestamp}-{seq}{ext}").
+					Example("{dir}/archive/{name}.{seq}{ext}"),
+				service.NewStringField(fileOutputFieldRollingTimestampFormat).
+					Description("The format of the `{timestamp}` placeholder, expressed as a Go time layout and rendered in UTC.").
+					Default("20060102T150405Z").
+					Advanced(),
+				service.NewStringEnumField(fileOutputFieldRollingCompression, append([]string{"none"}, pure.CompressWriterAlgsList()...)...).
+					Description("A compression algorithm to apply to files as they are written.").
+					Default("none"),
+			).
+				Description("Roll the files being written once they reach a size, a number of messages or an age, and only expose them under their final name once they are complete.").
+				Advanced().
+				Version("4.49.0"),
+		).
+		Example(
+			"Rolling Logs",
+			"Writes messages to a directory as gzip compressed files of at most one hundred thousand lines each, where no file remains open for longer than an hour.",
+			`
+output:
+  file:
+    path: /var/log/events/events.jsonl
+    codec: lines
+    rolling:
+      enabled: true
+      max_messages: 100000
+      max_age: 1h
+      compression: gzip
+`,
 		)
 }
 
+type fileRollingConfig struct {
+	MaxBytes        int
+	MaxMessages     int
+	MaxAge          time.Duration
+	FileName        string
+	TimestampFormat string
+	Compression     string
+}
+
 type fileOutputConfig struct {
-	Path  *service.InterpolatedString
-	Codec string
+	Path    *service.InterpolatedString
+	Codec   string
+	Rolling *fileRollingConfig
 }
 
 func fileOutputConfigFromParsed(pConf *service.ParsedConfig) (conf fileOutputConfig, err error) {
@@ -52,6 +124,32 @@ func fileOutputConfigFromParsed(pConf *service.ParsedConfig) (conf fileOutputCon
 	if conf.Codec, err = pConf.FieldString(fileOutputFieldCodec); err != nil {
 		return
 	}
+	rConf := pConf.Namespace(fileOutputFieldRolling)
+	var rollingEnabled bool
+	if rollingEnabled, err = rConf.FieldBool(fileOutputFieldRollingEnabled); err != nil {
+		return
+	}
+	if rollingEnabled {
+		conf.Rolling = &fileRollingConfig{}
+		if conf.Rolling.MaxBytes, err = rConf.FieldInt(fileOutputFieldRollingMaxBytes); err != nil {
+			return
+		}
+		if conf.Rolling.MaxMessages, err = rConf.FieldInt(fileOutputFieldRollingMaxMessages); err != nil {
+			return
+		}
+		if conf.Rolling.MaxAge, err = rConf.FieldDuration(fileOutputFieldRollingMaxAge); err != nil {
+			return
+		}
+		if conf.Rolling.FileName, err = rConf.FieldString(fileOutputFieldRollingFileName); err != nil {
+			return
+		}
+		if conf.Rolling.TimestampFormat, err = rConf.FieldString(fileOutputFieldRollingTimestampFormat); err != nil {
+			return
+		}
+		if conf.Rolling.Compression, err = rConf.FieldString(fileOutputFieldRollingCompression); err != nil {
+			return
+		}
+	}
 	return
 }
 
@@ -64,7 +162,7 @@ func init() {
 			}
 
 			mif = 1
-			out, err = newFileWriter(conf.Path, conf.Codec, res)
+			out, err = newFileWriter(conf.Path, conf.Codec, conf.Rolling, res)
 			return
 		})
 	if err != nil {
@@ -74,6 +172,83 @@ func init() {
 
 //------------------------------------------------------------------------------
 
+// fileRollingCompressionExts are the file extensions appended to the names of
+// rolled files for compression algorithms where it differs from their name.
+var fileRollingCompressionExts = map[string]string{
+	"gzip":   ".gz",
+	"pgzip":  ".gz",
+	"flate":  ".deflate",
+	"snappy": ".sz",
+}
+
+type fileRolling struct {
+	maxBytes        int
+	maxMessages     int
+	maxAge          time.Duration
+	fileName        string
+	timestampFormat string
+	compress        pure.CompressWriter
+	compressExt     string
+}
+
+func newFileRolling(conf *fileRollingConfig) (*fileRolling, error) {
+	r := &fileRolling{
+		maxBytes:        conf.MaxBytes,
+		maxMessages:     conf.MaxMessages,
+		maxAge:          conf.MaxAge,
+		fileName:        conf.FileName,
+		timestampFormat: conf.TimestampFormat,
+	}
+	if r.fileName == "" {
+		return nil, errors.New("rolling file name template must not be empty")
+	}
+	if conf.Compression != "" && conf.Compression != "none" {
+		var err error
+		if r.compress, err = pure.StrToCompressWriter(conf.Compression); err != nil {
+			return nil, err
+		}
+		var exists bool
+		if r.compressExt, exists = fileRollingCompressionExts[conf.Compression]; !exists {
+			r.compressExt = "." + conf.Compression
+		}
+	}
+	return r, nil
+}
+
+// tempPath returns the path of the file that messages are written to until it
+// is rolled.
+func (r *fileRolling) tempPath(path string) string {
+	dir, base := filepath.Split(path)
+	return filepath.Join(dir, "."+base+".tmp")
+}
+
+// name renders the template of a rolled file.
+func (r *fileRolling) name(path string, seq int, opened time.Time) string {
+	dir, base := filepath.Split(path)
+	ext := filepath.Ext(base)
+	return strings.NewReplacer(
+		"{dir}", filepath.Clean(dir),
+		"{base}", base,
+		"{name}", strings.TrimSuffix(base, ext),
+		"{ext}", ext,
+		"{timestamp}", opened.UTC().Format(r.timestampFormat),
+		"{seq}", strconv.Itoa(seq),
+	).Replace(r.fileName) + r.compressExt
+}
+
+// fileRollState tracks the currently open file of a rolling writer.
+type fileRollState struct {
+	tmpPath  string
+	opened   time.Time
+	bytes    int
+	messages int
+
+	// Incremented for each opened file so that an expiry timer is able to
+	// tell whether the file it was started for is still open.
+	gen   uint64
+	timer *time.Timer
+}
+
 type fileWriter struct {
 	log *service.Logger
 	nm  *service.Resources
@@ -81,24 +256,44 @@ type fileWriter struct {
 	path       *service.InterpolatedString
 	suffixFn   codec.SuffixFn
 	appendMode bool
+	encCtor    codec.WriterConstructor
+	rolling    *fileRolling
 
 	handleMut  sync.Mutex
 	handlePath string
 	handle     io.WriteCloser
+	encoder    codec.Writer
+	rollState  fileRollState
+	rollSeqs   map[string]int
 }
 
-func newFileWriter(path *service.InterpolatedString, codecStr string, mgr *service.Resources) (*fileWriter, error) {
-	codec, appendMode, err := codec.GetWriter(codecStr)
-	if err != nil {
+func newFileWriter(path *service.InterpolatedString, codecStr string, rolling *fileRollingConfig, mgr *service.Resources) (*fileWriter, error) {
+	w := &fileWriter{
+		path: path,
+		log:  mgr.Logger(),
+		nm:   mgr,
+	}
+
+	var err error
+	var isStream bool
+	if w.encCtor, isStream, err = codec.GetStreamWriter(codecStr); err != nil {
 		return nil, err
 	}
-	return &fileWriter{
-		suffixFn:   codec,
-		appendMode: appendMode,
-		path:       path,
-		log:        mgr.Logger(),
-		nm:         mgr,
-	}, nil
+	if !isStream {
+		if w.suffixFn, w.appendMode, err = codec.GetWriter(codecStr); err != nil {
+			return nil, err
+		}
+	}
+	if rolling != nil {
+		if !w.appendMode && w.encCtor == nil {
+			return nil, fmt.Errorf("codec %v does not support rolling files as it writes each message to a file of its own", codecStr)
+		}
+		if w.rolling, err = newFileRolling(rolling); err != nil {
+			return nil, err
+		}
+		w.rollSeqs = map[string]int{}
+	}
+	return w, nil
 }
 
 //------------------------------------------------------------------------------
@@ -108,6 +303,14 @@ func (w *fileWriter) Connect(ctx context.Context) error {
 }
 
 func (w *fileWriter) writeTo(wtr io.Writer, p *service.Message) error {
+	if w.encoder != nil {
+		v, err := p.AsStructured()
+		if err != nil {
+			return err
+		}
+		return w.encoder.Write(v)
+	}
+
 	mBytes, err := p.AsBytes()
 	if err != nil {
 		return err
@@ -133,22 +336,96 @@ func (w *fileWriter) Write(ctx context.Context, msg *service.Message) error {
 	}
 	path = filepath.Clean(path)
 
+	var size int
+	if w.rolling != nil && w.rolling.maxBytes > 0 {
+		mBytes, err := msg.AsBytes()
+		if err != nil {
+			return err
+		}
+		size = len(mBytes)
+	}
+
 	w.handleMut.Lock()
 	defer w.handleMut.Unlock()
 
-	if w.handle != nil && path == w.handlePath {
-		return w.writeTo(w.handle, msg)
+	if w.handle != nil && (path != w.handlePath || w.rollDue(size)) {
+		if err := w.closeHandle(); err != nil {
+			return err
+		}
 	}
-	if w.handle != nil {
-		if err := w.handle.Close(); err != nil {
+
+	opened := false
+	if w.handle == nil {
+		if err := w.openHandle(path); err != nil {
 			return err
 		}
+		opened = true
+	}
+
+	if err := w.writeTo(w.handle, msg); err != nil {
+		if opened {
+			w.discardHandle()
+		}
+		return err
+	}
+
+	if w.rolling != nil {
+		w.rollState.bytes += size
+		w.rollState.messages++
+		if w.rollLimitReached() {
+			return w.closeHandle()
+		}
+		return nil
+	}
+	if !w.appendMode && w.encoder == nil {
+		w.encoder = nil
+		_ = w.handle.Close()
+		w.handle = nil
+	}
+	return nil
+}
+
+// rollDue returns true when writing a message of the given size to the
+// currently open file would exceed the limits of a rolling writer.
+func (w *fileWriter) rollDue(size int) bool {
+	if w.rolling == nil {
+		return false
+	}
+	if w.rolling.maxBytes > 0 && w.rollState.bytes > 0 && w.rollState.bytes+size > w.rolling.maxBytes {
+		return true
+	}
+	return w.rollLimitReached()
+}
+
+// rollLimitReached returns true when the currently open file of a rolling
+// writer has reached any of its limits.
+func (w *fileWriter) rollLimitReached() bool {
+	if w.rolling.maxBytes > 0 && w.rollState.bytes >= w.rolling.maxBytes {
+		return true
 	}
+	if w.rolling.maxMessages > 0 && w.rollState.messages >= w.rolling.maxMessages {
+		return true
+	}
+	return w.rolling.maxAge > 0 && time.Since(w.rollState.opened) >= w.rolling.maxAge
+}
 
-	flag := os.O_CREATE | os.O_RDWR
-	if w.appendMode {
+func (w *fileWriter) openHandle(path string) error {
+	openPath, flag := path, os.O_CREATE|os.O_RDWR
+	switch {
+	case w.rolling != nil:
+		openPath = w.rolling.tempPath(path)
+		if _, err := w.nm.FS().Stat(openPath); err == nil {
+			// A previous run exited without rolling this file, and so we roll
+			// it now rather than overwriting its contents.
+			w.log.Warnf("Rolling file %v left behind by a previous run", openPath)
+			if err := w.rollFile(openPath, path, time.Now()); err != nil {
+				return err
+			}
+		}
+		flag |= os.O_TRUNC
+	case w.appendMode:
 		flag |= os.O_APPEND
-	} else {
+	default:
 		flag |= os.O_TRUNC
 	}
 
@@ -156,7 +433,7 @@ func (w *fileWriter) Write(ctx context.Context, msg *service.Message) error {
 		return err
 	}
 
-	file, err := w.nm.FS().OpenFile(path, flag, fs.FileMode(0o666))
+	file, err := w.nm.FS().OpenFile(openPath, flag, fs.FileMode(0o666))
 	if err != nil {
 		return err
 	}
@@ -167,28 +444,127 @@ func (w *fileWriter) Write(ctx context.Context, msg *service.Message) error {
 		return errors.New("failed to open file for writing")
 	}
 
-	w.handlePath = path
-	if err := w.writeTo(handle, msg); err != nil {
-		_ = handle.Close()
-		return err
+	if w.rolling != nil && w.rolling.compress != nil {
+		cw, err := w.rolling.compress(-1, handle)
+		if err != nil {
+			_ = handle.Close()
+			return err
+		}
+		if wc, ok := cw.(io.WriteCloser); ok {
+			handle = wc
+		} else {
+			handle = &pure.CombinedWriteCloser{Primary: cw, Sink: handle}
+		}
 	}
 
-	if w.appendMode {
-		w.handle = handle
-	} else {
-		_ = handle.Close()
+	if w.encCtor != nil {
+		if w.encoder, err = w.encCtor(handle); err != nil {
+			_ = handle.Close()
+			return err
+		}
+	}
+
+	w.handle, w.handlePath = handle, path
+	if w.rolling != nil {
+		gen := w.rollState.gen + 1
+		w.rollState = fileRollState{
+			tmpPath: openPath,
+			opened:  time.Now(),
+			gen:     gen,
+		}
+		if w.rolling.maxAge > 0 {
+			w.rollState.timer = time.AfterFunc(w.rolling.maxAge, func() {
+				w.rollExpired(gen)
+			})
+		}
 	}
 	return nil
 }
 
+// rollExpired rolls the currently open file once it reaches its maximum age,
+// provided that it is the same file the timer was started for.
+func (w *fileWriter) rollExpired(gen uint64) {
+	w.handleMut.Lock()
+	defer w.handleMut.Unlock()
+
+	if w.handle == nil || w.rollState.gen != gen {
+		return
+	}
+	if err := w.closeHandle(); err != nil {
+		w.log.Errorf("Failed to roll file %v: %v", w.rollState.tmpPath, err)
+	}
+}
+
+// rollFile renames a completed file to its rolled name.
+func (w *fileWriter) rollFile(tmpPath, path string, opened time.Time) error {
+	seq := w.rollSeqs[path]
+	for {
+		seq++
+		target := w.rolling.name(path, seq, opened)
+		if strings.Contains(w.rolling.fileName, "{seq}") {
+			if _, err := w.nm.FS().Stat(target); err == nil {
+				continue
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+		if err := w.nm.FS().MkdirAll(filepath.Dir(target), fs.FileMode(0o777)); err != nil {
+			return err
+		}
+		if err := w.nm.FS().Rename(tmpPath, target); err != nil {
+			return err
+		}
+		w.rollSeqs[path] = seq
+		return nil
+	}
+}
+
+// closeHandle finalises the encoding of the currently open file and closes it,
+// rolling it when the writer is configured to.
+func (w *fileWriter) closeHandle() error {
+	var err error
+	if w.encoder != nil {
+		err = w.encoder.Close()
+		w.encoder = nil
+	}
+	if cErr := w.handle.Close(); err == nil {
+		err = cErr
+	}
+	w.handle = nil
+
+	if w.rolling != nil {
+		if w.rollState.timer != nil {
+			w.rollState.timer.Stop()
+		}
+		if err == nil {
+			err = w.rollFile(w.rollState.tmpPath, w.handlePath, w.rollState.opened)
+		}
+	}
+	return err
+}
+
+// discardHandle closes the currently open file without finalising it, removing
+// the file of a rolling writer as its contents are incomplete.
+func (w *fileWriter) discardHandle() {
+	w.encoder = nil
+	_ = w.handle.Close()
+	w.handle = nil
+
+	if w.rolling != nil {
+		if w.rollState.timer != nil {
+			w.rollState.timer.Stop()
+		}
+		_ = w.nm.FS().Remove(w.rollState.tmpPath)
+	}
+}
+
 func (w *fileWriter) Close(ctx context.Context) error {
 	w.handleMut.Lock()
 	defer w.handleMut.Unlock()
 
 	var err error
 	if w.handle != nil {
-		err = w.handle.Close()
-		w.handle = nil
+		err = w.closeHandle()
 	}
 	return err
 }
@@ -0,0 +1,358 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package io
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/internal/codec"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestFileOutputLines(t *testing.T) {
+	dir := t.TempDir()
+
+	path, err := service.NewInterpolatedString(filepath.Join(dir, `${! meta("file") }.txt`))
+	require.NoError(t, err)
+
+	w, err := newFileWriter(path, "lines", nil, service.MockResources())
+	require.NoError(t, err)
+
+	tCtx := context.Background()
+	for _, m := range [][2]string{{"foo", "a"}, {"bar", "a"}, {"baz", "b"}, {"buz", "a"}} {
+		msg := service.NewMessage([]byte(m[0]))
+		msg.MetaSetMut("file", m[1])
+		require.NoError(t, w.Write(tCtx, msg))
+	}
+	require.NoError(t, w.Close(tCtx))
+
+	b, err := os.ReadFile(filepath.Join(dir, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "foo\nbar\nbuz\n", string(b))
+
+	b, err = os.ReadFile(filepath.Join(dir, "b.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "baz\n", string(b))
+}
+
+func TestFileOutputParquet(t *testing.T) {
+	dir := t.TempDir()
+
+	path, err := service.NewInterpolatedString(filepath.Join(dir, `${! meta("file") }.parquet`))
+	require.NoError(t, err)
+
+	w, err := newFileWriter(path, "parquet:compression=snappy", nil, service.MockResources())
+	require.NoError(t, err)
+
+	tCtx := context.Background()
+	for i, f := range []string{"a", "a", "b", "b", "b"} {
+		msg := service.NewMessage(fmt.Appendf(nil, `{"id":%v,"file":"%v"}`, i, f))
+		msg.MetaSetMut("file", f)
+		require.NoError(t, w.Write(tCtx, msg))
+	}
+
+	// Messages that don't fit the inferred schema are rejected.
+	msg := service.NewMessage([]byte(`{"id":"nope"}`))
+	msg.MetaSetMut("file", "b")
+	require.Error(t, w.Write(tCtx, msg))
+
+	require.NoError(t, w.Close(tCtx))
+
+	for f, exp := range map[string][]any{
+		"a": {
+			map[string]any{"id": int64(0), "file": "a"},
+			map[string]any{"id": int64(1), "file": "a"},
+		},
+		"b": {
+			map[string]any{"id": int64(2), "file": "b"},
+			map[string]any{"id": int64(3), "file": "b"},
+			map[string]any{"id": int64(4), "file": "b"},
+		},
+	} {
+		b, err := os.ReadFile(filepath.Join(dir, f+".parquet"))
+		require.NoError(t, err)
+
+		r, err := codec.NewParquetReaderFromBytes(b)
+		require.NoError(t, err)
+
+		var rows []any
+		for {
+			row, err := r.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			rows = append(rows, row)
+		}
+		assert.Equal(t, exp, rows, f)
+	}
+}
+
+func TestFileOutputCodecErrors(t *testing.T) {
+	path, err := service.NewInterpolatedString("foo.txt")
+	require.NoError(t, err)
+
+	_, err = newFileWriter(path, "nope", nil, service.MockResources())
+	require.Error(t, err)
+
+	_, err = newFileWriter(path, "parquet:compression=nope", nil, service.MockResources())
+	require.Error(t, err)
+
+	_, err = newFileWriter(path, "all-bytes", &fileRollingConfig{FileName: "{dir}/{seq}"}, service.MockResources())
+	require.Error(t, err)
+
+	_, err = newFileWriter(path, "lines", &fileRollingConfig{FileName: "{dir}/{seq}", Compression: "bzip2"}, service.MockResources())
+	require.Error(t, err)
+}
+
+func testRollingConfig(mutator func(c *fileRollingConfig)) *fileRollingConfig {
+	conf := &fileRollingConfig{
+		FileName:        "{dir}/{name}-{seq}{ext}",
+		TimestampFormat: "20060102T150405Z",
+		Compression:     "none",
+	}
+	mutator(conf)
+	return conf
+}
+
+func readDirFiles(t testing.TB, dir string) map[string]string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, e := range entries {
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		require.NoError(t, err)
+		files[e.Name()] = string(b)
+	}
+	return files
+}
+
+func TestFileOutputRollingMessages(t *testing.T) {
+	dir := t.TempDir()
+
+	path, err := service.NewInterpolatedString(filepath.Join(dir, `${! meta("file") }.txt`))
+	require.NoError(t, err)
+
+	w, err := newFileWriter(path, "lines", testRollingConfig(func(c *fileRollingConfig) {
+		c.MaxMessages = 2
+	}), service.MockResources())
+	require.NoError(t, err)
+
+	tCtx := context.Background()
+	for _, m := range [][2]string{{"foo", "a"}, {"bar", "a"}, {"baz", "a"}, {"buz", "b"}, {"bev", "a"}} {
+		msg := service.NewMessage([]byte(m[0]))
+		msg.MetaSetMut("file", m[1])
+		require.NoError(t, w.Write(tCtx, msg))
+	}
+
+	// The file reaching its limit is rolled immediately, whereas files
+	// still being written remain hidden.
+	assert.Equal(t, map[string]string{
+		"a-1.txt":    "foo\nbar\n",
+		"a-2.txt":    "baz\n",
+		"b-1.txt":    "buz\n",
+		".a.txt.tmp": "bev\n",
+	}, readDirFiles(t, dir))
+
+	require.NoError(t, w.Close(tCtx))
+
+	assert.Equal(t, map[string]string{
+		"a-1.txt": "foo\nbar\n",
+		"a-2.txt": "baz\n",
+		"a-3.txt": "bev\n",
+		"b-1.txt": "buz\n",
+	}, readDirFiles(t, dir))
+}
+
+func TestFileOutputRollingBytes(t *testing.T) {
+	dir := t.TempDir()
+
+	path, err := service.NewInterpolatedString(filepath.Join(dir, "data.txt"))
+	require.NoError(t, err)
+
+	w, err := newFileWriter(path, "lines", testRollingConfig(func(c *fileRollingConfig) {
+		c.MaxBytes = 10
+	}), service.MockResources())
+	require.NoError(t, err)
+
+	tCtx := context.Background()
+	for _, m := range []string{"abcd", "efgh", "ijk", "this is too long", "lm"} {
+		require.NoError(t, w.Write(tCtx, service.NewMessage([]byte(m))))
+	}
+	require.NoError(t, w.Close(tCtx))
+
+	assert.Equal(t, map[string]string{
+		"data-1.txt": "abcd\nefgh\n",
+		"data-2.txt": "ijk\n",
+		"data-3.txt": "this is too long\n",
+		"data-4.txt": "lm\n",
+	}, readDirFiles(t, dir))
+}
+
+func TestFileOutputRollingAge(t *testing.T) {
+	dir := t.TempDir()
+
+	path, err := service.NewInterpolatedString(filepath.Join(dir, "data.txt"))
+	require.NoError(t, err)
+
+	w, err := newFileWriter(path, "lines", testRollingConfig(func(c *fileRollingConfig) {
+		c.MaxAge = 50 * time.Millisecond
+	}), service.MockResources())
+	require.NoError(t, err)
+
+	tCtx := context.Background()
+	require.NoError(t, w.Write(tCtx, service.NewMessage([]byte("foo"))))
+	require.NoError(t, w.Write(tCtx, service.NewMessage([]byte("bar"))))
+
+	// The file is rolled once it expires without any further writes.
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(filepath.Join(dir, "data-1.txt"))
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, w.Write(tCtx, service.NewMessage([]byte("baz"))))
+	require.NoError(t, w.Close(tCtx))
+
+	assert.Equal(t, map[string]string{
+		"data-1.txt": "foo\nbar\n",
+		"data-2.txt": "baz\n",
+	}, readDirFiles(t, dir))
+}
+
+func TestFileOutputRollingCompression(t *testing.T) {
+	dir := t.TempDir()
+
+	path, err := service.NewInterpolatedString(filepath.Join(dir, "data.txt"))
+	require.NoError(t, err)
+
+	w, err := newFileWriter(path, "lines", testRollingConfig(func(c *fileRollingConfig) {
+		c.FileName = "{dir}/archive/{name}-{timestamp}{ext}"
+		c.TimestampFormat = "2006"
+		c.Compression = "gzip"
+	}), service.MockResources())
+	require.NoError(t, err)
+
+	tCtx := context.Background()
+	require.NoError(t, w.Write(tCtx, service.NewMessage([]byte("foo"))))
+	require.NoError(t, w.Write(tCtx, service.NewMessage([]byte("bar"))))
+	require.NoError(t, w.Close(tCtx))
+
+	f, err := os.Open(filepath.Join(dir, "archive", "data-"+time.Now().UTC().Format("2006")+".txt.gz"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+
+	zr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	b, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, "foo\nbar\n", string(b))
+}
+
+func TestFileOutputRollingLeftover(t *testing.T) {
+	dir := t.TempDir()
+
+	// Files left behind by a previous run are rolled rather than overwritten,
+	// and the sequence skips names that are already taken.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".data.txt.tmp"), []byte("foo\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data-1.txt"), []byte("bar\n"), 0o644))
+
+	path, err := service.NewInterpolatedString(filepath.Join(dir, "data.txt"))
+	require.NoError(t, err)
+
+	w, err := newFileWriter(path, "lines", testRollingConfig(func(c *fileRollingConfig) {}), service.MockResources())
+	require.NoError(t, err)
+
+	tCtx := context.Background()
+	require.NoError(t, w.Write(tCtx, service.NewMessage([]byte("baz"))))
+	require.NoError(t, w.Close(tCtx))
+
+	assert.Equal(t, map[string]string{
+		"data-1.txt": "bar\n",
+		"data-2.txt": "foo\n",
+		"data-3.txt": "baz\n",
+	}, readDirFiles(t, dir))
+}
+
+func TestFileOutputRollingParquet(t *testing.T) {
+	dir := t.TempDir()
+
+	path, err := service.NewInterpolatedString(filepath.Join(dir, "data.parquet"))
+	require.NoError(t, err)
+
+	w, err := newFileWriter(path, "parquet", testRollingConfig(func(c *fileRollingConfig) {
+		c.MaxMessages = 2
+	}), service.MockResources())
+	require.NoError(t, err)
+
+	tCtx := context.Background()
+	for i := range 3 {
+		require.NoError(t, w.Write(tCtx, service.NewMessage(fmt.Appendf(nil, `{"id":%v}`, i))))
+	}
+	require.NoError(t, w.Close(tCtx))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.Equal(t, []string{"data-1.parquet", "data-2.parquet"}, names)
+
+	b, err := os.ReadFile(filepath.Join(dir, "data-2.parquet"))
+	require.NoError(t, err)
+
+	r, err := codec.NewParquetReaderFromBytes(b)
+	require.NoError(t, err)
+
+	row, err := r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"id": int64(2)}, row)
+
+	_, err = r.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestFileOutputRollingConfig(t *testing.T) {
+	pConf, err := fileOutputSpec().ParseYAML(`path: /tmp/foo.txt`, nil)
+	require.NoError(t, err)
+
+	conf, err := fileOutputConfigFromParsed(pConf)
+	require.NoError(t, err)
+	assert.Nil(t, conf.Rolling)
+
+	pConf, err = fileOutputSpec().ParseYAML(`
+path: /tmp/foo.txt
+rolling:
+  enabled: true
+  max_messages: 10
+  max_age: 1m
+  compression: gzip
+`, nil)
+	require.NoError(t, err)
+
+	conf, err = fileOutputConfigFromParsed(pConf)
+	require.NoError(t, err)
+	assert.Equal(t, &fileRollingConfig{
+		MaxMessages:     10,
+		MaxAge:          time.Minute,
+		FileName:        "{dir}/{name}-{timestamp}-{seq}{ext}",
+		TimestampFormat: "20060102T150405Z",
+		Compression:     "gzip",
+	}, conf.Rolling)
+}
@@ -0,0 +1,5 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+// Package otlp contains component implementations that export telemetry to
+// Open Telemetry collectors using the OTLP protocol over HTTP or gRPC.
+package otlp
@@ -0,0 +1,314 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package otlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	otlpFieldHTTP                 = "http"
+	otlpFieldGRPC                 = "grpc"
+	otlpFieldAddress              = "address"
+	otlpFieldSecure               = "secure"
+	otlpFieldHeaders              = "headers"
+	otlpFieldService              = "service"
+	otlpFieldTags                 = "tags"
+	otlpFieldSampling             = "sampling"
+	otlpFieldSamplingEnabled      = "enabled"
+	otlpFieldSamplingRatio        = "ratio"
+	otlpFieldBatching             = "batching"
+	otlpFieldBatchingMaxQueueSize = "max_queue_size"
+	otlpFieldBatchingMaxBatchSize = "max_export_batch_size"
+	otlpFieldBatchingTimeout      = "batch_timeout"
+	otlpFieldBatchingExportTO     = "export_timeout"
+)
+
+func collectorListFields() []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewStringField(otlpFieldAddress).
+			Description("The endpoint of a collector to send tracing events to, without a URL scheme.").
+			Example("localhost:4318"),
+		service.NewBoolField(otlpFieldSecure).
+			Description("Connect to the collector over HTTPS.").
+			Default(false),
+		service.NewStringMapField(otlpFieldHeaders).
+			Description("A map of headers to add to each export request, useful for authenticating with a collector.").
+			Advanced().
+			Default(map[string]any{}),
+	}
+}
+
+func otlpSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Summary("Send tracing events to an https://opentelemetry.io/docs/collector/[Open Telemetry collector^].").
+		Description(`
+Spans are exported using the OTLP protocol over either HTTP or gRPC, and any number of collectors of each kind can be specified. Spans are batched before being exported, where the batching behaviour can be tuned with the `+"`batching`"+` fields.`).
+		Fields(
+			service.NewObjectListField(otlpFieldHTTP, collectorListFields()...).
+				Description("A list of http collectors.").
+				Default([]any{}),
+			service.NewObjectListField(otlpFieldGRPC, collectorListFields()...).
+				Description("A list of grpc collectors.").
+				Default([]any{}),
+			service.NewStringField(otlpFieldService).
+				Description("The name of the service in traces.").
+				Default("benthos"),
+			service.NewStringMapField(otlpFieldTags).
+				Description("A map of tags to add to all tracing spans as resource attributes.").
+				Advanced().
+				Default(map[string]any{}),
+			service.NewObjectField(otlpFieldSampling,
+				service.NewBoolField(otlpFieldSamplingEnabled).
+					Description("Whether to enable sampling.").
+					Default(false),
+				service.NewFloatField(otlpFieldSamplingRatio).
+					Description("Sets the ratio of traces to sample.").
+					Examples(0.85, 0.5).
+					Optional(),
+			).
+				Description("Settings for trace sampling. Sampling is recommended for high-volume production workloads.").
+				Advanced(),
+			service.NewObjectField(otlpFieldBatching,
+				service.NewIntField(otlpFieldBatchingMaxQueueSize).
+					Description("The maximum number of spans to buffer before they are dropped.").
+					Default(2048),
+				service.NewIntField(otlpFieldBatchingMaxBatchSize).
+					Description("The maximum number of spans to send within a single export request.").
+					Default(512),
+				service.NewDurationField(otlpFieldBatchingTimeout).
+					Description("The maximum period of time to wait before exporting a batch of spans.").
+					Default("5s"),
+				service.NewDurationField(otlpFieldBatchingExportTO).
+					Description("The maximum period of time an export request is allowed to take before it is abandoned.").
+					Default("30s"),
+			).
+				Description("Settings for how spans are batched before being exported.").
+				Advanced(),
+		)
+}
+
+func init() {
+	err := service.RegisterOtelTracerProvider("open_telemetry_collector", otlpSpec(),
+		func(conf *service.ParsedConfig) (trace.TracerProvider, error) {
+			c, err := otlpConfigFromParsed(conf)
+			if err != nil {
+				return nil, err
+			}
+			return newOtlp(c)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type collector struct {
+	address string
+	secure  bool
+	headers map[string]string
+}
+
+type sampleConfig struct {
+	enabled bool
+	ratio   float64
+}
+
+type batchConfig struct {
+	maxQueueSize  int
+	maxBatchSize  int
+	batchTimeout  time.Duration
+	exportTimeout time.Duration
+}
+
+type otlpConfig struct {
+	http     []collector
+	grpc     []collector
+	service  string
+	tags     map[string]string
+	sampling sampleConfig
+	batching batchConfig
+}
+
+func collectorsFromParsed(conf *service.ParsedConfig, name string) (collectors []collector, err error) {
+	var list []*service.ParsedConfig
+	if list, err = conf.FieldObjectList(name); err != nil {
+		return
+	}
+	for _, pc := range list {
+		var c collector
+		if c.address, err = pc.FieldString(otlpFieldAddress); err != nil {
+			return
+		}
+		if strings.Contains(c.address, "://") {
+			var u *url.URL
+			if u, err = url.Parse(c.address); err != nil {
+				err = fmt.Errorf("failed to parse collector address: %w", err)
+				return
+			}
+			c.address = u.Host
+		}
+		if c.address == "" {
+			err = errors.New("collector address must not be empty")
+			return
+		}
+		if c.secure, err = pc.FieldBool(otlpFieldSecure); err != nil {
+			return
+		}
+		if c.headers, err = pc.FieldStringMap(otlpFieldHeaders); err != nil {
+			return
+		}
+		collectors = append(collectors, c)
+	}
+	return
+}
+
+func otlpConfigFromParsed(conf *service.ParsedConfig) (c otlpConfig, err error) {
+	if c.http, err = collectorsFromParsed(conf, otlpFieldHTTP); err != nil {
+		return
+	}
+	if c.grpc, err = collectorsFromParsed(conf, otlpFieldGRPC); err != nil {
+		return
+	}
+	if c.service, err = conf.FieldString(otlpFieldService); err != nil {
+		return
+	}
+	if c.tags, err = conf.FieldStringMap(otlpFieldTags); err != nil {
+		return
+	}
+
+	sConf := conf.Namespace(otlpFieldSampling)
+	if c.sampling.enabled, err = sConf.FieldBool(otlpFieldSamplingEnabled); err != nil {
+		return
+	}
+	c.sampling.ratio = 1
+	if sConf.Contains(otlpFieldSamplingRatio) {
+		if c.sampling.ratio, err = sConf.FieldFloat(otlpFieldSamplingRatio); err != nil {
+			return
+		}
+		if c.sampling.ratio < 0 || c.sampling.ratio > 1 {
+			err = fmt.Errorf("sampling ratio %v must be between 0 and 1", c.sampling.ratio)
+			return
+		}
+	}
+
+	bConf := conf.Namespace(otlpFieldBatching)
+	if c.batching.maxQueueSize, err = bConf.FieldInt(otlpFieldBatchingMaxQueueSize); err != nil {
+		return
+	}
+	if c.batching.maxBatchSize, err = bConf.FieldInt(otlpFieldBatchingMaxBatchSize); err != nil {
+		return
+	}
+	if c.batching.maxQueueSize <= 0 || c.batching.maxBatchSize <= 0 {
+		err = errors.New("batching queue and export batch sizes must be greater than zero")
+		return
+	}
+	if c.batching.batchTimeout, err = bConf.FieldDuration(otlpFieldBatchingTimeout); err != nil {
+		return
+	}
+	if c.batching.exportTimeout, err = bConf.FieldDuration(otlpFieldBatchingExportTO); err != nil {
+		return
+	}
+	return
+}
+
+//------------------------------------------------------------------------------
+
+func newOtlp(config otlpConfig) (trace.TracerProvider, error) {
+	ctx := context.TODO()
+
+	attrs := make([]attribute.KeyValue, 0, len(config.tags)+1)
+	attrs = append(attrs, semconv.ServiceNameKey.String(config.service))
+	for k, v := range config.tags {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+
+	var opts []tracesdk.TracerProviderOption
+	opts = append(opts, tracesdk.WithResource(resource.NewWithAttributes(semconv.SchemaURL, attrs...)))
+
+	if config.sampling.enabled {
+		opts = append(opts, tracesdk.WithSampler(tracesdk.ParentBased(tracesdk.TraceIDRatioBased(config.sampling.ratio))))
+	}
+
+	var err error
+	if opts, err = addGrpcCollectors(ctx, config.grpc, config.batching, opts); err != nil {
+		return nil, err
+	}
+	if opts, err = addHTTPCollectors(ctx, config.http, config.batching, opts); err != nil {
+		return nil, err
+	}
+
+	// Span contexts are injected into and extracted from messages using the
+	// global propagator, which is a noop unless explicitly set.
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return tracesdk.NewTracerProvider(opts...), nil
+}
+
+func batcherOpt(exp *otlptrace.Exporter, bConf batchConfig) tracesdk.TracerProviderOption {
+	return tracesdk.WithBatcher(exp,
+		tracesdk.WithMaxQueueSize(bConf.maxQueueSize),
+		tracesdk.WithMaxExportBatchSize(bConf.maxBatchSize),
+		tracesdk.WithBatchTimeout(bConf.batchTimeout),
+		tracesdk.WithExportTimeout(bConf.exportTimeout),
+	)
+}
+
+func addGrpcCollectors(ctx context.Context, collectors []collector, bConf batchConfig, opts []tracesdk.TracerProviderOption) ([]tracesdk.TracerProviderOption, error) {
+	for _, c := range collectors {
+		clientOpts := []otlptracegrpc.Option{
+			otlptracegrpc.WithEndpoint(c.address),
+		}
+		if !c.secure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+		if len(c.headers) > 0 {
+			clientOpts = append(clientOpts, otlptracegrpc.WithHeaders(c.headers))
+		}
+
+		exp, err := otlptrace.New(ctx, otlptracegrpc.NewClient(clientOpts...))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, batcherOpt(exp, bConf))
+	}
+	return opts, nil
+}
+
+func addHTTPCollectors(ctx context.Context, collectors []collector, bConf batchConfig, opts []tracesdk.TracerProviderOption) ([]tracesdk.TracerProviderOption, error) {
+	for _, c := range collectors {
+		clientOpts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(c.address),
+		}
+		if !c.secure {
+			clientOpts = append(clientOpts, otlptracehttp.WithInsecure())
+		}
+		if len(c.headers) > 0 {
+			clientOpts = append(clientOpts, otlptracehttp.WithHeaders(c.headers))
+		}
+
+		exp, err := otlptrace.New(ctx, otlptracehttp.NewClient(clientOpts...))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, batcherOpt(exp, bConf))
+	}
+	return opts, nil
+}
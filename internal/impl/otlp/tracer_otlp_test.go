@@ -0,0 +1,261 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package otlp
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+)
+
+type spanReceiver struct {
+	mut   sync.Mutex
+	spans map[string]map[string]string
+}
+
+func newSpanReceiver() *spanReceiver {
+	return &spanReceiver{spans: map[string]map[string]string{}}
+}
+
+func (s *spanReceiver) add(req *coltracepb.ExportTraceServiceRequest) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	for _, rs := range req.GetResourceSpans() {
+		resAttrs := map[string]string{}
+		for _, kv := range rs.GetResource().GetAttributes() {
+			resAttrs[kv.GetKey()] = kv.GetValue().GetStringValue()
+		}
+		for _, ss := range rs.GetScopeSpans() {
+			for _, span := range ss.GetSpans() {
+				s.spans[span.GetName()] = resAttrs
+			}
+		}
+	}
+}
+
+func (s *spanReceiver) get() map[string]map[string]string {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	m := make(map[string]map[string]string, len(s.spans))
+	for k, v := range s.spans {
+		m[k] = v
+	}
+	return m
+}
+
+func (s *spanReceiver) httpHandler(t testing.TB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var req coltracepb.ExportTraceServiceRequest
+		require.NoError(t, proto.Unmarshal(body, &req))
+		s.add(&req)
+
+		resBytes, err := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+		require.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		_, _ = w.Write(resBytes)
+	}
+}
+
+type grpcTraceServer struct {
+	coltracepb.UnimplementedTraceServiceServer
+	recv *spanReceiver
+}
+
+func (g *grpcTraceServer) Export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	g.recv.add(req)
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func shutdownProvider(t testing.TB, tp any) {
+	t.Helper()
+
+	shutter, ok := tp.(interface {
+		Shutdown(context.Context) error
+	})
+	require.True(t, ok)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+	require.NoError(t, shutter.Shutdown(ctx))
+}
+
+func TestOTLPTracerHTTP(t *testing.T) {
+	recv := newSpanReceiver()
+	server := httptest.NewServer(recv.httpHandler(t))
+	t.Cleanup(server.Close)
+
+	pConf, err := otlpSpec().ParseYAML(`
+http:
+  - address: `+server.URL+`
+service: meow
+tags:
+  foo: bar
+`, nil)
+	require.NoError(t, err)
+
+	conf, err := otlpConfigFromParsed(pConf)
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimPrefix(server.URL, "http://"), conf.http[0].address)
+
+	tp, err := newOtlp(conf)
+	require.NoError(t, err)
+
+	_, span := tp.Tracer("test").Start(context.Background(), "woof")
+	span.End()
+
+	shutdownProvider(t, tp)
+
+	assert.Equal(t, map[string]map[string]string{
+		"woof": {
+			"service.name": "meow",
+			"foo":          "bar",
+		},
+	}, recv.get())
+}
+
+func TestOTLPTracerGRPC(t *testing.T) {
+	recv := newSpanReceiver()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	gServer := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(gServer, &grpcTraceServer{recv: recv})
+	go func() {
+		_ = gServer.Serve(lis)
+	}()
+	t.Cleanup(gServer.Stop)
+
+	pConf, err := otlpSpec().ParseYAML(`
+grpc:
+  - address: `+lis.Addr().String()+`
+batching:
+  max_export_batch_size: 1
+`, nil)
+	require.NoError(t, err)
+
+	conf, err := otlpConfigFromParsed(pConf)
+	require.NoError(t, err)
+
+	tp, err := newOtlp(conf)
+	require.NoError(t, err)
+
+	for _, name := range []string{"foo", "bar"} {
+		_, span := tp.Tracer("test").Start(context.Background(), name)
+		span.End()
+	}
+
+	shutdownProvider(t, tp)
+
+	spans := recv.get()
+	assert.Contains(t, spans, "foo")
+	assert.Contains(t, spans, "bar")
+	assert.Equal(t, "benthos", spans["foo"]["service.name"])
+}
+
+func TestOTLPTracerSamplingRatio(t *testing.T) {
+	recv := newSpanReceiver()
+	server := httptest.NewServer(recv.httpHandler(t))
+	t.Cleanup(server.Close)
+
+	pConf, err := otlpSpec().ParseYAML(`
+http:
+  - address: `+server.URL+`
+sampling:
+  enabled: true
+  ratio: 0
+`, nil)
+	require.NoError(t, err)
+
+	conf, err := otlpConfigFromParsed(pConf)
+	require.NoError(t, err)
+
+	tp, err := newOtlp(conf)
+	require.NoError(t, err)
+
+	_, span := tp.Tracer("test").Start(context.Background(), "woof")
+	span.End()
+
+	shutdownProvider(t, tp)
+	assert.Empty(t, recv.get())
+}
+
+func TestOTLPTracerBadConfig(t *testing.T) {
+	for _, confStr := range []string{
+		`sampling: { enabled: true, ratio: 1.5 }`,
+		`batching: { max_queue_size: 0 }`,
+		`http: [ { address: "" } ]`,
+	} {
+		pConf, err := otlpSpec().ParseYAML(confStr, nil)
+		require.NoError(t, err, confStr)
+
+		_, err = otlpConfigFromParsed(pConf)
+		assert.Error(t, err, confStr)
+	}
+}
+
+func TestOTLPTracerStreamSpans(t *testing.T) {
+	recv := newSpanReceiver()
+	server := httptest.NewServer(recv.httpHandler(t))
+	t.Cleanup(server.Close)
+
+	sb := service.NewStreamBuilder()
+	require.NoError(t, sb.SetYAML(`
+input:
+  generate:
+    count: 1
+    interval: ""
+    mapping: 'root = "hello world"'
+pipeline:
+  processors:
+    - label: fooproc
+      mapping: 'root = content().uppercase()'
+output:
+  drop: {}
+tracer:
+  open_telemetry_collector:
+    http:
+      - address: `+server.URL+`
+logger:
+  level: none
+metrics:
+  none: {}
+`))
+
+	strm, err := sb.Build()
+	require.NoError(t, err)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+	require.NoError(t, strm.Run(ctx))
+
+	spans := recv.get()
+	assert.Contains(t, spans, "input_generate")
+	assert.Contains(t, spans, "mapping")
+	assert.Contains(t, spans, "output_drop")
+}
@@ -110,6 +110,34 @@ func DecompressionAlgsList() (v []string) {
 	return v
 }
 
+// CompressWriterAlgsList returns the list of registered compression algorithms
+// that are able to compress a stream of data.
+func CompressWriterAlgsList() (v []string) {
+	knownCompressionAlgorithmsLock.Lock()
+	v = make([]string, 0, len(knownCompressionAlgorithms))
+	for k, a := range knownCompressionAlgorithms {
+		if a.CompressWriter != nil {
+			v = append(v, k)
+		}
+	}
+	knownCompressionAlgorithmsLock.Unlock()
+	sort.Strings(v)
+	return v
+}
+
+// StrToCompressWriter returns the stream compressor of a registered compression
+// algorithm by its name.
+func StrToCompressWriter(str string) (CompressWriter, error) {
+	alg, err := strToCompressAlg(str)
+	if err != nil {
+		return nil, err
+	}
+	if alg.CompressWriter == nil {
+		return nil, fmt.Errorf("compression type not recognised: %v", str)
+	}
+	return alg.CompressWriter, nil
+}
+
 func strToCompressAlg(str string) (KnownCompressionAlgorithm, error) {
 	fn, exists := knownCompressionAlgorithms[str]
 	if !exists {
@@ -0,0 +1,139 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/redpanda-data/benthos/v4/internal/value"
+)
+
+// binaryDocumentKey converts a map key decoded from a binary document format
+// into a string.
+func binaryDocumentKey(k any) (string, error) {
+	switch t := k.(type) {
+	case string:
+		return t, nil
+	case []byte:
+		return string(t), nil
+	case bool, int8, int16, int32, int64, int, uint8, uint16, uint32, uint64, uint, float32, float64:
+		return value.IToString(t), nil
+	}
+	return "", fmt.Errorf("unsupported map key type %T", k)
+}
+
+// normaliseBinaryDocument converts a value decoded from a binary document
+// format such as MessagePack or CBOR into the value types used by structured
+// messages. Maps become objects with string keys, all integers become int64
+// unless they overflow it, all floats become float64, and binary values are
+// preserved as byte arrays.
+func normaliseBinaryDocument(v any) (any, error) {
+	switch t := v.(type) {
+	case nil, bool, string, []byte, int64, float64, time.Time:
+		return t, nil
+	case map[string]any:
+		for k, e := range t {
+			var err error
+			if t[k], err = normaliseBinaryDocument(e); err != nil {
+				return nil, err
+			}
+		}
+		return t, nil
+	case map[any]any:
+		obj := make(map[string]any, len(t))
+		for k, e := range t {
+			keyStr, err := binaryDocumentKey(k)
+			if err != nil {
+				return nil, err
+			}
+			nv, err := normaliseBinaryDocument(e)
+			if err != nil {
+				return nil, err
+			}
+			obj[keyStr] = nv
+		}
+		return obj, nil
+	case []any:
+		for i, e := range t {
+			var err error
+			if t[i], err = normaliseBinaryDocument(e); err != nil {
+				return nil, err
+			}
+		}
+		return t, nil
+	case int8:
+		return int64(t), nil
+	case int16:
+		return int64(t), nil
+	case int32:
+		return int64(t), nil
+	case int:
+		return int64(t), nil
+	case uint8:
+		return int64(t), nil
+	case uint16:
+		return int64(t), nil
+	case uint32:
+		return int64(t), nil
+	case uint:
+		return normaliseBinaryDocument(uint64(t))
+	case uint64:
+		if t > math.MaxInt64 {
+			return t, nil
+		}
+		return int64(t), nil
+	case float32:
+		return float64(t), nil
+	case big.Int:
+		if t.IsInt64() {
+			return t.Int64(), nil
+		}
+		if t.IsUint64() {
+			return t.Uint64(), nil
+		}
+		f, _ := new(big.Float).SetInt(&t).Float64()
+		return f, nil
+	case cbor.Tag:
+		return normaliseBinaryDocument(t.Content)
+	case cbor.SimpleValue:
+		return int64(t), nil
+	}
+	return nil, fmt.Errorf("unsupported value type %T", v)
+}
+
+// encodableBinaryDocument prepares a structured value to be encoded into a
+// binary document format by converting json.Number values into numbers.
+func encodableBinaryDocument(v any) (any, error) {
+	switch t := v.(type) {
+	case map[string]any:
+		obj := make(map[string]any, len(t))
+		for k, e := range t {
+			var err error
+			if obj[k], err = encodableBinaryDocument(e); err != nil {
+				return nil, err
+			}
+		}
+		return obj, nil
+	case []any:
+		arr := make([]any, len(t))
+		for i, e := range t {
+			var err error
+			if arr[i], err = encodableBinaryDocument(e); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		return t.Float64()
+	}
+	return v, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/query"
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+var cborEncMode = func() cbor.EncMode {
+	opts := cbor.CoreDetEncOptions()
+	opts.Time = cbor.TimeRFC3339Nano
+	opts.TimeTag = cbor.EncTagRequired
+	mode, err := opts.EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}()
+
+func decodeCBOR(dec *cbor.Decoder) (any, error) {
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return normaliseBinaryDocument(v)
+}
+
+// parseCBOR decodes a single CBOR value, rejecting any data that follows it.
+func parseCBOR(data []byte) (any, error) {
+	var v any
+	if err := cbor.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return normaliseBinaryDocument(v)
+}
+
+func encodeCBOR(v any) ([]byte, error) {
+	v, err := encodableBinaryDocument(v)
+	if err != nil {
+		return nil, err
+	}
+	return cborEncMode.Marshal(v)
+}
+
+func init() {
+	if err := bloblang.RegisterMethodV2("parse_cbor",
+		bloblang.NewPluginSpec().
+			Category(query.MethodCategoryParsing).
+			Description("Parses a https://cbor.io/[CBOR^] encoded value and returns the result. Maps become objects with string keys, integers and floats become numbers, byte strings are preserved as bytes and date/time tags are parsed into timestamp values. Any other tags are removed and replaced with their content.").
+			Example("", `root = this.doc.decode("hex").parse_cbor()`,
+				[2]string{
+					`{"doc":"a163666f6f63626172"}`,
+					`{"foo":"bar"}`,
+				},
+			),
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			return bloblang.BytesMethod(func(data []byte) (any, error) {
+				v, err := parseCBOR(data)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse value as CBOR: %w", err)
+				}
+				return v, nil
+			}), nil
+		}); err != nil {
+		panic(err)
+	}
+
+	if err := bloblang.RegisterMethodV2("format_cbor",
+		bloblang.NewPluginSpec().
+			Category(query.MethodCategoryParsing).
+			Description("Serializes a target value into a https://cbor.io/[CBOR^] byte array using the core deterministic encoding. Byte arrays are encoded as byte strings and timestamps are encoded as RFC 3339 date/time strings with the standard date/time tag.").
+			Example("", `root.encoded = this.format_cbor().encode("hex")`,
+				[2]string{
+					`{"foo":"bar"}`,
+					`{"encoded":"a163666f6f63626172"}`,
+				},
+			),
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			return func(v any) (any, error) {
+				return encodeCBOR(v)
+			}, nil
+		}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+func TestParseCBORValueTypes(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	bigInt, ok := new(big.Int).SetString("100000000000000000000", 10)
+	require.True(t, ok)
+
+	encOpts := cbor.EncOptions{TimeTag: cbor.EncTagRequired, BigIntConvert: cbor.BigIntConvertNone}
+	encMode, err := encOpts.EncMode()
+	require.NoError(t, err)
+
+	input, err := encMode.Marshal(map[any]any{
+		"str":     "foo",
+		"bin":     []byte("bar"),
+		"int":     -5,
+		"uint64":  uint64(1) << 63,
+		"float32": float32(1.5),
+		"bool":    true,
+		"nil":     nil,
+		"time":    ts,
+		"big":     bigInt,
+		"tagged":  cbor.Tag{Number: 1000, Content: "baz"},
+		"arr":     []any{1, "two"},
+		int64(10): map[any]any{true: "yes"},
+	})
+	require.NoError(t, err)
+
+	exec, err := bloblang.Parse(`root = this.parse_cbor()`)
+	require.NoError(t, err)
+
+	res, err := exec.Query(input)
+	require.NoError(t, err)
+
+	resTime, ok := res.(map[string]any)["time"].(time.Time)
+	require.True(t, ok)
+	assert.True(t, ts.Equal(resTime))
+	delete(res.(map[string]any), "time")
+
+	assert.Equal(t, map[string]any{
+		"str":     "foo",
+		"bin":     []byte("bar"),
+		"int":     int64(-5),
+		"uint64":  uint64(1) << 63,
+		"float32": 1.5,
+		"bool":    true,
+		"nil":     nil,
+		"big":     1e20,
+		"tagged":  "baz",
+		"arr":     []any{int64(1), "two"},
+		"10":      map[string]any{"true": "yes"},
+	}, res)
+}
+
+func TestCBORRoundTrip(t *testing.T) {
+	exec, err := bloblang.Parse(`root = this.format_cbor().parse_cbor()`)
+	require.NoError(t, err)
+
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)
+	input := map[string]any{
+		"a": "foo",
+		"b": []byte("bar"),
+		"c": json.Number("10"),
+		"d": json.Number("1.5"),
+		"e": []any{int64(1), 2.5, false, nil},
+		"f": map[string]any{"g": int64(-300)},
+		"t": ts,
+	}
+
+	res, err := exec.Query(input)
+	require.NoError(t, err)
+
+	resTime, ok := res.(map[string]any)["t"].(time.Time)
+	require.True(t, ok)
+	assert.True(t, ts.Equal(resTime))
+	delete(res.(map[string]any), "t")
+
+	assert.Equal(t, map[string]any{
+		"a": "foo",
+		"b": []byte("bar"),
+		"c": int64(10),
+		"d": 1.5,
+		"e": []any{int64(1), 2.5, false, nil},
+		"f": map[string]any{"g": int64(-300)},
+	}, res)
+}
+
+func TestParseCBORErrors(t *testing.T) {
+	exec, err := bloblang.Parse(`root = this.parse_cbor()`)
+	require.NoError(t, err)
+
+	_, err = exec.Query([]byte{0xa2, 0x63, 'f', 'o', 'o'})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse value as CBOR")
+
+	// A map with a single entry where the key is itself a map.
+	_, err = exec.Query([]byte{0xa1, 0xa1, 0x61, 'a', 0x61, 'b', 0xf6})
+	require.Error(t, err)
+}
+
+func TestParseCBORTrailingData(t *testing.T) {
+	exec, err := bloblang.Parse(`root = this.parse_cbor()`)
+	require.NoError(t, err)
+
+	_, err = exec.Query([]byte{0xa1, 0x63, 'f', 'o', 'o', 0x63, 'b', 'a', 'r', 0xf6})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse value as CBOR")
+}
@@ -0,0 +1,206 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+func jwtTestPEMKeys(t *testing.T, priv any, pub any) (privPEM, pubPEM string) {
+	t.Helper()
+
+	var privBytes []byte
+	var privType string
+	switch k := priv.(type) {
+	case *rsa.PrivateKey:
+		privBytes, privType = x509.MarshalPKCS1PrivateKey(k), "RSA PRIVATE KEY"
+	case *ecdsa.PrivateKey:
+		var err error
+		privBytes, err = x509.MarshalECPrivateKey(k)
+		require.NoError(t, err)
+		privType = "EC PRIVATE KEY"
+	}
+	pubBytes, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+
+	privPEM = string(pem.EncodeToMemory(&pem.Block{Type: privType, Bytes: privBytes}))
+	pubPEM = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubBytes}))
+	return
+}
+
+func TestJWTMethodsRoundTrip(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaPriv, rsaPub := jwtTestPEMKeys(t, rsaKey, &rsaKey.PublicKey)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecPriv, ecPub := jwtTestPEMKeys(t, ecKey, &ecKey.PublicKey)
+
+	tests := []struct {
+		alg       string
+		signKey   string
+		verifyKey string
+		wrongAlg  string
+	}{
+		{alg: "hs256", signKey: "foo", verifyKey: "foo", wrongAlg: "hs512"},
+		{alg: "hs384", signKey: "foo", verifyKey: "foo", wrongAlg: "hs256"},
+		{alg: "hs512", signKey: "foo", verifyKey: "foo", wrongAlg: "hs384"},
+		{alg: "rs256", signKey: rsaPriv, verifyKey: rsaPub, wrongAlg: "rs512"},
+		{alg: "rs384", signKey: rsaPriv, verifyKey: rsaPub, wrongAlg: "rs256"},
+		{alg: "rs512", signKey: rsaPriv, verifyKey: rsaPub, wrongAlg: "rs384"},
+		{alg: "es256", signKey: ecPriv, verifyKey: ecPub, wrongAlg: "es384"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.alg, func(t *testing.T) {
+			signExec, err := bloblang.Parse(`root = this.sign_jwt_` + test.alg + `(signing_secret: """` + test.signKey + `""", headers: {"kid":"first"})`)
+			require.NoError(t, err)
+
+			exp := time.Now().Add(time.Hour).Unix()
+			token, err := signExec.Query(map[string]any{
+				"sub": "user123",
+				"aud": "orders",
+				"exp": exp,
+			})
+			require.NoError(t, err)
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token.(string), jwt.MapClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, "first", parsed.Header["kid"])
+
+			parseExec, err := bloblang.Parse(`root = this.parse_jwt_` + test.alg + `(signing_secret: """` + test.verifyKey + `""", audience: "orders", require_expiry: true)`)
+			require.NoError(t, err)
+
+			claims, err := parseExec.Query(token)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]any{
+				"sub": "user123",
+				"aud": "orders",
+				"exp": float64(exp),
+			}, claims)
+
+			wrongAlgExec, err := bloblang.Parse(`root = this.parse_jwt_` + test.wrongAlg + `(signing_secret: """` + test.verifyKey + `""")`)
+			require.NoError(t, err)
+
+			_, err = wrongAlgExec.Query(token)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "failed to parse JWT")
+		})
+	}
+}
+
+func TestJWTParseClaimValidation(t *testing.T) {
+	sign := func(claims jwt.MapClaims) string {
+		t.Helper()
+		s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("foo"))
+		require.NoError(t, err)
+		return s
+	}
+
+	tests := []struct {
+		name        string
+		mapping     string
+		token       string
+		errContains string
+	}{
+		{
+			name:        "expired",
+			mapping:     `root = this.parse_jwt_hs256("foo")`,
+			token:       sign(jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}),
+			errContains: "token is expired",
+		},
+		{
+			name:        "not valid yet",
+			mapping:     `root = this.parse_jwt_hs256("foo")`,
+			token:       sign(jwt.MapClaims{"nbf": time.Now().Add(time.Hour).Unix()}),
+			errContains: "token is not valid yet",
+		},
+		{
+			name:        "wrong audience",
+			mapping:     `root = this.parse_jwt_hs256(signing_secret: "foo", audience: "orders")`,
+			token:       sign(jwt.MapClaims{"aud": []string{"payments"}}),
+			errContains: "token has invalid audience",
+		},
+		{
+			name:        "missing expiry",
+			mapping:     `root = this.parse_jwt_hs256(signing_secret: "foo", require_expiry: true)`,
+			token:       sign(jwt.MapClaims{"sub": "foo"}),
+			errContains: "token is missing required claim",
+		},
+		{
+			name:        "wrong secret",
+			mapping:     `root = this.parse_jwt_hs256("bar")`,
+			token:       sign(jwt.MapClaims{"sub": "foo"}),
+			errContains: "signature is invalid",
+		},
+		{
+			name:        "unsigned",
+			mapping:     `root = this.parse_jwt_hs256("foo")`,
+			token:       "eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0.eyJzdWIiOiJmb28ifQ.",
+			errContains: "signing method none is invalid",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exec, err := bloblang.Parse(test.mapping)
+			require.NoError(t, err)
+
+			_, err = exec.Query(test.token)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.errContains)
+		})
+	}
+}
+
+func TestJWTMethodErrorsOmitSecrets(t *testing.T) {
+	secret := "-----BEGIN PUBLIC KEY-----\nnotactuallyakey\n-----END PUBLIC KEY-----"
+
+	for _, method := range []string{"parse_jwt_rs256", "parse_jwt_es256", "sign_jwt_rs256", "sign_jwt_es256"} {
+		_, err := bloblang.Parse(`root = this.` + method + `("""` + secret + `""")`)
+		require.Error(t, err, method)
+		assert.Contains(t, err.Error(), "failed to parse signing_secret", method)
+		assert.NotContains(t, err.Error(), "notactuallyakey", method)
+	}
+
+	_, err := bloblang.Parse(`root = this.sign_jwt_hs256(signing_secret: "foo", headers: {"alg":"none"})`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the alg header cannot be set")
+
+	exec, err := bloblang.Parse(`root = this.sign_jwt_hs256("foo")`)
+	require.NoError(t, err)
+
+	_, err = exec.Query("not an object")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected object value")
+}
+
+func TestJWTParamsAreSecret(t *testing.T) {
+	for _, method := range []string{"parse_jwt_hs256", "sign_jwt_rs384", "parse_jwt_es512"} {
+		var found bool
+		bloblang.GlobalEnvironment().WalkMethods(func(name string, view *bloblang.MethodView) {
+			if name != method {
+				return
+			}
+			found = true
+			for _, p := range view.TemplateData().Params.Definitions {
+				assert.Equal(t, p.Name == "signing_secret", p.IsSecret, "%v %v", method, p.Name)
+			}
+		})
+		assert.True(t, found, method)
+	}
+}
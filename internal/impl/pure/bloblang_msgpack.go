@@ -0,0 +1,133 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/query"
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+// maxMsgpackMapSizeHint limits the capacity allocated up front for decoded
+// maps.
+const maxMsgpackMapSizeHint = 1024
+
+func newMsgpackDecoder(r io.Reader) *msgpack.Decoder {
+	dec := msgpack.NewDecoder(r)
+	dec.SetMapDecoder(func(d *msgpack.Decoder) (any, error) {
+		n, err := d.DecodeMapLen()
+		if err != nil || n == -1 {
+			return nil, err
+		}
+		// The length is read from the input and so it is not trusted as a
+		// size hint beyond a modest limit.
+		obj := make(map[string]any, min(n, maxMsgpackMapSizeHint))
+		for i := 0; i < n; i++ {
+			k, err := d.DecodeInterface()
+			if err != nil {
+				return nil, err
+			}
+			key, err := binaryDocumentKey(k)
+			if err != nil {
+				return nil, err
+			}
+			if obj[key], err = d.DecodeInterface(); err != nil {
+				return nil, err
+			}
+		}
+		return obj, nil
+	})
+	return dec
+}
+
+// decodeMsgpack decodes the next value from a decoder. The decoder reports
+// truncated values as io.EOF, and therefore any io.EOF is converted into an
+// io.ErrUnexpectedEOF.
+func decodeMsgpack(dec *msgpack.Decoder) (any, error) {
+	v, err := dec.DecodeInterface()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return normaliseBinaryDocument(v)
+}
+
+// parseMsgpack decodes a single MessagePack value, rejecting any data that
+// follows it.
+func parseMsgpack(data []byte) (any, error) {
+	r := bytes.NewReader(data)
+	v, err := decodeMsgpack(newMsgpackDecoder(r))
+	if err != nil {
+		return nil, err
+	}
+	if r.Len() > 0 {
+		return nil, fmt.Errorf("unexpected %v bytes of trailing data", r.Len())
+	}
+	return v, nil
+}
+
+func encodeMsgpack(v any) ([]byte, error) {
+	v, err := encodableBinaryDocument(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetSortMapKeys(true)
+	enc.UseCompactInts(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func init() {
+	if err := bloblang.RegisterMethodV2("parse_msgpack",
+		bloblang.NewPluginSpec().
+			Category(query.MethodCategoryParsing).
+			Description("Parses a https://msgpack.org/[MessagePack^] encoded value and returns the result. Maps become objects with string keys, integers and floats become numbers, binary values are preserved as bytes and timestamps are parsed into timestamp values.").
+			Example("", `root = this.doc.decode("hex").parse_msgpack()`,
+				[2]string{
+					`{"doc":"81a3666f6fa3626172"}`,
+					`{"foo":"bar"}`,
+				},
+			),
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			return bloblang.BytesMethod(func(data []byte) (any, error) {
+				v, err := parseMsgpack(data)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse value as MessagePack: %w", err)
+				}
+				return v, nil
+			}), nil
+		}); err != nil {
+		panic(err)
+	}
+
+	if err := bloblang.RegisterMethodV2("format_msgpack",
+		bloblang.NewPluginSpec().
+			Category(query.MethodCategoryParsing).
+			Description("Serializes a target value into a https://msgpack.org/[MessagePack^] byte array. Byte arrays are encoded as binary values, timestamps are encoded with the timestamp extension type and the keys of objects are written in alphabetical order.").
+			Example("", `root.encoded = this.format_msgpack().encode("hex")`,
+				[2]string{
+					`{"foo":"bar"}`,
+					`{"encoded":"81a3666f6fa3626172"}`,
+				},
+			),
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			return func(v any) (any, error) {
+				return encodeMsgpack(v)
+			}, nil
+		}); err != nil {
+		panic(err)
+	}
+}
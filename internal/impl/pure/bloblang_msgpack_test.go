@@ -0,0 +1,121 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+func TestParseMsgpackValueTypes(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)
+	input, err := msgpack.Marshal(map[any]any{
+		"str":     "foo",
+		"bin":     []byte("bar"),
+		"int8":    int8(-5),
+		"uint16":  uint16(500),
+		"int64":   int64(-1) << 40,
+		"uint64":  uint64(1) << 63,
+		"float32": float32(1.5),
+		"float64": 2.25,
+		"bool":    true,
+		"nil":     nil,
+		"time":    ts,
+		"arr":     []any{int8(1), "two"},
+		int64(10): map[any]any{true: "yes"},
+	})
+	require.NoError(t, err)
+
+	exec, err := bloblang.Parse(`root = this.parse_msgpack()`)
+	require.NoError(t, err)
+
+	res, err := exec.Query(input)
+	require.NoError(t, err)
+
+	resTime, ok := res.(map[string]any)["time"].(time.Time)
+	require.True(t, ok)
+	assert.True(t, ts.Equal(resTime))
+	delete(res.(map[string]any), "time")
+
+	assert.Equal(t, map[string]any{
+		"str":     "foo",
+		"bin":     []byte("bar"),
+		"int8":    int64(-5),
+		"uint16":  int64(500),
+		"int64":   int64(-1) << 40,
+		"uint64":  uint64(1) << 63,
+		"float32": 1.5,
+		"float64": 2.25,
+		"bool":    true,
+		"nil":     nil,
+		"arr":     []any{int64(1), "two"},
+		"10":      map[string]any{"true": "yes"},
+	}, res)
+}
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	exec, err := bloblang.Parse(`root = this.format_msgpack().parse_msgpack()`)
+	require.NoError(t, err)
+
+	input := map[string]any{
+		"a": "foo",
+		"b": []byte("bar"),
+		"c": json.Number("10"),
+		"d": json.Number("1.5"),
+		"e": []any{int64(1), 2.5, false, nil},
+		"f": map[string]any{"g": int64(-300)},
+	}
+
+	res, err := exec.Query(input)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"a": "foo",
+		"b": []byte("bar"),
+		"c": int64(10),
+		"d": 1.5,
+		"e": []any{int64(1), 2.5, false, nil},
+		"f": map[string]any{"g": int64(-300)},
+	}, res)
+}
+
+func TestParseMsgpackErrors(t *testing.T) {
+	exec, err := bloblang.Parse(`root = this.parse_msgpack()`)
+	require.NoError(t, err)
+
+	_, err = exec.Query([]byte{0x82, 0xa3, 'f', 'o', 'o'})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse value as MessagePack")
+
+	// A map with a single entry where the key is itself a map.
+	key, err := msgpack.Marshal(map[string]any{"foo": "bar"})
+	require.NoError(t, err)
+	_, err = exec.Query(append(append([]byte{0x81}, key...), 0xc0))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported map key type")
+}
+
+func TestParseMsgpackUntrustedLengths(t *testing.T) {
+	exec, err := bloblang.Parse(`root = this.parse_msgpack()`)
+	require.NoError(t, err)
+
+	// A map32 header claiming 4294967295 entries with no content.
+	_, err = exec.Query([]byte{0xdf, 0xff, 0xff, 0xff, 0xff})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse value as MessagePack")
+}
+
+func TestParseMsgpackTrailingData(t *testing.T) {
+	exec, err := bloblang.Parse(`root = this.parse_msgpack()`)
+	require.NoError(t, err)
+
+	_, err = exec.Query([]byte{0x81, 0xa3, 'f', 'o', 'o', 0xa3, 'b', 'a', 'r', 0xc0})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "trailing data")
+}
@@ -0,0 +1,328 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/query"
+	"github.com/redpanda-data/benthos/v4/internal/sketch"
+	"github.com/redpanda-data/benthos/v4/internal/value"
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+// maxNamedSketches is the maximum number of sketches of each type retained by
+// the Bloblang sketch functions, beyond which the least recently used sketch is
+// discarded in order to bound memory usage when names are dynamic.
+const maxNamedSketches = 256
+
+type namedSketch[T any, P comparable] struct {
+	params P
+	sketch T
+}
+
+// namedSketches holds the sketches created by Bloblang functions, which are
+// local to the process and are identified by name, allowing multiple mappings
+// to share them.
+type namedSketches[T any, P comparable] struct {
+	kind string
+
+	mut      sync.Mutex
+	sketches *simplelru.LRU[string, namedSketch[T, P]]
+}
+
+func newNamedSketches[T any, P comparable](kind string) *namedSketches[T, P] {
+	sketches, err := simplelru.NewLRU[string, namedSketch[T, P]](maxNamedSketches, nil)
+	if err != nil {
+		panic(err)
+	}
+	return &namedSketches[T, P]{kind: kind, sketches: sketches}
+}
+
+// with calls a function with the sketch of a name, creating it if it does not
+// yet exist. An error is returned if the sketch was created with different
+// parameters.
+func (n *namedSketches[T, P]) with(name string, params P, newFn func() (T, error), fn func(T) any) (any, error) {
+	n.mut.Lock()
+	defer n.mut.Unlock()
+
+	s, exists := n.sketches.Get(name)
+	if !exists {
+		sketch, err := newFn()
+		if err != nil {
+			return nil, err
+		}
+		s = namedSketch[T, P]{params: params, sketch: sketch}
+		n.sketches.Add(name, s)
+	} else if s.params != params {
+		return nil, fmt.Errorf("%v %v already exists with different parameters %+v", n.kind, name, s.params)
+	}
+	return fn(s.sketch), nil
+}
+
+// get calls a function with the sketch of a name and returns its result, or
+// returns false if the sketch does not exist.
+func (n *namedSketches[T, P]) get(name string, fn func(T) any) (any, bool) {
+	n.mut.Lock()
+	defer n.mut.Unlock()
+
+	s, exists := n.sketches.Get(name)
+	if !exists {
+		return nil, false
+	}
+	return fn(s.sketch), true
+}
+
+type hyperLogLogParams struct {
+	precision int64
+}
+
+type bloomFilterParams struct {
+	capacity          int64
+	falsePositiveRate float64
+}
+
+type countMinSketchParams struct {
+	width, depth, topK int64
+}
+
+var (
+	hyperLogLogs     = newNamedSketches[*sketch.HyperLogLog, hyperLogLogParams]("hyperloglog")
+	bloomFilters     = newNamedSketches[*sketch.BloomFilter, bloomFilterParams]("bloom filter")
+	countMinSketches = newNamedSketches[*sketch.CountMinSketch, countMinSketchParams]("count-min sketch")
+)
+
+func sketchTopKToValue(items []sketch.TopKItem) []any {
+	res := make([]any, len(items))
+	for i, item := range items {
+		res[i] = map[string]any{
+			"value": item.Item,
+			"count": int64(item.Count),
+		}
+	}
+	return res
+}
+
+func init() {
+	if err := bloblang.RegisterFunctionV2("hyperloglog_add",
+		bloblang.NewPluginSpec().
+			Impure().
+			Category(query.FunctionCategoryGeneral).
+			Description("Adds a value to a process-local HyperLogLog identified by a name, creating it if it does not yet exist, and returns the estimated number of distinct values that have been added to it. The HyperLogLog uses a fixed amount of memory, 2^precision bytes, regardless of the number of values added, with a standard error of roughly `1.04/sqrt(2^precision)`. HyperLogLogs are shared by all mappings and streams of the process, and at most 256 are retained, beyond which the least recently used is discarded. An error is returned if the precision differs from that of an existing HyperLogLog of the same name.").
+			Param(bloblang.NewStringParam("name").Description("An identifier for the HyperLogLog.")).
+			Param(bloblang.NewAnyParam("value").Description("The value to add, which is hashed in its serialized form.")).
+			Param(bloblang.NewInt64Param("precision").Description("The precision of the HyperLogLog, between 4 and 18, which must match that of an existing HyperLogLog of the same name.").Default(14)).
+			Example("", `root.distinct_users = hyperloglog_add("users", this.user)`,
+				[2]string{`{"user":"alice"}`, `{"distinct_users":1}`},
+				[2]string{`{"user":"bob"}`, `{"distinct_users":2}`},
+				[2]string{`{"user":"alice"}`, `{"distinct_users":2}`},
+			),
+		func(args *bloblang.ParsedParams) (bloblang.Function, error) {
+			name, err := args.GetString("name")
+			if err != nil {
+				return nil, err
+			}
+			v, err := args.Get("value")
+			if err != nil {
+				return nil, err
+			}
+			precision, err := args.GetInt64("precision")
+			if err != nil {
+				return nil, err
+			}
+			return func() (any, error) {
+				return hyperLogLogs.with(name, hyperLogLogParams{precision: precision}, func() (*sketch.HyperLogLog, error) {
+					return sketch.NewHyperLogLog(int(precision))
+				}, func(h *sketch.HyperLogLog) any {
+					h.Add(value.IToBytes(v))
+					return int64(h.Estimate())
+				})
+			}, nil
+		},
+	); err != nil {
+		panic(err)
+	}
+
+	if err := bloblang.RegisterFunctionV2("hyperloglog_estimate",
+		bloblang.NewPluginSpec().
+			Impure().
+			Category(query.FunctionCategoryGeneral).
+			Description("Returns the estimated number of distinct values that have been added to a process-local HyperLogLog with the <<hyperloglog_add, `hyperloglog_add`>> function, or zero if it does not exist.").
+			Param(bloblang.NewStringParam("name").Description("An identifier for the HyperLogLog.")).
+			Example("", `root.distinct_users = hyperloglog_estimate("users")`),
+		func(args *bloblang.ParsedParams) (bloblang.Function, error) {
+			name, err := args.GetString("name")
+			if err != nil {
+				return nil, err
+			}
+			return func() (any, error) {
+				if v, exists := hyperLogLogs.get(name, func(h *sketch.HyperLogLog) any {
+					return int64(h.Estimate())
+				}); exists {
+					return v, nil
+				}
+				return int64(0), nil
+			}, nil
+		},
+	); err != nil {
+		panic(err)
+	}
+
+	if err := bloblang.RegisterFunctionV2("bloom_filter_add",
+		bloblang.NewPluginSpec().
+			Impure().
+			Category(query.FunctionCategoryGeneral).
+			Description("Adds a value to a process-local Bloom filter identified by a name, creating it if it does not yet exist, and returns whether the value was possibly added before. A result of `false` means that the value was definitely not added before, whereas a result of `true` is a false positive with a probability of roughly the configured false positive rate, provided the number of distinct values added does not exceed the capacity. Filters are shared by all mappings and streams of the process, and at most 256 are retained, beyond which the least recently used is discarded. An error is returned if the capacity or false positive rate differ from those of an existing filter of the same name.").
+			Param(bloblang.NewStringParam("name").Description("An identifier for the Bloom filter.")).
+			Param(bloblang.NewAnyParam("value").Description("The value to add, which is hashed in its serialized form.")).
+			Param(bloblang.NewInt64Param("capacity").Description("The number of distinct values the filter is sized for, which must match that of an existing filter of the same name.").Default(1000000)).
+			Param(bloblang.NewFloat64Param("false_positive_rate").Description("The desired false positive rate of the filter once it reaches capacity, which must match that of an existing filter of the same name.").Default(0.01)).
+			Example("", `root.duplicate = bloom_filter_add("ids", this.id)`,
+				[2]string{`{"id":"a"}`, `{"duplicate":false}`},
+				[2]string{`{"id":"b"}`, `{"duplicate":false}`},
+				[2]string{`{"id":"a"}`, `{"duplicate":true}`},
+			).
+			Example("Messages that were possibly seen before can be dropped with the `deleted` function.", `root = if bloom_filter_add("seen_ids", this.id) { deleted() } else { this }`),
+		func(args *bloblang.ParsedParams) (bloblang.Function, error) {
+			name, err := args.GetString("name")
+			if err != nil {
+				return nil, err
+			}
+			v, err := args.Get("value")
+			if err != nil {
+				return nil, err
+			}
+			capacity, err := args.GetInt64("capacity")
+			if err != nil {
+				return nil, err
+			}
+			fpRate, err := args.GetFloat64("false_positive_rate")
+			if err != nil {
+				return nil, err
+			}
+			return func() (any, error) {
+				return bloomFilters.with(name, bloomFilterParams{capacity: capacity, falsePositiveRate: fpRate}, func() (*sketch.BloomFilter, error) {
+					return sketch.NewBloomFilter(int(capacity), fpRate)
+				}, func(b *sketch.BloomFilter) any {
+					return b.Add(value.IToBytes(v))
+				})
+			}, nil
+		},
+	); err != nil {
+		panic(err)
+	}
+
+	if err := bloblang.RegisterFunctionV2("bloom_filter_test",
+		bloblang.NewPluginSpec().
+			Impure().
+			Category(query.FunctionCategoryGeneral).
+			Description("Returns whether a value was possibly added to a process-local Bloom filter with the <<bloom_filter_add, `bloom_filter_add`>> function, without adding it. Returns `false` if the filter does not exist.").
+			Param(bloblang.NewStringParam("name").Description("An identifier for the Bloom filter.")).
+			Param(bloblang.NewAnyParam("value").Description("The value to test.")).
+			Example("", `root.seen = bloom_filter_test("ids", this.id)`),
+		func(args *bloblang.ParsedParams) (bloblang.Function, error) {
+			name, err := args.GetString("name")
+			if err != nil {
+				return nil, err
+			}
+			v, err := args.Get("value")
+			if err != nil {
+				return nil, err
+			}
+			return func() (any, error) {
+				if res, exists := bloomFilters.get(name, func(b *sketch.BloomFilter) any {
+					return b.Test(value.IToBytes(v))
+				}); exists {
+					return res, nil
+				}
+				return false, nil
+			}, nil
+		},
+	); err != nil {
+		panic(err)
+	}
+
+	if err := bloblang.RegisterFunctionV2("count_min_sketch_add",
+		bloblang.NewPluginSpec().
+			Impure().
+			Category(query.FunctionCategoryGeneral).
+			Description("Adds a value to a process-local Count-Min Sketch identified by a name, creating it if it does not yet exist, and returns the estimated number of times the value has been added. Estimates may be higher than the true count but are never lower. The most frequently added values are also tracked and can be obtained with the <<count_min_sketch_top_k, `count_min_sketch_top_k`>> function. Sketches are shared by all mappings and streams of the process, and at most 256 are retained, beyond which the least recently used is discarded. An error is returned if the width, depth or top_k differ from those of an existing sketch of the same name.").
+			Param(bloblang.NewStringParam("name").Description("An identifier for the Count-Min Sketch.")).
+			Param(bloblang.NewAnyParam("value").Description("The value to add, which is hashed in its serialized form.")).
+			Param(bloblang.NewInt64Param("count").Description("The number of times to add the value.").Default(1)).
+			Param(bloblang.NewInt64Param("width").Description("The number of counters in each row of the sketch, which must match that of an existing sketch of the same name. Estimates exceed the true count by at most `2.72/width` of the total count with high probability.").Default(2048)).
+			Param(bloblang.NewInt64Param("depth").Description("The number of rows of the sketch, which must match that of an existing sketch of the same name.").Default(5)).
+			Param(bloblang.NewInt64Param("top_k").Description("The number of most frequently added values to track, which must match that of an existing sketch of the same name.").Default(10)).
+			Example("", `root.page_views = count_min_sketch_add("pages", this.page)`,
+				[2]string{`{"page":"/home"}`, `{"page_views":1}`},
+				[2]string{`{"page":"/about"}`, `{"page_views":1}`},
+				[2]string{`{"page":"/home"}`, `{"page_views":2}`},
+			),
+		func(args *bloblang.ParsedParams) (bloblang.Function, error) {
+			name, err := args.GetString("name")
+			if err != nil {
+				return nil, err
+			}
+			v, err := args.Get("value")
+			if err != nil {
+				return nil, err
+			}
+			count, err := args.GetInt64("count")
+			if err != nil {
+				return nil, err
+			}
+			if count < 0 {
+				return nil, fmt.Errorf("count must not be negative, got %v", count)
+			}
+			width, err := args.GetInt64("width")
+			if err != nil {
+				return nil, err
+			}
+			depth, err := args.GetInt64("depth")
+			if err != nil {
+				return nil, err
+			}
+			topK, err := args.GetInt64("top_k")
+			if err != nil {
+				return nil, err
+			}
+			return func() (any, error) {
+				return countMinSketches.with(name, countMinSketchParams{width: width, depth: depth, topK: topK}, func() (*sketch.CountMinSketch, error) {
+					return sketch.NewCountMinSketch(int(width), int(depth), int(topK))
+				}, func(c *sketch.CountMinSketch) any {
+					return int64(c.Add(value.IToBytes(v), uint64(count)))
+				})
+			}, nil
+		},
+	); err != nil {
+		panic(err)
+	}
+
+	if err := bloblang.RegisterFunctionV2("count_min_sketch_top_k",
+		bloblang.NewPluginSpec().
+			Impure().
+			Category(query.FunctionCategoryGeneral).
+			Description("Returns the most frequently added values of a process-local Count-Min Sketch created with the <<count_min_sketch_add, `count_min_sketch_add`>> function, as an array of objects containing the `value` and its estimated `count` in descending order of count. Returns an empty array if the sketch does not exist.").
+			Param(bloblang.NewStringParam("name").Description("An identifier for the Count-Min Sketch.")).
+			Example("", `root.top_pages = count_min_sketch_top_k("pages")`),
+		func(args *bloblang.ParsedParams) (bloblang.Function, error) {
+			name, err := args.GetString("name")
+			if err != nil {
+				return nil, err
+			}
+			return func() (any, error) {
+				if res, exists := countMinSketches.get(name, func(c *sketch.CountMinSketch) any {
+					return sketchTopKToValue(c.TopK())
+				}); exists {
+					return res, nil
+				}
+				return []any{}, nil
+			}, nil
+		},
+	); err != nil {
+		panic(err)
+	}
+}
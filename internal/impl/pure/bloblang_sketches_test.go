@@ -0,0 +1,82 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+func TestSketchFunctionsConflictingParams(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		first   string
+		second  string
+		errPart string
+	}{
+		{
+			name:    "hyperloglog",
+			first:   `root = hyperloglog_add("test_conflict", this, 12)`,
+			second:  `root = hyperloglog_add("test_conflict", this)`,
+			errPart: "hyperloglog test_conflict already exists with different parameters",
+		},
+		{
+			name:    "bloom filter",
+			first:   `root = bloom_filter_add(name: "test_conflict", value: this, capacity: 100)`,
+			second:  `root = bloom_filter_add(name: "test_conflict", value: this, capacity: 100, false_positive_rate: 0.1)`,
+			errPart: "bloom filter test_conflict already exists with different parameters",
+		},
+		{
+			name:    "count-min sketch",
+			first:   `root = count_min_sketch_add(name: "test_conflict", value: this, depth: 3)`,
+			second:  `root = count_min_sketch_add("test_conflict", this)`,
+			errPart: "count-min sketch test_conflict already exists with different parameters",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			first, err := bloblang.Parse(test.first)
+			require.NoError(t, err)
+
+			second, err := bloblang.Parse(test.second)
+			require.NoError(t, err)
+
+			_, err = first.Query("foo")
+			require.NoError(t, err)
+
+			_, err = first.Query("bar")
+			require.NoError(t, err)
+
+			_, err = second.Query("foo")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.errPart)
+		})
+	}
+}
+
+func TestSketchFunctionsEviction(t *testing.T) {
+	add, err := bloblang.Parse(`root = hyperloglog_add("test_evict_" + this.name, this.value)`)
+	require.NoError(t, err)
+
+	estimate, err := bloblang.Parse(`root = hyperloglog_estimate("test_evict_" + this.name)`)
+	require.NoError(t, err)
+
+	for i := 0; i <= maxNamedSketches; i++ {
+		_, err := add.Query(map[string]any{"name": fmt.Sprintf("%v", i), "value": "foo"})
+		require.NoError(t, err)
+	}
+	assert.LessOrEqual(t, hyperLogLogs.sketches.Len(), maxNamedSketches)
+
+	// The first sketch was the least recently used and has been discarded.
+	res, err := estimate.Query(map[string]any{"name": "0"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), res)
+
+	res, err = estimate.Query(map[string]any{"name": fmt.Sprintf("%v", maxNamedSketches)})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), res)
+}
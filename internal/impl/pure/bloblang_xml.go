@@ -0,0 +1,140 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"fmt"
+
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/query"
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+func xmlOptionsFromArgs(args *bloblang.ParsedParams) (*xmlOptions, error) {
+	opts := newXMLOptions()
+
+	var err error
+	if opts.attrPrefix, err = args.GetString("attribute_prefix"); err != nil {
+		return nil, err
+	}
+	if opts.textKey, err = args.GetString("text_key"); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+func init() {
+	if err := bloblang.RegisterMethodV2("parse_xml",
+		bloblang.NewPluginSpec().
+			Category(query.MethodCategoryParsing).
+			Description(`Attempts to parse a string as an XML document and returns a structured result, where elements appear as keys of an object.
+
+The conversion follows these rules:
+
+- Elements are converted into keys of the object of their parent, and the root element is a key of the resulting object.
+- Attributes are converted into keys prefixed with the attribute prefix, which is `+"`-`"+` by default.
+- Elements that contain only text are converted into a string. When an element also contains attributes or child elements its text is stored under the text key, which is `+"`#text`"+` by default.
+- Elements that appear more than once within their parent are converted into an array. The paths of elements that should always be converted into an array, even when they appear only once, can be specified with the `+"`force_array`"+` parameter.
+- Whitespace surrounding text is removed, and comments and processing instructions are ignored.
+
+By default all values are strings, set the `+"`cast`"+` parameter to true in order to convert values that represent numbers or booleans into those types. Numbers with leading zeros are left as strings.`).
+			Param(bloblang.NewBoolParam("cast").Description("Whether to convert values that represent numbers or booleans into those types.").Default(false)).
+			Param(bloblang.NewStringParam("attribute_prefix").Description("A prefix added to the keys of attributes.").Default(xmlDefaultAttributePrefix)).
+			Param(bloblang.NewStringParam("text_key").Description("The key of the text of elements that also contain attributes or child elements.").Default(xmlDefaultTextKey)).
+			Param(bloblang.NewAnyParam("force_array").Description("An array of dot separated paths of element names, starting with the root element, that are always converted into an array.").Default([]any{})).
+			Example("", `root.doc = this.doc.parse_xml()`,
+				[2]string{
+					`{"doc":"<root><title id=\"1\">This is a title</title><content>This is some content</content></root>"}`,
+					`{"doc":{"root":{"content":"This is some content","title":{"#text":"This is a title","-id":"1"}}}}`,
+				},
+			).
+			Example("Values that represent numbers or booleans can be converted into those types with the `cast` parameter.", `root.doc = this.doc.parse_xml(cast: true)`,
+				[2]string{
+					`{"doc":"<root><title id=\"1\">This is a title</title><number>123</number><bool>true</bool></root>"}`,
+					`{"doc":{"root":{"bool":true,"number":123,"title":{"#text":"This is a title","-id":1}}}}`,
+				},
+			).
+			Example("Elements that should always be converted into an array can be specified with the `force_array` parameter.", `root.items = this.doc.parse_xml(attribute_prefix: "@", force_array: ["order.item"]).order.item`,
+				[2]string{
+					`{"doc":"<order><item sku=\"a\">apple</item></order>"}`,
+					`{"items":[{"#text":"apple","@sku":"a"}]}`,
+				},
+			),
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			opts, err := xmlOptionsFromArgs(args)
+			if err != nil {
+				return nil, err
+			}
+			if opts.cast, err = args.GetBool("cast"); err != nil {
+				return nil, err
+			}
+			forceArray, err := args.Get("force_array")
+			if err != nil {
+				return nil, err
+			}
+			paths, ok := forceArray.([]any)
+			if !ok {
+				return nil, fmt.Errorf("expected force_array to be an array, got %T", forceArray)
+			}
+			for _, p := range paths {
+				pStr, ok := p.(string)
+				if !ok {
+					return nil, fmt.Errorf("expected force_array to contain strings, got %T", p)
+				}
+				opts.forceArray[pStr] = struct{}{}
+			}
+			return bloblang.BytesMethod(func(data []byte) (any, error) {
+				v, err := opts.decodeDocument(data)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse value as XML: %w", err)
+				}
+				return v, nil
+			}), nil
+		}); err != nil {
+		panic(err)
+	}
+
+	if err := bloblang.RegisterMethodV2("format_xml",
+		bloblang.NewPluginSpec().
+			Category(query.MethodCategoryParsing).
+			Description(`Serializes a target value into an XML byte array, following the same conversion rules as the `+"`parse_xml`"+` method in reverse. Keys with the attribute prefix become attributes, the text key becomes the text of the element, arrays become repeated elements and any other key becomes a child element.
+
+Unless a root tag is specified the target value must be an object with a single key, which becomes the root element. Child elements and attributes are written in the alphabetical order of their keys.`).
+			Param(bloblang.NewStringParam("indent").Description("An indentation string to add to each level of nested elements, no indentation is added by default.").Default("")).
+			Param(bloblang.NewStringParam("root_tag").Description("An optional name of a root element to wrap the target value in.").Default("")).
+			Param(bloblang.NewStringParam("attribute_prefix").Description("The prefix of keys that are written as attributes.").Default(xmlDefaultAttributePrefix)).
+			Param(bloblang.NewStringParam("text_key").Description("The key of the text of elements that also contain attributes or child elements.").Default(xmlDefaultTextKey)).
+			Example("", `root = this.format_xml()`,
+				[2]string{
+					`{"root":{"content":"This is some content","title":{"#text":"This is a title","-id":"1"}}}`,
+					`<root><content>This is some content</content><title id="1">This is a title</title></root>`,
+				},
+			).
+			Example("Use the `indent` parameter in order to pretty-print the result, and the `root_tag` parameter in order to wrap a value that does not have a single root element.", `root = this.items.format_xml(indent: "  ", root_tag: "items")`,
+				[2]string{
+					`{"items":{"item":["foo","bar"]}}`,
+					`<items>
+  <item>foo</item>
+  <item>bar</item>
+</items>`,
+				},
+			),
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			opts, err := xmlOptionsFromArgs(args)
+			if err != nil {
+				return nil, err
+			}
+			indent, err := args.GetString("indent")
+			if err != nil {
+				return nil, err
+			}
+			rootTag, err := args.GetString("root_tag")
+			if err != nil {
+				return nil, err
+			}
+			return func(v any) (any, error) {
+				return opts.encodeDocument(v, rootTag, indent)
+			}, nil
+		}); err != nil {
+		panic(err)
+	}
+}
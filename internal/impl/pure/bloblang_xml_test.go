@@ -0,0 +1,45 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+func TestXMLMethodsRoundTrip(t *testing.T) {
+	exec, err := bloblang.Parse(`root = this.parse_xml(cast: true, attribute_prefix: "@", text_key: "_", force_array: ["order.item"]).format_xml(attribute_prefix: "@", text_key: "_").string()`)
+	require.NoError(t, err)
+
+	res, err := exec.Query([]byte(`<order id="5"><item sku="a">apple</item></order>`))
+	require.NoError(t, err)
+	assert.Equal(t, `<order id="5"><item sku="a">apple</item></order>`, res)
+}
+
+func TestXMLMethodsErrors(t *testing.T) {
+	_, err := bloblang.Parse(`root = this.parse_xml(force_array: "order.item")`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected force_array to be an array")
+
+	_, err = bloblang.Parse(`root = this.parse_xml(force_array: [ 10 ])`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected force_array to contain strings")
+
+	exec, err := bloblang.Parse(`root = this.parse_xml()`)
+	require.NoError(t, err)
+
+	_, err = exec.Query([]byte(`<order><item></order>`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse value as XML")
+
+	exec, err = bloblang.Parse(`root = this.format_xml()`)
+	require.NoError(t, err)
+
+	_, err = exec.Query([]any{"foo"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected an object with a single key")
+}
@@ -0,0 +1,703 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/internal/batch"
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	etwFieldTimestampMapping = "timestamp_mapping"
+	etwFieldMode             = "mode"
+	etwFieldSize             = "size"
+	etwFieldSlide            = "slide"
+	etwFieldOffset           = "offset"
+	etwFieldGap              = "gap"
+	etwFieldGroupKey         = "group_key"
+	etwFieldAllowedLateness  = "allowed_lateness"
+	etwFieldIdleTimeout      = "idle_timeout"
+	etwFieldLateData         = "late_data"
+
+	etwModeTumbling = "tumbling"
+	etwModeSliding  = "sliding"
+	etwModeSession  = "session"
+
+	etwLateDataDrop = "drop"
+	etwLateDataEmit = "emit"
+)
+
+func eventTimeWindowBufferConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Windowing").
+		Summary("Chops a stream of messages into tumbling, sliding or session windows following the event time of messages, where windows are flushed as a watermark derived from the event times progresses.").
+		Description(`
+A window is a grouping of messages that fit within a discrete measure of event time, where the event time of each message is provided by the `+"<<timestamp_mapping, `timestamp_mapping`>>"+`. Unlike the `+"xref:components:buffers/system_window.adoc[`system_window` buffer]"+` the system clock plays no part in deciding when windows are flushed.
+
+== Watermarks
+
+The buffer tracks a watermark, which is the latest event time observed minus the `+"<<allowed_lateness, `allowed_lateness`>>"+`. A window is flushed once the watermark surpasses its end, and therefore the allowed lateness is the length of time that messages are permitted to arrive out of order.
+
+Since the watermark only progresses as messages arrive, windows remain open when a stream goes quiet. An `+"<<idle_timeout, `idle_timeout`>>"+` can be specified in order to advance the watermark by the time elapsed since the last message was received once that period has passed without any messages. Inputs wait for their messages to be acknowledged before ending, and therefore an idle timeout is also required in order to flush the final windows of a finite input, after which all remaining windows are flushed.
+
+== Window modes
+
+In `+"`tumbling`"+` mode windows are of a fixed `+"`size`"+`, where the beginning of a window immediately follows the end of a prior window. Windows are aligned to the zeroth minute and zeroth hour on the UTC clock, which can be adjusted with an `+"`offset`"+`.
+
+In `+"`sliding`"+` mode windows of a fixed `+"`size`"+` begin every `+"`slide`"+`, and therefore messages may belong to multiple windows.
+
+In `+"`session`"+` mode windows are formed from messages separated by less than the `+"`gap`"+`, and a window ends once the gap has passed since its latest message.
+
+When a message is flushed it has the metadata fields `+"`window_start_timestamp`"+` and `+"`window_end_timestamp`"+` added to it containing the beginning and the end of its window as RFC3339 strings.
+
+== Grouping
+
+When a `+"<<group_key, `group_key`>>"+` is specified windows are tracked separately for each unique key, and are flushed independently of one another. For example, a session window keyed by a user ID yields one window per user session. Flushed messages have the metadata field `+"`window_key`"+` added to them containing the key.
+
+== Late data
+
+A message is late when the watermark has already surpassed the end of every window it belongs to. By default late messages are dropped (and acknowledged), but with `+"`late_data`"+` set to `+"`emit`"+` they are instead flushed in batches of their own with the metadata field `+"`window_late`"+` set to `+"`true`"+`, which can be used in order to route them separately with a `+"xref:components:outputs/switch.adoc[`switch` output]"+`.
+
+== Delivery guarantees
+
+This buffer honours the transaction model within Redpanda Connect in order to ensure that messages are not acknowledged until they are either intentionally dropped or successfully delivered to outputs. Messages that belong to multiple sliding windows are acknowledged once all of their windows have been delivered, and are nacked if the delivery of any of them fails.
+
+Windows are held in memory until they are flushed, and therefore you should ensure that you have enough system memory to store all open windows, including those of every group key and the data held back by the allowed lateness.
+
+During graceful termination messages of windows that have not been flushed are nacked such that they are re-consumed the next time the service starts.
+`).
+		Fields(
+			service.NewBloblangField(etwFieldTimestampMapping).
+				Description(`
+A xref:guides:bloblang/about.adoc[Bloblang mapping] applied to each message during ingestion that provides the event time of the message.
+
+The timestamp value assigned to `+"`root`"+` must either be a numerical unix time in seconds (with up to nanosecond precision via decimals), or a string in ISO 8601 format. If the mapping fails or provides an invalid result the batch is rejected (with logging to describe the problem).
+`).
+				Example("root = this.created_at").Example(`root = meta("kafka_timestamp_unix").number()`),
+			service.NewStringEnumField(etwFieldMode, etwModeTumbling, etwModeSliding, etwModeSession).
+				Description("The type of windows to produce.").
+				Default(etwModeTumbling),
+			service.NewDurationField(etwFieldSize).
+				Description("The size of each window, required for `tumbling` and `sliding` modes.").
+				Example("30s").Example("10m").
+				Optional(),
+			service.NewDurationField(etwFieldSlide).
+				Description("The duration between the beginning of each window, required for `sliding` mode and must be smaller than the `size`.").
+				Example("30s").Example("10m").
+				Optional(),
+			service.NewDurationField(etwFieldOffset).
+				Description("An offset to apply to the beginning of windows in `tumbling` and `sliding` modes, otherwise they are aligned to the zeroth minute and zeroth hour on the UTC clock. The offset must be a smaller measure than the window size or the slide.").
+				Example("-6h").Example("30m").
+				Optional(),
+			service.NewDurationField(etwFieldGap).
+				Description("The gap of event time without messages that ends a window, required for `session` mode.").
+				Example("5m").
+				Optional(),
+			service.NewInterpolatedStringField(etwFieldGroupKey).
+				Description("An optional key to group windows by, where windows of each unique key are flushed independently.").
+				Example(`${! this.user_id }`).
+				Optional(),
+			service.NewDurationField(etwFieldAllowedLateness).
+				Description("The length of event time that the watermark trails the latest event time observed, and therefore the length of time that messages are permitted to arrive out of order before they are considered late.").
+				Default("0s").
+				Example("10s").Example("1m"),
+			service.NewDurationField(etwFieldIdleTimeout).
+				Description("An optional period of time without messages after which the watermark is advanced by the time elapsed since the last message, allowing windows to be flushed when a stream goes quiet.").
+				Example("1m").
+				Optional().
+				Advanced(),
+			service.NewStringAnnotatedEnumField(etwFieldLateData, map[string]string{
+				etwLateDataDrop: "Late messages are dropped and acknowledged.",
+				etwLateDataEmit: "Late messages are flushed in batches of their own with the metadata field `window_late` set to `true`.",
+			}).
+				Description("How to handle messages that arrive after all of the windows they belong to have been flushed.").
+				Default(etwLateDataDrop),
+		).
+		Example("Per-User Sessions", `Given a stream of click events of the form:
+
+`+"```json"+`
+{
+  "user_id": "bf22ea2c",
+  "created_at": "2021-08-07T09:49:35Z",
+  "page": "/checkout"
+}
+`+"```"+`
+
+We can group the events of each user into sessions separated by at least ten minutes of inactivity, and summarize each session:`,
+			`
+buffer:
+  event_time_window:
+    timestamp_mapping: root = this.created_at
+    mode: session
+    gap: 10m
+    group_key: ${! this.user_id }
+    allowed_lateness: 30s
+    idle_timeout: 1m
+
+pipeline:
+  processors:
+    - mapping: |
+        root = if batch_index() == 0 {
+          {
+            "user_id": meta("window_key"),
+            "started_at": meta("window_start_timestamp"),
+            "ended_at": meta("window_end_timestamp"),
+            "pages": json("page").from_all(),
+          }
+        } else { deleted() }
+`,
+		)
+}
+
+func init() {
+	err := service.RegisterBatchBuffer(
+		"event_time_window", eventTimeWindowBufferConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchBuffer, error) {
+			eConf, err := eventTimeWindowConfigFromParsed(conf)
+			if err != nil {
+				return nil, err
+			}
+			return newEventTimeWindowBuffer(eConf, time.Now, mgr.Logger()), nil
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type eventTimeWindowConfig struct {
+	tsMapping       *bloblang.Executor
+	groupKey        *service.InterpolatedString
+	mode            string
+	size            time.Duration
+	slide           time.Duration
+	offset          time.Duration
+	gap             time.Duration
+	allowedLateness time.Duration
+	idleTimeout     time.Duration
+	emitLate        bool
+}
+
+func optionalDuration(conf *service.ParsedConfig, name string) (time.Duration, error) {
+	if !conf.Contains(name) {
+		return 0, nil
+	}
+	return conf.FieldDuration(name)
+}
+
+func eventTimeWindowConfigFromParsed(conf *service.ParsedConfig) (c eventTimeWindowConfig, err error) {
+	if c.tsMapping, err = conf.FieldBloblang(etwFieldTimestampMapping); err != nil {
+		return
+	}
+	if conf.Contains(etwFieldGroupKey) {
+		if c.groupKey, err = conf.FieldInterpolatedString(etwFieldGroupKey); err != nil {
+			return
+		}
+	}
+	if c.mode, err = conf.FieldString(etwFieldMode); err != nil {
+		return
+	}
+	if c.size, err = optionalDuration(conf, etwFieldSize); err != nil {
+		return
+	}
+	if c.slide, err = optionalDuration(conf, etwFieldSlide); err != nil {
+		return
+	}
+	if c.offset, err = optionalDuration(conf, etwFieldOffset); err != nil {
+		return
+	}
+	if c.gap, err = optionalDuration(conf, etwFieldGap); err != nil {
+		return
+	}
+	if c.allowedLateness, err = conf.FieldDuration(etwFieldAllowedLateness); err != nil {
+		return
+	}
+	if c.allowedLateness < 0 {
+		err = fmt.Errorf("invalid allowed_lateness '%v' must not be negative", c.allowedLateness)
+		return
+	}
+	if c.idleTimeout, err = optionalDuration(conf, etwFieldIdleTimeout); err != nil {
+		return
+	}
+	var lateData string
+	if lateData, err = conf.FieldString(etwFieldLateData); err != nil {
+		return
+	}
+	c.emitLate = lateData == etwLateDataEmit
+
+	switch c.mode {
+	case etwModeTumbling, etwModeSliding:
+		if c.size <= 0 {
+			err = fmt.Errorf("a positive window size is required for %v windows", c.mode)
+			return
+		}
+		epoch := c.size
+		if c.mode == etwModeSliding {
+			if c.slide <= 0 || c.slide >= c.size {
+				err = fmt.Errorf("invalid window slide '%v' must be positive and lower than the size '%v'", c.slide, c.size)
+				return
+			}
+			epoch = c.slide
+		} else if c.slide != 0 {
+			err = errors.New("a slide can only be specified for sliding windows")
+			return
+		}
+		if c.offset >= epoch || -c.offset >= epoch {
+			err = fmt.Errorf("invalid offset '%v' must be a smaller measure than '%v'", c.offset, epoch)
+			return
+		}
+	case etwModeSession:
+		if c.gap <= 0 {
+			err = errors.New("a positive gap is required for session windows")
+			return
+		}
+	}
+	return
+}
+
+//------------------------------------------------------------------------------
+
+type etwMessage struct {
+	ts    time.Time
+	key   string
+	m     *service.Message
+	ackFn service.AckFunc
+
+	// The number of windows that the message has yet to be delivered within,
+	// and the first error of those that have been delivered.
+	pending int
+	err     error
+}
+
+type etwWindowID struct {
+	key   string
+	start time.Time
+}
+
+type etwWindow struct {
+	start, end time.Time
+	msgs       []*etwMessage
+}
+
+type eventTimeWindowBuffer struct {
+	conf   eventTimeWindowConfig
+	clock  func() time.Time
+	logger *service.Logger
+
+	cond *sync.Cond
+
+	maxTS      time.Time
+	watermark  time.Time
+	lastIngest time.Time
+
+	// Windows of fixed size indexed by their key and start, used by tumbling
+	// and sliding modes.
+	windows map[etwWindowID]*etwWindow
+
+	// Messages ordered by event time for each key, used by session mode.
+	sessions map[string][]*etwMessage
+
+	late []*etwMessage
+
+	endOfInput bool
+	closed     bool
+}
+
+func newEventTimeWindowBuffer(conf eventTimeWindowConfig, clock func() time.Time, logger *service.Logger) *eventTimeWindowBuffer {
+	return &eventTimeWindowBuffer{
+		conf:     conf,
+		clock:    clock,
+		logger:   logger,
+		cond:     sync.NewCond(&sync.Mutex{}),
+		windows:  map[etwWindowID]*etwWindow{},
+		sessions: map[string][]*etwMessage{},
+	}
+}
+
+// windowStarts returns the start of each fixed size window that a timestamp
+// belongs to, ordered from the latest.
+func (e *eventTimeWindowBuffer) windowStarts(ts time.Time) []time.Time {
+	epoch := e.conf.size
+	if e.conf.mode == etwModeSliding {
+		epoch = e.conf.slide
+	}
+
+	start := ts.Add(-e.conf.offset).Truncate(epoch).Add(e.conf.offset)
+	if start.After(ts) {
+		start = start.Add(-epoch)
+	}
+
+	var starts []time.Time
+	for s := start; s.Add(e.conf.size).After(ts); s = s.Add(-epoch) {
+		starts = append(starts, s)
+	}
+	return starts
+}
+
+// advanceWatermarkLocked moves the watermark forward according to the latest
+// event time observed and, when configured, the time spent idle.
+func (e *eventTimeWindowBuffer) advanceWatermarkLocked() {
+	if e.maxTS.IsZero() {
+		return
+	}
+	wm := e.maxTS.Add(-e.conf.allowedLateness)
+	if e.conf.idleTimeout > 0 {
+		if idle := e.clock().Sub(e.lastIngest); idle >= e.conf.idleTimeout {
+			wm = wm.Add(idle)
+		}
+	}
+	if wm.After(e.watermark) {
+		e.watermark = wm
+	}
+}
+
+// isClosedLocked returns whether a window ending at a given time has been
+// surpassed by the watermark.
+func (e *eventTimeWindowBuffer) isClosedLocked(end time.Time) bool {
+	return e.endOfInput || (!e.watermark.IsZero() && !end.After(e.watermark))
+}
+
+func (e *eventTimeWindowBuffer) addSessionLocked(msg *etwMessage) bool {
+	pending := e.sessions[msg.key]
+	i := sort.Search(len(pending), func(i int) bool {
+		return pending[i].ts.After(msg.ts)
+	})
+
+	// A message is only late if its own session would have already closed
+	// and it doesn't extend a session that's still open.
+	if e.isClosedLocked(msg.ts.Add(e.conf.gap)) {
+		joins := (i > 0 && msg.ts.Sub(pending[i-1].ts) < e.conf.gap) ||
+			(i < len(pending) && pending[i].ts.Sub(msg.ts) < e.conf.gap)
+		if !joins {
+			return false
+		}
+	}
+
+	pending = append(pending, nil)
+	copy(pending[i+1:], pending[i:])
+	pending[i] = msg
+	e.sessions[msg.key] = pending
+	return true
+}
+
+func (e *eventTimeWindowBuffer) addWindowsLocked(msg *etwMessage) bool {
+	for _, start := range e.windowStarts(msg.ts) {
+		end := start.Add(e.conf.size)
+		if e.isClosedLocked(end) {
+			continue
+		}
+		id := etwWindowID{key: msg.key, start: start}
+		w, exists := e.windows[id]
+		if !exists {
+			w = &etwWindow{start: start, end: end}
+			e.windows[id] = w
+		}
+		w.msgs = append(w.msgs, msg)
+		msg.pending++
+	}
+	return msg.pending > 0
+}
+
+func (e *eventTimeWindowBuffer) WriteBatch(ctx context.Context, msgBatch service.MessageBatch, aFn service.AckFunc) error {
+	bExec := msgBatch.BloblangExecutor(e.conf.tsMapping)
+
+	msgs := make([]*etwMessage, len(msgBatch))
+	for i, msg := range msgBatch {
+		ts, err := getWindowTimestamp(e.logger, i, bExec)
+		if err != nil {
+			return err
+		}
+		var key string
+		if e.conf.groupKey != nil {
+			if key, err = msgBatch.TryInterpolatedString(i, e.conf.groupKey); err != nil {
+				e.logger.Errorf("Group key interpolation failed for message: %v", err)
+				return fmt.Errorf("group key interpolation failed: %w", err)
+			}
+		}
+		msgs[i] = &etwMessage{ts: ts, key: key, m: msg}
+	}
+
+	e.cond.L.Lock()
+	defer e.cond.L.Unlock()
+
+	if e.closed || e.endOfInput {
+		return service.ErrEndOfBuffer
+	}
+
+	aggregatedAck := batch.NewCombinedAcker(batch.AckFunc(aFn))
+	for _, msg := range msgs {
+		msg.ackFn = service.AckFunc(aggregatedAck.Derive())
+
+		var added bool
+		if e.conf.mode == etwModeSession {
+			added = e.addSessionLocked(msg)
+		} else {
+			added = e.addWindowsLocked(msg)
+		}
+
+		if !added {
+			if e.conf.emitLate {
+				msg.pending = 1
+				e.late = append(e.late, msg)
+			} else {
+				_ = msg.ackFn(ctx, nil)
+			}
+		}
+		if msg.ts.After(e.maxTS) {
+			e.maxTS = msg.ts
+		}
+	}
+
+	e.lastIngest = e.clock()
+	e.advanceWatermarkLocked()
+	e.cond.Broadcast()
+	return nil
+}
+
+// nextSessionLocked returns the closed session with the earliest end, if any,
+// along with the number of messages it consists of.
+func (e *eventTimeWindowBuffer) nextSessionLocked() (key string, n int, w *etwWindow) {
+	for k, pending := range e.sessions {
+		i := 1
+		for i < len(pending) && pending[i].ts.Sub(pending[i-1].ts) < e.conf.gap {
+			i++
+		}
+		end := pending[i-1].ts.Add(e.conf.gap)
+		if !e.isClosedLocked(end) {
+			continue
+		}
+		if w == nil || end.Before(w.end) || (end.Equal(w.end) && k < key) {
+			key, n = k, i
+			w = &etwWindow{start: pending[0].ts, end: end, msgs: pending[:i]}
+		}
+	}
+	return
+}
+
+// nextWindowLocked returns the closed fixed size window with the earliest end,
+// if any.
+func (e *eventTimeWindowBuffer) nextWindowLocked() (id etwWindowID, w *etwWindow) {
+	for k, v := range e.windows {
+		if !e.isClosedLocked(v.end) {
+			continue
+		}
+		if w == nil || v.end.Before(w.end) || (v.end.Equal(w.end) && k.key < id.key) {
+			id, w = k, v
+		}
+	}
+	return
+}
+
+// nextEndLocked returns the earliest end of all open windows.
+func (e *eventTimeWindowBuffer) nextEndLocked() (end time.Time, exists bool) {
+	consider := func(t time.Time) {
+		if !exists || t.Before(end) {
+			end, exists = t, true
+		}
+	}
+	for _, w := range e.windows {
+		consider(w.end)
+	}
+	for _, pending := range e.sessions {
+		consider(pending[0].ts.Add(e.conf.gap))
+	}
+	return
+}
+
+func (e *eventTimeWindowBuffer) flushAckFn(msgs []*etwMessage) service.AckFunc {
+	return func(ctx context.Context, err error) error {
+		var completed []*etwMessage
+
+		e.cond.L.Lock()
+		for _, m := range msgs {
+			if err != nil && m.err == nil {
+				m.err = err
+			}
+			if m.pending--; m.pending == 0 {
+				completed = append(completed, m)
+			}
+		}
+		e.cond.L.Unlock()
+
+		for _, m := range completed {
+			_ = m.ackFn(ctx, m.err)
+		}
+		return nil
+	}
+}
+
+func (e *eventTimeWindowBuffer) flushBatchLocked(w *etwWindow, key string) (service.MessageBatch, service.AckFunc) {
+	outBatch := make(service.MessageBatch, 0, len(w.msgs))
+	for _, m := range w.msgs {
+		tmpMsg := m.m.Copy()
+		tmpMsg.MetaSetMut("window_start_timestamp", w.start.UTC().Format(time.RFC3339Nano))
+		tmpMsg.MetaSetMut("window_end_timestamp", w.end.UTC().Format(time.RFC3339Nano))
+		if e.conf.groupKey != nil {
+			tmpMsg.MetaSetMut("window_key", key)
+		}
+		outBatch = append(outBatch, tmpMsg)
+	}
+	return outBatch, e.flushAckFn(w.msgs)
+}
+
+func (e *eventTimeWindowBuffer) nextFlushLocked() (service.MessageBatch, service.AckFunc, bool) {
+	if len(e.late) > 0 {
+		late := e.late
+		e.late = nil
+
+		outBatch := make(service.MessageBatch, 0, len(late))
+		for _, m := range late {
+			tmpMsg := m.m.Copy()
+			tmpMsg.MetaSetMut("window_late", "true")
+			if e.conf.groupKey != nil {
+				tmpMsg.MetaSetMut("window_key", m.key)
+			}
+			outBatch = append(outBatch, tmpMsg)
+		}
+		return outBatch, e.flushAckFn(late), true
+	}
+
+	if e.conf.mode == etwModeSession {
+		key, n, w := e.nextSessionLocked()
+		if w == nil {
+			return nil, nil, false
+		}
+		if remaining := e.sessions[key][n:]; len(remaining) > 0 {
+			e.sessions[key] = remaining
+		} else {
+			delete(e.sessions, key)
+		}
+		outBatch, aFn := e.flushBatchLocked(w, key)
+		return outBatch, aFn, true
+	}
+
+	id, w := e.nextWindowLocked()
+	if w == nil {
+		return nil, nil, false
+	}
+	delete(e.windows, id)
+	outBatch, aFn := e.flushBatchLocked(w, id.key)
+	return outBatch, aFn, true
+}
+
+// idleWakeupLocked returns the period of time to wait before the idle timeout
+// would advance the watermark enough to close the next window.
+func (e *eventTimeWindowBuffer) idleWakeupLocked() (time.Duration, bool) {
+	if e.conf.idleTimeout <= 0 || e.lastIngest.IsZero() {
+		return 0, false
+	}
+	nextEnd, exists := e.nextEndLocked()
+	if !exists {
+		return 0, false
+	}
+	idleNeeded := nextEnd.Sub(e.maxTS.Add(-e.conf.allowedLateness))
+	if idleNeeded < e.conf.idleTimeout {
+		idleNeeded = e.conf.idleTimeout
+	}
+	wait := e.lastIngest.Add(idleNeeded).Sub(e.clock())
+	if wait < 0 {
+		wait = 0
+	}
+	return wait, true
+}
+
+func (e *eventTimeWindowBuffer) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	ctx, done := context.WithCancel(ctx)
+	defer done()
+
+	go func() {
+		<-ctx.Done()
+		e.cond.L.Lock()
+		e.cond.Broadcast()
+		e.cond.L.Unlock()
+	}()
+
+	e.cond.L.Lock()
+	defer e.cond.L.Unlock()
+
+	for {
+		if e.closed {
+			return nil, nil, service.ErrEndOfBuffer
+		}
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+
+		e.advanceWatermarkLocked()
+		if outBatch, aFn, ok := e.nextFlushLocked(); ok {
+			return outBatch, aFn, nil
+		}
+		if e.endOfInput {
+			return nil, nil, service.ErrEndOfBuffer
+		}
+
+		if wait, ok := e.idleWakeupLocked(); ok {
+			timer := time.AfterFunc(wait, func() {
+				e.cond.L.Lock()
+				e.cond.Broadcast()
+				e.cond.L.Unlock()
+			})
+			e.cond.Wait()
+			timer.Stop()
+		} else {
+			e.cond.Wait()
+		}
+	}
+}
+
+func (e *eventTimeWindowBuffer) EndOfInput() {
+	e.cond.L.Lock()
+	e.endOfInput = true
+	e.cond.Broadcast()
+	e.cond.L.Unlock()
+}
+
+func (e *eventTimeWindowBuffer) Close(ctx context.Context) error {
+	e.cond.L.Lock()
+	defer e.cond.L.Unlock()
+
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+
+	// Nack all messages of windows that haven't been flushed so that they're
+	// re-consumed the next time the service starts.
+	nacked := map[*etwMessage]struct{}{}
+	nack := func(m *etwMessage) {
+		if _, exists := nacked[m]; !exists {
+			nacked[m] = struct{}{}
+			// Prevents in-flight deliveries of other windows from
+			// acknowledging the message again.
+			m.pending = -1
+			_ = m.ackFn(ctx, errWindowClosed)
+		}
+	}
+	for _, w := range e.windows {
+		for _, m := range w.msgs {
+			nack(m)
+		}
+	}
+	for _, pending := range e.sessions {
+		for _, m := range pending {
+			nack(m)
+		}
+	}
+	for _, m := range e.late {
+		nack(m)
+	}
+	e.windows = map[etwWindowID]*etwWindow{}
+	e.sessions = map[string][]*etwMessage{}
+	e.late = nil
+
+	e.cond.Broadcast()
+	return nil
+}
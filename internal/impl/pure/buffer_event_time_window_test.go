@@ -0,0 +1,520 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func etwBufferFromYAML(t *testing.T, confStr string, clock func() time.Time) *eventTimeWindowBuffer {
+	t.Helper()
+
+	pConf, err := eventTimeWindowBufferConfig().ParseYAML(confStr, nil)
+	require.NoError(t, err)
+
+	conf, err := eventTimeWindowConfigFromParsed(pConf)
+	require.NoError(t, err)
+
+	if clock == nil {
+		clock = time.Now
+	}
+	return newEventTimeWindowBuffer(conf, clock, service.MockResources().Logger())
+}
+
+type etwAckRecorder struct {
+	mut  sync.Mutex
+	acks map[string][]error
+}
+
+func (r *etwAckRecorder) get(id string) []error {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	return r.acks[id]
+}
+
+// write adds messages to the buffer individually, where each message is a
+// triplet of ID, timestamp and key.
+func (r *etwAckRecorder) write(t *testing.T, e *eventTimeWindowBuffer, msgs ...[3]string) {
+	t.Helper()
+
+	for _, m := range msgs {
+		msg := service.NewMessage(nil)
+		msg.SetStructured(map[string]any{"id": m[0], "ts": m[1], "key": m[2]})
+
+		id := m[0]
+		require.NoError(t, e.WriteBatch(context.Background(), service.MessageBatch{msg}, func(ctx context.Context, err error) error {
+			r.mut.Lock()
+			if r.acks == nil {
+				r.acks = map[string][]error{}
+			}
+			r.acks[id] = append(r.acks[id], err)
+			r.mut.Unlock()
+			return nil
+		}))
+	}
+}
+
+type etwFlushed struct {
+	ids   []string
+	start string
+	end   string
+	key   string
+	late  bool
+}
+
+func etwRead(t *testing.T, e *eventTimeWindowBuffer, timeout time.Duration) (etwFlushed, service.AckFunc, error) {
+	t.Helper()
+
+	ctx, done := context.WithTimeout(context.Background(), timeout)
+	defer done()
+
+	b, aFn, err := e.ReadBatch(ctx)
+	if err != nil {
+		return etwFlushed{}, nil, err
+	}
+
+	var f etwFlushed
+	for i, m := range b {
+		v, err := m.AsStructured()
+		require.NoError(t, err)
+		f.ids = append(f.ids, v.(map[string]any)["id"].(string))
+		if i == 0 {
+			f.start, _ = m.MetaGet("window_start_timestamp")
+			f.end, _ = m.MetaGet("window_end_timestamp")
+			f.key, _ = m.MetaGet("window_key")
+			_, f.late = m.MetaGet("window_late")
+		}
+	}
+	return f, aFn, nil
+}
+
+func etwReadAndAck(t *testing.T, e *eventTimeWindowBuffer) etwFlushed {
+	t.Helper()
+
+	f, aFn, err := etwRead(t, e, time.Second*5)
+	require.NoError(t, err)
+	require.NoError(t, aFn(context.Background(), nil))
+	return f
+}
+
+func etwExpectNothing(t *testing.T, e *eventTimeWindowBuffer) {
+	t.Helper()
+
+	_, _, err := etwRead(t, e, time.Millisecond*50)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestEventTimeWindowBufferConfigs(t *testing.T) {
+	tests := []struct {
+		config      string
+		errContains string
+	}{
+		{
+			config: `
+mode: tumbling
+`,
+			errContains: "size is required",
+		},
+		{
+			config: `
+mode: tumbling
+size: 1m
+slide: 10s
+`,
+			errContains: "only be specified for sliding",
+		},
+		{
+			config: `
+mode: tumbling
+size: 1m
+offset: 1m
+`,
+			errContains: "invalid offset",
+		},
+		{
+			config: `
+mode: sliding
+size: 1m
+`,
+			errContains: "invalid window slide",
+		},
+		{
+			config: `
+mode: sliding
+size: 1m
+slide: 2m
+`,
+			errContains: "invalid window slide",
+		},
+		{
+			config: `
+mode: sliding
+size: 1m
+slide: 10s
+offset: -10s
+`,
+			errContains: "invalid offset",
+		},
+		{
+			config: `
+mode: session
+`,
+			errContains: "gap is required",
+		},
+		{
+			config: `
+size: 1m
+allowed_lateness: -1s
+`,
+			errContains: "must not be negative",
+		},
+		{
+			config: `
+size: 1m
+offset: -30s
+`,
+		},
+		{
+			config: `
+mode: sliding
+size: 1m
+slide: 10s
+offset: 5s
+`,
+		},
+		{
+			config: `
+mode: session
+gap: 10s
+group_key: '${! this.key }'
+`,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			pConf, err := eventTimeWindowBufferConfig().ParseYAML("timestamp_mapping: root = this.ts"+test.config, nil)
+			require.NoError(t, err)
+
+			_, err = eventTimeWindowConfigFromParsed(pConf)
+			if test.errContains == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errContains)
+			}
+		})
+	}
+}
+
+func TestEventTimeWindowBufferWindowStarts(t *testing.T) {
+	ts, err := time.Parse(time.RFC3339, "2006-01-02T15:04:05Z")
+	require.NoError(t, err)
+
+	formatted := func(starts []time.Time) (s []string) {
+		for _, v := range starts {
+			s = append(s, v.Format(time.RFC3339))
+		}
+		return
+	}
+
+	e := etwBufferFromYAML(t, `
+timestamp_mapping: root = this.ts
+size: 1h
+offset: -30m
+`, nil)
+	assert.Equal(t, []string{"2006-01-02T14:30:00Z"}, formatted(e.windowStarts(ts)))
+
+	e = etwBufferFromYAML(t, `
+timestamp_mapping: root = this.ts
+mode: sliding
+size: 1h
+slide: 20m
+`, nil)
+	assert.Equal(t, []string{
+		"2006-01-02T15:00:00Z",
+		"2006-01-02T14:40:00Z",
+		"2006-01-02T14:20:00Z",
+	}, formatted(e.windowStarts(ts)))
+}
+
+func TestEventTimeWindowBufferTumbling(t *testing.T) {
+	e := etwBufferFromYAML(t, `
+timestamp_mapping: root = this.ts
+size: 1m
+allowed_lateness: 10s
+`, nil)
+
+	var r etwAckRecorder
+	r.write(t, e,
+		[3]string{"a", "2006-01-02T15:00:05Z"},
+		[3]string{"b", "2006-01-02T15:00:50Z"},
+		[3]string{"c", "2006-01-02T15:01:05Z"},
+		// Out of order, but within the allowed lateness.
+		[3]string{"d", "2006-01-02T15:00:58Z"},
+	)
+	etwExpectNothing(t, e)
+
+	// Advances the watermark past the end of the first window.
+	r.write(t, e, [3]string{"e", "2006-01-02T15:01:10Z"})
+
+	assert.Equal(t, etwFlushed{
+		ids:   []string{"a", "b", "d"},
+		start: "2006-01-02T15:00:00Z",
+		end:   "2006-01-02T15:01:00Z",
+	}, etwReadAndAck(t, e))
+	assert.Equal(t, []error{nil}, r.get("a"))
+	assert.Nil(t, r.get("c"))
+
+	// Late data is dropped and acknowledged.
+	r.write(t, e, [3]string{"f", "2006-01-02T15:00:30Z"})
+	assert.Equal(t, []error{nil}, r.get("f"))
+	etwExpectNothing(t, e)
+
+	// Jumping forward several windows flushes them in order.
+	r.write(t, e,
+		[3]string{"g", "2006-01-02T15:02:30Z"},
+		[3]string{"h", "2006-01-02T15:10:00Z"},
+	)
+	assert.Equal(t, []string{"c", "e"}, etwReadAndAck(t, e).ids)
+	assert.Equal(t, []string{"g"}, etwReadAndAck(t, e).ids)
+	etwExpectNothing(t, e)
+
+	// Remaining windows are flushed at the end of input.
+	e.EndOfInput()
+	assert.Equal(t, []string{"h"}, etwReadAndAck(t, e).ids)
+
+	_, _, err := etwRead(t, e, time.Second)
+	require.ErrorIs(t, err, service.ErrEndOfBuffer)
+}
+
+func TestEventTimeWindowBufferSliding(t *testing.T) {
+	e := etwBufferFromYAML(t, `
+timestamp_mapping: root = this.ts
+mode: sliding
+size: 1m
+slide: 30s
+`, nil)
+
+	var r etwAckRecorder
+	r.write(t, e,
+		[3]string{"a", "2006-01-02T15:00:40Z"},
+		[3]string{"b", "2006-01-02T15:01:10Z"},
+	)
+
+	// Closes the window starting at 15:00:00.
+	r.write(t, e, [3]string{"c", "2006-01-02T15:01:20Z"})
+
+	f, aFn, err := etwRead(t, e, time.Second*5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, f.ids)
+	assert.Equal(t, "2006-01-02T15:00:00Z", f.start)
+	require.NoError(t, aFn(context.Background(), errors.New("nope")))
+
+	// The message is not acknowledged until all of its windows are delivered.
+	assert.Nil(t, r.get("a"))
+
+	// Closes the window starting at 15:00:30.
+	r.write(t, e, [3]string{"d", "2006-01-02T15:01:40Z"})
+
+	f = etwReadAndAck(t, e)
+	assert.Equal(t, []string{"a", "b", "c"}, f.ids)
+	assert.Equal(t, "2006-01-02T15:00:30Z", f.start)
+
+	errs := r.get("a")
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "nope")
+	assert.Nil(t, r.get("b"))
+}
+
+func TestEventTimeWindowBufferSessions(t *testing.T) {
+	e := etwBufferFromYAML(t, `
+timestamp_mapping: root = this.ts
+mode: session
+gap: 1m
+group_key: ${! this.key }
+late_data: emit
+`, nil)
+
+	var r etwAckRecorder
+	r.write(t, e,
+		[3]string{"a1", "2006-01-02T15:00:00Z", "a"},
+		[3]string{"b1", "2006-01-02T15:00:10Z", "b"},
+		[3]string{"a2", "2006-01-02T15:00:50Z", "a"},
+		[3]string{"b2", "2006-01-02T15:01:00Z", "b"},
+	)
+	etwExpectNothing(t, e)
+
+	// Closes the session of key a, whereas the session of key b remains open.
+	r.write(t, e, [3]string{"b3", "2006-01-02T15:01:55Z", "b"})
+	assert.Equal(t, etwFlushed{
+		ids:   []string{"a1", "a2"},
+		start: "2006-01-02T15:00:00Z",
+		end:   "2006-01-02T15:01:50Z",
+		key:   "a",
+	}, etwReadAndAck(t, e))
+	etwExpectNothing(t, e)
+
+	r.write(t, e, [3]string{"a3", "2006-01-02T15:02:20Z", "a"})
+	etwExpectNothing(t, e)
+
+	// A late message for key a is emitted separately, whereas a message of key
+	// b that would otherwise be late extends an open session.
+	r.write(t, e,
+		[3]string{"a4", "2006-01-02T15:00:30Z", "a"},
+		[3]string{"b4", "2006-01-02T15:01:15Z", "b"},
+	)
+	assert.Equal(t, etwFlushed{
+		ids:  []string{"a4"},
+		key:  "a",
+		late: true,
+	}, etwReadAndAck(t, e))
+	assert.Equal(t, []error{nil}, r.get("a4"))
+	etwExpectNothing(t, e)
+
+	e.EndOfInput()
+
+	var flushed []etwFlushed
+	for i := 0; i < 2; i++ {
+		flushed = append(flushed, etwReadAndAck(t, e))
+	}
+	assert.ElementsMatch(t, []etwFlushed{
+		{
+			ids:   []string{"b1", "b2", "b4", "b3"},
+			start: "2006-01-02T15:00:10Z",
+			end:   "2006-01-02T15:02:55Z",
+			key:   "b",
+		},
+		{
+			ids:   []string{"a3"},
+			start: "2006-01-02T15:02:20Z",
+			end:   "2006-01-02T15:03:20Z",
+			key:   "a",
+		},
+	}, flushed)
+}
+
+func TestEventTimeWindowBufferIdleTimeout(t *testing.T) {
+	var clockMut sync.Mutex
+	now := time.Now()
+	clock := func() time.Time {
+		clockMut.Lock()
+		defer clockMut.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		clockMut.Lock()
+		now = now.Add(d)
+		clockMut.Unlock()
+	}
+
+	e := etwBufferFromYAML(t, `
+timestamp_mapping: root = this.ts
+size: 1m
+idle_timeout: 30s
+`, clock)
+
+	var r etwAckRecorder
+	r.write(t, e, [3]string{"a", "2006-01-02T15:00:10Z"})
+
+	advance(time.Second * 20)
+	etwExpectNothing(t, e)
+
+	// The idle timeout has passed, but the watermark isn't yet beyond the end
+	// of the window.
+	advance(time.Second * 20)
+	etwExpectNothing(t, e)
+
+	advance(time.Second * 20)
+	assert.Equal(t, []string{"a"}, etwReadAndAck(t, e).ids)
+}
+
+func TestEventTimeWindowBufferClose(t *testing.T) {
+	e := etwBufferFromYAML(t, `
+timestamp_mapping: root = this.ts
+mode: sliding
+size: 1m
+slide: 30s
+`, nil)
+
+	var r etwAckRecorder
+	r.write(t, e,
+		[3]string{"a", "2006-01-02T15:00:40Z"},
+		[3]string{"b", "2006-01-02T15:01:10Z"},
+	)
+
+	f, aFn, err := etwRead(t, e, time.Second*5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, f.ids)
+
+	require.NoError(t, e.Close(context.Background()))
+	assert.Equal(t, []error{errWindowClosed}, r.get("a"))
+	assert.Equal(t, []error{errWindowClosed}, r.get("b"))
+
+	// Acknowledging an in-flight window after closing has no effect.
+	require.NoError(t, aFn(context.Background(), nil))
+	assert.Equal(t, []error{errWindowClosed}, r.get("a"))
+
+	_, _, err = etwRead(t, e, time.Second)
+	require.ErrorIs(t, err, service.ErrEndOfBuffer)
+}
+
+func TestEventTimeWindowBufferStream(t *testing.T) {
+	builder := service.NewStreamBuilder()
+	require.NoError(t, builder.SetLoggerYAML(`level: OFF`))
+	require.NoError(t, builder.AddInputYAML(`
+generate:
+  count: 6
+  interval: ""
+  mapping: |
+    root.ts = 1136214000.005 + (counter() * 0.02)
+    root.key = if counter() % 2 == 0 { "even" } else { "odd" }
+`))
+	require.NoError(t, builder.SetBufferYAML(`
+event_time_window:
+  timestamp_mapping: root = this.ts
+  size: 50ms
+  group_key: ${! this.key }
+  idle_timeout: 10ms
+`))
+
+	var mut sync.Mutex
+	var results []string
+	require.NoError(t, builder.AddBatchConsumerFunc(func(ctx context.Context, b service.MessageBatch) error {
+		mut.Lock()
+		defer mut.Unlock()
+
+		key, _ := b[0].MetaGet("window_key")
+		end, _ := b[0].MetaGet("window_end_timestamp")
+		results = append(results, key+" "+end+" "+strconv.Itoa(len(b)))
+		return nil
+	}))
+
+	strm, err := builder.Build()
+	require.NoError(t, err)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+	require.NoError(t, strm.Run(ctx))
+
+	mut.Lock()
+	defer mut.Unlock()
+	assert.ElementsMatch(t, []string{
+		"odd 2006-01-02T15:00:00.05Z 1",
+		"odd 2006-01-02T15:00:00.1Z 1",
+		"odd 2006-01-02T15:00:00.15Z 1",
+		"even 2006-01-02T15:00:00.05Z 1",
+		"even 2006-01-02T15:00:00.1Z 1",
+		"even 2006-01-02T15:00:00.15Z 1",
+	}, results)
+}
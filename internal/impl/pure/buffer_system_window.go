@@ -268,9 +268,15 @@ func (w *systemWindowBuffer) nextSystemWindow() (prevStart, prevEnd, start, end
 }
 
 func (w *systemWindowBuffer) getTimestamp(i int, exec *service.MessageBatchBloblangExecutor) (ts time.Time, err error) {
+	return getWindowTimestamp(w.logger, i, exec)
+}
+
+// getWindowTimestamp executes a timestamp mapping against a message of a batch
+// and parses the result as a timestamp.
+func getWindowTimestamp(logger *service.Logger, i int, exec *service.MessageBatchBloblangExecutor) (ts time.Time, err error) {
 	var tsValueMsg *service.Message
 	if tsValueMsg, err = exec.Query(i); err != nil {
-		w.logger.Errorf("Timestamp mapping failed for message: %v", err)
+		logger.Errorf("Timestamp mapping failed for message: %v", err)
 		err = fmt.Errorf("timestamp mapping failed: %w", err)
 		return
 	}
@@ -283,13 +289,13 @@ func (w *systemWindowBuffer) getTimestamp(i int, exec *service.MessageBatchBlobl
 		}
 	}
 	if err != nil {
-		w.logger.Errorf("Timestamp mapping failed for message: unable to parse result as structured value: %v", err)
+		logger.Errorf("Timestamp mapping failed for message: unable to parse result as structured value: %v", err)
 		err = fmt.Errorf("unable to parse result of timestamp mapping as structured value: %w", err)
 		return
 	}
 
 	if ts, err = value.IGetTimestamp(tsValue); err != nil {
-		w.logger.Errorf("Timestamp mapping failed for message: %v", err)
+		logger.Errorf("Timestamp mapping failed for message: %v", err)
 		err = fmt.Errorf("unable to parse result of timestamp mapping as timestamp: %w", err)
 	}
 	return
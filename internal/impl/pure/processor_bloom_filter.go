@@ -0,0 +1,153 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"context"
+
+	"github.com/redpanda-data/benthos/v4/internal/sketch"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	bfFieldOperator          = "operator"
+	bfFieldCapacity          = "capacity"
+	bfFieldFalsePositiveRate = "false_positive_rate"
+
+	bfMetaExists = "bloom_filter_exists"
+)
+
+func bloomFilterProcSpec() *service.ConfigSpec {
+	fields := append(sketchCacheFields("bloom_filter"),
+		service.NewStringAnnotatedEnumField(bfFieldOperator, map[string]string{
+			"dedupe": "Add the key of each message to the filter and drop messages where the key was possibly added before.",
+			"add":    "Add the key of each message to the filter and set the metadata field `" + bfMetaExists + "` to whether the key was possibly added before.",
+			"test":   "Set the metadata field `" + bfMetaExists + "` to whether the key of each message was possibly added to the filter, without adding it.",
+		}).
+			Description("The operation to perform for each message.").
+			Default("dedupe"),
+		service.NewIntField(bfFieldCapacity).
+			Description("The number of distinct keys the filter is sized for. This must match the filter stored within the cache, if any, and the false positive rate rises once the number of distinct keys added exceeds it.").
+			Default(1000000),
+		service.NewFloatField(bfFieldFalsePositiveRate).
+			Description("The desired probability of a key being reported as added before when it was not, once the filter reaches capacity. This must match the filter stored within the cache, if any.").
+			Default(0.01),
+	)
+
+	return service.NewConfigSpec().
+		Categories("Utility").
+		Beta().
+		Summary("Tests and adds the keys of messages to a Bloom filter persisted within a cache, which is able to deduplicate messages using a small and fixed amount of memory at the cost of occasional false positives.").
+		Description(`
+A Bloom filter reports whether a key was possibly added to it before, where false positives are possible but false negatives are not. When deduplicating, a small proportion of messages that were never seen before are therefore dropped, the rate of which is controlled by the `+"`"+bfFieldFalsePositiveRate+"`"+` field. In return the size of the filter remains the same regardless of the number of keys added, which is roughly 1.2 megabytes for one million keys with a false positive rate of 1%, making it far cheaper than the `+"xref:components:processors/dedupe.adoc[`dedupe` processor]"+` with a cache of exact keys.
+
+Keys cannot be removed from a Bloom filter. In order to start afresh delete the cache key of the filter, or set a TTL on the cache.
+`+sketchPersistenceDescription()).
+		Fields(fields...).
+		Example(
+			"Deduplicate Kafka messages",
+			"The following configuration drops messages with a Kafka key that was possibly seen before, persisting the filter within a file cache so that it survives restarts.",
+			`
+pipeline:
+  processors:
+    - bloom_filter:
+        cache: filters
+        key: ${! meta("kafka_key") }
+        capacity: 10000000
+        false_positive_rate: 0.001
+
+cache_resources:
+  - label: filters
+    file:
+      directory: /var/lib/benthos/filters
+`,
+		)
+}
+
+func init() {
+	err := service.RegisterBatchProcessor(
+		"bloom_filter", bloomFilterProcSpec(),
+		func(conf *service.ParsedConfig, res *service.Resources) (service.BatchProcessor, error) {
+			return newBloomFilterProcFromParsed(conf, res)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type bloomFilterSize struct {
+	hashes, bits int
+}
+
+type bloomFilterProc struct {
+	key      *service.InterpolatedString
+	operator string
+	filter   *cachedSketch[*sketch.BloomFilter]
+}
+
+func newBloomFilterProcFromParsed(conf *service.ParsedConfig, res *service.Resources) (*bloomFilterProc, error) {
+	capacity, err := conf.FieldInt(bfFieldCapacity)
+	if err != nil {
+		return nil, err
+	}
+	fpRate, err := conf.FieldFloat(bfFieldFalsePositiveRate)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &bloomFilterProc{}
+	if p.key, err = conf.FieldInterpolatedString(sketchFieldKey); err != nil {
+		return nil, err
+	}
+	if p.operator, err = conf.FieldString(bfFieldOperator); err != nil {
+		return nil, err
+	}
+	if p.filter, err = newCachedSketchFromParsed(conf, res, func() (*sketch.BloomFilter, error) {
+		return sketch.NewBloomFilter(capacity, fpRate)
+	}, func(b *sketch.BloomFilter) any {
+		// The capacity and false positive rate are not recoverable from a
+		// filter, and therefore its size is compared instead.
+		return bloomFilterSize{hashes: b.Hashes(), bits: b.Bits()}
+	}); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *bloomFilterProc) ProcessBatch(ctx context.Context, batch service.MessageBatch) ([]service.MessageBatch, error) {
+	keys := sketchKeys(batch, p.key)
+
+	newBatch := make(service.MessageBatch, 0, len(batch))
+	if err := p.filter.update(ctx, func(f *sketch.BloomFilter) bool {
+		newBatch = newBatch[:0]
+		for i, msg := range batch {
+			if keys[i] == nil {
+				newBatch = append(newBatch, msg)
+				continue
+			}
+			switch p.operator {
+			case "dedupe":
+				if f.Add(keys[i]) {
+					continue
+				}
+			case "add":
+				msg.MetaSetMut(bfMetaExists, f.Add(keys[i]))
+			case "test":
+				msg.MetaSetMut(bfMetaExists, f.Test(keys[i]))
+			}
+			newBatch = append(newBatch, msg)
+		}
+		return p.operator != "test"
+	}); err != nil {
+		return nil, err
+	}
+
+	if len(newBatch) == 0 {
+		return nil, nil
+	}
+	return []service.MessageBatch{newBatch}, nil
+}
+
+func (p *bloomFilterProc) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/internal/component/testutil"
+	"github.com/redpanda-data/benthos/v4/internal/manager/mock"
+	"github.com/redpanda-data/benthos/v4/internal/message"
+)
+
+func TestBloomFilterDedupe(t *testing.T) {
+	mgr := mock.NewManager()
+	mgr.Caches["foocache"] = map[string]mock.CacheItem{}
+
+	conf, err := testutil.ProcessorFromYAML(`
+bloom_filter:
+  cache: foocache
+  key: ${! content() }
+  capacity: 100
+`)
+	require.NoError(t, err)
+
+	proc, err := mgr.NewProcessor(conf)
+	require.NoError(t, err)
+
+	msgOut, err := proc.ProcessBatch(context.Background(), message.QuickBatch([][]byte{
+		[]byte("foo"), []byte("bar"), []byte("foo"),
+	}))
+	require.NoError(t, err)
+	require.Len(t, msgOut, 1)
+	require.Equal(t, 2, msgOut[0].Len())
+	assert.Equal(t, "foo", string(msgOut[0].Get(0).AsBytes()))
+	assert.Equal(t, "bar", string(msgOut[0].Get(1).AsBytes()))
+
+	assert.Contains(t, mgr.Caches["foocache"], "bloom_filter")
+
+	// A new processor picks up the filter persisted within the cache.
+	proc, err = mgr.NewProcessor(conf)
+	require.NoError(t, err)
+
+	msgOut, err = proc.ProcessBatch(context.Background(), message.QuickBatch([][]byte{
+		[]byte("bar"), []byte("baz"),
+	}))
+	require.NoError(t, err)
+	require.Len(t, msgOut, 1)
+	require.Equal(t, 1, msgOut[0].Len())
+	assert.Equal(t, "baz", string(msgOut[0].Get(0).AsBytes()))
+
+	msgOut, err = proc.ProcessBatch(context.Background(), message.QuickBatch([][]byte{
+		[]byte("foo"),
+	}))
+	require.NoError(t, err)
+	assert.Empty(t, msgOut)
+}
+
+func TestBloomFilterAddAndTest(t *testing.T) {
+	mgr := mock.NewManager()
+	mgr.Caches["foocache"] = map[string]mock.CacheItem{}
+
+	addConf, err := testutil.ProcessorFromYAML(`
+bloom_filter:
+  cache: foocache
+  cache_key: ids
+  key: ${! content() }
+  operator: add
+  capacity: 100
+`)
+	require.NoError(t, err)
+
+	testConf, err := testutil.ProcessorFromYAML(`
+bloom_filter:
+  cache: foocache
+  cache_key: ids
+  key: ${! content() }
+  operator: test
+  capacity: 100
+`)
+	require.NoError(t, err)
+
+	addProc, err := mgr.NewProcessor(addConf)
+	require.NoError(t, err)
+
+	testProc, err := mgr.NewProcessor(testConf)
+	require.NoError(t, err)
+
+	msgOut, err := testProc.ProcessBatch(context.Background(), message.QuickBatch([][]byte{[]byte("foo")}))
+	require.NoError(t, err)
+	require.Len(t, msgOut, 1)
+	assert.Equal(t, false, sketchMeta(msgOut[0].Get(0), "bloom_filter_exists"))
+	assert.NotContains(t, mgr.Caches["foocache"], "ids")
+
+	msgOut, err = addProc.ProcessBatch(context.Background(), message.QuickBatch([][]byte{
+		[]byte("foo"), []byte("foo"),
+	}))
+	require.NoError(t, err)
+	require.Len(t, msgOut, 1)
+	require.Equal(t, 2, msgOut[0].Len())
+	assert.Equal(t, false, sketchMeta(msgOut[0].Get(0), "bloom_filter_exists"))
+	assert.Equal(t, true, sketchMeta(msgOut[0].Get(1), "bloom_filter_exists"))
+
+	msgOut, err = testProc.ProcessBatch(context.Background(), message.QuickBatch([][]byte{
+		[]byte("foo"), []byte("bar"),
+	}))
+	require.NoError(t, err)
+	require.Len(t, msgOut, 1)
+	assert.Equal(t, true, sketchMeta(msgOut[0].Get(0), "bloom_filter_exists"))
+	assert.Equal(t, false, sketchMeta(msgOut[0].Get(1), "bloom_filter_exists"))
+}
+
+func TestBloomFilterBadConfig(t *testing.T) {
+	mgr := mock.NewManager()
+	mgr.Caches["foocache"] = map[string]mock.CacheItem{}
+
+	for _, yaml := range []string{
+		`
+bloom_filter:
+  cache: barcache
+  key: ${! content() }
+`,
+		`
+bloom_filter:
+  cache: foocache
+  key: ${! content() }
+  false_positive_rate: 2
+`,
+	} {
+		conf, err := testutil.ProcessorFromYAML(yaml)
+		require.NoError(t, err)
+
+		_, err = mgr.NewProcessor(conf)
+		require.Error(t, err)
+	}
+}
+
+func TestBloomFilterCacheErrors(t *testing.T) {
+	mgr := mock.NewManager()
+	mgr.Caches["foocache"] = map[string]mock.CacheItem{
+		"bloom_filter": {Value: "not a filter"},
+	}
+
+	conf, err := testutil.ProcessorFromYAML(`
+bloom_filter:
+  cache: foocache
+  key: ${! content() }
+`)
+	require.NoError(t, err)
+
+	proc, err := mgr.NewProcessor(conf)
+	require.NoError(t, err)
+
+	msgOut, err := proc.ProcessBatch(context.Background(), message.QuickBatch([][]byte{[]byte("foo")}))
+	require.NoError(t, err)
+	require.Len(t, msgOut, 1)
+	assert.Error(t, msgOut[0].Get(0).ErrorGet())
+}
+
+func sketchMeta(p *message.Part, key string) any {
+	v, _ := p.MetaGetMut(key)
+	return v
+}
+
+func TestBloomFilterParamsMismatch(t *testing.T) {
+	mgr := mock.NewManager()
+	mgr.Caches["foocache"] = map[string]mock.CacheItem{}
+
+	for _, test := range []struct {
+		capacity int
+		errs     bool
+	}{
+		{capacity: 100},
+		{capacity: 1000, errs: true},
+		{capacity: 100},
+	} {
+		conf, err := testutil.ProcessorFromYAML(fmt.Sprintf(`
+bloom_filter:
+  cache: foocache
+  key: ${! content() }
+  operator: add
+  capacity: %v
+`, test.capacity))
+		require.NoError(t, err)
+
+		proc, err := mgr.NewProcessor(conf)
+		require.NoError(t, err)
+
+		msgOut, err := proc.ProcessBatch(context.Background(), message.QuickBatch([][]byte{[]byte("foo")}))
+		require.NoError(t, err)
+		require.Len(t, msgOut, 1)
+		if test.errs {
+			assert.ErrorContains(t, msgOut[0].Get(0).ErrorGet(), "created with different parameters", test.capacity)
+		} else {
+			assert.NoError(t, msgOut[0].Get(0).ErrorGet(), test.capacity)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"context"
+
+	"github.com/redpanda-data/benthos/v4/internal/sketch"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	cmsFieldWidth = "width"
+	cmsFieldDepth = "depth"
+	cmsFieldTopK  = "top_k"
+
+	cmsMetaCount = "count_min_sketch_count"
+	cmsMetaTopK  = "count_min_sketch_top_k"
+)
+
+func countMinSketchProcSpec() *service.ConfigSpec {
+	fields := append(sketchCacheFields("count_min_sketch"),
+		service.NewIntField(cmsFieldWidth).
+			Description("The number of counters in each row of the sketch. Estimated counts exceed the true count by at most `2.72/width` of the total count of all keys with high probability. This must match the sketch stored within the cache, if any.").
+			Default(2048),
+		service.NewIntField(cmsFieldDepth).
+			Description("The number of rows of the sketch, where each additional row reduces the probability of an estimate exceeding the error bound. This must match the sketch stored within the cache, if any.").
+			Default(5),
+		service.NewIntField(cmsFieldTopK).
+			Description("The number of most frequently added keys to track. This must match the sketch stored within the cache, if any.").
+			Default(10),
+	)
+
+	return service.NewConfigSpec().
+		Categories("Utility").
+		Beta().
+		Summary("Adds the keys of messages to a Count-Min Sketch persisted within a cache, estimating the number of times each key has been seen and tracking the most frequently seen keys using a fixed amount of memory.").
+		Description(`
+Each message is given the metadata field `+"`"+cmsMetaCount+"`"+`, which contains the estimated number of times the key of the message has been added, and the structured metadata field `+"`"+cmsMetaTopK+"`"+`, which contains an array of objects with the `+"`value` and estimated `count`"+` of the most frequently added keys in descending order of count. Estimated counts may be higher than the true count but are never lower.
+`+sketchPersistenceDescription()).
+		Fields(fields...).
+		Example(
+			"Track popular pages",
+			"The following configuration emits the ten most viewed pages along with every thousandth page view.",
+			`
+pipeline:
+  processors:
+    - count_min_sketch:
+        cache: sketches
+        cache_key: page_views
+        key: ${! json("page") }
+    - mapping: |
+        root = if count() % 1000 != 0 { deleted() }
+        root.top_pages = @count_min_sketch_top_k
+
+cache_resources:
+  - label: sketches
+    memory:
+      compaction_interval: ""
+`,
+		)
+}
+
+func init() {
+	err := service.RegisterBatchProcessor(
+		"count_min_sketch", countMinSketchProcSpec(),
+		func(conf *service.ParsedConfig, res *service.Resources) (service.BatchProcessor, error) {
+			return newCountMinSketchProcFromParsed(conf, res)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type countMinSketchProc struct {
+	key *service.InterpolatedString
+	cms *cachedSketch[*sketch.CountMinSketch]
+}
+
+func newCountMinSketchProcFromParsed(conf *service.ParsedConfig, res *service.Resources) (*countMinSketchProc, error) {
+	width, err := conf.FieldInt(cmsFieldWidth)
+	if err != nil {
+		return nil, err
+	}
+	depth, err := conf.FieldInt(cmsFieldDepth)
+	if err != nil {
+		return nil, err
+	}
+	topK, err := conf.FieldInt(cmsFieldTopK)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &countMinSketchProc{}
+	if p.key, err = conf.FieldInterpolatedString(sketchFieldKey); err != nil {
+		return nil, err
+	}
+	if p.cms, err = newCachedSketchFromParsed(conf, res, func() (*sketch.CountMinSketch, error) {
+		return sketch.NewCountMinSketch(width, depth, topK)
+	}, func(c *sketch.CountMinSketch) any {
+		return countMinSketchParams{width: int64(c.Width()), depth: int64(c.Depth()), topK: int64(c.K())}
+	}); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *countMinSketchProc) ProcessBatch(ctx context.Context, batch service.MessageBatch) ([]service.MessageBatch, error) {
+	keys := sketchKeys(batch, p.key)
+
+	if err := p.cms.update(ctx, func(c *sketch.CountMinSketch) bool {
+		for i, msg := range batch {
+			if keys[i] == nil {
+				continue
+			}
+			msg.MetaSetMut(cmsMetaCount, int64(c.Add(keys[i], 1)))
+			msg.MetaSetMut(cmsMetaTopK, sketchTopKToValue(c.TopK()))
+		}
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	return []service.MessageBatch{batch}, nil
+}
+
+func (p *countMinSketchProc) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/internal/component/testutil"
+	"github.com/redpanda-data/benthos/v4/internal/manager/mock"
+	"github.com/redpanda-data/benthos/v4/internal/message"
+)
+
+func TestCountMinSketch(t *testing.T) {
+	mgr := mock.NewManager()
+	mgr.Caches["foocache"] = map[string]mock.CacheItem{}
+
+	conf, err := testutil.ProcessorFromYAML(`
+count_min_sketch:
+  cache: foocache
+  key: ${! content() }
+  top_k: 2
+`)
+	require.NoError(t, err)
+
+	proc, err := mgr.NewProcessor(conf)
+	require.NoError(t, err)
+
+	msgOut, err := proc.ProcessBatch(context.Background(), message.QuickBatch([][]byte{
+		[]byte("foo"), []byte("bar"), []byte("foo"),
+	}))
+	require.NoError(t, err)
+	require.Len(t, msgOut, 1)
+	require.Equal(t, 3, msgOut[0].Len())
+	assert.Equal(t, int64(1), sketchMeta(msgOut[0].Get(0), "count_min_sketch_count"))
+	assert.Equal(t, int64(1), sketchMeta(msgOut[0].Get(1), "count_min_sketch_count"))
+	assert.Equal(t, int64(2), sketchMeta(msgOut[0].Get(2), "count_min_sketch_count"))
+
+	// A new processor picks up the sketch persisted within the cache.
+	proc, err = mgr.NewProcessor(conf)
+	require.NoError(t, err)
+
+	msgOut, err = proc.ProcessBatch(context.Background(), message.QuickBatch([][]byte{
+		[]byte("baz"), []byte("bar"), []byte("bar"),
+	}))
+	require.NoError(t, err)
+	require.Len(t, msgOut, 1)
+	assert.Equal(t, int64(3), sketchMeta(msgOut[0].Get(2), "count_min_sketch_count"))
+	assert.Equal(t, []any{
+		map[string]any{"value": "bar", "count": int64(3)},
+		map[string]any{"value": "foo", "count": int64(2)},
+	}, sketchMeta(msgOut[0].Get(2), "count_min_sketch_top_k"))
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"context"
+
+	"github.com/redpanda-data/benthos/v4/internal/sketch"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	hllFieldPrecision = "precision"
+
+	hllMetaEstimate = "hyperloglog_estimate"
+)
+
+func hyperLogLogProcSpec() *service.ConfigSpec {
+	fields := append(sketchCacheFields("hyperloglog"),
+		service.NewIntField(hllFieldPrecision).
+			Description("The precision of the HyperLogLog, between 4 and 18, which determines its size of 2^precision bytes and a standard error of roughly `1.04/sqrt(2^precision)`. This must match the HyperLogLog stored within the cache, if any.").
+			Default(14),
+	)
+
+	return service.NewConfigSpec().
+		Categories("Utility").
+		Beta().
+		Summary("Adds the keys of messages to a HyperLogLog persisted within a cache, estimating the number of distinct keys seen using a small and fixed amount of memory.").
+		Description(`
+Each message is given the metadata field `+"`"+hllMetaEstimate+"`"+`, which contains the estimated number of distinct keys added to the HyperLogLog after the key of the message was added. With the default precision of 14 the HyperLogLog occupies 16 kilobytes and estimates are typically within 1% of the true count.
+`+sketchPersistenceDescription()).
+		Fields(fields...).
+		Example(
+			"Count distinct users",
+			"The following configuration exposes the estimated number of distinct users seen as a gauge metric.",
+			`
+pipeline:
+  processors:
+    - hyperloglog:
+        cache: sketches
+        cache_key: distinct_users
+        key: ${! json("user.id") }
+    - metric:
+        type: gauge
+        name: distinct_users
+        value: ${! @hyperloglog_estimate }
+
+cache_resources:
+  - label: sketches
+    memory:
+      compaction_interval: ""
+`,
+		)
+}
+
+func init() {
+	err := service.RegisterBatchProcessor(
+		"hyperloglog", hyperLogLogProcSpec(),
+		func(conf *service.ParsedConfig, res *service.Resources) (service.BatchProcessor, error) {
+			return newHyperLogLogProcFromParsed(conf, res)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type hyperLogLogProc struct {
+	key *service.InterpolatedString
+	hll *cachedSketch[*sketch.HyperLogLog]
+}
+
+func newHyperLogLogProcFromParsed(conf *service.ParsedConfig, res *service.Resources) (*hyperLogLogProc, error) {
+	precision, err := conf.FieldInt(hllFieldPrecision)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &hyperLogLogProc{}
+	if p.key, err = conf.FieldInterpolatedString(sketchFieldKey); err != nil {
+		return nil, err
+	}
+	if p.hll, err = newCachedSketchFromParsed(conf, res, func() (*sketch.HyperLogLog, error) {
+		return sketch.NewHyperLogLog(precision)
+	}, func(h *sketch.HyperLogLog) any {
+		return hyperLogLogParams{precision: int64(h.Precision())}
+	}); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *hyperLogLogProc) ProcessBatch(ctx context.Context, batch service.MessageBatch) ([]service.MessageBatch, error) {
+	keys := sketchKeys(batch, p.key)
+
+	if err := p.hll.update(ctx, func(h *sketch.HyperLogLog) bool {
+		for i, msg := range batch {
+			if keys[i] == nil {
+				continue
+			}
+			h.Add(keys[i])
+			msg.MetaSetMut(hllMetaEstimate, int64(h.Estimate()))
+		}
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	return []service.MessageBatch{batch}, nil
+}
+
+func (p *hyperLogLogProc) Close(context.Context) error {
+	return nil
+}
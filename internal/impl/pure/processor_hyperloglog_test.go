@@ -0,0 +1,93 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/internal/component/testutil"
+	"github.com/redpanda-data/benthos/v4/internal/manager/mock"
+	"github.com/redpanda-data/benthos/v4/internal/message"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	mgr := mock.NewManager()
+	mgr.Caches["foocache"] = map[string]mock.CacheItem{}
+
+	conf, err := testutil.ProcessorFromYAML(`
+hyperloglog:
+  cache: foocache
+  key: ${! json("user") }
+`)
+	require.NoError(t, err)
+
+	proc, err := mgr.NewProcessor(conf)
+	require.NoError(t, err)
+
+	msgOut, err := proc.ProcessBatch(context.Background(), message.QuickBatch([][]byte{
+		[]byte(`{"user":"alice"}`),
+		[]byte(`{"user":"bob"}`),
+		[]byte(`{"user":"alice"}`),
+	}))
+	require.NoError(t, err)
+	require.Len(t, msgOut, 1)
+	require.Equal(t, 3, msgOut[0].Len())
+	assert.Equal(t, int64(1), sketchMeta(msgOut[0].Get(0), "hyperloglog_estimate"))
+	assert.Equal(t, int64(2), sketchMeta(msgOut[0].Get(1), "hyperloglog_estimate"))
+	assert.Equal(t, int64(2), sketchMeta(msgOut[0].Get(2), "hyperloglog_estimate"))
+
+	// A new processor picks up the HyperLogLog persisted within the cache.
+	proc, err = mgr.NewProcessor(conf)
+	require.NoError(t, err)
+
+	msgOut, err = proc.ProcessBatch(context.Background(), message.QuickBatch([][]byte{
+		[]byte(`{"user":"carol"}`),
+		[]byte(`not json`),
+	}))
+	require.NoError(t, err)
+	require.Len(t, msgOut, 1)
+	require.Equal(t, 2, msgOut[0].Len())
+	assert.Equal(t, int64(3), sketchMeta(msgOut[0].Get(0), "hyperloglog_estimate"))
+	assert.Nil(t, sketchMeta(msgOut[0].Get(1), "hyperloglog_estimate"))
+	assert.Error(t, msgOut[0].Get(1).ErrorGet())
+}
+
+func TestHyperLogLogParamsMismatch(t *testing.T) {
+	mgr := mock.NewManager()
+	mgr.Caches["foocache"] = map[string]mock.CacheItem{}
+
+	newProc := func(precision int) message.Batch {
+		t.Helper()
+
+		conf, err := testutil.ProcessorFromYAML(fmt.Sprintf(`
+hyperloglog:
+  cache: foocache
+  key: ${! content() }
+  precision: %v
+`, precision))
+		require.NoError(t, err)
+
+		proc, err := mgr.NewProcessor(conf)
+		require.NoError(t, err)
+
+		msgOut, err := proc.ProcessBatch(context.Background(), message.QuickBatch([][]byte{[]byte("foo")}))
+		require.NoError(t, err)
+		require.Len(t, msgOut, 1)
+		return msgOut[0]
+	}
+
+	require.NoError(t, newProc(12).Get(0).ErrorGet())
+
+	// The HyperLogLog within the cache is not silently used with a different
+	// precision.
+	err := newProc(14).Get(0).ErrorGet()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "different parameters {precision:12}, expected {precision:14}")
+
+	require.NoError(t, newProc(12).Get(0).ErrorGet())
+}
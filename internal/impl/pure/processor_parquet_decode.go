@@ -0,0 +1,82 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/redpanda-data/benthos/v4/internal/codec"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func parquetDecodeProcConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Parsing").
+		Summary("Decodes https://parquet.apache.org/docs/[Parquet files^] into a batch of structured messages, one for each row.").
+		Description(`
+Each decoded message replaces the original message in the batch and adopts its metadata. Messages that fail to decode remain unchanged in the batch but will be flagged as having failed, allowing you to xref:configuration:error_handling.adoc[error handle them].
+
+This processor requires the entire file to be held in memory. When consuming Parquet files with the `+"xref:components:inputs/file.adoc[`file` input]"+` the `+"`parquet`"+` codec can be used instead in order to read rows one row group at a time.`).
+		Field(service.NewObjectField("").Default(map[string]any{})).
+		Version("4.49.0").
+		Example("Decoding Uploaded Files", `
+In this example we accept Parquet files uploaded over HTTP and expand each file into a batch of messages, one for each row, which are then written to stdout as newline delimited JSON.`, `
+input:
+  http_server:
+    path: /upload
+  processors:
+    - parquet_decode: {}
+
+output:
+  stdout:
+    codec: lines
+`)
+}
+
+func init() {
+	err := service.RegisterProcessor(
+		"parquet_decode", parquetDecodeProcConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return &parquetDecodeProcessor{}, nil
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type parquetDecodeProcessor struct{}
+
+func (p *parquetDecodeProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	mBytes, err := msg.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := codec.NewParquetReaderFromBytes(mBytes)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var batch service.MessageBatch
+	for {
+		row, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		newMsg := msg.Copy()
+		newMsg.SetStructuredMut(row)
+		batch = append(batch, newMsg)
+	}
+	return batch, nil
+}
+
+func (p *parquetDecodeProcessor) Close(ctx context.Context) error {
+	return nil
+}
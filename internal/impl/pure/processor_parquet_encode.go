@@ -0,0 +1,224 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/redpanda-data/benthos/v4/internal/codec"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	peFieldSchema             = "schema"
+	peFieldSchemaName         = "name"
+	peFieldSchemaType         = "type"
+	peFieldSchemaOptional     = "optional"
+	peFieldSchemaRepeated     = "repeated"
+	peFieldSchemaFields       = "fields"
+	peFieldDefaultCompression = "default_compression"
+	peFieldRowGroupSize       = "row_group_size"
+)
+
+func parquetSchemaFields() []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewStringField(peFieldSchemaName).Description("The name of the column."),
+		service.NewStringEnumField(peFieldSchemaType, codec.ParquetTypes...).
+			Description("The type of the column, only applicable for leaf columns with no child fields.").
+			Optional(),
+		service.NewBoolField(peFieldSchemaRepeated).
+			Description("Whether the field is repeated, in which case values must be arrays.").
+			Default(false),
+		service.NewBoolField(peFieldSchemaOptional).
+			Description("Whether the field is optional, in which case values can be null or missing.").
+			Default(false),
+	}
+}
+
+func parquetEncodeProcConfig() *service.ConfigSpec {
+	// The schema field is recursive, and therefore we only document a limited
+	// depth of nested fields whilst parsing is unbounded.
+	nestedFields := append(parquetSchemaFields(),
+		service.NewAnyListField(peFieldSchemaFields).
+			Description("A list of child fields, which follow the same structure as their parent.").
+			Optional())
+
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Parsing").
+		Summary("Encodes the messages of a batch as the rows of a https://parquet.apache.org/docs/[Parquet file^], which becomes the contents of a single message.").
+		Description(`
+Each message must be a structured object that is converted into a row according to the schema, values are coerced to the type of their column where possible and fields that are not part of the schema are ignored.
+
+When a `+"`schema`"+` is not configured it is inferred from the first message of each batch. All inferred columns are optional, arrays become repeated columns, objects become groups and fields with a null value, an empty array or an empty object are inferred as strings. Configuring a schema is recommended as inferred schemas may vary between batches.
+
+The resulting message adopts the metadata of the _first_ message of the batch.
+
+The functionality of this processor depends on being applied across messages that are batched. You can find out more about batching xref:configuration:batching.adoc[in this doc].`).
+		Field(service.NewObjectListField(peFieldSchema, append(parquetSchemaFields(),
+			service.NewObjectListField(peFieldSchemaFields, nestedFields...).
+				Description("A list of child fields.").
+				Optional().
+				Example([]any{
+					map[string]any{"name": "foo", "type": "INT64"},
+					map[string]any{"name": "bar", "type": "BYTE_ARRAY"},
+				}),
+		)...).
+			Description("Parquet schema, inferred from the first message of each batch when omitted.").
+			Optional()).
+		Field(service.NewStringEnumField(peFieldDefaultCompression, codec.ParquetCompressionCodecs...).
+			Description("The default compression type to use for fields.").
+			Default("uncompressed")).
+		Field(service.NewIntField(peFieldRowGroupSize).
+			Description("The maximum number of rows within each row group, where zero means all rows of a batch are written to a single row group.").
+			Default(0).
+			Advanced()).
+		Version("4.49.0").
+		Example("Writing Parquet Files", `
+In this example we batch messages and write each batch as a Parquet file to disk:`, `
+output:
+  file:
+    path: ./stuff/${! timestamp_unix() }-${! uuid_v4() }.parquet
+    codec: all-bytes
+    batching:
+      count: 1000
+      period: 10s
+      processors:
+        - parquet_encode:
+            default_compression: zstd
+            schema:
+              - name: id
+                type: INT64
+              - name: weight
+                type: DOUBLE
+              - name: content
+                type: UTF8
+                optional: true
+`)
+}
+
+func init() {
+	err := service.RegisterBatchProcessor(
+		"parquet_encode", parquetEncodeProcConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchProcessor, error) {
+			return newParquetEncodeProcessorFromConfig(conf)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func parquetColumnsFromConfig(confs []*service.ParsedConfig) ([]codec.ParquetColumn, error) {
+	columns := make([]codec.ParquetColumn, 0, len(confs))
+	for _, conf := range confs {
+		var col codec.ParquetColumn
+		var err error
+		if col.Name, err = conf.FieldString(peFieldSchemaName); err != nil {
+			return nil, err
+		}
+		if conf.Contains(peFieldSchemaType) {
+			if col.Type, err = conf.FieldString(peFieldSchemaType); err != nil {
+				return nil, err
+			}
+		}
+		// Nested fields beyond the documented depth are parsed without
+		// defaults, and therefore we check for their presence.
+		if conf.Contains(peFieldSchemaOptional) {
+			if col.Optional, err = conf.FieldBool(peFieldSchemaOptional); err != nil {
+				return nil, err
+			}
+		}
+		if conf.Contains(peFieldSchemaRepeated) {
+			if col.Repeated, err = conf.FieldBool(peFieldSchemaRepeated); err != nil {
+				return nil, err
+			}
+		}
+		if conf.Contains(peFieldSchemaFields) {
+			fieldConfs, err := conf.FieldAnyList(peFieldSchemaFields)
+			if err != nil {
+				return nil, err
+			}
+			if col.Fields, err = parquetColumnsFromConfig(fieldConfs); err != nil {
+				return nil, err
+			}
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+func newParquetEncodeProcessorFromConfig(conf *service.ParsedConfig) (*parquetEncodeProcessor, error) {
+	var pConf codec.ParquetWriterConfig
+	if conf.Contains(peFieldSchema) {
+		schemaConfs, err := conf.FieldObjectList(peFieldSchema)
+		if err != nil {
+			return nil, err
+		}
+		if len(schemaConfs) > 0 {
+			columns, err := parquetColumnsFromConfig(schemaConfs)
+			if err != nil {
+				return nil, err
+			}
+			if pConf.Schema, err = codec.NewParquetSchema(columns); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var err error
+	if pConf.Compression, err = conf.FieldString(peFieldDefaultCompression); err != nil {
+		return nil, err
+	}
+	if pConf.RowGroupSize, err = conf.FieldInt(peFieldRowGroupSize); err != nil {
+		return nil, err
+	}
+	if pConf.RowGroupSize < 0 {
+		return nil, fmt.Errorf("field %v must not be negative", peFieldRowGroupSize)
+	}
+	return newParquetEncodeProcessor(pConf)
+}
+
+func newParquetEncodeProcessor(conf codec.ParquetWriterConfig) (*parquetEncodeProcessor, error) {
+	if _, err := codec.ParquetCompression(conf.Compression); err != nil {
+		return nil, err
+	}
+	return &parquetEncodeProcessor{conf: conf}, nil
+}
+
+type parquetEncodeProcessor struct {
+	conf codec.ParquetWriterConfig
+}
+
+func (p *parquetEncodeProcessor) ProcessBatch(ctx context.Context, batch service.MessageBatch) ([]service.MessageBatch, error) {
+	if len(batch) == 0 {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	w, err := codec.NewParquetWriter(&buf, p.conf)
+	if err != nil {
+		return nil, err
+	}
+	for i, m := range batch {
+		v, err := m.AsStructured()
+		if err != nil {
+			return nil, fmt.Errorf("message %v: %w", i, err)
+		}
+		if err := w.Write(v); err != nil {
+			return nil, fmt.Errorf("message %v: %w", i, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	outMsg := batch[0].Copy()
+	outMsg.SetBytes(buf.Bytes())
+	return []service.MessageBatch{{outMsg}}, nil
+}
+
+func (p *parquetEncodeProcessor) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func parquetRoundTrip(t *testing.T, encodeConf string, inputs ...string) []string {
+	t.Helper()
+
+	conf, err := parquetEncodeProcConfig().ParseYAML(encodeConf, nil)
+	require.NoError(t, err)
+
+	encoder, err := newParquetEncodeProcessorFromConfig(conf)
+	require.NoError(t, err)
+
+	var batch service.MessageBatch
+	for i, in := range inputs {
+		msg := service.NewMessage([]byte(in))
+		msg.MetaSetMut("index", i)
+		batch = append(batch, msg)
+	}
+
+	encoded, err := encoder.ProcessBatch(context.Background(), batch)
+	require.NoError(t, err)
+	require.Len(t, encoded, 1)
+	require.Len(t, encoded[0], 1)
+
+	v, exists := encoded[0][0].MetaGetMut("index")
+	require.True(t, exists)
+	assert.Equal(t, 0, v)
+
+	decoder := &parquetDecodeProcessor{}
+	decoded, err := decoder.Process(context.Background(), encoded[0][0])
+	require.NoError(t, err)
+
+	var outputs []string
+	for _, m := range decoded {
+		b, err := m.AsBytes()
+		require.NoError(t, err)
+		outputs = append(outputs, string(b))
+	}
+	return outputs
+}
+
+func TestParquetEncodeDecodeSchema(t *testing.T) {
+	outputs := parquetRoundTrip(t, `
+default_compression: zstd
+schema:
+  - name: id
+    type: INT64
+  - name: score
+    type: DOUBLE
+    optional: true
+  - name: tags
+    type: UTF8
+    repeated: true
+  - name: doc
+    optional: true
+    fields:
+      - name: title
+        type: UTF8
+      - name: sections
+        repeated: true
+        fields:
+          - name: name
+            type: UTF8
+            optional: true
+`,
+		`{"id":1,"score":1.5,"tags":["a"],"doc":{"title":"foo","sections":[{"name":"bar"}]}}`,
+		`{"id":"2","ignored":true}`,
+	)
+	assert.Equal(t, []string{
+		`{"doc":{"sections":[{"name":"bar"}],"title":"foo"},"id":1,"score":1.5,"tags":["a"]}`,
+		`{"doc":null,"id":2,"score":null,"tags":[]}`,
+	}, outputs)
+}
+
+func TestParquetEncodeDecodeInferred(t *testing.T) {
+	outputs := parquetRoundTrip(t, `
+row_group_size: 1
+`,
+		`{"id":1,"name":"foo"}`,
+		`{"id":2}`,
+		`{"id":3,"name":"bar"}`,
+	)
+	assert.Equal(t, []string{
+		`{"id":1,"name":"foo"}`,
+		`{"id":2,"name":null}`,
+		`{"id":3,"name":"bar"}`,
+	}, outputs)
+}
+
+func TestParquetEncodeErrors(t *testing.T) {
+	conf, err := parquetEncodeProcConfig().ParseYAML(`
+schema:
+  - name: id
+    type: INT64
+`, nil)
+	require.NoError(t, err)
+
+	encoder, err := newParquetEncodeProcessorFromConfig(conf)
+	require.NoError(t, err)
+
+	_, err = encoder.ProcessBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":1}`)),
+		service.NewMessage([]byte(`{"id":"nope"}`)),
+	})
+	require.ErrorContains(t, err, "message 1: field id")
+
+	conf, err = parquetEncodeProcConfig().ParseYAML(`
+schema:
+  - name: id
+    type: INT64
+    fields:
+      - name: foo
+        type: INT64
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newParquetEncodeProcessorFromConfig(conf)
+	require.EqualError(t, err, "column id must not specify both a type and fields")
+}
+
+func TestParquetDecodeInvalid(t *testing.T) {
+	decoder := &parquetDecodeProcessor{}
+	_, err := decoder.Process(context.Background(), service.NewMessage([]byte(`not a parquet file`)))
+	require.Error(t, err)
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/redpanda-data/benthos/v4/internal/bloblang/field"
 	"github.com/redpanda-data/benthos/v4/internal/bundle"
 	"github.com/redpanda-data/benthos/v4/internal/component"
 	"github.com/redpanda-data/benthos/v4/internal/component/interop"
@@ -19,6 +20,7 @@ import (
 
 const (
 	rlimitFieldResource = "resource"
+	rlimitFieldKey      = "key"
 )
 
 func rlimitProcSpec() *service.ConfigSpec {
@@ -27,7 +29,12 @@ func rlimitProcSpec() *service.ConfigSpec {
 		Stable().
 		Summary(`Throttles the throughput of a pipeline according to a specified ` + "xref:components:rate_limits/about.adoc[`rate_limit`]" + ` resource. Rate limits are shared across components and therefore apply globally to all processing pipelines.`).
 		Field(service.NewStringField(rlimitFieldResource).
-			Description("The target xref:components:rate_limits/about.adoc[`rate_limit` resource]."))
+			Description("The target xref:components:rate_limits/about.adoc[`rate_limit` resource].")).
+		Field(service.NewInterpolatedStringField(rlimitFieldKey).
+			Description("An optional key to limit each message by, allowing a single rate limit resource to enforce a separate limit for each distinct key, such as a tenant. Rate limits that do not support keyed access ignore this field and apply a single limit to all messages.").
+			Example(`${! meta("tenant_id") }`).
+			Optional().
+			Version("4.49.0"))
 }
 
 func init() {
@@ -44,6 +51,15 @@ func init() {
 			if err != nil {
 				return nil, err
 			}
+			if conf.Contains(rlimitFieldKey) {
+				keyStr, err := conf.FieldString(rlimitFieldKey)
+				if err != nil {
+					return nil, err
+				}
+				if r.key, err = mgr.BloblEnvironment().NewField(keyStr); err != nil {
+					return nil, fmt.Errorf("failed to parse key expression: %v", err)
+				}
+			}
 			return interop.NewUnwrapInternalBatchProcessor(processor.NewAutoObservedProcessor("rate_limit", r, mgr)), nil
 		})
 	if err != nil {
@@ -53,6 +69,7 @@ func init() {
 
 type rateLimitProc struct {
 	rlName string
+	key    *field.Expression
 	mgr    bundle.NewManagement
 
 	closeChan chan struct{}
@@ -72,10 +89,22 @@ func newRateLimitProc(resStr string, mgr bundle.NewManagement) (*rateLimitProc,
 }
 
 func (r *rateLimitProc) Process(ctx context.Context, msg *message.Part) ([]*message.Part, error) {
+	var key string
+	if r.key != nil {
+		var err error
+		if key, err = r.key.String(0, message.Batch{msg}); err != nil {
+			return nil, fmt.Errorf("key interpolation error: %w", err)
+		}
+	}
+
 	for {
 		var waitFor time.Duration
 		var err error
 		if rerr := r.mgr.AccessRateLimit(ctx, r.rlName, func(rl ratelimit.V1) {
+			if kl, ok := rl.(ratelimit.KeyedV1); ok && r.key != nil {
+				waitFor, err = kl.AccessKey(ctx, key)
+				return
+			}
 			waitFor, err = rl.Access(ctx)
 		}); rerr != nil {
 			err = rerr
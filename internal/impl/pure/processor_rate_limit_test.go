@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -16,6 +17,7 @@ import (
 	"github.com/redpanda-data/benthos/v4/internal/component/testutil"
 	"github.com/redpanda-data/benthos/v4/internal/manager/mock"
 	"github.com/redpanda-data/benthos/v4/internal/message"
+	"github.com/redpanda-data/benthos/v4/public/service"
 
 	_ "github.com/redpanda-data/benthos/v4/internal/impl/pure"
 )
@@ -170,3 +172,55 @@ rate_limit:
 		t.Error("Timed out")
 	}
 }
+
+func TestRateLimitKeyed(t *testing.T) {
+	strmBuilder := service.NewStreamBuilder()
+	require.NoError(t, strmBuilder.SetYAML(`
+input:
+  generate:
+    interval: 1ns
+    count: 3
+    mapping: 'root = "tenant-" + counter().string()'
+
+pipeline:
+  processors:
+    - rate_limit:
+        resource: limited
+        key: ${! content() }
+
+rate_limit_resources:
+  - label: limited
+    local:
+      count: 1
+      interval: 1h
+
+logger:
+  level: NONE
+`))
+
+	var mut sync.Mutex
+	var received []string
+	require.NoError(t, strmBuilder.AddConsumerFunc(func(ctx context.Context, m *service.Message) error {
+		b, err := m.AsBytes()
+		if err != nil {
+			return err
+		}
+		mut.Lock()
+		received = append(received, string(b))
+		mut.Unlock()
+		return nil
+	}))
+
+	strm, err := strmBuilder.Build()
+	require.NoError(t, err)
+
+	// Each message has a distinct key and therefore none of them are limited
+	// by the previous ones.
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+	require.NoError(t, strm.Run(ctx))
+
+	mut.Lock()
+	assert.Equal(t, []string{"tenant-1", "tenant-2", "tenant-3"}, received)
+	mut.Unlock()
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	xmlFieldOperator        = "operator"
+	xmlFieldCast            = "cast"
+	xmlFieldAttributePrefix = "attribute_prefix"
+	xmlFieldTextKey         = "text_key"
+	xmlFieldForceArray      = "force_array"
+	xmlFieldIndent          = "indent"
+	xmlFieldRootTag         = "root_tag"
+)
+
+func xmlParsingFields() []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewBoolField(xmlFieldCast).
+			Description("Whether to convert values that represent numbers or booleans into those types. Numbers with leading zeros are left as strings.").
+			Default(false),
+		service.NewStringField(xmlFieldAttributePrefix).
+			Description("A prefix added to the keys of attributes.").
+			Default(xmlDefaultAttributePrefix).
+			Advanced(),
+		service.NewStringField(xmlFieldTextKey).
+			Description("The key of the text of elements that also contain attributes or child elements.").
+			Default(xmlDefaultTextKey).
+			Advanced(),
+		service.NewStringListField(xmlFieldForceArray).
+			Description("A list of dot separated paths of element names, starting with the root element, that are always converted into an array even when they appear only once.").
+			Example([]string{"order.item"}).
+			Default([]any{}),
+	}
+}
+
+func xmlOptionsFromParsed(conf *service.ParsedConfig) (*xmlOptions, error) {
+	opts := newXMLOptions()
+
+	var err error
+	if opts.cast, err = conf.FieldBool(xmlFieldCast); err != nil {
+		return nil, err
+	}
+	if opts.attrPrefix, err = conf.FieldString(xmlFieldAttributePrefix); err != nil {
+		return nil, err
+	}
+	if opts.textKey, err = conf.FieldString(xmlFieldTextKey); err != nil {
+		return nil, err
+	}
+	paths, err := conf.FieldStringList(xmlFieldForceArray)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range paths {
+		opts.forceArray[p] = struct{}{}
+	}
+	return opts, nil
+}
+
+func xmlProcConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Parsing").
+		Summary("Converts messages between XML documents and structured documents.").
+		Description(`
+The `+"`to_json`"+` operator parses the contents of each message as an XML document and replaces it with a structured document, and the `+"`from_json`"+` operator serializes a structured message back into an XML document. The conversion follows the same rules as the `+"xref:guides:bloblang/methods.adoc#parse_xml[`parse_xml`]"+` and `+"xref:guides:bloblang/methods.adoc#format_xml[`format_xml`]"+` Bloblang methods:
+
+- Elements are converted into keys of the object of their parent, and the root element is a key of the resulting object.
+- Attributes are converted into keys prefixed with the attribute prefix.
+- Elements that contain only text are converted into a single value. When an element also contains attributes or child elements its text is stored under the text key.
+- Elements that appear more than once within their parent are converted into an array.
+
+For example, the following XML document:
+
+`+"```xml"+`
+<root>
+  <title id="1">This is a title</title>
+  <content>This is some content</content>
+  <content>This is more content</content>
+</root>
+`+"```"+`
+
+Is converted by the `+"`to_json`"+` operator into:
+
+`+"```json"+`
+{
+  "root": {
+    "title": {
+      "-id": "1",
+      "#text": "This is a title"
+    },
+    "content": [
+      "This is some content",
+      "This is more content"
+    ]
+  }
+}
+`+"```"+`
+
+Messages that fail to convert remain unchanged and are flagged as having failed, allowing you to xref:configuration:error_handling.adoc[error handle them].`).
+		Fields(
+			service.NewStringAnnotatedEnumField(xmlFieldOperator, map[string]string{
+				"to_json":   "Parse the message as an XML document and replace it with a structured document.",
+				"from_json": "Serialize a structured message into an XML document.",
+			}).
+				Description("The conversion to perform."),
+		).
+		Fields(xmlParsingFields()...).
+		Fields(
+			service.NewStringField(xmlFieldIndent).
+				Description("An indentation string to add to each level of nested elements when serializing, no indentation is added by default.").
+				Default("").
+				Advanced(),
+			service.NewStringField(xmlFieldRootTag).
+				Description("An optional name of a root element that structured messages are wrapped in when serializing. When empty each message must be an object with a single key, which becomes the root element.").
+				Default("").
+				Advanced(),
+		).
+		Version("4.49.0").
+		Example("Consuming SOAP Responses", `
+In this example we extract the result of SOAP responses, converting numeric and boolean values into those types:`, `
+pipeline:
+  processors:
+    - xml:
+        operator: to_json
+        cast: true
+    - mapping: |
+        root = this."soap:Envelope"."soap:Body"."m:GetPriceResponse"
+`)
+}
+
+func init() {
+	err := service.RegisterProcessor(
+		"xml", xmlProcConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newXMLProcessorFromConfig(conf)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type xmlProcessor struct {
+	opts    *xmlOptions
+	toJSON  bool
+	indent  string
+	rootTag string
+}
+
+func newXMLProcessorFromConfig(conf *service.ParsedConfig) (*xmlProcessor, error) {
+	p := &xmlProcessor{}
+
+	operator, err := conf.FieldString(xmlFieldOperator)
+	if err != nil {
+		return nil, err
+	}
+	switch operator {
+	case "to_json":
+		p.toJSON = true
+	case "from_json":
+	default:
+		return nil, fmt.Errorf("operator not recognised: %v", operator)
+	}
+
+	if p.opts, err = xmlOptionsFromParsed(conf); err != nil {
+		return nil, err
+	}
+	if p.indent, err = conf.FieldString(xmlFieldIndent); err != nil {
+		return nil, err
+	}
+	if p.rootTag, err = conf.FieldString(xmlFieldRootTag); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *xmlProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	if p.toJSON {
+		mBytes, err := msg.AsBytes()
+		if err != nil {
+			return nil, err
+		}
+		v, err := p.opts.decodeDocument(mBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse message as XML: %w", err)
+		}
+		msg.SetStructuredMut(v)
+		return service.MessageBatch{msg}, nil
+	}
+
+	v, err := msg.AsStructured()
+	if err != nil {
+		return nil, err
+	}
+	xmlBytes, err := p.opts.encodeDocument(v, p.rootTag, p.indent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize message as XML: %w", err)
+	}
+	msg.SetBytes(xmlBytes)
+	return service.MessageBatch{msg}, nil
+}
+
+func (p *xmlProcessor) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func xmlProcess(t *testing.T, confStr, input string) (string, error) {
+	t.Helper()
+
+	conf, err := xmlProcConfig().ParseYAML(confStr, nil)
+	require.NoError(t, err)
+
+	proc, err := newXMLProcessorFromConfig(conf)
+	require.NoError(t, err)
+
+	batch, err := proc.Process(context.Background(), service.NewMessage([]byte(input)))
+	if err != nil {
+		return "", err
+	}
+	require.Len(t, batch, 1)
+
+	mBytes, err := batch[0].AsBytes()
+	require.NoError(t, err)
+	return string(mBytes), nil
+}
+
+func TestXMLProcessorToJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		conf   string
+		input  string
+		output string
+	}{
+		{
+			name: "basic",
+			conf: `operator: to_json`,
+			input: `<?xml version="1.0" encoding="UTF-8"?>
+<!-- a comment -->
+<root>
+  <title id="1">This is a title</title>
+  <content>This is some content</content>
+  <content>This is more content</content>
+  <empty/>
+</root>`,
+			output: `{"root":{"content":["This is some content","This is more content"],"empty":"","title":{"#text":"This is a title","-id":"1"}}}`,
+		},
+		{
+			name: "cast values",
+			conf: `
+operator: to_json
+cast: true
+`,
+			input:  `<root a="true"><int>10</int><neg>-5</neg><float>1.5</float><exp>1e3</exp><zip>01234</zip><zero>0</zero><str>10 apples</str><inf>Inf</inf></root>`,
+			output: `{"root":{"-a":true,"exp":1000,"float":1.5,"inf":"Inf","int":10,"neg":-5,"str":"10 apples","zero":0,"zip":"01234"}}`,
+		},
+		{
+			name: "force arrays and custom keys",
+			conf: `
+operator: to_json
+attribute_prefix: "@"
+text_key: _value
+force_array: [ root, root.item, root.item.tag ]
+`,
+			input:  `<root><item sku="a">apple<tag>fruit</tag></item></root>`,
+			output: `{"root":[{"item":[{"@sku":"a","_value":"apple","tag":["fruit"]}]}]}`,
+		},
+		{
+			name:   "namespaces",
+			conf:   `operator: to_json`,
+			input:  `<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope"><soap:Body><m:Price xmlns:m="https://www.example.org/stock">34.5</m:Price></soap:Body></soap:Envelope>`,
+			output: `{"soap:Envelope":{"-xmlns:soap":"http://www.w3.org/2003/05/soap-envelope","soap:Body":{"m:Price":{"#text":"34.5","-xmlns:m":"https://www.example.org/stock"}}}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output, err := xmlProcess(t, test.conf, test.input)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.output, output)
+		})
+	}
+}
+
+func TestXMLProcessorToJSONErrors(t *testing.T) {
+	for _, input := range []string{
+		``,
+		`not xml`,
+		`<root><foo></root>`,
+		`<root><foo>bar</foo>`,
+	} {
+		_, err := xmlProcess(t, `operator: to_json`, input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestXMLProcessorFromJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		conf        string
+		input       string
+		output      string
+		errContains string
+	}{
+		{
+			name:   "basic",
+			conf:   `operator: from_json`,
+			input:  `{"root":{"content":["This is some content","This is more content"],"empty":null,"count":5,"title":{"#text":"This is a <title>","-id":1}}}`,
+			output: `<root><content>This is some content</content><content>This is more content</content><count>5</count><empty></empty><title id="1">This is a &lt;title&gt;</title></root>`,
+		},
+		{
+			name: "indent and root tag",
+			conf: `
+operator: from_json
+indent: "  "
+root_tag: items
+`,
+			input: `{"item":[{"-sku":"a","name":"apple"},{"-sku":"b","name":"banana"}]}`,
+			output: `<items>
+  <item sku="a">
+    <name>apple</name>
+  </item>
+  <item sku="b">
+    <name>banana</name>
+  </item>
+</items>`,
+		},
+		{
+			name: "custom attribute prefix",
+			conf: `
+operator: from_json
+attribute_prefix: "@"
+root_tag: item
+`,
+			input:  `{"@sku":"a","name":"apple"}`,
+			output: `<item sku="a"><name>apple</name></item>`,
+		},
+		{
+			name:        "multiple roots",
+			conf:        `operator: from_json`,
+			input:       `{"a":"foo","b":"bar"}`,
+			errContains: "expected an object with a single key",
+		},
+		{
+			name:        "structured attribute",
+			conf:        `operator: from_json`,
+			input:       `{"root":{"-a":{"b":"c"}}}`,
+			errContains: "attribute -a of element <root> must be a scalar value",
+		},
+		{
+			name:        "invalid element name",
+			conf:        `operator: from_json`,
+			input:       `{"root":{"foo bar":"baz"}}`,
+			errContains: `invalid element name "foo bar"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output, err := xmlProcess(t, test.conf, test.input)
+			if test.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.output, output)
+		})
+	}
+}
+
+func TestXMLProcessorRoundTrip(t *testing.T) {
+	input := `<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope"><soap:Body><m:Price xmlns:m="https://www.example.org/stock">34.5</m:Price><m:Tags><m:Tag>a</m:Tag><m:Tag>b</m:Tag></m:Tags></soap:Body></soap:Envelope>`
+
+	structured, err := xmlProcess(t, `operator: to_json`, input)
+	require.NoError(t, err)
+
+	output, err := xmlProcess(t, `operator: from_json`, structured)
+	require.NoError(t, err)
+	assert.Equal(t, input, output)
+}
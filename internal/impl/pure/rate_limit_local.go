@@ -5,22 +5,57 @@ package pure
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/redpanda-data/benthos/v4/public/service"
 )
 
+const (
+	lrlFieldCount    = "count"
+	lrlFieldInterval = "interval"
+	lrlFieldMode     = "mode"
+	lrlFieldBurst    = "burst"
+
+	lrlModeFixedWindow   = "fixed_window"
+	lrlModeTokenBucket   = "token_bucket"
+	lrlModeSlidingLog    = "sliding_log"
+	lrlModeSlidingWindow = "sliding_window"
+)
+
 func localRatelimitConfig() *service.ConfigSpec {
 	spec := service.NewConfigSpec().
 		Stable().
 		Summary(`The local rate limit is a simple X every Y type rate limit that can be shared across any number of components within the pipeline but does not support distributed rate limits across multiple running instances of Benthos.`).
-		Field(service.NewIntField("count").
+		Description(`
+== Modes
+
+The algorithm used to enforce the limit is chosen with the field ` + "`mode`" + `. The default, ` + "`fixed_window`" + `, allows ` + "`count`" + ` requests within each consecutive ` + "`interval`" + `, which means bursts of up to twice the count are possible around the boundary between two intervals. The ` + "`sliding_log`" + ` and ` + "`sliding_window`" + ` modes remove this effect at the cost of additional bookkeeping, and the ` + "`token_bucket`" + ` mode allows requests at a steady rate whilst tolerating short bursts.
+
+== Keyed limits
+
+Components that support it, such as the ` + "xref:components:processors/rate_limit.adoc[`rate_limit` processor]" + `, are able to provide a key with each request, in which case the limit is enforced separately for each distinct key. This makes it possible to give each tenant of a pipeline its own limit from a single resource. The state of a key is discarded once it would be indistinguishable from that of a new key, and therefore memory usage scales with the number of keys seen within the most recent interval.`).
+		Field(service.NewIntField(lrlFieldCount).
 			Description("The maximum number of requests to allow for a given period of time.").
 			Default(1000)).
-		Field(service.NewDurationField("interval").
+		Field(service.NewDurationField(lrlFieldInterval).
 			Description("The time window to limit requests by.").
-			Default("1s"))
+			Default("1s")).
+		Field(service.NewStringAnnotatedEnumField(lrlFieldMode, map[string]string{
+			lrlModeFixedWindow:   "Allows `count` requests within consecutive, non-overlapping windows of `interval`.",
+			lrlModeTokenBucket:   "Refills a bucket of `burst` tokens at a rate of `count` tokens every `interval`, where each request consumes a token.",
+			lrlModeSlidingLog:    "Records the time of each allowed request and allows at most `count` within any `interval` period. This mode is exact but stores up to `count` timestamps for each key.",
+			lrlModeSlidingWindow: "Estimates the number of requests within the last `interval` by weighting the count of the previous window according to how much of it overlaps. This is an approximation of `sliding_log` that requires constant memory.",
+		}).
+			Description("The algorithm used to enforce the limit.").
+			Default(lrlModeFixedWindow).
+			Version("4.49.0")).
+		Field(service.NewIntField(lrlFieldBurst).
+			Description("The maximum number of tokens held by the bucket when the mode is `" + lrlModeTokenBucket + "`, which determines the largest burst of requests allowed after a period of inactivity. Defaults to the value of `count`.").
+			Optional().
+			Advanced().
+			Version("4.49.0"))
 
 	return spec
 }
@@ -37,59 +72,275 @@ func init() {
 }
 
 func newLocalRatelimitFromConfig(conf *service.ParsedConfig) (*localRatelimit, error) {
-	count, err := conf.FieldInt("count")
+	count, err := conf.FieldInt(lrlFieldCount)
 	if err != nil {
 		return nil, err
 	}
-	interval, err := conf.FieldDuration("interval")
+	interval, err := conf.FieldDuration(lrlFieldInterval)
 	if err != nil {
 		return nil, err
 	}
-	return newLocalRatelimit(count, interval)
+	mode, err := conf.FieldString(lrlFieldMode)
+	if err != nil {
+		return nil, err
+	}
+	burst := count
+	if conf.Contains(lrlFieldBurst) {
+		if burst, err = conf.FieldInt(lrlFieldBurst); err != nil {
+			return nil, err
+		}
+		if mode != lrlModeTokenBucket {
+			return nil, fmt.Errorf("field %v is only supported by the %v mode", lrlFieldBurst, lrlModeTokenBucket)
+		}
+	}
+	return newLocalRatelimit(mode, count, burst, interval)
 }
 
 //------------------------------------------------------------------------------
 
+// localLimiter is the state of a rate limit for a single key.
+type localLimiter interface {
+	// access attempts to consume a request at the given time, returning either
+	// zero or the duration to wait before trying again.
+	access(now time.Time) time.Duration
+
+	// idle returns true when the state is equivalent to that of a fresh
+	// limiter and can therefore be discarded.
+	idle(now time.Time) bool
+}
+
 type localRatelimit struct {
-	mut         sync.Mutex
-	bucket      int
-	lastRefresh time.Time
+	mut       sync.Mutex
+	limiters  map[string]localLimiter
+	lastSweep time.Time
 
-	size   int
-	period time.Duration
+	period     time.Duration
+	newLimiter func(now time.Time) localLimiter
 }
 
-func newLocalRatelimit(count int, interval time.Duration) (*localRatelimit, error) {
+func newLocalRatelimit(mode string, count, burst int, interval time.Duration) (*localRatelimit, error) {
 	if count <= 0 {
 		return nil, errors.New("count must be larger than zero")
 	}
+
+	var newLimiter func(now time.Time) localLimiter
+	switch mode {
+	case lrlModeFixedWindow:
+		newLimiter = func(now time.Time) localLimiter {
+			return &fixedWindowLimiter{bucket: count, lastRefresh: now, size: count, period: interval}
+		}
+	case lrlModeTokenBucket:
+		if burst <= 0 {
+			return nil, errors.New("burst must be larger than zero")
+		}
+		if interval <= 0 {
+			return nil, errors.New("interval must be larger than zero")
+		}
+		rate := float64(count) / float64(interval)
+		newLimiter = func(now time.Time) localLimiter {
+			return &tokenBucketLimiter{tokens: float64(burst), last: now, capacity: float64(burst), rate: rate}
+		}
+	case lrlModeSlidingLog:
+		newLimiter = func(now time.Time) localLimiter {
+			return &slidingLogLimiter{log: make([]time.Time, 0, count), size: count, period: interval}
+		}
+	case lrlModeSlidingWindow:
+		if interval <= 0 {
+			return nil, errors.New("interval must be larger than zero")
+		}
+		newLimiter = func(now time.Time) localLimiter {
+			return &slidingWindowLimiter{windowStart: now, size: count, period: interval}
+		}
+	default:
+		return nil, fmt.Errorf("mode not recognised: %v", mode)
+	}
+
 	return &localRatelimit{
-		bucket:      count,
-		lastRefresh: time.Now(),
-		size:        count,
-		period:      interval,
+		limiters:   map[string]localLimiter{},
+		lastSweep:  time.Now(),
+		period:     interval,
+		newLimiter: newLimiter,
 	}, nil
 }
 
 func (r *localRatelimit) Access(ctx context.Context) (time.Duration, error) {
+	return r.AccessKey(ctx, "")
+}
+
+func (r *localRatelimit) AccessKey(ctx context.Context, key string) (time.Duration, error) {
+	now := time.Now()
+
 	r.mut.Lock()
-	r.bucket--
+	defer r.mut.Unlock()
 
-	if r.bucket < 0 {
-		r.bucket = 0
-		remaining := r.period - time.Since(r.lastRefresh)
+	r.sweepLocked(now)
 
-		if remaining > 0 {
-			r.mut.Unlock()
-			return remaining, nil
+	l, exists := r.limiters[key]
+	if !exists {
+		l = r.newLimiter(now)
+		r.limiters[key] = l
+	}
+	return l.access(now), nil
+}
+
+// sweepLocked discards the state of keys that have been idle for long enough
+// to be indistinguishable from new ones. The sweep runs at most once per
+// interval, and no more than once a second, so that its cost is amortised
+// across many requests.
+func (r *localRatelimit) sweepLocked(now time.Time) {
+	if len(r.limiters) <= 1 || now.Sub(r.lastSweep) < max(r.period, time.Second) {
+		return
+	}
+	r.lastSweep = now
+	for k, l := range r.limiters {
+		if l.idle(now) {
+			delete(r.limiters, k)
 		}
-		r.bucket = r.size - 1
-		r.lastRefresh = time.Now()
 	}
-	r.mut.Unlock()
-	return 0, nil
 }
 
 func (r *localRatelimit) Close(ctx context.Context) error {
 	return nil
 }
+
+//------------------------------------------------------------------------------
+
+type fixedWindowLimiter struct {
+	bucket      int
+	lastRefresh time.Time
+
+	size   int
+	period time.Duration
+}
+
+func (l *fixedWindowLimiter) access(now time.Time) time.Duration {
+	l.bucket--
+
+	if l.bucket < 0 {
+		l.bucket = 0
+		remaining := l.period - now.Sub(l.lastRefresh)
+
+		if remaining > 0 {
+			return remaining
+		}
+		l.bucket = l.size - 1
+		l.lastRefresh = now
+	}
+	return 0
+}
+
+func (l *fixedWindowLimiter) idle(now time.Time) bool {
+	return now.Sub(l.lastRefresh) >= l.period
+}
+
+//------------------------------------------------------------------------------
+
+type tokenBucketLimiter struct {
+	tokens float64
+	last   time.Time
+
+	capacity float64
+	rate     float64 // Tokens per nanosecond
+}
+
+func (l *tokenBucketLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = min(l.capacity, l.tokens+float64(elapsed)*l.rate)
+		l.last = now
+	}
+}
+
+func (l *tokenBucketLimiter) access(now time.Time) time.Duration {
+	l.refill(now)
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return max(time.Duration((1-l.tokens)/l.rate), 1)
+}
+
+func (l *tokenBucketLimiter) idle(now time.Time) bool {
+	l.refill(now)
+	return l.tokens >= l.capacity
+}
+
+//------------------------------------------------------------------------------
+
+type slidingLogLimiter struct {
+	// Ring buffer of the times of allowed requests, oldest first from head.
+	log  []time.Time
+	head int
+
+	size   int
+	period time.Duration
+}
+
+func (l *slidingLogLimiter) access(now time.Time) time.Duration {
+	if len(l.log) < l.size {
+		l.log = append(l.log, now)
+		return 0
+	}
+	if remaining := l.period - now.Sub(l.log[l.head]); remaining > 0 {
+		return remaining
+	}
+	l.log[l.head] = now
+	l.head = (l.head + 1) % l.size
+	return 0
+}
+
+func (l *slidingLogLimiter) idle(now time.Time) bool {
+	if len(l.log) == 0 {
+		return true
+	}
+	newest := l.log[(l.head+len(l.log)-1)%len(l.log)]
+	return now.Sub(newest) >= l.period
+}
+
+//------------------------------------------------------------------------------
+
+type slidingWindowLimiter struct {
+	windowStart time.Time
+	current     int
+	previous    int
+
+	size   int
+	period time.Duration
+}
+
+func (l *slidingWindowLimiter) advance(now time.Time) {
+	elapsed := now.Sub(l.windowStart)
+	if elapsed < l.period {
+		return
+	}
+	if elapsed < 2*l.period {
+		l.previous = l.current
+	} else {
+		l.previous = 0
+	}
+	l.current = 0
+	l.windowStart = l.windowStart.Add(elapsed - elapsed%l.period)
+}
+
+func (l *slidingWindowLimiter) access(now time.Time) time.Duration {
+	l.advance(now)
+
+	elapsed := now.Sub(l.windowStart)
+	overlap := 1 - float64(elapsed)/float64(l.period)
+	if float64(l.previous)*overlap+float64(l.current) < float64(l.size) {
+		l.current++
+		return 0
+	}
+
+	// Wait until the weight of the previous window has decayed enough for a
+	// request to fit, or until the next window when the current one is full.
+	untilNext := l.period - elapsed
+	if l.previous == 0 || l.current >= l.size {
+		return untilNext
+	}
+	target := time.Duration(float64(l.period) * (1 - float64(l.size-l.current)/float64(l.previous)))
+	return min(max(target-elapsed, 1), untilNext)
+}
+
+func (l *slidingWindowLimiter) idle(now time.Time) bool {
+	return now.Sub(l.windowStart) >= 2*l.period
+}
@@ -4,6 +4,7 @@ package pure
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -91,6 +92,188 @@ interval: 10ms
 	}
 }
 
+func TestLocalRateLimitModeConfErrors(t *testing.T) {
+	for _, conf := range []string{
+		`mode: nope`,
+		`burst: 10`,
+		"mode: token_bucket\nburst: 0",
+		"mode: token_bucket\ninterval: 0s",
+		"mode: sliding_window\ninterval: 0s",
+	} {
+		parsed, err := localRatelimitConfig().ParseYAML(conf, nil)
+		if err != nil {
+			continue
+		}
+		_, err = newLocalRatelimitFromConfig(parsed)
+		assert.Error(t, err, conf)
+	}
+}
+
+func TestLocalRateLimitModes(t *testing.T) {
+	for _, mode := range []string{"fixed_window", "token_bucket", "sliding_log", "sliding_window"} {
+		t.Run(mode, func(t *testing.T) {
+			conf, err := localRatelimitConfig().ParseYAML(fmt.Sprintf(`
+count: 10
+interval: 50ms
+mode: %v
+`, mode), nil)
+			require.NoError(t, err)
+
+			rl, err := newLocalRatelimitFromConfig(conf)
+			require.NoError(t, err)
+
+			ctx := context.Background()
+
+			for i := 0; i < 10; i++ {
+				period, _ := rl.Access(ctx)
+				assert.Equal(t, time.Duration(0), period, i)
+			}
+
+			period, _ := rl.Access(ctx)
+			assert.Greater(t, period, time.Duration(0))
+			assert.LessOrEqual(t, period, time.Millisecond*50)
+
+			<-time.After(time.Millisecond * 110)
+
+			period, _ = rl.Access(ctx)
+			assert.Equal(t, time.Duration(0), period)
+		})
+	}
+}
+
+func TestLocalRateLimitTokenBucket(t *testing.T) {
+	conf, err := localRatelimitConfig().ParseYAML(`
+count: 1
+interval: 20ms
+mode: token_bucket
+burst: 3
+`, nil)
+	require.NoError(t, err)
+
+	rl, err := newLocalRatelimitFromConfig(conf)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		period, _ := rl.Access(ctx)
+		assert.Equal(t, time.Duration(0), period, i)
+	}
+
+	// Tokens refill one at a time rather than all at once.
+	period, _ := rl.Access(ctx)
+	assert.Greater(t, period, time.Duration(0))
+	assert.LessOrEqual(t, period, time.Millisecond*20)
+
+	<-time.After(period + time.Millisecond*5)
+
+	period, _ = rl.Access(ctx)
+	assert.Equal(t, time.Duration(0), period)
+
+	period, _ = rl.Access(ctx)
+	assert.Greater(t, period, time.Duration(0))
+}
+
+func TestLocalRateLimitSlidingLog(t *testing.T) {
+	rl, err := newLocalRatelimit("sliding_log", 2, 2, time.Millisecond*60)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	period, _ := rl.Access(ctx)
+	assert.Equal(t, time.Duration(0), period)
+
+	<-time.After(time.Millisecond * 30)
+
+	period, _ = rl.Access(ctx)
+	assert.Equal(t, time.Duration(0), period)
+
+	// The oldest request expires before the most recent one, which allows a
+	// single request through without waiting for a whole interval.
+	period, _ = rl.Access(ctx)
+	assert.Greater(t, period, time.Duration(0))
+	assert.LessOrEqual(t, period, time.Millisecond*30)
+
+	<-time.After(period + time.Millisecond*5)
+
+	period, _ = rl.Access(ctx)
+	assert.Equal(t, time.Duration(0), period)
+
+	period, _ = rl.Access(ctx)
+	assert.Greater(t, period, time.Duration(0))
+}
+
+func TestLocalRateLimitSlidingWindow(t *testing.T) {
+	now := time.Now()
+	l := &slidingWindowLimiter{windowStart: now, size: 10, period: time.Second}
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, time.Duration(0), l.access(now), i)
+	}
+	assert.Equal(t, time.Second, l.access(now))
+
+	// A quarter of the way into the next window the previous window still
+	// counts for three quarters of its requests.
+	now = now.Add(time.Second + time.Millisecond*250)
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, time.Duration(0), l.access(now), i)
+	}
+	assert.Equal(t, time.Millisecond*50, l.access(now))
+
+	now = now.Add(time.Millisecond * 51)
+	assert.Equal(t, time.Duration(0), l.access(now))
+	assert.False(t, l.idle(now))
+	assert.True(t, l.idle(now.Add(time.Second*2)))
+}
+
+func TestLocalRateLimitKeyed(t *testing.T) {
+	conf, err := localRatelimitConfig().ParseYAML(`
+count: 2
+interval: 1h
+`, nil)
+	require.NoError(t, err)
+
+	rl, err := newLocalRatelimitFromConfig(conf)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	for _, key := range []string{"foo", "bar", "foo", "bar"} {
+		period, _ := rl.AccessKey(ctx, key)
+		assert.Equal(t, time.Duration(0), period, key)
+	}
+
+	for _, key := range []string{"foo", "bar"} {
+		period, _ := rl.AccessKey(ctx, key)
+		assert.Greater(t, period, time.Duration(0), key)
+	}
+
+	period, _ := rl.AccessKey(ctx, "baz")
+	assert.Equal(t, time.Duration(0), period)
+
+	// Unkeyed access has its own limit.
+	period, _ = rl.Access(ctx)
+	assert.Equal(t, time.Duration(0), period)
+}
+
+func TestLocalRateLimitKeySweep(t *testing.T) {
+	rl, err := newLocalRatelimit("token_bucket", 10, 10, time.Millisecond*10)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for _, key := range []string{"foo", "bar", "baz"} {
+		_, _ = rl.AccessKey(ctx, key)
+	}
+	assert.Len(t, rl.limiters, 3)
+
+	// Force a sweep once the buckets have refilled.
+	rl.lastSweep = time.Now().Add(-time.Hour)
+	<-time.After(time.Millisecond * 20)
+
+	_, _ = rl.AccessKey(ctx, "buz")
+	assert.Len(t, rl.limiters, 1)
+}
+
 //------------------------------------------------------------------------------
 
 func BenchmarkRateLimit(b *testing.B) {
@@ -0,0 +1,74 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"context"
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func cborScannerSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.49.0").
+		Summary("Consumes a stream of concatenated https://cbor.io/[CBOR^] values and emits a structured message for each value.").
+		Description("Values are converted following the same rules as the xref:guides:bloblang/methods.adoc#parse_cbor[`parse_cbor`] Bloblang method.").
+		Field(service.NewObjectField("").Default(map[string]any{}))
+}
+
+func init() {
+	err := service.RegisterBatchScannerCreator("cbor", cborScannerSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchScannerCreator, error) {
+			return &cborScannerCreator{}, nil
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type cborScannerCreator struct{}
+
+func (c *cborScannerCreator) Create(rdr io.ReadCloser, aFn service.AckFunc, details *service.ScannerSourceDetails) (service.BatchScanner, error) {
+	return service.AutoAggregateBatchScannerAcks(&cborScanner{
+		d: cbor.NewDecoder(rdr),
+		r: rdr,
+	}, aFn), nil
+}
+
+func (c *cborScannerCreator) Close(context.Context) error {
+	return nil
+}
+
+type cborScanner struct {
+	d *cbor.Decoder
+	r io.ReadCloser
+}
+
+func (c *cborScanner) NextBatch(ctx context.Context) (service.MessageBatch, error) {
+	if c.r == nil {
+		return nil, io.EOF
+	}
+
+	v, err := decodeCBOR(c.d)
+	if err != nil {
+		_ = c.r.Close()
+		c.r = nil
+		return nil, err
+	}
+
+	msg := service.NewMessage(nil)
+	msg.SetStructuredMut(v)
+
+	return service.MessageBatch{msg}, nil
+}
+
+func (c *cborScanner) Close(ctx context.Context) error {
+	if c.r == nil {
+		return nil
+	}
+	return c.r.Close()
+}
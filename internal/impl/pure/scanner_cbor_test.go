@@ -0,0 +1,90 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/internal/component/scanner/testutil"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestCBORScannerSuite(t *testing.T) {
+	confSpec := service.NewConfigSpec().Field(service.NewScannerField("test"))
+	pConf, err := confSpec.ParseYAML(`
+test:
+  cbor: {}
+`, nil)
+	require.NoError(t, err)
+
+	rdr, err := pConf.FieldScanner("test")
+	require.NoError(t, err)
+
+	var data []byte
+	for _, v := range []any{
+		map[string]any{"a": "a0"},
+		map[string]any{"a": 1},
+		[]any{"foo", 2.5},
+		"bar",
+		nil,
+	} {
+		b, err := cbor.Marshal(v)
+		require.NoError(t, err)
+		data = append(data, b...)
+	}
+
+	testutil.ScannerTestSuite(t, rdr, nil, data,
+		`{"a":"a0"}`,
+		`{"a":1}`,
+		`["foo",2.5]`,
+		`"bar"`,
+		`null`,
+	)
+}
+
+func TestCBORScannerBadData(t *testing.T) {
+	confSpec := service.NewConfigSpec().Field(service.NewScannerField("test"))
+	pConf, err := confSpec.ParseYAML(`
+test:
+  cbor: {}
+`, nil)
+	require.NoError(t, err)
+
+	rdr, err := pConf.FieldScanner("test")
+	require.NoError(t, err)
+
+	first, err := cbor.Marshal(map[string]any{"a": "a0"})
+	require.NoError(t, err)
+
+	var ack error
+
+	// The second value is a map declaring two entries but only containing one.
+	scanner, err := rdr.Create(io.NopCloser(strings.NewReader(string(first)+"\xa2\x61a\x61b")), func(ctx context.Context, err error) error {
+		ack = err
+		return nil
+	}, &service.ScannerSourceDetails{})
+	require.NoError(t, err)
+
+	resBatch, aFn, err := scanner.NextBatch(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, aFn(context.Background(), nil))
+	require.Len(t, resBatch, 1)
+	mBytes, err := resBatch[0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":"a0"}`, string(mBytes))
+
+	_, _, err = scanner.NextBatch(context.Background())
+	assert.Error(t, err)
+
+	_, _, err = scanner.NextBatch(context.Background())
+	assert.ErrorIs(t, err, io.EOF)
+
+	assert.ErrorIs(t, ack, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"bufio"
+	"context"
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func msgpackScannerSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.49.0").
+		Summary("Consumes a stream of concatenated https://msgpack.org/[MessagePack^] values and emits a structured message for each value.").
+		Description("Values are converted following the same rules as the xref:guides:bloblang/methods.adoc#parse_msgpack[`parse_msgpack`] Bloblang method.").
+		Field(service.NewObjectField("").Default(map[string]any{}))
+}
+
+func init() {
+	err := service.RegisterBatchScannerCreator("msgpack", msgpackScannerSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchScannerCreator, error) {
+			return &msgpackScannerCreator{}, nil
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type msgpackScannerCreator struct{}
+
+func (m *msgpackScannerCreator) Create(rdr io.ReadCloser, aFn service.AckFunc, details *service.ScannerSourceDetails) (service.BatchScanner, error) {
+	buf := bufio.NewReader(rdr)
+	return service.AutoAggregateBatchScannerAcks(&msgpackScanner{
+		buf: buf,
+		d:   newMsgpackDecoder(buf),
+		r:   rdr,
+	}, aFn), nil
+}
+
+func (m *msgpackScannerCreator) Close(context.Context) error {
+	return nil
+}
+
+type msgpackScanner struct {
+	buf *bufio.Reader
+	d   *msgpack.Decoder
+	r   io.ReadCloser
+}
+
+func (m *msgpackScanner) NextBatch(ctx context.Context) (service.MessageBatch, error) {
+	if m.r == nil {
+		return nil, io.EOF
+	}
+
+	// The decoder reads from the same buffer, and so peeking allows us to
+	// distinguish the end of the stream from a truncated value.
+	_, err := m.buf.Peek(1)
+	var v any
+	if err == nil {
+		v, err = decodeMsgpack(m.d)
+	}
+	if err != nil {
+		_ = m.r.Close()
+		m.r = nil
+		return nil, err
+	}
+
+	msg := service.NewMessage(nil)
+	msg.SetStructuredMut(v)
+
+	return service.MessageBatch{msg}, nil
+}
+
+func (m *msgpackScanner) Close(ctx context.Context) error {
+	if m.r == nil {
+		return nil
+	}
+	return m.r.Close()
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	xsFieldElementPath = "element_path"
+)
+
+func xmlScannerSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.49.0").
+		Summary("Consumes a stream of XML and emits a message for each occurrence of a repeated element, without reading the entire document into memory.").
+		Description(`
+Each message is a structured document of the value of an element found at the configured path, converted following the same rules as the ` + "xref:guides:bloblang/methods.adoc#parse_xml[`parse_xml`]" + ` Bloblang method. Elements outside of the path are ignored.
+
+For example, with an ` + "`element_path`" + ` of ` + "`feed.entry`" + ` the following document:
+
+` + "```xml" + `
+<feed>
+  <title>Example Feed</title>
+  <entry id="1"><title>First</title></entry>
+  <entry id="2"><title>Second</title></entry>
+</feed>
+` + "```" + `
+
+Is emitted as the messages ` + "`{\"-id\":\"1\",\"title\":\"First\"}`" + ` and ` + "`{\"-id\":\"2\",\"title\":\"Second\"}`" + `.`).
+		Fields(
+			service.NewStringField(xsFieldElementPath).
+				Description("A dot separated path of element names, starting with the root element, of the element to emit messages for.").
+				Example("feed.entry").
+				Example("soap:Envelope.soap:Body.item"),
+		).
+		Fields(xmlParsingFields()...)
+}
+
+func init() {
+	err := service.RegisterBatchScannerCreator("xml", xmlScannerSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchScannerCreator, error) {
+			return xmlScannerCreatorFromParsed(conf)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func xmlScannerCreatorFromParsed(conf *service.ParsedConfig) (*xmlScannerCreator, error) {
+	c := &xmlScannerCreator{}
+
+	var err error
+	if c.elementPath, err = conf.FieldString(xsFieldElementPath); err != nil {
+		return nil, err
+	}
+	if c.elementPath = strings.Trim(c.elementPath, "."); c.elementPath == "" {
+		return nil, errors.New("an element path must be specified")
+	}
+	if c.opts, err = xmlOptionsFromParsed(conf); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type xmlScannerCreator struct {
+	elementPath string
+	opts        *xmlOptions
+}
+
+func (c *xmlScannerCreator) Create(rdr io.ReadCloser, aFn service.AckFunc, details *service.ScannerSourceDetails) (service.BatchScanner, error) {
+	return service.AutoAggregateBatchScannerAcks(&xmlScanner{
+		elementPath: c.elementPath,
+		opts:        c.opts,
+		d:           xml.NewDecoder(rdr),
+		r:           rdr,
+	}, aFn), nil
+}
+
+func (c *xmlScannerCreator) Close(context.Context) error {
+	return nil
+}
+
+type xmlScanner struct {
+	elementPath string
+	opts        *xmlOptions
+
+	// The names of the elements that encapsulate the current position.
+	stack []string
+
+	d *xml.Decoder
+	r io.ReadCloser
+}
+
+func (x *xmlScanner) nextElement() (any, error) {
+	for {
+		tok, err := x.d.RawToken()
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(x.stack) > 0 {
+				err = fmt.Errorf("element <%v> is not closed", x.stack[len(x.stack)-1])
+			}
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := xmlName(t.Name)
+			path := xmlPath(strings.Join(x.stack, "."), name)
+			if path == x.elementPath {
+				return x.opts.decodeElement(x.d, t, path)
+			}
+			x.stack = append(x.stack, name)
+		case xml.EndElement:
+			if len(x.stack) == 0 {
+				return nil, fmt.Errorf("unexpected closing element </%v>", xmlName(t.Name))
+			}
+			if name, endName := x.stack[len(x.stack)-1], xmlName(t.Name); name != endName {
+				return nil, fmt.Errorf("element <%v> closed by </%v>", name, endName)
+			}
+			x.stack = x.stack[:len(x.stack)-1]
+		}
+	}
+}
+
+func (x *xmlScanner) NextBatch(ctx context.Context) (service.MessageBatch, error) {
+	if x.r == nil {
+		return nil, io.EOF
+	}
+
+	v, err := x.nextElement()
+	if err != nil {
+		_ = x.r.Close()
+		x.r = nil
+		return nil, err
+	}
+
+	msg := service.NewMessage(nil)
+	msg.SetStructuredMut(v)
+
+	return service.MessageBatch{msg}, nil
+}
+
+func (x *xmlScanner) Close(ctx context.Context) error {
+	if x.r == nil {
+		return nil
+	}
+	return x.r.Close()
+}
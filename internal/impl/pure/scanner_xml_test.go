@@ -0,0 +1,86 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/internal/component/scanner/testutil"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestXMLScannerSuite(t *testing.T) {
+	confSpec := service.NewConfigSpec().Field(service.NewScannerField("test"))
+	pConf, err := confSpec.ParseYAML(`
+test:
+  xml:
+    element_path: feed.entry
+    cast: true
+    force_array: [ feed.entry.tag ]
+`, nil)
+	require.NoError(t, err)
+
+	rdr, err := pConf.FieldScanner("test")
+	require.NoError(t, err)
+
+	testutil.ScannerTestSuite(t, rdr, nil, []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<feed>
+  <title>Example Feed</title>
+  <entry id="1"><title>First</title><tag>a</tag></entry>
+  <other><entry>ignored</entry></other>
+  <entry id="2"><title>Second</title><tag>a</tag><tag>b</tag></entry>
+  <entry>third</entry>
+</feed>
+`),
+		`{"-id":1,"tag":["a"],"title":"First"}`,
+		`{"-id":2,"tag":["a","b"],"title":"Second"}`,
+		`"third"`,
+	)
+}
+
+func TestXMLScannerBadData(t *testing.T) {
+	confSpec := service.NewConfigSpec().Field(service.NewScannerField("test"))
+	pConf, err := confSpec.ParseYAML(`
+test:
+  xml:
+    element_path: feed.entry
+`, nil)
+	require.NoError(t, err)
+
+	rdr, err := pConf.FieldScanner("test")
+	require.NoError(t, err)
+
+	var ack error
+
+	scanner, err := rdr.Create(io.NopCloser(strings.NewReader(`<feed>
+  <entry>first</entry>
+  <entry>second</nope>
+  <entry>third</entry>
+</feed>`)), func(ctx context.Context, err error) error {
+		ack = err
+		return nil
+	}, &service.ScannerSourceDetails{})
+	require.NoError(t, err)
+
+	resBatch, aFn, err := scanner.NextBatch(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, aFn(context.Background(), nil))
+	require.Len(t, resBatch, 1)
+	mBytes, err := resBatch[0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, `"first"`, string(mBytes))
+
+	_, _, err = scanner.NextBatch(context.Background())
+	assert.Error(t, err)
+
+	_, _, err = scanner.NextBatch(context.Background())
+	assert.ErrorIs(t, err, io.EOF)
+
+	assert.ErrorContains(t, ack, "element <entry> closed by </nope>")
+}
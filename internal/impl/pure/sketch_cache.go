@@ -0,0 +1,164 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	sketchFieldCache    = "cache"
+	sketchFieldCacheKey = "cache_key"
+	sketchFieldKey      = "key"
+)
+
+func sketchCacheFields(defaultCacheKey string) []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewStringField(sketchFieldCache).
+			Description("The xref:components:caches/about.adoc[`cache` resource] in which the sketch is persisted."),
+		service.NewStringField(sketchFieldCacheKey).
+			Description("The key under which the sketch is stored within the cache. Processors that share a cache and key also share the sketch.").
+			Default(defaultCacheKey),
+		service.NewInterpolatedStringField(sketchFieldKey).
+			Description("An interpolated string yielding the value to add for each message.").
+			Examples(`${! meta("kafka_key") }`, `${! json("user.id") }`),
+	}
+}
+
+func sketchPersistenceDescription() string {
+	return `
+Caches must be configured as resources, for more information check out the xref:components:caches/about.adoc[cache documentation].
+
+== Persistence
+
+The sketch is read from the cache at the beginning of each batch and written back at the end of it, and therefore survives restarts when the cache is persistent. Batches processed by this processor within the same process are serialized, but updates made by separate processes sharing the same cache key are not coordinated and may overwrite one another. In order to share a sketch across multiple instances partition messages by key and give each partition its own cache key instead.
+
+Batches fail with an error when the sketch stored within the cache was created with parameters that differ from those of the processor, in which case either the parameters must be restored or a different cache key used.`
+}
+
+type sketchEncoding interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// cachedSketch reads, modifies and writes back a sketch persisted within a
+// cache resource.
+type cachedSketch[T sketchEncoding] struct {
+	res       *service.Resources
+	cacheName string
+	cacheKey  string
+	newFn     func() (T, error)
+	paramsFn  func(T) any
+	params    any
+
+	mut sync.Mutex
+}
+
+// newCachedSketchFromParsed creates a cachedSketch where newFn creates an empty
+// sketch with the configured parameters, and paramsFn returns a comparable
+// representation of the parameters of a sketch, which is used in order to
+// reject sketches read from the cache that were created with different
+// parameters.
+func newCachedSketchFromParsed[T sketchEncoding](conf *service.ParsedConfig, res *service.Resources, newFn func() (T, error), paramsFn func(T) any) (*cachedSketch[T], error) {
+	cacheName, err := conf.FieldString(sketchFieldCache)
+	if err != nil {
+		return nil, err
+	}
+	if !res.HasCache(cacheName) {
+		return nil, fmt.Errorf("cache resource '%v' was not found", cacheName)
+	}
+
+	cacheKey, err := conf.FieldString(sketchFieldCacheKey)
+	if err != nil {
+		return nil, err
+	}
+	if cacheKey == "" {
+		return nil, errors.New("cache_key must not be empty")
+	}
+
+	// Ensure that the sketch parameters are valid before the first batch.
+	s, err := newFn()
+	if err != nil {
+		return nil, err
+	}
+
+	return &cachedSketch[T]{
+		res:       res,
+		cacheName: cacheName,
+		cacheKey:  cacheKey,
+		newFn:     newFn,
+		paramsFn:  paramsFn,
+		params:    paramsFn(s),
+	}, nil
+}
+
+// update reads the sketch from the cache, or creates a new one when it does not
+// exist, calls a closure with it, and writes it back to the cache if the
+// closure reports a modification. An error is returned if the sketch within
+// the cache was created with different parameters.
+func (c *cachedSketch[T]) update(ctx context.Context, fn func(s T) (modified bool)) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	var data []byte
+	var err error
+	if cerr := c.res.AccessCache(ctx, c.cacheName, func(cache service.Cache) {
+		data, err = cache.Get(ctx, c.cacheKey)
+	}); cerr != nil {
+		return cerr
+	}
+	if err != nil && !errors.Is(err, service.ErrKeyNotFound) {
+		return fmt.Errorf("failed to read sketch from cache: %w", err)
+	}
+
+	s, nErr := c.newFn()
+	if nErr != nil {
+		return nErr
+	}
+	if err == nil {
+		if err = s.UnmarshalBinary(data); err != nil {
+			return fmt.Errorf("failed to decode sketch from cache, this indicates the data was not set by this processor: %w", err)
+		}
+		if params := c.paramsFn(s); params != c.params {
+			return fmt.Errorf("sketch within cache was created with different parameters %+v, expected %+v", params, c.params)
+		}
+	}
+
+	if !fn(s) {
+		return nil
+	}
+
+	if data, err = s.MarshalBinary(); err != nil {
+		return err
+	}
+	if cerr := c.res.AccessCache(ctx, c.cacheName, func(cache service.Cache) {
+		err = cache.Set(ctx, c.cacheKey, data, nil)
+	}); cerr != nil {
+		return cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write sketch to cache: %w", err)
+	}
+	return nil
+}
+
+// sketchKeys resolves the key of each message of a batch, flagging messages
+// where the interpolation fails with an error and returning a nil key for them.
+func sketchKeys(batch service.MessageBatch, key *service.InterpolatedString) [][]byte {
+	keys := make([][]byte, len(batch))
+	for i, msg := range batch {
+		k, err := batch.TryInterpolatedBytes(i, key)
+		if err != nil {
+			msg.SetError(fmt.Errorf("key interpolation error: %w", err))
+			continue
+		}
+		keys[i] = k
+	}
+	return keys
+}
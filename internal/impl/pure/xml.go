@@ -0,0 +1,288 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package pure
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/redpanda-data/benthos/v4/internal/value"
+)
+
+const (
+	xmlDefaultAttributePrefix = "-"
+	xmlDefaultTextKey         = "#text"
+)
+
+// xmlOptions describes how XML documents are converted to and from
+// structured values. Elements become object keys, attributes become keys
+// prefixed with attrPrefix, and the text of elements that also contain
+// attributes or child elements is stored under textKey. Elements that contain
+// only text become a single value, and elements that repeat within the same
+// parent become an array.
+type xmlOptions struct {
+	attrPrefix string
+	textKey    string
+	cast       bool
+
+	// Dot separated paths of element names, starting with the root element,
+	// that are always converted into an array even when they appear once.
+	forceArray map[string]struct{}
+}
+
+func newXMLOptions() *xmlOptions {
+	return &xmlOptions{
+		attrPrefix: xmlDefaultAttributePrefix,
+		textKey:    xmlDefaultTextKey,
+		forceArray: map[string]struct{}{},
+	}
+}
+
+func xmlName(n xml.Name) string {
+	if n.Space != "" {
+		return n.Space + ":" + n.Local
+	}
+	return n.Local
+}
+
+func xmlPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// xmlCastValue converts a text value into a bool or a number when it
+// represents one. Numbers with leading zeros are left as strings as they are
+// commonly identifiers.
+func xmlCastValue(s string) any {
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	digits := strings.TrimLeft(s, "+-")
+	if digits == "" || digits[0] < '0' || digits[0] > '9' {
+		return s
+	}
+	if len(digits) > 1 && digits[0] == '0' && digits[1] != '.' {
+		return s
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	if strings.IndexFunc(digits, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.' && r != 'e' && r != 'E' && r != '+' && r != '-'
+	}) != -1 {
+		return s
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return s
+}
+
+func (o *xmlOptions) textValue(s string) any {
+	if o.cast {
+		return xmlCastValue(s)
+	}
+	return s
+}
+
+// decodeDocument parses an XML document into a structured value, which is an
+// object containing a single key for the root element.
+func (o *xmlOptions) decodeDocument(data []byte) (any, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.RawToken()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("no root element found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			name := xmlName(start.Name)
+			v, err := o.decodeElement(dec, start, name)
+			if err != nil {
+				return nil, err
+			}
+			if _, forced := o.forceArray[name]; forced {
+				v = []any{v}
+			}
+			return map[string]any{name: v}, nil
+		}
+	}
+}
+
+// decodeElement consumes the tokens of an element after its start token up to
+// and including its end token, and returns the structured value of the
+// element.
+func (o *xmlOptions) decodeElement(dec *xml.Decoder, start xml.StartElement, path string) (any, error) {
+	name := xmlName(start.Name)
+
+	obj := map[string]any{}
+	for _, attr := range start.Attr {
+		obj[o.attrPrefix+xmlName(attr.Name)] = o.textValue(attr.Value)
+	}
+
+	var text strings.Builder
+	for {
+		tok, err := dec.RawToken()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = fmt.Errorf("element <%v> is not closed", name)
+			}
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			childName := xmlName(t.Name)
+			childPath := xmlPath(path, childName)
+			child, err := o.decodeElement(dec, t, childPath)
+			if err != nil {
+				return nil, err
+			}
+			switch existing := obj[childName].(type) {
+			case nil:
+				if _, forced := o.forceArray[childPath]; forced {
+					child = []any{child}
+				}
+				obj[childName] = child
+			case []any:
+				obj[childName] = append(existing, child)
+			default:
+				obj[childName] = []any{existing, child}
+			}
+		case xml.EndElement:
+			if endName := xmlName(t.Name); endName != name {
+				return nil, fmt.Errorf("element <%v> closed by </%v>", name, endName)
+			}
+			textStr := strings.TrimSpace(text.String())
+			if len(obj) == 0 {
+				return o.textValue(textStr), nil
+			}
+			if textStr != "" {
+				obj[o.textKey] = o.textValue(textStr)
+			}
+			return obj, nil
+		case xml.CharData:
+			_, _ = text.Write(t)
+		}
+	}
+}
+
+// encodeDocument serializes a structured value into an XML document. When a
+// root tag is not provided the value must be an object with a single key,
+// which becomes the root element.
+func (o *xmlOptions) encodeDocument(v any, rootTag, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", indent)
+
+	if rootTag != "" {
+		if err := o.encodeElement(enc, rootTag, v); err != nil {
+			return nil, err
+		}
+	} else {
+		obj, ok := v.(map[string]any)
+		if !ok || len(obj) != 1 {
+			return nil, errors.New("expected an object with a single key as the root element, or a root tag to be specified")
+		}
+		for k, child := range obj {
+			if err := o.encodeElement(enc, k, child); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (o *xmlOptions) encodeElement(enc *xml.Encoder, name string, v any) error {
+	if name == "" || strings.ContainsAny(name, " \t\r\n<>&\"'=/") {
+		return fmt.Errorf("invalid element name %q", name)
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	switch t := v.(type) {
+	case []any:
+		for _, e := range t {
+			if err := o.encodeElement(enc, name, e); err != nil {
+				return err
+			}
+		}
+		return nil
+	case nil:
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		return enc.EncodeToken(start.End())
+	case map[string]any:
+		return o.encodeObject(enc, start, t)
+	default:
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		if err := enc.EncodeToken(xml.CharData(value.IToString(v))); err != nil {
+			return err
+		}
+		return enc.EncodeToken(start.End())
+	}
+}
+
+func (o *xmlOptions) encodeObject(enc *xml.Encoder, start xml.StartElement, obj map[string]any) error {
+	name := start.Name.Local
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var text *string
+	var children []string
+	for _, k := range keys {
+		switch {
+		case k == o.textKey:
+			textStr := value.IToString(obj[k])
+			text = &textStr
+		case o.attrPrefix != "" && strings.HasPrefix(k, o.attrPrefix):
+			switch obj[k].(type) {
+			case map[string]any, []any:
+				return fmt.Errorf("attribute %v of element <%v> must be a scalar value", k, name)
+			}
+			start.Attr = append(start.Attr, xml.Attr{
+				Name:  xml.Name{Local: strings.TrimPrefix(k, o.attrPrefix)},
+				Value: value.IToString(obj[k]),
+			})
+		default:
+			children = append(children, k)
+		}
+	}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	if text != nil {
+		if err := enc.EncodeToken(xml.CharData(*text)); err != nil {
+			return err
+		}
+	}
+	for _, k := range children {
+		if err := o.encodeElement(enc, k, obj[k]); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
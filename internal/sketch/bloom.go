@@ -0,0 +1,114 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package sketch
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+const bloomFilterVersion = 1
+
+// BloomFilter tests whether items have been added to it, where false positives
+// are possible but false negatives are not.
+type BloomFilter struct {
+	k    uint32
+	bits []uint64
+}
+
+// NewBloomFilter creates a BloomFilter sized such that, once the given
+// capacity of distinct items have been added, the probability of a false
+// positive is roughly the given false positive rate.
+func NewBloomFilter(capacity int, falsePositiveRate float64) (*BloomFilter, error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("capacity must be greater than zero, got %v", capacity)
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		return nil, fmt.Errorf("false positive rate must be between 0 and 1, got %v", falsePositiveRate)
+	}
+
+	m := math.Ceil(-float64(capacity) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	k := max(math.Round(m/float64(capacity)*math.Ln2), 1)
+	return &BloomFilter{
+		k:    uint32(k),
+		bits: make([]uint64, (uint64(m)+63)/64),
+	}, nil
+}
+
+// Hashes returns the number of hash functions used by the filter.
+func (b *BloomFilter) Hashes() int {
+	return int(b.k)
+}
+
+// Bits returns the number of bits of the filter.
+func (b *BloomFilter) Bits() int {
+	return len(b.bits) * 64
+}
+
+// Add an item to the filter and return whether it was possibly present
+// beforehand.
+func (b *BloomFilter) Add(item []byte) (existed bool) {
+	existed = true
+	b.locations(item, func(word int, mask uint64) {
+		if b.bits[word]&mask == 0 {
+			existed = false
+			b.bits[word] |= mask
+		}
+	})
+	return
+}
+
+// Test returns whether an item was possibly added to the filter. A false result
+// means the item was definitely not added.
+func (b *BloomFilter) Test(item []byte) (exists bool) {
+	exists = true
+	b.locations(item, func(word int, mask uint64) {
+		if b.bits[word]&mask == 0 {
+			exists = false
+		}
+	})
+	return
+}
+
+func (b *BloomFilter) locations(item []byte, fn func(word int, mask uint64)) {
+	h1, h2 := hashes(item)
+	m := uint64(len(b.bits)) * 64
+	for i := range uint64(b.k) {
+		loc := (h1 + i*h2) % m
+		fn(int(loc/64), 1<<(loc%64))
+	}
+}
+
+// MarshalBinary encodes the filter into a binary form.
+func (b *BloomFilter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 9, 9+len(b.bits)*8)
+	data[0] = bloomFilterVersion
+	binary.BigEndian.PutUint32(data[1:], b.k)
+	binary.BigEndian.PutUint32(data[5:], uint32(len(b.bits)))
+	for _, w := range b.bits {
+		data = binary.BigEndian.AppendUint64(data, w)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a filter from the binary form created by
+// MarshalBinary.
+func (b *BloomFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 9 || data[0] != bloomFilterVersion {
+		return ErrUnsupportedEncoding
+	}
+	k := binary.BigEndian.Uint32(data[1:])
+	words := binary.BigEndian.Uint32(data[5:])
+	data = data[9:]
+	if k == 0 || words == 0 || uint64(len(data)) != uint64(words)*8 {
+		return ErrUnsupportedEncoding
+	}
+
+	b.k = k
+	b.bits = make([]uint64, words)
+	for i := range b.bits {
+		b.bits[i] = binary.BigEndian.Uint64(data[i*8:])
+	}
+	return nil
+}
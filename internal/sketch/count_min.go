@@ -0,0 +1,188 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package sketch
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+const countMinSketchVersion = 1
+
+// TopKItem is an item tracked by a CountMinSketch as one of the most frequently
+// added items, along with its estimated count.
+type TopKItem struct {
+	Item  string
+	Count uint64
+}
+
+// CountMinSketch estimates the number of times that items have been added to
+// it, where counts may be overestimated but are never underestimated. The most
+// frequently added items are also tracked.
+type CountMinSketch struct {
+	width, depth uint32
+	counters     []uint64
+
+	k    int
+	topK map[string]uint64
+}
+
+// NewCountMinSketch creates a CountMinSketch with a given width and depth of
+// counters, which also tracks the k most frequently added items. Counts are
+// overestimated by at most e/width of the total count with a probability of
+// 1-e^-depth.
+func NewCountMinSketch(width, depth, k int) (*CountMinSketch, error) {
+	if width <= 0 || depth <= 0 {
+		return nil, fmt.Errorf("width and depth must be greater than zero, got %v and %v", width, depth)
+	}
+	if k < 0 {
+		return nil, fmt.Errorf("k must not be negative, got %v", k)
+	}
+	return &CountMinSketch{
+		width:    uint32(width),
+		depth:    uint32(depth),
+		counters: make([]uint64, width*depth),
+		k:        k,
+		topK:     make(map[string]uint64, k),
+	}, nil
+}
+
+// Width returns the number of counters in each row of the sketch.
+func (c *CountMinSketch) Width() int {
+	return int(c.width)
+}
+
+// Depth returns the number of rows of the sketch.
+func (c *CountMinSketch) Depth() int {
+	return int(c.depth)
+}
+
+// K returns the number of most frequently added items tracked by the sketch.
+func (c *CountMinSketch) K() int {
+	return c.k
+}
+
+// Add an item to the sketch a number of times and return the estimated count
+// of the item afterwards.
+func (c *CountMinSketch) Add(item []byte, count uint64) uint64 {
+	h1, h2 := hashes(item)
+
+	estimate := ^uint64(0)
+	for i := range uint64(c.depth) {
+		idx := i*uint64(c.width) + (h1+i*h2)%uint64(c.width)
+		c.counters[idx] += count
+		estimate = min(estimate, c.counters[idx])
+	}
+
+	c.trackTopK(string(item), estimate)
+	return estimate
+}
+
+// Count returns the estimated number of times that an item has been added.
+func (c *CountMinSketch) Count(item []byte) uint64 {
+	h1, h2 := hashes(item)
+
+	estimate := ^uint64(0)
+	for i := range uint64(c.depth) {
+		idx := i*uint64(c.width) + (h1+i*h2)%uint64(c.width)
+		estimate = min(estimate, c.counters[idx])
+	}
+	return estimate
+}
+
+func (c *CountMinSketch) trackTopK(item string, estimate uint64) {
+	if c.k == 0 {
+		return
+	}
+	if _, exists := c.topK[item]; exists || len(c.topK) < c.k {
+		c.topK[item] = estimate
+		return
+	}
+
+	var minItem string
+	minCount := ^uint64(0)
+	for k, v := range c.topK {
+		if v < minCount || (v == minCount && k > minItem) {
+			minItem, minCount = k, v
+		}
+	}
+	if estimate > minCount {
+		delete(c.topK, minItem)
+		c.topK[item] = estimate
+	}
+}
+
+// TopK returns the most frequently added items in descending order of their
+// estimated counts.
+func (c *CountMinSketch) TopK() []TopKItem {
+	items := make([]TopKItem, 0, len(c.topK))
+	for k, v := range c.topK {
+		items = append(items, TopKItem{Item: k, Count: v})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count == items[j].Count {
+			return items[i].Item < items[j].Item
+		}
+		return items[i].Count > items[j].Count
+	})
+	return items
+}
+
+// MarshalBinary encodes the sketch into a binary form.
+func (c *CountMinSketch) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 13, 13+len(c.counters)*8)
+	data[0] = countMinSketchVersion
+	binary.BigEndian.PutUint32(data[1:], c.width)
+	binary.BigEndian.PutUint32(data[5:], c.depth)
+	binary.BigEndian.PutUint32(data[9:], uint32(c.k))
+	for _, v := range c.counters {
+		data = binary.BigEndian.AppendUint64(data, v)
+	}
+	for _, item := range c.TopK() {
+		data = binary.AppendUvarint(data, uint64(len(item.Item)))
+		data = append(data, item.Item...)
+		data = binary.BigEndian.AppendUint64(data, item.Count)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a sketch from the binary form created by
+// MarshalBinary.
+func (c *CountMinSketch) UnmarshalBinary(data []byte) error {
+	if len(data) < 13 || data[0] != countMinSketchVersion {
+		return ErrUnsupportedEncoding
+	}
+	width := binary.BigEndian.Uint32(data[1:])
+	depth := binary.BigEndian.Uint32(data[5:])
+	k := int(binary.BigEndian.Uint32(data[9:]))
+	data = data[13:]
+
+	nCounters := uint64(width) * uint64(depth)
+	if width == 0 || depth == 0 || uint64(len(data)) < nCounters*8 {
+		return ErrUnsupportedEncoding
+	}
+	counters := make([]uint64, nCounters)
+	for i := range counters {
+		counters[i] = binary.BigEndian.Uint64(data[i*8:])
+	}
+	data = data[nCounters*8:]
+
+	topK := map[string]uint64{}
+	for len(data) > 0 {
+		l, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < l+8 {
+			return ErrUnsupportedEncoding
+		}
+		data = data[n:]
+		topK[string(data[:l])] = binary.BigEndian.Uint64(data[l:])
+		data = data[l+8:]
+	}
+	if len(topK) > k {
+		return ErrUnsupportedEncoding
+	}
+
+	c.width, c.depth, c.k = width, depth, k
+	c.counters, c.topK = counters, topK
+	return nil
+}
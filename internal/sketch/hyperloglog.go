@@ -0,0 +1,116 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package sketch
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+const hyperLogLogVersion = 1
+
+// HyperLogLog estimates the number of distinct items added to it using 2^p
+// registers of a single byte each, with a standard error of roughly
+// 1.04/sqrt(2^p).
+type HyperLogLog struct {
+	p         uint8
+	registers []uint8
+}
+
+// NewHyperLogLog creates a HyperLogLog with a given precision, which must be
+// between 4 and 18.
+func NewHyperLogLog(precision int) (*HyperLogLog, error) {
+	if precision < 4 || precision > 18 {
+		return nil, fmt.Errorf("precision must be between 4 and 18, got %v", precision)
+	}
+	return &HyperLogLog{
+		p:         uint8(precision),
+		registers: make([]uint8, 1<<precision),
+	}, nil
+}
+
+// Precision returns the precision of the HyperLogLog.
+func (h *HyperLogLog) Precision() int {
+	return int(h.p)
+}
+
+// Add an item to the HyperLogLog.
+func (h *HyperLogLog) Add(item []byte) {
+	x, _ := hashes(item)
+	idx := x >> (64 - h.p)
+	rank := uint8(bits.LeadingZeros64(x<<h.p|1<<(h.p-1))) + 1
+	if rank > h.registers[idx] {
+		h.registers[idx] = rank
+	}
+}
+
+// Estimate returns the approximate number of distinct items that have been
+// added to the HyperLogLog.
+func (h *HyperLogLog) Estimate() uint64 {
+	m := float64(len(h.registers))
+
+	var sum float64
+	var zeros int
+	for _, r := range h.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	var alpha float64
+	switch len(h.registers) {
+	case 16:
+		alpha = 0.673
+	case 32:
+		alpha = 0.697
+	case 64:
+		alpha = 0.709
+	default:
+		alpha = 0.7213 / (1 + 1.079/m)
+	}
+
+	estimate := alpha * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// Linear counting gives a better estimate for small cardinalities.
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(math.Round(estimate))
+}
+
+// Merge adds all items of another HyperLogLog of the same precision into this
+// one.
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if h.p != other.p {
+		return fmt.Errorf("cannot merge HyperLogLogs of precision %v and %v", h.p, other.p)
+	}
+	for i, r := range other.registers {
+		if r > h.registers[i] {
+			h.registers[i] = r
+		}
+	}
+	return nil
+}
+
+// MarshalBinary encodes the HyperLogLog into a binary form.
+func (h *HyperLogLog) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 2+len(h.registers))
+	b = append(b, hyperLogLogVersion, h.p)
+	return append(b, h.registers...), nil
+}
+
+// UnmarshalBinary decodes a HyperLogLog from the binary form created by
+// MarshalBinary.
+func (h *HyperLogLog) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != hyperLogLogVersion {
+		return ErrUnsupportedEncoding
+	}
+	p := data[1]
+	if p < 4 || p > 18 || len(data)-2 != 1<<p {
+		return ErrUnsupportedEncoding
+	}
+	h.p = p
+	h.registers = append([]uint8(nil), data[2:]...)
+	return nil
+}
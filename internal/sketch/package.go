@@ -0,0 +1,22 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+// Package sketch provides probabilistic data structures that summarise streams
+// of items within a fixed amount of memory, along with binary encodings that
+// allow them to be persisted and restored.
+package sketch
+
+import (
+	"errors"
+
+	"github.com/OneOfOne/xxhash"
+)
+
+// ErrUnsupportedEncoding is returned when decoding data that was not encoded
+// by a supported version of a sketch.
+var ErrUnsupportedEncoding = errors.New("unsupported sketch encoding")
+
+// hashes returns two independent 64-bit hashes of an item, which are combined
+// in order to simulate any number of hash functions.
+func hashes(item []byte) (h1, h2 uint64) {
+	return xxhash.Checksum64(item), xxhash.Checksum64S(item, 0x9e3779b97f4a7c15) | 1
+}
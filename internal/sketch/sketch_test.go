@@ -0,0 +1,140 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package sketch
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHyperLogLogEstimate(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 1000, 100000} {
+		h, err := NewHyperLogLog(14)
+		require.NoError(t, err)
+
+		for i := range n {
+			h.Add([]byte("item-" + strconv.Itoa(i)))
+			h.Add([]byte("item-" + strconv.Itoa(i)))
+		}
+		assert.InEpsilon(t, float64(n)+1, float64(h.Estimate())+1, 0.03, "n = %v", n)
+	}
+}
+
+func TestHyperLogLogMergeAndEncoding(t *testing.T) {
+	a, err := NewHyperLogLog(12)
+	require.NoError(t, err)
+	b, err := NewHyperLogLog(12)
+	require.NoError(t, err)
+
+	for i := range 5000 {
+		a.Add([]byte("a-" + strconv.Itoa(i)))
+		b.Add([]byte("b-" + strconv.Itoa(i)))
+	}
+	require.NoError(t, a.Merge(b))
+	assert.InEpsilon(t, 10000, float64(a.Estimate()), 0.05)
+
+	data, err := a.MarshalBinary()
+	require.NoError(t, err)
+
+	var decoded HyperLogLog
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, 12, decoded.Precision())
+	assert.Equal(t, a.Estimate(), decoded.Estimate())
+
+	c, err := NewHyperLogLog(10)
+	require.NoError(t, err)
+	require.Error(t, a.Merge(c))
+
+	require.ErrorIs(t, decoded.UnmarshalBinary(data[:len(data)-1]), ErrUnsupportedEncoding)
+
+	_, err = NewHyperLogLog(3)
+	require.Error(t, err)
+}
+
+func TestBloomFilter(t *testing.T) {
+	b, err := NewBloomFilter(10000, 0.01)
+	require.NoError(t, err)
+
+	var existed int
+	for i := range 10000 {
+		if b.Add([]byte("in-" + strconv.Itoa(i))) {
+			existed++
+		}
+	}
+	assert.Less(t, existed, 100)
+	for i := range 10000 {
+		require.True(t, b.Test([]byte("in-"+strconv.Itoa(i))), i)
+		require.True(t, b.Add([]byte("in-"+strconv.Itoa(i))), i)
+	}
+
+	var falsePositives int
+	for i := range 10000 {
+		if b.Test([]byte("out-" + strconv.Itoa(i))) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, falsePositives, 200)
+
+	data, err := b.MarshalBinary()
+	require.NoError(t, err)
+
+	var decoded BloomFilter
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	for i := range 10000 {
+		require.True(t, decoded.Test([]byte("in-"+strconv.Itoa(i))), i)
+	}
+
+	require.ErrorIs(t, decoded.UnmarshalBinary(data[:20]), ErrUnsupportedEncoding)
+
+	_, err = NewBloomFilter(0, 0.01)
+	require.Error(t, err)
+	_, err = NewBloomFilter(10, 1)
+	require.Error(t, err)
+}
+
+func TestCountMinSketch(t *testing.T) {
+	c, err := NewCountMinSketch(2048, 5, 3)
+	require.NoError(t, err)
+
+	for i := range 1000 {
+		c.Add([]byte("rare-"+strconv.Itoa(i)), 1)
+	}
+	for i := range 100 {
+		c.Add([]byte("a"), 1)
+		if i%2 == 0 {
+			c.Add([]byte("b"), 1)
+		}
+		if i%4 == 0 {
+			c.Add([]byte("c"), 1)
+		}
+	}
+	assert.Equal(t, uint64(60), c.Add([]byte("d"), 60))
+
+	assert.GreaterOrEqual(t, c.Count([]byte("a")), uint64(100))
+	assert.GreaterOrEqual(t, c.Count([]byte("b")), uint64(50))
+	assert.Equal(t, uint64(0), c.Count([]byte("never")))
+
+	topK := c.TopK()
+	require.Len(t, topK, 3)
+	assert.Equal(t, []string{"a", "d", "b"}, []string{topK[0].Item, topK[1].Item, topK[2].Item})
+
+	data, err := c.MarshalBinary()
+	require.NoError(t, err)
+
+	var decoded CountMinSketch
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, c.Count([]byte("a")), decoded.Count([]byte("a")))
+	assert.Equal(t, topK, decoded.TopK())
+
+	decoded.Add([]byte("e"), 200)
+	assert.Equal(t, "e", decoded.TopK()[0].Item)
+	assert.Len(t, decoded.TopK(), 3)
+
+	require.ErrorIs(t, decoded.UnmarshalBinary(data[:len(data)-1]), ErrUnsupportedEncoding)
+
+	_, err = NewCountMinSketch(0, 5, 3)
+	require.Error(t, err)
+}
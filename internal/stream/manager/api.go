@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -32,7 +33,7 @@ import (
 func (m *Type) registerEndpoints(enableCrud bool) {
 	m.manager.RegisterEndpoint(
 		"/ready",
-		"Returns 200 OK if the inputs and outputs of all running streams are connected, otherwise a 503 is returned. If there are no active streams 200 is returned.",
+		"Returns 200 OK if the inputs and outputs of all running streams are connected, otherwise a 503 is returned. The inputs of paused streams are not considered. If there are no active streams 200 is returned.",
 		m.HandleStreamReady,
 	)
 	if !enableCrud {
@@ -48,6 +49,16 @@ func (m *Type) registerEndpoints(enableCrud bool) {
 		"GET a structured JSON object containing metrics for the stream.",
 		m.HandleStreamStats,
 	)
+	m.manager.RegisterEndpoint(
+		"/streams/{id}/pause",
+		"POST: Pause a stream, which stops reading from its input whilst in-flight messages continue to be processed and delivered.",
+		m.HandleStreamPause,
+	)
+	m.manager.RegisterEndpoint(
+		"/streams/{id}/resume",
+		"POST: Resume a paused stream.",
+		m.HandleStreamResume,
+	)
 	m.manager.RegisterEndpoint(
 		"/streams/{id}",
 		"Perform CRUD operations on streams, supporting POST (Create),"+
@@ -104,6 +115,7 @@ func (m *Type) HandleStreamsCRUD(w http.ResponseWriter, r *http.Request) {
 
 	type confInfo struct {
 		Active    bool    `json:"active"`
+		Paused    bool    `json:"paused"`
 		Uptime    float64 `json:"uptime"`
 		UptimeStr string  `json:"uptime_str"`
 	}
@@ -113,6 +125,7 @@ func (m *Type) HandleStreamsCRUD(w http.ResponseWriter, r *http.Request) {
 	for id, strInfo := range m.streams {
 		infos[id] = confInfo{
 			Active:    strInfo.IsRunning(),
+			Paused:    strInfo.IsPaused(),
 			Uptime:    strInfo.Uptime().Seconds(),
 			UptimeStr: strInfo.Uptime().String(),
 		}
@@ -295,7 +308,9 @@ func (m *Type) HandleStreamCRUD(w http.ResponseWriter, r *http.Request) {
 
 		ignoreLints := r.URL.Query().Get("chilled") == "true"
 
-		if confBytes, err = config.NewReader("", nil).ReplaceEnvVariables(context.TODO(), confBytes); err != nil {
+		// Secret references are rejected as they would otherwise allow clients
+		// of the API to read files and run commands through secret providers.
+		if confBytes, err = config.NewReader("", nil, config.OptRejectSecrets()).ReplaceEnvVariables(context.TODO(), confBytes); err != nil {
 			var errEnvMissing *config.ErrMissingEnvVars
 			if ignoreLints && errors.As(err, &errEnvMissing) {
 				confBytes = errEnvMissing.BestAttempt
@@ -396,11 +411,13 @@ func (m *Type) HandleStreamCRUD(w http.ResponseWriter, r *http.Request) {
 			var bodyBytes []byte
 			if bodyBytes, serverErr = json.Marshal(struct {
 				Active    bool    `json:"active"`
+				Paused    bool    `json:"paused"`
 				Uptime    float64 `json:"uptime"`
 				UptimeStr string  `json:"uptime_str"`
 				Config    any     `json:"config"`
 			}{
 				Active:    info.IsRunning(),
+				Paused:    info.IsPaused(),
 				Uptime:    info.Uptime().Seconds(),
 				UptimeStr: info.Uptime().String(),
 				Config:    sanit,
@@ -484,51 +501,8 @@ func (m *Type) HandleResourceCRUD(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
-	var storeFn func(*yaml.Node)
-
 	docType := docs.Type(mux.Vars(r)["type"])
-	switch docType {
-	case docs.TypeCache:
-		storeFn = func(n *yaml.Node) {
-			var cacheConf cache.Config
-			if cacheConf, requestErr = cache.FromAny(m.manager.Environment(), n); requestErr != nil {
-				return
-			}
-			serverErr = m.manager.StoreCache(ctx, id, cacheConf)
-		}
-	case docs.TypeInput:
-		storeFn = func(n *yaml.Node) {
-			var inputConf input.Config
-			if inputConf, requestErr = input.FromAny(m.manager.Environment(), n); requestErr != nil {
-				return
-			}
-			serverErr = m.manager.StoreInput(ctx, id, inputConf)
-		}
-	case docs.TypeOutput:
-		storeFn = func(n *yaml.Node) {
-			var outputConf output.Config
-			if outputConf, requestErr = output.FromAny(m.manager.Environment(), n); requestErr != nil {
-				return
-			}
-			serverErr = m.manager.StoreOutput(ctx, id, outputConf)
-		}
-	case docs.TypeProcessor:
-		storeFn = func(n *yaml.Node) {
-			var procConf processor.Config
-			if procConf, requestErr = processor.FromAny(m.manager.Environment(), n); requestErr != nil {
-				return
-			}
-			serverErr = m.manager.StoreProcessor(ctx, id, procConf)
-		}
-	case docs.TypeRateLimit:
-		storeFn = func(n *yaml.Node) {
-			var rlConf ratelimit.Config
-			if rlConf, requestErr = ratelimit.FromAny(m.manager.Environment(), n); requestErr != nil {
-				return
-			}
-			serverErr = m.manager.StoreRateLimit(ctx, id, rlConf)
-		}
-	default:
+	if !slices.Contains(storableResourceTypes, docType) {
 		http.Error(w, "Var `type` must be set to one of `cache`, `input`, `output`, `processor` or `rate_limit`", http.StatusBadRequest)
 		return
 	}
@@ -543,7 +517,8 @@ func (m *Type) HandleResourceCRUD(w http.ResponseWriter, r *http.Request) {
 
 		ignoreLints := r.URL.Query().Get("chilled") == "true"
 
-		if confBytes, requestErr = config.NewReader("", nil).ReplaceEnvVariables(r.Context(), confBytes); requestErr != nil {
+		// Secret references are rejected for the same reasons as streams.
+		if confBytes, requestErr = config.NewReader("", nil, config.OptRejectSecrets()).ReplaceEnvVariables(r.Context(), confBytes); requestErr != nil {
 			var errEnvMissing *config.ErrMissingEnvVars
 			if ignoreLints && errors.As(requestErr, &errEnvMissing) {
 				confBytes = errEnvMissing.BestAttempt
@@ -576,7 +551,71 @@ func (m *Type) HandleResourceCRUD(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	storeFn(confNode)
+	if requestErr, serverErr = m.storeResource(ctx, docType, id, confNode); requestErr != nil || serverErr != nil {
+		return
+	}
+	if m.store != nil {
+		storeNode := confNode
+		if storeNode.Kind == yaml.DocumentNode && len(storeNode.Content) > 0 {
+			storeNode = storeNode.Content[0]
+		}
+		var confBytes []byte
+		if confBytes, serverErr = docs.MarshalYAML(*storeNode); serverErr != nil {
+			return
+		}
+		if serverErr = m.store.SetResource(docType, id, confBytes); serverErr != nil {
+			serverErr = fmt.Errorf("failed to store resource config: %w", serverErr)
+		}
+	}
+}
+
+var storableResourceTypes = []docs.Type{
+	docs.TypeCache,
+	docs.TypeInput,
+	docs.TypeOutput,
+	docs.TypeProcessor,
+	docs.TypeRateLimit,
+}
+
+// storeResource parses a resource config and creates or replaces the resource
+// within the manager, returning a request error when the config is invalid and
+// a server error when the resource could not be stored.
+func (m *Type) storeResource(ctx context.Context, docType docs.Type, id string, n *yaml.Node) (requestErr, serverErr error) {
+	switch docType {
+	case docs.TypeCache:
+		var cacheConf cache.Config
+		if cacheConf, requestErr = cache.FromAny(m.manager.Environment(), n); requestErr != nil {
+			return
+		}
+		serverErr = m.manager.StoreCache(ctx, id, cacheConf)
+	case docs.TypeInput:
+		var inputConf input.Config
+		if inputConf, requestErr = input.FromAny(m.manager.Environment(), n); requestErr != nil {
+			return
+		}
+		serverErr = m.manager.StoreInput(ctx, id, inputConf)
+	case docs.TypeOutput:
+		var outputConf output.Config
+		if outputConf, requestErr = output.FromAny(m.manager.Environment(), n); requestErr != nil {
+			return
+		}
+		serverErr = m.manager.StoreOutput(ctx, id, outputConf)
+	case docs.TypeProcessor:
+		var procConf processor.Config
+		if procConf, requestErr = processor.FromAny(m.manager.Environment(), n); requestErr != nil {
+			return
+		}
+		serverErr = m.manager.StoreProcessor(ctx, id, procConf)
+	case docs.TypeRateLimit:
+		var rlConf ratelimit.Config
+		if rlConf, requestErr = ratelimit.FromAny(m.manager.Environment(), n); requestErr != nil {
+			return
+		}
+		serverErr = m.manager.StoreRateLimit(ctx, id, rlConf)
+	default:
+		requestErr = fmt.Errorf("resource type not supported: %v", docType)
+	}
+	return
 }
 
 // HandleStreamStats is an http.HandleFunc for obtaining metrics for a stream.
@@ -645,6 +684,52 @@ func (m *Type) HandleStreamStats(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// HandleStreamPause is an http.HandleFunc for pausing a stream.
+func (m *Type) HandleStreamPause(w http.ResponseWriter, r *http.Request) {
+	m.handleStreamPauseResume(w, r, m.Pause)
+}
+
+// HandleStreamResume is an http.HandleFunc for resuming a paused stream.
+func (m *Type) HandleStreamResume(w http.ResponseWriter, r *http.Request) {
+	m.handleStreamPauseResume(w, r, m.Resume)
+}
+
+func (m *Type) handleStreamPauseResume(w http.ResponseWriter, r *http.Request, fn func(id string) error) {
+	var serverErr, requestErr error
+	defer func() {
+		if r.Body != nil {
+			r.Body.Close()
+		}
+		if serverErr != nil {
+			m.manager.Logger().Error("Stream pause Error: %v\n", serverErr)
+			http.Error(w, fmt.Sprintf("Error: %v", serverErr), http.StatusBadGateway)
+			return
+		}
+		if requestErr != nil {
+			m.manager.Logger().Debug("Stream request pause Error: %v\n", requestErr)
+			http.Error(w, fmt.Sprintf("Error: %v", requestErr), http.StatusBadRequest)
+			return
+		}
+	}()
+
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		http.Error(w, "Var `id` must be set", http.StatusBadRequest)
+		return
+	}
+
+	if r.Method != "POST" {
+		requestErr = fmt.Errorf("verb not supported: %v", r.Method)
+		return
+	}
+
+	if serverErr = fn(id); serverErr == ErrStreamDoesNotExist {
+		serverErr = nil
+		http.Error(w, "Stream not found", http.StatusNotFound)
+		return
+	}
+}
+
 // HandleStreamReady is an http.HandleFunc for providing a ready check across
 // all streams.
 func (m *Type) HandleStreamReady(w http.ResponseWriter, r *http.Request) {
@@ -4,6 +4,7 @@ package manager_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -41,6 +42,8 @@ func router(m *manager.Type) *mux.Router {
 	router.HandleFunc("/streams", m.HandleStreamsCRUD)
 	router.HandleFunc("/streams/{id}", m.HandleStreamCRUD)
 	router.HandleFunc("/streams/{id}/stats", m.HandleStreamStats)
+	router.HandleFunc("/streams/{id}/pause", m.HandleStreamPause)
+	router.HandleFunc("/streams/{id}/resume", m.HandleStreamResume)
 	router.HandleFunc("/resources/{type}/{id}", m.HandleResourceCRUD)
 	return router
 }
@@ -93,6 +96,7 @@ func genYAMLRequest(verb, url string, payload any) *http.Request {
 
 type listItemBody struct {
 	Active    bool    `json:"active"`
+	Paused    bool    `json:"paused"`
 	Uptime    float64 `json:"uptime"`
 	UptimeStr string  `json:"uptime_str"`
 }
@@ -109,6 +113,7 @@ func parseListBody(data *bytes.Buffer) listBody {
 
 type getBody struct {
 	Active    bool    `json:"active"`
+	Paused    bool    `json:"paused"`
 	Uptime    float64 `json:"uptime"`
 	UptimeStr string  `json:"uptime_str"`
 	Config    any     `json:"config"`
@@ -462,6 +467,71 @@ output:
 	}
 }
 
+func TestTypeAPIPauseResume(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	res, err := bmanager.New(bmanager.NewResourceConfig())
+	require.NoError(t, err)
+
+	mgr := manager.New(res)
+	r := router(mgr)
+
+	conf, err := testutil.StreamFromYAML(`
+input:
+  generate:
+    mapping: 'root = deleted()'
+output:
+  drop: {}
+`)
+	require.NoError(t, err)
+	require.NoError(t, mgr.Create("foo", conf))
+
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, genRequest("POST", "/streams/bar/pause", nil))
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, genRequest("GET", "/streams/foo/pause", nil))
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, genRequest("POST", "/streams/foo/pause", nil))
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	info, err := mgr.Read("foo")
+	require.NoError(t, err)
+	assert.True(t, info.IsPaused())
+
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, genRequest("GET", "/streams", nil))
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.True(t, parseListBody(response.Body)["foo"].Paused)
+
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, genRequest("GET", "/streams/foo", nil))
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.True(t, parseGetBody(t, response.Body).Paused)
+
+	assert.Eventually(t, func() bool {
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, genRequest("GET", "/ready", nil))
+		return response.Code == http.StatusOK
+	}, time.Second*5, time.Millisecond*10)
+
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, genRequest("POST", "/streams/foo/resume", nil))
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.False(t, info.IsPaused())
+
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, genRequest("GET", "/streams/foo", nil))
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.False(t, parseGetBody(t, response.Body).Paused)
+
+	require.NoError(t, mgr.Stop(ctx))
+}
+
 func TestTypeAPISetStreams(t *testing.T) {
 	res, err := bmanager.New(bmanager.NewResourceConfig())
 	require.NoError(t, err)
@@ -1013,3 +1083,46 @@ output:
 		return response.Code == http.StatusServiceUnavailable
 	}, time.Second*10, time.Millisecond*50)
 }
+
+func TestTypeAPISecretsRejected(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	res, err := bmanager.New(bmanager.NewResourceConfig())
+	require.NoError(t, err)
+
+	mgr := manager.New(res)
+	r := router(mgr)
+
+	markerPath := filepath.Join(t.TempDir(), "marker")
+
+	for _, chilled := range []string{"", "?chilled=true"} {
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, genYAMLRequest("POST", "/streams/foo"+chilled, `
+input:
+  generate:
+    mapping: 'root = "${secret:exec:touch `+markerPath+`}"'
+output:
+  drop: {}
+`))
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Contains(t, response.Body.String(), "secret references are not permitted")
+
+		response = httptest.NewRecorder()
+		r.ServeHTTP(response, genYAMLRequest("POST", "/resources/cache/foocache"+chilled, `
+file:
+  directory: ${secret:exec:touch `+markerPath+`}
+`))
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Contains(t, response.Body.String(), "secret references are not permitted")
+	}
+
+	_, err = mgr.Read("foo")
+	assert.ErrorIs(t, err, manager.ErrStreamDoesNotExist)
+	assert.False(t, res.ProbeCache("foocache"))
+
+	_, err = os.Stat(markerPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, mgr.Stop(ctx))
+}
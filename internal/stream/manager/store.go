@@ -0,0 +1,254 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/redpanda-data/benthos/v4/internal/docs"
+	"github.com/redpanda-data/benthos/v4/internal/stream"
+)
+
+// Store durably records the configs of streams and resources managed by a
+// stream manager, allowing them to be restored after a restart. Configs are
+// stored as YAML documents.
+//
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// ReadStreams returns all stored stream configs keyed by their ID.
+	ReadStreams() (map[string][]byte, error)
+
+	// SetStream creates or replaces the stored config of a stream.
+	SetStream(id string, conf []byte) error
+
+	// DeleteStream removes the stored config of a stream, which is a no-op if
+	// it does not exist.
+	DeleteStream(id string) error
+
+	// ReadResources returns all stored resource configs keyed by their type
+	// and then their name.
+	ReadResources() (map[docs.Type]map[string][]byte, error)
+
+	// SetResource creates or replaces the stored config of a resource.
+	SetResource(typ docs.Type, id string, conf []byte) error
+}
+
+//------------------------------------------------------------------------------
+
+// DirStore is a Store that writes each config to a separate file within a
+// directory, where stream configs are written to a `streams` sub-directory and
+// resource configs are written to a sub-directory for each resource type
+// within a `resources` sub-directory.
+type DirStore struct {
+	dir string
+	mut sync.Mutex
+}
+
+// NewDirStore returns a Store that persists configs within a directory, which
+// is created if it does not yet exist.
+func NewDirStore(dir string) (*DirStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "streams"), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "resources"), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	return &DirStore{dir: dir}, nil
+}
+
+const dirStoreFileSuffix = ".yaml"
+
+// Identifiers are escaped so that they are always a single, valid file name.
+func dirStoreFileName(id string) string {
+	return url.PathEscape(id) + dirStoreFileSuffix
+}
+
+func (d *DirStore) readDir(dir string) (map[string][]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return map[string][]byte{}, nil
+		}
+		return nil, err
+	}
+
+	confs := make(map[string][]byte, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, dirStoreFileSuffix) {
+			continue
+		}
+		id, err := url.PathUnescape(strings.TrimSuffix(name, dirStoreFileSuffix))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode identifier from file name %v: %w", name, err)
+		}
+		if confs[id], err = os.ReadFile(filepath.Join(dir, name)); err != nil {
+			return nil, err
+		}
+	}
+	return confs, nil
+}
+
+// writeFile writes data to a temporary file and then renames it to the target
+// path in order to avoid leaving a partially written config behind on a crash.
+func (d *DirStore) writeFile(dir, id string, data []byte) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(dir, dirStoreFileName(id)))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// ReadStreams returns all stored stream configs keyed by their ID.
+func (d *DirStore) ReadStreams() (map[string][]byte, error) {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	return d.readDir(filepath.Join(d.dir, "streams"))
+}
+
+// SetStream creates or replaces the stored config of a stream.
+func (d *DirStore) SetStream(id string, conf []byte) error {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	return d.writeFile(filepath.Join(d.dir, "streams"), id, conf)
+}
+
+// DeleteStream removes the stored config of a stream.
+func (d *DirStore) DeleteStream(id string) error {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+
+	err := os.Remove(filepath.Join(d.dir, "streams", dirStoreFileName(id)))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// ReadResources returns all stored resource configs keyed by their type and
+// then their name.
+func (d *DirStore) ReadResources() (map[docs.Type]map[string][]byte, error) {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+
+	resources := map[docs.Type]map[string][]byte{}
+	for _, t := range storableResourceTypes {
+		confs, err := d.readDir(filepath.Join(d.dir, "resources", string(t)))
+		if err != nil {
+			return nil, err
+		}
+		if len(confs) > 0 {
+			resources[t] = confs
+		}
+	}
+	return resources, nil
+}
+
+// SetResource creates or replaces the stored config of a resource.
+func (d *DirStore) SetResource(typ docs.Type, id string, conf []byte) error {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	return d.writeFile(filepath.Join(d.dir, "resources", string(typ)), id, conf)
+}
+
+//------------------------------------------------------------------------------
+
+func storableStreamConfig(conf stream.Config) ([]byte, error) {
+	rawSource := conf.GetRawSource()
+	if rawSource == nil {
+		return nil, errors.New("stream config cannot be stored as it was not created from a raw source")
+	}
+	var node yaml.Node
+	if err := node.Encode(rawSource); err != nil {
+		return nil, err
+	}
+	return docs.MarshalYAML(node)
+}
+
+// RestoreResources creates the resources recorded within the store of the
+// manager, if one is set.
+func (m *Type) RestoreResources(ctx context.Context) error {
+	if m.store == nil {
+		return nil
+	}
+
+	resources, err := m.store.ReadResources()
+	if err != nil {
+		return fmt.Errorf("failed to read stored resource configs: %w", err)
+	}
+	for _, typ := range storableResourceTypes {
+		for id, confBytes := range resources[typ] {
+			node, err := docs.UnmarshalYAML(confBytes)
+			if err != nil {
+				return fmt.Errorf("failed to parse stored %v resource '%v': %w", typ, id, err)
+			}
+			reqErr, srvErr := m.storeResource(ctx, typ, id, node)
+			if reqErr != nil {
+				return fmt.Errorf("failed to parse stored %v resource '%v': %w", typ, id, reqErr)
+			}
+			if srvErr != nil {
+				return fmt.Errorf("failed to create stored %v resource '%v': %w", typ, id, srvErr)
+			}
+		}
+	}
+	return nil
+}
+
+// StoredStreams returns the stream configs recorded within the store of the
+// manager, if one is set. The streams are not created.
+func (m *Type) StoredStreams() (map[string]stream.Config, error) {
+	if m.store == nil {
+		return map[string]stream.Config{}, nil
+	}
+
+	streams, err := m.store.ReadStreams()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stored stream configs: %w", err)
+	}
+
+	confs := make(map[string]stream.Config, len(streams))
+	for id, confBytes := range streams {
+		node, err := docs.UnmarshalYAML(confBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse stored stream '%v': %w", id, err)
+		}
+
+		var rawSource any
+		_ = node.Decode(&rawSource)
+
+		pConf, err := stream.Spec().ParsedConfigFromAny(node)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse stored stream '%v': %w", id, err)
+		}
+		if confs[id], err = stream.FromParsed(m.manager.Environment(), pConf, rawSource); err != nil {
+			return nil, fmt.Errorf("failed to parse stored stream '%v': %w", id, err)
+		}
+	}
+	return confs, nil
+}
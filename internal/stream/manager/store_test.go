@@ -0,0 +1,212 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package manager_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/internal/component/testutil"
+	"github.com/redpanda-data/benthos/v4/internal/docs"
+	bmanager "github.com/redpanda-data/benthos/v4/internal/manager"
+	"github.com/redpanda-data/benthos/v4/internal/stream/manager"
+)
+
+func TestDirStore(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := manager.NewDirStore(dir)
+	require.NoError(t, err)
+
+	streams, err := store.ReadStreams()
+	require.NoError(t, err)
+	assert.Empty(t, streams)
+
+	require.NoError(t, store.SetStream("foo", []byte("a: 1\n")))
+	require.NoError(t, store.SetStream("../bar/baz", []byte("b: 2\n")))
+	require.NoError(t, store.SetStream("foo", []byte("a: 3\n")))
+	require.NoError(t, store.SetResource(docs.TypeCache, "qux", []byte("c: 4\n")))
+
+	// A fresh store reads what was written by the previous one.
+	store, err = manager.NewDirStore(dir)
+	require.NoError(t, err)
+
+	streams, err = store.ReadStreams()
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"foo":        []byte("a: 3\n"),
+		"../bar/baz": []byte("b: 2\n"),
+	}, streams)
+
+	resources, err := store.ReadResources()
+	require.NoError(t, err)
+	assert.Equal(t, map[docs.Type]map[string][]byte{
+		docs.TypeCache: {"qux": []byte("c: 4\n")},
+	}, resources)
+
+	require.NoError(t, store.DeleteStream("../bar/baz"))
+	require.NoError(t, store.DeleteStream("does not exist"))
+
+	streams, err = store.ReadStreams()
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"foo": []byte("a: 3\n"),
+	}, streams)
+}
+
+func TestTypeStoreRestore(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	dir := t.TempDir()
+
+	store, err := manager.NewDirStore(dir)
+	require.NoError(t, err)
+
+	res, err := bmanager.New(bmanager.NewResourceConfig())
+	require.NoError(t, err)
+
+	mgr := manager.New(res, manager.OptSetStore(store))
+	r := router(mgr)
+
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, genYAMLRequest("POST", "/resources/cache/foocache", `
+memory: {}
+`))
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	for _, id := range []string{"foo", "bar"} {
+		response = httptest.NewRecorder()
+		r.ServeHTTP(response, genYAMLRequest("POST", "/streams/"+id, `
+input:
+  generate:
+    mapping: 'root = deleted()'
+output:
+  drop: {}
+`))
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	}
+
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, genYAMLRequest("PUT", "/streams/foo", `
+input:
+  generate:
+    mapping: 'root = deleted()'
+buffer:
+  memory: {}
+output:
+  drop: {}
+`))
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, genRequest("DELETE", "/streams/bar", nil))
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	require.NoError(t, mgr.Stop(ctx))
+
+	// Restore into a fresh manager as if the process was restarted.
+	store, err = manager.NewDirStore(dir)
+	require.NoError(t, err)
+
+	res, err = bmanager.New(bmanager.NewResourceConfig())
+	require.NoError(t, err)
+
+	mgr = manager.New(res, manager.OptSetStore(store))
+	require.NoError(t, mgr.RestoreResources(ctx))
+	assert.True(t, res.ProbeCache("foocache"))
+
+	confs, err := mgr.StoredStreams()
+	require.NoError(t, err)
+	require.Len(t, confs, 1)
+	require.Contains(t, confs, "foo")
+	assert.Equal(t, "memory", confs["foo"].Buffer.Type)
+
+	require.NoError(t, mgr.Create("foo", confs["foo"]))
+	require.NoError(t, mgr.Stop(ctx))
+}
+
+func TestTypeStoreFailedCreate(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	store, err := manager.NewDirStore(t.TempDir())
+	require.NoError(t, err)
+
+	res, err := bmanager.New(bmanager.NewResourceConfig())
+	require.NoError(t, err)
+
+	mgr := manager.New(res, manager.OptSetStore(store))
+	r := router(mgr)
+
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, genYAMLRequest("POST", "/streams/foo?chilled=true", `
+input:
+  resource: does_not_exist
+output:
+  drop: {}
+`))
+	require.NotEqual(t, http.StatusOK, response.Code)
+
+	streams, err := store.ReadStreams()
+	require.NoError(t, err)
+	assert.Empty(t, streams)
+
+	require.NoError(t, mgr.Stop(ctx))
+}
+
+func TestTypeStoreUnstored(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	store, err := manager.NewDirStore(t.TempDir())
+	require.NoError(t, err)
+
+	res, err := bmanager.New(bmanager.NewResourceConfig())
+	require.NoError(t, err)
+
+	mgr := manager.New(res, manager.OptSetStore(store))
+
+	conf, err := testutil.StreamFromYAML(`
+input:
+  generate:
+    mapping: 'root = deleted()'
+output:
+  drop: {}
+`)
+	require.NoError(t, err)
+
+	updatedConf, err := testutil.StreamFromYAML(`
+input:
+  generate:
+    mapping: 'root = deleted()'
+buffer:
+  memory: {}
+output:
+  drop: {}
+`)
+	require.NoError(t, err)
+
+	require.NoError(t, mgr.CreateUnstored("foo", conf))
+	require.NoError(t, mgr.UpdateUnstored(ctx, "foo", updatedConf))
+
+	streams, err := store.ReadStreams()
+	require.NoError(t, err)
+	assert.Empty(t, streams)
+
+	// Updates made through the API are recorded regardless of the origin of
+	// the stream.
+	require.NoError(t, mgr.Update(ctx, "foo", conf))
+
+	streams, err = store.ReadStreams()
+	require.NoError(t, err)
+	assert.Contains(t, streams, "foo")
+
+	require.NoError(t, mgr.Stop(ctx))
+}
@@ -20,6 +20,7 @@ import (
 // StreamStatus tracks a stream along with information regarding its internals.
 type StreamStatus struct {
 	stoppedAfter int64
+	configMut    sync.Mutex
 	config       stream.Config
 	strm         *stream.Type
 	metrics      *metrics.Local
@@ -45,11 +46,17 @@ func (s *StreamStatus) IsRunning() bool {
 }
 
 // IsReady returns a boolean indicating whether the stream is connected at both
-// the input and output level.
+// the input and output level. The input level of a paused stream is not
+// considered.
 func (s *StreamStatus) IsReady() bool {
 	return s.strm.IsReady()
 }
 
+// IsPaused returns a boolean indicating whether the stream is paused.
+func (s *StreamStatus) IsPaused() bool {
+	return s.strm.IsPaused()
+}
+
 // Uptime returns a time.Duration indicating the current uptime of the stream.
 func (s *StreamStatus) Uptime() time.Duration {
 	if stoppedAfter := atomic.LoadInt64(&s.stoppedAfter); stoppedAfter > 0 {
@@ -60,9 +67,17 @@ func (s *StreamStatus) Uptime() time.Duration {
 
 // Config returns the configuration of the stream.
 func (s *StreamStatus) Config() stream.Config {
+	s.configMut.Lock()
+	defer s.configMut.Unlock()
 	return s.config
 }
 
+func (s *StreamStatus) setConfig(conf stream.Config) {
+	s.configMut.Lock()
+	s.config = conf
+	s.configMut.Unlock()
+}
+
 // Metrics returns a metrics aggregator of the stream.
 func (s *StreamStatus) Metrics() *metrics.Local {
 	return s.metrics
@@ -89,6 +104,7 @@ type Type struct {
 
 	manager    bundle.NewManagement
 	apiEnabled bool
+	store      Store
 
 	lock sync.Mutex
 }
@@ -117,6 +133,16 @@ func OptAPIEnabled(b bool) func(*Type) {
 	}
 }
 
+// OptSetStore sets a Store in which the configs of streams and resources are
+// recorded as they are created, updated and deleted. Stored configs are not
+// loaded automatically, this must be done by calling RestoreResources and
+// StoredStreams.
+func OptSetStore(s Store) func(*Type) {
+	return func(t *Type) {
+		t.store = s
+	}
+}
+
 //------------------------------------------------------------------------------
 
 // Errors specifically returned by a stream manager.
@@ -141,6 +167,18 @@ func (m *Type) ConnectionStatus() (s component.ConnectionStatuses) {
 // Create attempts to construct and run a new stream under a unique ID. If the
 // ID already exists an error is returned.
 func (m *Type) Create(id string, conf stream.Config) error {
+	return m.create(id, conf, true)
+}
+
+// CreateUnstored attempts to construct and run a new stream under a unique ID
+// without recording its config within the store of the manager, which is
+// useful for streams that are already persisted elsewhere, such as those read
+// from config files. If the ID already exists an error is returned.
+func (m *Type) CreateUnstored(id string, conf stream.Config) error {
+	return m.create(id, conf, false)
+}
+
+func (m *Type) create(id string, conf stream.Config, store bool) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
@@ -152,6 +190,17 @@ func (m *Type) Create(id string, conf stream.Config) error {
 		return ErrStreamExists
 	}
 
+	store = store && m.store != nil
+	if store {
+		confBytes, err := storableStreamConfig(conf)
+		if err != nil {
+			return err
+		}
+		if err := m.store.SetStream(id, confBytes); err != nil {
+			return fmt.Errorf("failed to store stream config: %w", err)
+		}
+	}
+
 	strmFlatMetrics := metrics.NewLocal()
 	sMgr := m.manager.ForStream(id).WithAddedMetrics(strmFlatMetrics)
 
@@ -165,6 +214,11 @@ func (m *Type) Create(id string, conf stream.Config) error {
 		wrapper.setClosed()
 	}))
 	if err != nil {
+		if store {
+			if dErr := m.store.DeleteStream(id); dErr != nil {
+				m.manager.Logger().Error("Failed to remove stored config of stream '%v': %v", id, dErr)
+			}
+		}
 		return err
 	}
 
@@ -191,11 +245,23 @@ func (m *Type) Read(id string) (*StreamStatus, error) {
 	return wrapper, nil
 }
 
-// Update attempts to stop an existing stream and replace it with a new version
-// of the same stream.
+// Update attempts to apply a new config to an existing stream. Where possible
+// only the components of the stream that have changed are replaced, otherwise
+// the stream is stopped and replaced with a new version of the same stream.
 func (m *Type) Update(ctx context.Context, id string, conf stream.Config) error {
+	return m.update(ctx, id, conf, true)
+}
+
+// UpdateUnstored attempts to apply a new config to an existing stream in the
+// same way as Update, but without recording the new config within the store of
+// the manager.
+func (m *Type) UpdateUnstored(ctx context.Context, id string, conf stream.Config) error {
+	return m.update(ctx, id, conf, false)
+}
+
+func (m *Type) update(ctx context.Context, id string, conf stream.Config, store bool) error {
 	m.lock.Lock()
-	_, exists := m.streams[id]
+	wrapper, exists := m.streams[id]
 	closed := m.closed
 	m.lock.Unlock()
 
@@ -206,16 +272,53 @@ func (m *Type) Update(ctx context.Context, id string, conf stream.Config) error
 		return ErrStreamDoesNotExist
 	}
 
-	if err := m.Delete(ctx, id); err != nil {
+	store = store && m.store != nil
+
+	var confBytes []byte
+	if store {
+		var err error
+		if confBytes, err = storableStreamConfig(conf); err != nil {
+			return err
+		}
+	}
+
+	err := wrapper.strm.Update(ctx, conf)
+	if err == nil {
+		wrapper.setConfig(conf)
+		if store {
+			if err := m.store.SetStream(id, confBytes); err != nil {
+				return fmt.Errorf("failed to store stream config: %w", err)
+			}
+		}
+		return nil
+	}
+	if !errors.Is(err, stream.ErrUpdateRequiresRestart) {
+		return err
+	}
+
+	// The stored config is left in place as it is replaced by create.
+	if err := m.stopAndRemove(ctx, id); err != nil {
 		return err
 	}
-	return m.Create(id, conf)
+	return m.create(id, conf, store)
 }
 
 // Delete attempts to stop and remove a stream by its ID. Returns an error if
 // the stream was not found, or if clean shutdown fails in the specified period
 // of time.
 func (m *Type) Delete(ctx context.Context, id string) error {
+	if err := m.stopAndRemove(ctx, id); err != nil {
+		return err
+	}
+	if m.store != nil {
+		if err := m.store.DeleteStream(id); err != nil {
+			return fmt.Errorf("failed to delete stored stream config: %w", err)
+		}
+	}
+	return nil
+}
+
+func (m *Type) stopAndRemove(ctx context.Context, id string) error {
 	m.lock.Lock()
 	if m.closed {
 		m.lock.Unlock()
@@ -241,6 +344,37 @@ func (m *Type) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// Pause stops a stream from reading from its input, applying back pressure to
+// the input whilst messages already read continue to be processed and
+// delivered. Pausing a stream that is already paused has no effect. Returns an
+// error if the stream was not found.
+//
+// The paused state of a stream is not retained when it is updated.
+func (m *Type) Pause(id string) error {
+	wrapper, err := m.Read(id)
+	if err != nil {
+		return err
+	}
+	if wrapper.strm.Pause() {
+		m.manager.Logger().Info("Paused stream '%v'", id)
+	}
+	return nil
+}
+
+// Resume continues reading from the input of a paused stream. Resuming a stream
+// that is not paused has no effect. Returns an error if the stream was not
+// found.
+func (m *Type) Resume(id string) error {
+	wrapper, err := m.Read(id)
+	if err != nil {
+		return err
+	}
+	if wrapper.strm.Resume() {
+		m.manager.Logger().Info("Resumed stream '%v'", id)
+	}
+	return nil
+}
+
 //------------------------------------------------------------------------------
 
 // Stop attempts to gracefully shut down all active streams and close the
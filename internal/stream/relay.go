@@ -0,0 +1,188 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package stream
+
+import (
+	"sync"
+
+	"github.com/redpanda-data/benthos/v4/internal/message"
+)
+
+// relay forwards transactions between two layers of a stream, and allows the
+// layers on either side to be swapped whilst the stream is running.
+//
+// Whilst paused the relay stops reading from its source in order to apply back
+// pressure to it. Transactions that have already been relayed are unaffected
+// and continue to be processed, delivered and acknowledged.
+type relay struct {
+	mut        sync.Mutex
+	paused     bool
+	resumeChan chan struct{}
+
+	nextSources []<-chan message.Transaction
+	finished    bool
+	sinkChan    chan chan message.Transaction
+	doneChan    chan struct{}
+
+	closeOnce sync.Once
+	closeChan chan struct{}
+}
+
+func newRelay() *relay {
+	resumeChan := make(chan struct{})
+	close(resumeChan)
+	return &relay{
+		resumeChan: resumeChan,
+		sinkChan:   make(chan chan message.Transaction),
+		doneChan:   make(chan struct{}),
+		closeChan:  make(chan struct{}),
+	}
+}
+
+// pause stops the relay of transactions, returning false if the relay was
+// already paused.
+func (r *relay) pause() bool {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if r.paused {
+		return false
+	}
+	r.paused = true
+	r.resumeChan = make(chan struct{})
+	return true
+}
+
+// resume continues the relay of transactions, returning false if the relay
+// was not paused.
+func (r *relay) resume() bool {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if !r.paused {
+		return false
+	}
+	r.paused = false
+	close(r.resumeChan)
+	return true
+}
+
+func (r *relay) isPaused() bool {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	return r.paused
+}
+
+func (r *relay) resumed() <-chan struct{} {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	return r.resumeChan
+}
+
+// closeNow abandons the relay, which is only necessary when the downstream
+// layers are being closed ungracefully and might stop consuming.
+func (r *relay) closeNow() {
+	r.closeOnce.Do(func() {
+		close(r.closeChan)
+	})
+}
+
+// addNextSource queues a source to continue reading from once the current
+// source and any sources queued before it are closed, rather than closing the
+// sink. This must be called before the current source is closed. Returns false
+// if the relay has already finished.
+func (r *relay) addNextSource(in <-chan message.Transaction) bool {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	if r.finished {
+		return false
+	}
+	r.nextSources = append(r.nextSources, in)
+	return true
+}
+
+// popNextSource returns the next queued source, or marks the relay as finished
+// when there are none.
+func (r *relay) popNextSource() (<-chan message.Transaction, bool) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	if len(r.nextSources) == 0 {
+		r.finished = true
+		return nil, false
+	}
+	in := r.nextSources[0]
+	r.nextSources = r.nextSources[1:]
+	return in, true
+}
+
+// swapSink closes the current sink, which signals the downstream layer to
+// finish, and continues relaying transactions to a new sink. Returns false if
+// the relay has already finished.
+func (r *relay) swapSink(out chan message.Transaction) bool {
+	select {
+	case r.sinkChan <- out:
+		return true
+	case <-r.doneChan:
+		return false
+	}
+}
+
+// run begins relaying transactions from a source, returning the sink that
+// transactions are relayed to until it is swapped. The sink is closed once the
+// source is closed without a next source set.
+func (r *relay) run(in <-chan message.Transaction) <-chan message.Transaction {
+	out := make(chan message.Transaction)
+	go func() {
+		defer func() {
+			r.mut.Lock()
+			r.finished = true
+			r.mut.Unlock()
+
+			close(out)
+			close(r.doneChan)
+		}()
+		for {
+			select {
+			case <-r.resumed():
+			case newOut := <-r.sinkChan:
+				close(out)
+				out = newOut
+				continue
+			case <-r.closeChan:
+				return
+			}
+
+			var tran message.Transaction
+			var open bool
+			select {
+			case tran, open = <-in:
+				if !open {
+					var ok bool
+					if in, ok = r.popNextSource(); ok {
+						continue
+					}
+					return
+				}
+			case newOut := <-r.sinkChan:
+				close(out)
+				out = newOut
+				continue
+			case <-r.closeChan:
+				return
+			}
+
+			for sent := false; !sent; {
+				select {
+				case out <- tran:
+					sent = true
+				case newOut := <-r.sinkChan:
+					close(out)
+					out = newOut
+				case <-r.closeChan:
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package stream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/internal/message"
+)
+
+func TestRelayQueuedSources(t *testing.T) {
+	sources := []chan message.Transaction{
+		make(chan message.Transaction),
+		make(chan message.Transaction),
+		make(chan message.Transaction),
+	}
+
+	r := newRelay()
+	out := r.run(sources[0])
+
+	// Both sources are queued before the first is closed, as happens when a
+	// stream is updated twice in quick succession.
+	require.True(t, r.addNextSource(sources[1]))
+	require.True(t, r.addNextSource(sources[2]))
+
+	go func() {
+		for i, s := range sources {
+			s <- message.NewTransaction(message.QuickBatch([][]byte{{byte('a' + i)}}), nil)
+			close(s)
+		}
+	}()
+
+	var received []string
+	for tran := range out {
+		received = append(received, string(tran.Payload.Get(0).AsBytes()))
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, received)
+
+	select {
+	case <-r.doneChan:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for relay to finish")
+	}
+
+	assert.False(t, r.addNextSource(make(chan message.Transaction)))
+	assert.False(t, r.swapSink(make(chan message.Transaction)))
+}
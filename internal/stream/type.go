@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net/http"
 	"runtime/pprof"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -28,11 +29,23 @@ import (
 type Type struct {
 	conf Config
 
+	layersMut     sync.RWMutex
 	inputLayer    input.Streamed
 	bufferLayer   buffer.Streamed
 	pipelineLayer processor.Pipeline
 	outputLayer   output.Streamed
 
+	// Relays between the layers of the stream, allowing them to be swapped
+	// whilst the stream is running. The input relay also pauses the stream.
+	inputRelay  *relay
+	bufferRelay *relay
+	outputRelay *relay
+
+	// Serialises updates with each other and with graceful stops, where
+	// stopping prevents updates once a stop has begun.
+	updateMut sync.Mutex
+	stopping  bool
+
 	manager bundle.NewManagement
 
 	onClose func()
@@ -42,10 +55,13 @@ type Type struct {
 // New creates a new stream.Type.
 func New(conf Config, mgr bundle.NewManagement, opts ...func(*Type)) (*Type, error) {
 	t := &Type{
-		conf:    conf,
-		manager: mgr,
-		onClose: func() {},
-		closed:  0,
+		conf:        conf,
+		inputRelay:  newRelay(),
+		bufferRelay: newRelay(),
+		outputRelay: newRelay(),
+		manager:     mgr,
+		onClose:     func() {},
+		closed:      0,
 	}
 	for _, opt := range opts {
 		opt(t)
@@ -67,7 +83,9 @@ func New(conf Config, mgr bundle.NewManagement, opts ...func(*Type)) (*Type, err
 			Statuses []connStatus `json:"statuses"`
 		}{}
 
-		inputStatuses := t.inputLayer.ConnectionStatus()
+		inputLayer, _, _, outputLayer := t.layers()
+
+		inputStatuses := inputLayer.ConnectionStatus()
 		for _, v := range inputStatuses {
 			s := connStatus{
 				Label:     v.Label,
@@ -80,7 +98,7 @@ func New(conf Config, mgr bundle.NewManagement, opts ...func(*Type)) (*Type, err
 			healthCheckRes.Statuses = append(healthCheckRes.Statuses, s)
 		}
 
-		outputStatuses := t.outputLayer.ConnectionStatus()
+		outputStatuses := outputLayer.ConnectionStatus()
 		for _, v := range outputStatuses {
 			s := connStatus{
 				Label:     v.Label,
@@ -125,19 +143,51 @@ func OptOnClose(onClose func()) func(*Type) {
 //------------------------------------------------------------------------------
 
 // IsReady returns a boolean indicating whether both the input and output layers
-// of the stream are connected.
+// of the stream are connected. The input layer of a paused stream is not
+// considered.
 func (t *Type) IsReady() bool {
-	return t.inputLayer.ConnectionStatus().AllActive() && t.outputLayer.ConnectionStatus().AllActive()
+	inputLayer, _, _, outputLayer := t.layers()
+	if !t.IsPaused() && !inputLayer.ConnectionStatus().AllActive() {
+		return false
+	}
+	return outputLayer.ConnectionStatus().AllActive()
+}
+
+// Pause stops the stream from reading from its input layer, which applies back
+// pressure to the input whilst it remains connected. Messages already read are
+// still processed and delivered, and the outputs remain connected. At most one
+// further transaction may be read from the input after pausing. Returns false
+// if the stream was already paused.
+func (t *Type) Pause() bool {
+	return t.inputRelay.pause()
+}
+
+// Resume continues reading from the input layer of a paused stream. Returns
+// false if the stream was not paused.
+func (t *Type) Resume() bool {
+	return t.inputRelay.resume()
+}
+
+// IsPaused returns a boolean indicating whether the stream is paused.
+func (t *Type) IsPaused() bool {
+	return t.inputRelay.isPaused()
 }
 
 // ConnectionStatus returns the aggregate connection status of all inputs and
 // outputs of the stream.
 func (t *Type) ConnectionStatus() (s component.ConnectionStatuses) {
-	s = append(s, t.inputLayer.ConnectionStatus()...)
-	s = append(s, t.outputLayer.ConnectionStatus()...)
+	inputLayer, _, _, outputLayer := t.layers()
+	s = append(s, inputLayer.ConnectionStatus()...)
+	s = append(s, outputLayer.ConnectionStatus()...)
 	return
 }
 
+func (t *Type) layers() (input.Streamed, buffer.Streamed, processor.Pipeline, output.Streamed) {
+	t.layersMut.RLock()
+	defer t.layersMut.RUnlock()
+	return t.inputLayer, t.bufferLayer, t.pipelineLayer, t.outputLayer
+}
+
 func (t *Type) start() (err error) {
 	// Constructors
 	iMgr := t.manager.IntoPath("input")
@@ -164,12 +214,12 @@ func (t *Type) start() (err error) {
 	// Start chaining components
 	var nextTranChan <-chan message.Transaction
 
-	nextTranChan = t.inputLayer.TransactionChan()
+	nextTranChan = t.inputRelay.run(t.inputLayer.TransactionChan())
 	if t.bufferLayer != nil {
 		if err = t.bufferLayer.Consume(nextTranChan); err != nil {
 			return
 		}
-		nextTranChan = t.bufferLayer.TransactionChan()
+		nextTranChan = t.bufferRelay.run(t.bufferLayer.TransactionChan())
 	}
 	if t.pipelineLayer != nil {
 		if err = t.pipelineLayer.Consume(nextTranChan); err != nil {
@@ -177,21 +227,29 @@ func (t *Type) start() (err error) {
 		}
 		nextTranChan = t.pipelineLayer.TransactionChan()
 	}
-	if err = t.outputLayer.Consume(nextTranChan); err != nil {
+	if err = t.outputLayer.Consume(t.outputRelay.run(nextTranChan)); err != nil {
 		return
 	}
 
-	go func(out output.Streamed) {
+	t.watchOutput(t.outputLayer)
+	return nil
+}
+
+// watchOutput marks the stream as closed once the provided output layer closes,
+// unless it has been swapped for another output layer by then.
+func (t *Type) watchOutput(out output.Streamed) {
+	go func() {
 		for {
 			if err := out.WaitForClose(context.Background()); err == nil {
-				t.onClose()
-				atomic.StoreUint32(&t.closed, 1)
+				_, _, _, current := t.layers()
+				if current == out {
+					t.onClose()
+					atomic.StoreUint32(&t.closed, 1)
+				}
 				return
 			}
 		}
-	}(t.outputLayer)
-
-	return nil
+	}()
 }
 
 // StopGracefully attempts to close the stream in the most graceful way by only
@@ -199,28 +257,38 @@ func (t *Type) start() (err error) {
 // proxy. This should guarantee that all in-flight and buffered data is resolved
 // before shutting down.
 func (t *Type) StopGracefully(ctx context.Context) (err error) {
-	t.inputLayer.TriggerStopConsuming()
-	if err = t.inputLayer.WaitForClose(ctx); err != nil {
+	t.updateMut.Lock()
+	defer t.updateMut.Unlock()
+	t.stopping = true
+
+	inputLayer, bufferLayer, pipelineLayer, outputLayer := t.layers()
+
+	inputLayer.TriggerStopConsuming()
+
+	// A paused stream is resumed so that the input layer is able to flush
+	// pending transactions and close.
+	t.inputRelay.resume()
+	if err = inputLayer.WaitForClose(ctx); err != nil {
 		return fmt.Errorf("waiting on input layer failed: %w", err)
 	}
 
 	// If we have a buffer then wait right here. We want to try and allow the
 	// buffer to empty out before prompting the other layers to shut down.
-	if t.bufferLayer != nil {
-		t.bufferLayer.TriggerStopConsuming()
-		if err = t.bufferLayer.WaitForClose(ctx); err != nil {
+	if bufferLayer != nil {
+		bufferLayer.TriggerStopConsuming()
+		if err = bufferLayer.WaitForClose(ctx); err != nil {
 			return fmt.Errorf("waiting on buffer layer failed: %w", err)
 		}
 	}
 
 	// After this point we can start closing the remaining components.
-	if t.pipelineLayer != nil {
-		if err = t.pipelineLayer.WaitForClose(ctx); err != nil {
+	if pipelineLayer != nil {
+		if err = pipelineLayer.WaitForClose(ctx); err != nil {
 			return fmt.Errorf("waiting on pipeline layer failed: %w", err)
 		}
 	}
 
-	if err = t.outputLayer.WaitForClose(ctx); err != nil {
+	if err = outputLayer.WaitForClose(ctx); err != nil {
 		return fmt.Errorf("waiting on output layer failed: %w", err)
 	}
 	return nil
@@ -230,32 +298,37 @@ func (t *Type) StopGracefully(ctx context.Context) (err error) {
 // the stream to gracefully wind down in the order of component layers. This
 // should only be attempted if both stopGracefully and stopOrdered failed.
 func (t *Type) StopUnordered(ctx context.Context) (err error) {
-	t.inputLayer.TriggerCloseNow()
-	if t.bufferLayer != nil {
-		t.bufferLayer.TriggerCloseNow()
+	inputLayer, bufferLayer, pipelineLayer, outputLayer := t.layers()
+
+	inputLayer.TriggerCloseNow()
+	t.inputRelay.closeNow()
+	if bufferLayer != nil {
+		bufferLayer.TriggerCloseNow()
+		t.bufferRelay.closeNow()
 	}
-	if t.pipelineLayer != nil {
-		t.pipelineLayer.TriggerCloseNow()
+	if pipelineLayer != nil {
+		pipelineLayer.TriggerCloseNow()
 	}
-	t.outputLayer.TriggerCloseNow()
+	t.outputRelay.closeNow()
+	outputLayer.TriggerCloseNow()
 
-	if err = t.inputLayer.WaitForClose(ctx); err != nil {
+	if err = inputLayer.WaitForClose(ctx); err != nil {
 		return
 	}
 
-	if t.bufferLayer != nil {
-		if err = t.bufferLayer.WaitForClose(ctx); err != nil {
+	if bufferLayer != nil {
+		if err = bufferLayer.WaitForClose(ctx); err != nil {
 			return
 		}
 	}
 
-	if t.pipelineLayer != nil {
-		if err = t.pipelineLayer.WaitForClose(ctx); err != nil {
+	if pipelineLayer != nil {
+		if err = pipelineLayer.WaitForClose(ctx); err != nil {
 			return
 		}
 	}
 
-	if err = t.outputLayer.WaitForClose(ctx); err != nil {
+	if err = outputLayer.WaitForClose(ctx); err != nil {
 		return
 	}
 	return nil
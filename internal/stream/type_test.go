@@ -239,3 +239,69 @@ output:
 `
 	validateHealthCheckResponse(t, mockAPIReg.server.URL, http.StatusServiceUnavailable, exp)
 }
+
+func TestStreamPauseResume(t *testing.T) {
+	t.Parallel()
+
+	conf, err := testutil.StreamFromYAML(`
+input:
+  generate:
+    interval: ""
+    mapping: 'root = "hello world"'
+output:
+  inproc: foo
+`)
+	require.NoError(t, err)
+
+	newMgr, err := manager.New(manager.NewResourceConfig())
+	require.NoError(t, err)
+
+	strm, err := stream.New(conf, newMgr)
+	require.NoError(t, err)
+
+	tChan, err := newMgr.GetPipe("foo")
+	require.NoError(t, err)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	readTran := func(timeout time.Duration) bool {
+		select {
+		case tran, open := <-tChan:
+			require.True(t, open)
+			require.NoError(t, tran.Ack(ctx, nil))
+			return true
+		case <-time.After(timeout):
+			return false
+		}
+	}
+
+	require.True(t, readTran(time.Second))
+	assert.False(t, strm.IsPaused())
+
+	assert.True(t, strm.Pause())
+	assert.False(t, strm.Pause())
+	assert.True(t, strm.IsPaused())
+
+	// Transactions read before pausing are still delivered, but the input is no
+	// longer read from once they are drained.
+	drained := 0
+	for readTran(time.Millisecond * 100) {
+		drained++
+	}
+	assert.LessOrEqual(t, drained, 3)
+
+	assert.True(t, strm.Resume())
+	assert.False(t, strm.Resume())
+	assert.False(t, strm.IsPaused())
+	require.True(t, readTran(time.Second))
+
+	// A paused stream is still able to shut down gracefully.
+	assert.True(t, strm.Pause())
+	go func() {
+		for tran := range tChan {
+			_ = tran.Ack(ctx, nil)
+		}
+	}()
+	require.NoError(t, strm.Stop(ctx))
+}
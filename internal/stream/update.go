@@ -0,0 +1,208 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/redpanda-data/benthos/v4/internal/component/input"
+	"github.com/redpanda-data/benthos/v4/internal/component/output"
+	"github.com/redpanda-data/benthos/v4/internal/component/processor"
+	"github.com/redpanda-data/benthos/v4/internal/docs"
+	"github.com/redpanda-data/benthos/v4/internal/message"
+	"github.com/redpanda-data/benthos/v4/internal/pipeline"
+)
+
+// ErrUpdateRequiresRestart is returned by Update when the changes to the
+// config of a stream cannot be applied without restarting the stream.
+var ErrUpdateRequiresRestart = errors.New("stream config changes require a restart")
+
+type closableLayer interface {
+	TriggerCloseNow()
+	WaitForClose(ctx context.Context) error
+}
+
+// Update applies a new config to a running stream by replacing only the layers
+// of the stream whose config has changed, leaving the remaining layers, their
+// connections and in-flight transactions intact. Replaced layers are closed
+// gracefully, where a replaced input stops consuming and a replaced pipeline or
+// output finishes processing and delivering the transactions it has already
+// received.
+//
+// If the buffer config has changed, or the input config has changed whilst the
+// stream is paused, then ErrUpdateRequiresRestart is returned without changes
+// being made and the stream must instead be restarted. If a replacement layer
+// fails to be constructed then the error is returned and the stream continues
+// to run with its existing config.
+//
+// The provided context bounds the period of time spent waiting for replaced
+// layers to close, which are closed ungracefully beyond it.
+func (t *Type) Update(ctx context.Context, conf Config) error {
+	t.updateMut.Lock()
+	defer t.updateMut.Unlock()
+
+	if t.stopping || atomic.LoadUint32(&t.closed) == 1 {
+		return ErrUpdateRequiresRestart
+	}
+	if !docs.EqualYAML(t.conf.Buffer, conf.Buffer) {
+		return ErrUpdateRequiresRestart
+	}
+
+	inputChanged := !docs.EqualYAML(t.conf.Input, conf.Input)
+	pipelineChanged := !docs.EqualYAML(t.conf.Pipeline, conf.Pipeline)
+	outputChanged := !docs.EqualYAML(t.conf.Output, conf.Output)
+
+	if inputChanged && t.IsPaused() {
+		return ErrUpdateRequiresRestart
+	}
+
+	// Construct all replacement layers before swapping any of them in order
+	// to leave the stream intact if any fail.
+	var newInput input.Streamed
+	var newPipeline processor.Pipeline
+	var newOutput output.Streamed
+	var err error
+
+	closeNew := func() {
+		for _, l := range []closableLayer{newInput, newPipeline, newOutput} {
+			if l != nil {
+				l.TriggerCloseNow()
+			}
+		}
+	}
+
+	if inputChanged {
+		if newInput, err = t.manager.IntoPath("input").NewInput(conf.Input); err != nil {
+			return fmt.Errorf("failed to create updated input: %w", err)
+		}
+	}
+	if pipelineChanged && len(conf.Pipeline.Processors) > 0 {
+		if newPipeline, err = pipeline.New(conf.Pipeline, t.manager.IntoPath("pipeline")); err != nil {
+			closeNew()
+			return fmt.Errorf("failed to create updated pipeline: %w", err)
+		}
+	}
+	if outputChanged {
+		if newOutput, err = t.manager.IntoPath("output").NewOutput(conf.Output); err != nil {
+			closeNew()
+			return fmt.Errorf("failed to create updated output: %w", err)
+		}
+	}
+
+	// Begin consuming from the sinks of the relays that will feed into the
+	// replacement layers, transactions only arrive once the relays are swapped
+	// over.
+	var outputSink, pipelineSource chan message.Transaction
+	if outputChanged {
+		outputSink = make(chan message.Transaction)
+		if err := newOutput.Consume(outputSink); err != nil {
+			closeNew()
+			return fmt.Errorf("failed to start updated output: %w", err)
+		}
+	}
+	if pipelineChanged {
+		pipelineSource = make(chan message.Transaction)
+		if newPipeline != nil {
+			if err := newPipeline.Consume(pipelineSource); err != nil {
+				closeNew()
+				return fmt.Errorf("failed to start updated pipeline: %w", err)
+			}
+		}
+	}
+
+	// A relay that has already finished means the stream is shutting down, in
+	// which case the replacement layers that have not yet been swapped in are
+	// closed along with the sinks that they consume from.
+	abandon := func(sinks ...chan message.Transaction) {
+		for _, s := range sinks {
+			if s != nil {
+				close(s)
+			}
+		}
+		closeNew()
+	}
+
+	var replaced []closableLayer
+
+	if outputChanged {
+		if !t.outputRelay.swapSink(outputSink) {
+			abandon(outputSink, pipelineSource)
+			return fmt.Errorf("failed to swap output: %w", ErrUpdateRequiresRestart)
+		}
+
+		t.layersMut.Lock()
+		replaced = append(replaced, t.outputLayer)
+		t.outputLayer = newOutput
+		t.layersMut.Unlock()
+
+		t.watchOutput(newOutput)
+		t.conf.Output = conf.Output
+		newOutput = nil
+		t.manager.Logger().Info("Replaced output with updated config")
+	}
+
+	if pipelineChanged {
+		next := (<-chan message.Transaction)(pipelineSource)
+		if newPipeline != nil {
+			next = newPipeline.TransactionChan()
+		}
+
+		// The feed is the relay that sends transactions into the pipeline
+		// layer, or into the output relay when there are no processors.
+		feed := t.inputRelay
+		if t.bufferLayer != nil {
+			feed = t.bufferRelay
+		}
+
+		// The output relay moves on to the new pipeline once the old one has
+		// finished, which happens once the feed into it is closed.
+		if !t.outputRelay.addNextSource(next) || !feed.swapSink(pipelineSource) {
+			abandon(pipelineSource)
+			return fmt.Errorf("failed to swap pipeline: %w", ErrUpdateRequiresRestart)
+		}
+
+		t.layersMut.Lock()
+		if t.pipelineLayer != nil {
+			replaced = append(replaced, t.pipelineLayer)
+		}
+		t.pipelineLayer = newPipeline
+		t.layersMut.Unlock()
+
+		t.conf.Pipeline = conf.Pipeline
+		newPipeline = nil
+		t.manager.Logger().Info("Replaced pipeline with updated config")
+	}
+
+	if inputChanged {
+		// The input relay moves on to the new input once the old one has
+		// stopped consuming.
+		if !t.inputRelay.addNextSource(newInput.TransactionChan()) {
+			abandon()
+			return fmt.Errorf("failed to swap input: %w", ErrUpdateRequiresRestart)
+		}
+
+		t.layersMut.Lock()
+		oldInput := t.inputLayer
+		t.inputLayer = newInput
+		t.layersMut.Unlock()
+
+		oldInput.TriggerStopConsuming()
+		replaced = append(replaced, oldInput)
+		t.manager.Logger().Info("Replaced input with updated config")
+	}
+
+	t.conf = conf
+
+	// The update has been applied at this point, and so failing to close the
+	// replaced layers gracefully is not considered an error.
+	for _, l := range replaced {
+		if err := l.WaitForClose(ctx); err != nil {
+			t.manager.Logger().Warn("Failed to close replaced component gracefully: %v", err)
+			l.TriggerCloseNow()
+		}
+	}
+	return nil
+}
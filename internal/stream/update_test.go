@@ -0,0 +1,224 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package stream_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/internal/component/testutil"
+	"github.com/redpanda-data/benthos/v4/internal/manager"
+	"github.com/redpanda-data/benthos/v4/internal/message"
+	"github.com/redpanda-data/benthos/v4/internal/stream"
+)
+
+func TestStreamUpdate(t *testing.T) {
+	t.Parallel()
+
+	streamConf := func(inMapping, procMapping, outPipe, buffer string) stream.Config {
+		t.Helper()
+		conf, err := testutil.StreamFromYAML(`
+input:
+  generate:
+    interval: 1ms
+    mapping: '` + inMapping + `'
+buffer:
+  ` + buffer + `: {}
+pipeline:
+  processors:
+    - mapping: '` + procMapping + `'
+output:
+  inproc: ` + outPipe + `
+`)
+		require.NoError(t, err)
+		return conf
+	}
+
+	newMgr, err := manager.New(manager.NewResourceConfig())
+	require.NoError(t, err)
+
+	strm, err := stream.New(streamConf(`root = "a"`, `root = content().uppercase()`, "foo", "none"), newMgr)
+	require.NoError(t, err)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	fooChan, err := newMgr.GetPipe("foo")
+	require.NoError(t, err)
+
+	// Reads transactions until one matches the expected content, transactions
+	// processed prior to an update might still be in flight.
+	waitFor := func(tChan <-chan message.Transaction, exp string) {
+		t.Helper()
+		timeout := time.After(time.Second * 10)
+		for {
+			select {
+			case tran, open := <-tChan:
+				require.True(t, open)
+				require.NoError(t, tran.Ack(ctx, nil))
+				if string(tran.Payload.Get(0).AsBytes()) == exp {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("timed out waiting for message: %v", exp)
+			}
+		}
+	}
+
+	drain := func(tChan <-chan message.Transaction) (stop func()) {
+		doneChan := make(chan struct{})
+		go func() {
+			for {
+				select {
+				case tran, open := <-tChan:
+					if !open {
+						return
+					}
+					_ = tran.Ack(ctx, nil)
+				case <-doneChan:
+					return
+				}
+			}
+		}()
+		return func() { close(doneChan) }
+	}
+
+	waitFor(fooChan, "A")
+
+	// Only the pipeline is replaced, and therefore the output continues to
+	// deliver to the same pipe.
+	stopDrain := drain(fooChan)
+	require.NoError(t, strm.Update(ctx, streamConf(`root = "a"`, `root = content() + " b"`, "foo", "none")))
+	stopDrain()
+	waitFor(fooChan, "a b")
+
+	stopDrain = drain(fooChan)
+	require.NoError(t, strm.Update(ctx, streamConf(`root = "c"`, `root = content() + " b"`, "foo", "none")))
+	stopDrain()
+	waitFor(fooChan, "c b")
+
+	// Replacing the output closes the previous one once it has delivered the
+	// transactions it has already received.
+	stopDrain = drain(fooChan)
+	require.NoError(t, strm.Update(ctx, streamConf(`root = "c"`, `root = content() + " b"`, "bar", "none")))
+	stopDrain()
+
+	barChan, err := newMgr.GetPipe("bar")
+	require.NoError(t, err)
+	waitFor(barChan, "c b")
+	assert.Eventually(t, strm.IsReady, time.Second*5, time.Millisecond*10)
+
+	// Unchanged configs are a no-op.
+	require.NoError(t, strm.Update(ctx, streamConf(`root = "c"`, `root = content() + " b"`, "bar", "none")))
+	waitFor(barChan, "c b")
+
+	assert.ErrorIs(t, strm.Update(ctx, streamConf(`root = "c"`, `root = content() + " b"`, "bar", "memory")), stream.ErrUpdateRequiresRestart)
+
+	// Failing to construct a replacement leaves the stream intact.
+	require.Error(t, strm.Update(ctx, streamConf(`root = "c"`, `root = nope(`, "bar", "none")))
+	waitFor(barChan, "c b")
+
+	drain(barChan)
+	require.NoError(t, strm.Stop(ctx))
+}
+
+func TestStreamUpdateWithBuffer(t *testing.T) {
+	t.Parallel()
+
+	streamConf := func(procMapping string) stream.Config {
+		t.Helper()
+		conf, err := testutil.StreamFromYAML(`
+input:
+  generate:
+    interval: 1ms
+    mapping: 'root = "a"'
+buffer:
+  memory: {}
+pipeline:
+  processors:
+    - mapping: '` + procMapping + `'
+output:
+  inproc: foo
+`)
+		require.NoError(t, err)
+		return conf
+	}
+
+	newMgr, err := manager.New(manager.NewResourceConfig())
+	require.NoError(t, err)
+
+	strm, err := stream.New(streamConf(`root = content().uppercase()`), newMgr)
+	require.NoError(t, err)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	fooChan, err := newMgr.GetPipe("foo")
+	require.NoError(t, err)
+
+	updateErr := make(chan error, 1)
+	go func() {
+		updateErr <- strm.Update(ctx, streamConf(`root = content() + " b"`))
+	}()
+
+	var seenUpdated bool
+	for !seenUpdated {
+		select {
+		case tran := <-fooChan:
+			require.NoError(t, tran.Ack(ctx, nil))
+			seenUpdated = string(tran.Payload.Get(0).AsBytes()) == "a b"
+		case <-time.After(time.Second * 10):
+			t.Fatal("timed out waiting for updated pipeline")
+		}
+	}
+	require.NoError(t, <-updateErr)
+
+	go func() {
+		for tran := range fooChan {
+			_ = tran.Ack(ctx, nil)
+		}
+	}()
+	require.NoError(t, strm.Stop(ctx))
+}
+
+func TestStreamUpdateAfterStop(t *testing.T) {
+	t.Parallel()
+
+	conf, err := testutil.StreamFromYAML(`
+input:
+  generate:
+    count: 1
+    interval: ""
+    mapping: 'root = "a"'
+output:
+  drop: {}
+`)
+	require.NoError(t, err)
+
+	newMgr, err := manager.New(manager.NewResourceConfig())
+	require.NoError(t, err)
+
+	strm, err := stream.New(conf, newMgr)
+	require.NoError(t, err)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	require.NoError(t, strm.StopGracefully(ctx))
+
+	updated, err := testutil.StreamFromYAML(`
+input:
+  generate:
+    count: 1
+    interval: ""
+    mapping: 'root = "a"'
+output:
+  reject: nope
+`)
+	require.NoError(t, err)
+	assert.ErrorIs(t, strm.Update(ctx, updated), stream.ErrUpdateRequiresRestart)
+}
@@ -0,0 +1,387 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package value
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IEqual returns true if both the left and right are structurally equal,
+// following the same rules as ICompare with the exception that objects must
+// contain the exact same keys, including keys with null values.
+func IEqual(left, right any) bool {
+	switch lhs := left.(type) {
+	case map[string]any:
+		rhs, matches := right.(map[string]any)
+		if !matches || len(lhs) != len(rhs) {
+			return false
+		}
+		for k, vl := range lhs {
+			vr, exists := rhs[k]
+			if !exists || !IEqual(vl, vr) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		rhs, matches := right.([]any)
+		if !matches || len(lhs) != len(rhs) {
+			return false
+		}
+		for i, vl := range lhs {
+			if !IEqual(vl, rhs[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return ICompare(left, right)
+}
+
+//------------------------------------------------------------------------------
+
+// JSONPointerToSlice parses an RFC 6901 JSON Pointer into a slice of its
+// reference tokens. An empty string refers to the whole document and results
+// in an empty slice.
+func JSONPointerToSlice(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("JSON Pointer %q must begin with a slash", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// SliceToJSONPointer converts a slice of reference tokens into an RFC 6901
+// JSON Pointer.
+func SliceToJSONPointer(tokens ...string) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteByte('/')
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(t, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
+
+func jsonPointerIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i > length || (i == length && !allowEnd) {
+		return 0, fmt.Errorf("array index %v is out of bounds", i)
+	}
+	return i, nil
+}
+
+func jsonPointerGet(root any, tokens []string) (any, error) {
+	current := root
+	for i, t := range tokens {
+		switch c := current.(type) {
+		case map[string]any:
+			v, exists := c[t]
+			if !exists {
+				return nil, fmt.Errorf("path %v does not exist", SliceToJSONPointer(tokens[:i+1]...))
+			}
+			current = v
+		case []any:
+			idx, err := jsonPointerIndex(t, len(c), false)
+			if err != nil {
+				return nil, fmt.Errorf("path %v: %w", SliceToJSONPointer(tokens[:i+1]...), err)
+			}
+			current = c[idx]
+		default:
+			return nil, fmt.Errorf("path %v does not exist", SliceToJSONPointer(tokens[:i+1]...))
+		}
+	}
+	return current, nil
+}
+
+// jsonPointerModify descends a value following all but the last token of a
+// pointer, and replaces the container found with the result of a function
+// given the container and the last token.
+func jsonPointerModify(root any, tokens []string, fn func(container any, key string) (any, error)) (any, error) {
+	parent, err := jsonPointerGet(root, tokens[:len(tokens)-1])
+	if err != nil {
+		return nil, err
+	}
+	newParent, err := fn(parent, tokens[len(tokens)-1])
+	if err != nil {
+		return nil, fmt.Errorf("path %v: %w", SliceToJSONPointer(tokens...), err)
+	}
+	if len(tokens) == 1 {
+		return newParent, nil
+	}
+
+	// Arrays may have been reallocated and therefore the modified container
+	// must be set within its own parent.
+	grandParent, _ := jsonPointerGet(root, tokens[:len(tokens)-2])
+	switch g := grandParent.(type) {
+	case map[string]any:
+		g[tokens[len(tokens)-2]] = newParent
+	case []any:
+		idx, _ := jsonPointerIndex(tokens[len(tokens)-2], len(g), false)
+		g[idx] = newParent
+	}
+	return root, nil
+}
+
+func jsonPointerAdd(root any, tokens []string, v any) (any, error) {
+	if len(tokens) == 0 {
+		return v, nil
+	}
+	return jsonPointerModify(root, tokens, func(container any, key string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			c[key] = v
+			return c, nil
+		case []any:
+			idx, err := jsonPointerIndex(key, len(c), true)
+			if err != nil {
+				return nil, err
+			}
+			c = append(c, nil)
+			copy(c[idx+1:], c[idx:])
+			c[idx] = v
+			return c, nil
+		}
+		return nil, errors.New("parent is not an object or array")
+	})
+}
+
+func jsonPointerRemove(root any, tokens []string) (any, error) {
+	if len(tokens) == 0 {
+		return nil, errors.New("cannot remove the root of the document")
+	}
+	return jsonPointerModify(root, tokens, func(container any, key string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			if _, exists := c[key]; !exists {
+				return nil, errors.New("does not exist")
+			}
+			delete(c, key)
+			return c, nil
+		case []any:
+			idx, err := jsonPointerIndex(key, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			return append(c[:idx], c[idx+1:]...), nil
+		}
+		return nil, errors.New("parent is not an object or array")
+	})
+}
+
+//------------------------------------------------------------------------------
+
+func jsonPatchOp(op string, path []string, v any, withValue bool) map[string]any {
+	m := map[string]any{
+		"op":   op,
+		"path": SliceToJSONPointer(path...),
+	}
+	if withValue {
+		m["value"] = IClone(v)
+	}
+	return m
+}
+
+func jsonPatchDiff(path []string, from, to any, ops []any) []any {
+	switch f := from.(type) {
+	case map[string]any:
+		t, ok := to.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(f))
+		for k := range f {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			childPath := append(path[:len(path):len(path)], k)
+			if tv, exists := t[k]; exists {
+				ops = jsonPatchDiff(childPath, f[k], tv, ops)
+			} else {
+				ops = append(ops, jsonPatchOp("remove", childPath, nil, false))
+			}
+		}
+		keys = keys[:0]
+		for k := range t {
+			if _, exists := f[k]; !exists {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			ops = append(ops, jsonPatchOp("add", append(path[:len(path):len(path)], k), t[k], true))
+		}
+		return ops
+	case []any:
+		t, ok := to.([]any)
+		if !ok {
+			break
+		}
+		common := min(len(f), len(t))
+		for i := 0; i < common; i++ {
+			ops = jsonPatchDiff(append(path[:len(path):len(path)], strconv.Itoa(i)), f[i], t[i], ops)
+		}
+		for i := len(f) - 1; i >= common; i-- {
+			ops = append(ops, jsonPatchOp("remove", append(path[:len(path):len(path)], strconv.Itoa(i)), nil, false))
+		}
+		for i := common; i < len(t); i++ {
+			ops = append(ops, jsonPatchOp("add", append(path[:len(path):len(path)], strconv.Itoa(i)), t[i], true))
+		}
+		return ops
+	}
+	if !IEqual(from, to) {
+		ops = append(ops, jsonPatchOp("replace", path, to, true))
+	}
+	return ops
+}
+
+// CreateJSONPatch returns an RFC 6902 JSON Patch, in the form of an array of
+// operation objects, that transforms one value into another. Objects are
+// compared key by key and arrays are compared index by index, where values
+// that differ are replaced.
+func CreateJSONPatch(from, to any) []any {
+	return jsonPatchDiff(nil, from, to, []any{})
+}
+
+// ApplyJSONPatch applies an RFC 6902 JSON Patch, in the form of an array of
+// operation objects, to a value and returns the result. The provided value is
+// not modified. An error is returned if any of the operations fail, including
+// test operations.
+func ApplyJSONPatch(root, patch any) (any, error) {
+	ops, ok := patch.([]any)
+	if !ok {
+		return nil, NewTypeError(patch, TArray)
+	}
+
+	root = IClone(root)
+	for i, o := range ops {
+		var err error
+		if root, err = applyJSONPatchOp(root, o); err != nil {
+			return nil, fmt.Errorf("operation %v: %w", i, err)
+		}
+	}
+	return root, nil
+}
+
+func applyJSONPatchOp(root, o any) (any, error) {
+	obj, ok := o.(map[string]any)
+	if !ok {
+		return nil, NewTypeError(o, TObject)
+	}
+
+	getPointer := func(field string) ([]string, error) {
+		p, exists := obj[field]
+		if !exists {
+			return nil, fmt.Errorf("missing field %v", field)
+		}
+		pStr, ok := p.(string)
+		if !ok {
+			return nil, fmt.Errorf("field %v: %w", field, NewTypeError(p, TString))
+		}
+		return JSONPointerToSlice(pStr)
+	}
+
+	op, _ := obj["op"].(string)
+	path, err := getPointer("path")
+	if err != nil {
+		return nil, err
+	}
+
+	v, hasValue := obj["value"]
+	switch op {
+	case "add", "replace", "test":
+		if !hasValue {
+			return nil, fmt.Errorf("missing field value for %v operation", op)
+		}
+		v = IClone(v)
+	}
+
+	switch op {
+	case "add":
+		return jsonPointerAdd(root, path, v)
+	case "remove":
+		return jsonPointerRemove(root, path)
+	case "replace":
+		if len(path) == 0 {
+			return v, nil
+		}
+		if root, err = jsonPointerRemove(root, path); err != nil {
+			return nil, err
+		}
+		return jsonPointerAdd(root, path, v)
+	case "move", "copy":
+		from, err := getPointer("from")
+		if err != nil {
+			return nil, err
+		}
+		if v, err = jsonPointerGet(root, from); err != nil {
+			return nil, err
+		}
+		if op == "copy" {
+			return jsonPointerAdd(root, path, IClone(v))
+		}
+		if len(path) > len(from) && SliceToJSONPointer(path[:len(from)]...) == SliceToJSONPointer(from...) {
+			return nil, errors.New("cannot move a value into one of its own children")
+		}
+		if root, err = jsonPointerRemove(root, from); err != nil {
+			return nil, err
+		}
+		return jsonPointerAdd(root, path, v)
+	case "test":
+		current, err := jsonPointerGet(root, path)
+		if err != nil {
+			return nil, err
+		}
+		if !IEqual(current, v) {
+			return nil, fmt.Errorf("test failed as the value at path %v does not match", SliceToJSONPointer(path...))
+		}
+		return root, nil
+	}
+	return nil, fmt.Errorf("unrecognised operation %q", op)
+}
+
+// ApplyMergePatch applies an RFC 7396 JSON Merge Patch to a value and returns
+// the result. Objects within the patch are merged recursively, null values
+// remove keys and any other value replaces the target. The provided value is
+// not modified.
+func ApplyMergePatch(root, patch any) any {
+	return mergePatch(IClone(root), patch)
+}
+
+func mergePatch(root, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return IClone(patch)
+	}
+	rootObj, ok := root.(map[string]any)
+	if !ok {
+		rootObj = map[string]any{}
+	}
+	for k, v := range patchObj {
+		if v == nil {
+			delete(rootObj, k)
+			continue
+		}
+		rootObj[k] = mergePatch(rootObj[k], v)
+	}
+	return rootObj
+}
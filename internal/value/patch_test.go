@@ -0,0 +1,256 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package value
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseTestJSON(t testing.TB, s string) any {
+	t.Helper()
+
+	var v any
+	require.NoError(t, json.Unmarshal([]byte(s), &v))
+	return v
+}
+
+func TestJSONPointers(t *testing.T) {
+	tokens, err := JSONPointerToSlice("/a~1b/c~0d/0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a/b", "c~d", "0"}, tokens)
+	assert.Equal(t, "/a~1b/c~0d/0", SliceToJSONPointer(tokens...))
+
+	tokens, err = JSONPointerToSlice("")
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+
+	_, err = JSONPointerToSlice("a/b")
+	require.Error(t, err)
+}
+
+func TestCreateJSONPatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		patch    string
+	}{
+		{
+			name:  "equal values",
+			from:  `{"a":[1,{"b":null}]}`,
+			to:    `{"a":[1,{"b":null}]}`,
+			patch: `[]`,
+		},
+		{
+			name:  "object keys",
+			from:  `{"a":1,"b":{"c":"foo","d":true},"e":null}`,
+			to:    `{"a":2,"b":{"c":"foo"},"f/g":[]}`,
+			patch: `[{"op":"replace","path":"/a","value":2},{"op":"remove","path":"/b/d"},{"op":"remove","path":"/e"},{"op":"add","path":"/f~1g","value":[]}]`,
+		},
+		{
+			name:  "array shrinks",
+			from:  `[1,2,3,4]`,
+			to:    `[1,5]`,
+			patch: `[{"op":"replace","path":"/1","value":5},{"op":"remove","path":"/3"},{"op":"remove","path":"/2"}]`,
+		},
+		{
+			name:  "array grows",
+			from:  `{"a":[1]}`,
+			to:    `{"a":[1,2,3]}`,
+			patch: `[{"op":"add","path":"/a/1","value":2},{"op":"add","path":"/a/2","value":3}]`,
+		},
+		{
+			name:  "type changes",
+			from:  `{"a":{"b":1}}`,
+			to:    `{"a":[1]}`,
+			patch: `[{"op":"replace","path":"/a","value":[1]}]`,
+		},
+		{
+			name:  "root replaced",
+			from:  `"foo"`,
+			to:    `{"a":1}`,
+			patch: `[{"op":"replace","path":"","value":{"a":1}}]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			from, to := parseTestJSON(t, test.from), parseTestJSON(t, test.to)
+
+			patch := CreateJSONPatch(from, to)
+			assert.Equal(t, parseTestJSON(t, test.patch), parseTestJSON(t, string(IToBytes(patch))))
+
+			res, err := ApplyJSONPatch(from, patch)
+			require.NoError(t, err)
+			assert.Equal(t, to, res)
+			assert.Equal(t, parseTestJSON(t, test.from), from)
+		})
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		doc    string
+		patch  string
+		result string
+		err    string
+	}{
+		{
+			name:   "add object member",
+			doc:    `{"foo":"bar"}`,
+			patch:  `[{"op":"add","path":"/baz","value":"qux"}]`,
+			result: `{"baz":"qux","foo":"bar"}`,
+		},
+		{
+			name:   "add array element",
+			doc:    `{"foo":["bar","baz"]}`,
+			patch:  `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			result: `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			name:   "add to end of nested array",
+			doc:    `{"foo":[["bar"]]}`,
+			patch:  `[{"op":"add","path":"/foo/0/-","value":"baz"}]`,
+			result: `{"foo":[["bar","baz"]]}`,
+		},
+		{
+			name:   "remove array element",
+			doc:    `{"foo":["bar","qux","baz"]}`,
+			patch:  `[{"op":"remove","path":"/foo/1"}]`,
+			result: `{"foo":["bar","baz"]}`,
+		},
+		{
+			name:   "replace value",
+			doc:    `{"baz":"qux","foo":"bar"}`,
+			patch:  `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			result: `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			name:   "move value",
+			doc:    `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch:  `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			result: `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			name:   "move array element",
+			doc:    `{"foo":["all","grass","cows","eat"]}`,
+			patch:  `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			result: `{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			name:   "copy value",
+			doc:    `{"foo":{"bar":[1]}}`,
+			patch:  `[{"op":"copy","from":"/foo/bar","path":"/baz"},{"op":"add","path":"/baz/-","value":2}]`,
+			result: `{"baz":[1,2],"foo":{"bar":[1]}}`,
+		},
+		{
+			name:   "test passes",
+			doc:    `{"baz":"qux","foo":["a",2,"c"]}`,
+			patch:  `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			result: `{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		{
+			name:  "test fails",
+			doc:   `{"baz":"qux"}`,
+			patch: `[{"op":"test","path":"/baz","value":"bar"}]`,
+			err:   "operation 0: test failed as the value at path /baz does not match",
+		},
+		{
+			name:  "test fails on missing key",
+			doc:   `{"a":{}}`,
+			patch: `[{"op":"test","path":"/a","value":{"b":null}}]`,
+			err:   "operation 0: test failed as the value at path /a does not match",
+		},
+		{
+			name:  "add to missing parent",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			err:   "operation 0: path /baz does not exist",
+		},
+		{
+			name:  "remove missing key",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/a","value":1},{"op":"remove","path":"/baz"}]`,
+			err:   "operation 1: path /baz: does not exist",
+		},
+		{
+			name:  "array index out of bounds",
+			doc:   `[1,2]`,
+			patch: `[{"op":"add","path":"/3","value":3}]`,
+			err:   "operation 0: path /3: array index 3 is out of bounds",
+		},
+		{
+			name:  "array index leading zero",
+			doc:   `[1,2]`,
+			patch: `[{"op":"replace","path":"/01","value":3}]`,
+			err:   `operation 0: path /01: invalid array index "01"`,
+		},
+		{
+			name:  "move into own child",
+			doc:   `{"a":{"b":{}}}`,
+			patch: `[{"op":"move","from":"/a","path":"/a/b/c"}]`,
+			err:   "operation 0: cannot move a value into one of its own children",
+		},
+		{
+			name:  "missing value",
+			doc:   `{}`,
+			patch: `[{"op":"add","path":"/a"}]`,
+			err:   "operation 0: missing field value for add operation",
+		},
+		{
+			name:  "unknown operation",
+			doc:   `{}`,
+			patch: `[{"op":"nope","path":"/a"}]`,
+			err:   `operation 0: unrecognised operation "nope"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc := parseTestJSON(t, test.doc)
+
+			res, err := ApplyJSONPatch(doc, parseTestJSON(t, test.patch))
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, parseTestJSON(t, test.result), res)
+			assert.Equal(t, parseTestJSON(t, test.doc), doc)
+		})
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	// Test cases from https://datatracker.ietf.org/doc/html/rfc7396#appendix-A
+	tests := []struct {
+		doc, patch, result string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, test := range tests {
+		doc := parseTestJSON(t, test.doc)
+		res := ApplyMergePatch(doc, parseTestJSON(t, test.patch))
+		assert.Equal(t, parseTestJSON(t, test.result), res, "%v + %v", test.doc, test.patch)
+		assert.Equal(t, parseTestJSON(t, test.doc), doc)
+	}
+}
@@ -103,6 +103,16 @@ func (d ParamDefinition) Default(v any) ParamDefinition {
 	return d
 }
 
+// Secret marks the parameter as being a secret, which means it represents
+// information that is generally considered sensitive such as passwords or
+// signing keys. Errors returned when the arguments of secret parameters fail to
+// be parsed or resolved omit details that might contain their values, and
+// implementations should also avoid including them within errors of their own.
+func (d ParamDefinition) Secret() ParamDefinition {
+	d.def = d.def.Secret()
+	return d
+}
+
 //------------------------------------------------------------------------------
 
 // PluginSpec documents and defines the parameters of a function or method and
@@ -93,6 +93,7 @@ func templateParams(p query.Params) TemplateParamsData {
 			Description:       d.Description,
 			ValueType:         string(d.ValueType),
 			IsOptional:        d.IsOptional,
+			IsSecret:          d.IsSecret,
 			DefaultMarshalled: jDefault,
 		})
 	}
@@ -113,6 +114,10 @@ type TemplateParamData struct {
 	// default.
 	IsOptional        bool
 	DefaultMarshalled string
+
+	// IsSecret indicates whether the parameter represents information that is
+	// generally considered sensitive such as passwords or signing keys.
+	IsSecret bool
 }
 
 // TemplateParamsData describes the overall set of parameters for a bloblang
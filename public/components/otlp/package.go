@@ -0,0 +1,15 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+// Package otlp contains component implementations that export telemetry to
+// Open Telemetry collectors using the OTLP protocol.
+//
+// EXPERIMENTAL: The specific components excluded by this package may change
+// outside of major version releases. This means we may choose to remove certain
+// plugins if we determine that their dependencies are likely to interfere with
+// the goals of this package.
+package otlp
+
+import (
+	// Import only otlp packages.
+	_ "github.com/redpanda-data/benthos/v4/internal/impl/otlp"
+)
@@ -21,6 +21,16 @@ type RateLimit interface {
 	Closer
 }
 
+// keyedRateLimit represents a rate limit that is able to track a separate limit
+// for each key. This interface is optional for rate limits and when implemented
+// will automatically be utilised by components that provide a key, such as the
+// rate_limit processor.
+type keyedRateLimit interface {
+	// AccessKey is equivalent to Access but the limit is applied only to
+	// requests that share the same key.
+	AccessKey(ctx context.Context, key string) (time.Duration, error)
+}
+
 //------------------------------------------------------------------------------
 
 func newAirGapRateLimit(c RateLimit, stats metrics.Type) ratelimit.V1 {
@@ -34,6 +44,8 @@ type reverseAirGapRateLimit struct {
 	r ratelimit.V1
 }
 
+var _ keyedRateLimit = &reverseAirGapRateLimit{}
+
 func newReverseAirGapRateLimit(r ratelimit.V1) *reverseAirGapRateLimit {
 	return &reverseAirGapRateLimit{r}
 }
@@ -42,6 +54,13 @@ func (a *reverseAirGapRateLimit) Access(ctx context.Context) (time.Duration, err
 	return a.r.Access(ctx)
 }
 
+func (a *reverseAirGapRateLimit) AccessKey(ctx context.Context, key string) (time.Duration, error) {
+	if kr, ok := a.r.(ratelimit.KeyedV1); ok {
+		return kr.AccessKey(ctx, key)
+	}
+	return a.r.Access(ctx)
+}
+
 func (a *reverseAirGapRateLimit) Close(ctx context.Context) error {
 	return a.r.Close(ctx)
 }
@@ -0,0 +1,23 @@
+// Copyright 2025 Redpanda Data, Inc.
+
+package service
+
+import (
+	"context"
+
+	"github.com/redpanda-data/benthos/v4/internal/config"
+)
+
+// RegisterSecretProvider adds a provider that resolves secret references of the
+// form `${secret:<name>:<key>}` within configs, where the provided function is
+// called with the key of the reference and returns the value of the secret.
+// Resolved secrets are cached for a period of time, and are scrubbed from
+// configs printed by the `/debug/config` endpoints.
+//
+// The providers `dotenv` and `encrypted` are registered by default, and the
+// providers `file` and `exec` are available to configs run with the
+// `--secret-provider` CLI flag. An error is returned if a provider of the same
+// name already exists.
+func RegisterSecretProvider(name string, fn func(ctx context.Context, key string) (string, error)) error {
+	return config.RegisterSecretProvider(name, config.SecretProviderFunc(fn))
+}
@@ -765,8 +765,8 @@ reject: lol nah`,
 				},
 				{
 					typeStr: "metrics",
-					name:    "none",
-					conf: `none: {}
+					name:    "prometheus",
+					conf: `prometheus: {}
 mapping: ""`,
 				},
 				{
@@ -829,8 +829,8 @@ mutation: 'root = "eh"'`,
 				},
 				{
 					typeStr: "metrics",
-					name:    "none",
-					conf: `none: {}
+					name:    "prometheus",
+					conf: `prometheus: {}
 mapping: ""`,
 				},
 				{
@@ -880,8 +880,8 @@ stdout: {}`,
 				},
 				{
 					typeStr: "metrics",
-					name:    "none",
-					conf: `none: {}
+					name:    "prometheus",
+					conf: `prometheus: {}
 mapping: ""`,
 				},
 				{
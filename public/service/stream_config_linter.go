@@ -81,7 +81,7 @@ func (s *StreamConfigLinter) LintYAML(yamlBytes []byte) (lints []Lint, err error
 
 	if yamlBytes, err = config.NewReader("", nil, config.OptUseEnvLookupFunc(func(ctx context.Context, key string) (string, bool) {
 		return s.envVarLookupFn(key)
-	})).ReplaceEnvVariables(context.TODO(), yamlBytes); err != nil {
+	}), config.OptSkipSecrets()).ReplaceEnvVariables(context.TODO(), yamlBytes); err != nil {
 		var errEnvMissing *config.ErrMissingEnvVars
 		if !errors.As(err, &errEnvMissing) {
 			return